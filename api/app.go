@@ -1491,6 +1491,7 @@ func getServiceInstance(ctx stdContext.Context, serviceName, instanceName, appNa
 //	400: Invalid data
 //	401: Unauthorized
 //	404: App not found
+//	409: Service instance is still being provisioned
 func bindServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	ctx := r.Context()
 	instanceName := r.URL.Query().Get(":instance")
@@ -1509,7 +1510,7 @@ func bindServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token) (
 		return err
 	}
 	allowed := permission.Check(ctx, t, permission.PermServiceInstanceUpdateBind,
-		append(permission.Contexts(permTypes.CtxTeam, instance.Teams),
+		append(permission.Contexts(permTypes.CtxTeam, instance.BindTeamsForApp(a.Name, a.TeamOwner)),
 			permission.Context(permTypes.CtxTeam, instance.TeamOwner),
 			permission.Context(permTypes.CtxServiceInstance, instance.Name),
 		)...,
@@ -1523,6 +1524,9 @@ func bindServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token) (
 	if !allowed {
 		return permission.ErrUnauthorized
 	}
+	if err = instance.CheckAppBindAccess(a.Name, a.TeamOwner); err != nil {
+		return &errors.HTTP{Code: http.StatusForbidden, Message: err.Error()}
+	}
 	err = app.ValidateService(ctx, a, serviceName)
 	if err != nil {
 		if err == pool.ErrPoolHasNoService {
@@ -1560,6 +1564,9 @@ func bindServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token) (
 	evt.SetLogWriter(writer)
 	err = instance.BindApp(ctx, a, req.Parameters, !req.NoRestart, evt, evt, requestIDHeader(r))
 	if err != nil {
+		if httpErr := provisioningError(err); httpErr != nil {
+			return httpErr
+		}
 		status, errStatus := instance.Status(ctx, requestIDHeader(r))
 		if errStatus != nil {
 			return fmt.Errorf("%v (failed to retrieve instance status: %v)", err, errStatus)
@@ -1599,7 +1606,7 @@ func unbindServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 		return err
 	}
 	allowed := permission.Check(ctx, t, permission.PermServiceInstanceUpdateUnbind,
-		append(permission.Contexts(permTypes.CtxTeam, instance.Teams),
+		append(permission.Contexts(permTypes.CtxTeam, instance.BindTeamsForApp(a.Name, a.TeamOwner)),
 			permission.Context(permTypes.CtxTeam, instance.TeamOwner),
 			permission.Context(permTypes.CtxServiceInstance, instance.Name),
 		)...,
@@ -5262,6 +5262,34 @@ func (s *S) TestBindHandlerErrorShowsStatusMessage(c *check.C) {
 	c.Assert(recorder.Body.String(), check.Equals, "Failed to bind the instance \"mysql/my-mysql\" to the app \"painkiller\": invalid response:  (code: 500) (\"my-mysql\" is down)\n")
 }
 
+func (s *S) TestBindHandlerInstanceStillProvisioning(c *check.C) {
+	srvc := service.Service{Name: "mysql", Endpoint: map[string]string{"production": "http://localhost:1234"}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := service.Create(context.TODO(), srvc)
+	c.Assert(err, check.IsNil)
+	instance := service.ServiceInstance{
+		Name:            "my-mysql",
+		ServiceName:     "mysql",
+		Teams:           []string{s.team.Name},
+		ProvisionStatus: service.ProvisionStatusProvisioning,
+	}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), instance)
+	c.Assert(err, check.IsNil)
+	a := appTypes.App{Name: "painkiller", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	u := fmt.Sprintf("/services/%s/instances/%s/%s", instance.ServiceName, instance.Name, a.Name)
+	request, err := http.NewRequest("PUT", u, strings.NewReader("noRestart=true"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	c.Assert(recorder.Body.String(), check.Equals, service.ErrServiceInstanceProvisioning.Error()+"\n")
+}
+
 func (s *S) TestBindHandlerReturns404IfTheInstanceDoesNotExist(c *check.C) {
 	a := appTypes.App{Name: "serviceapp", Platform: "zend", TeamOwner: s.team.Name}
 	err := app.CreateApp(context.TODO(), &a, s.user)
@@ -11,6 +11,8 @@ import (
 	"net/http"
 	"reflect"
 	"runtime"
+	"strconv"
+	"time"
 
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/app"
@@ -25,6 +27,7 @@ import (
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	eventTypes "github.com/tsuru/tsuru/types/event"
 	permTypes "github.com/tsuru/tsuru/types/permission"
+	"github.com/tsuru/tsuru/types/quota"
 	"github.com/tsuru/tsuru/volume"
 )
 
@@ -39,6 +42,9 @@ func handleAuthError(err error) error {
 	if err == authTypes.ErrUserNotFound {
 		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
 	}
+	if err == auth.ErrUserDisabled {
+		return &errors.HTTP{Code: http.StatusUnauthorized, Message: err.Error()}
+	}
 	switch err.(type) {
 	case *errors.ValidationError:
 		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
@@ -158,11 +164,16 @@ func login(w http.ResponseWriter, r *http.Request) (err error) {
 //
 //	200: Ok
 func logout(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	// Revoking the session also logs out tokens the scheme cannot
+	// invalidate, like the ones issued by OpenID providers.
+	err = auth.RevokeSession(ctx, t.GetUserName(), auth.SessionID(t.GetValue()))
+	if err != nil && err != auth.ErrSessionNotFound {
+		return err
+	}
 	if userScheme, ok := app.AuthScheme.(auth.UserScheme); ok {
-		return userScheme.Logout(r.Context(), t.GetValue())
+		return userScheme.Logout(ctx, t.GetValue())
 	}
-
-	w.WriteHeader(http.StatusNotImplemented)
 	return nil
 }
 
@@ -265,6 +276,7 @@ var teamRenameFns = []func(ctx context.Context, oldName, newName string) error{
 	service.RenameServiceInstanceTeam,
 	volume.RenameTeam,
 	pool.RenamePoolTeam,
+	auth.RenameTeamParent,
 }
 
 // title: team update
@@ -296,7 +308,7 @@ func updateTeam(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	if !allowed {
 		return permission.ErrUnauthorized
 	}
-	_, err = servicemanager.Team.FindByName(ctx, name)
+	team, err := servicemanager.Team.FindByName(ctx, name)
 	if err != nil {
 		if err == authTypes.ErrTeamNotFound {
 			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
@@ -326,6 +338,12 @@ func updateTeam(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	if err != nil {
 		return err
 	}
+	if team.Parent != "" {
+		err = servicemanager.Team.SetParent(ctx, changeRequest.NewName, team.Parent, team.ShareParentQuota)
+		if err != nil {
+			return err
+		}
+	}
 	var toRollback []func(ctx context.Context, oldName, newName string) error
 	defer func() {
 		if err == nil {
@@ -375,6 +393,14 @@ func createTeam(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	}
 	tags, _ := InputValues(r, "tag")
 	team.Tags = append(team.Tags, tags...) // for compatibility
+	if team.Parent != "" {
+		allowed = permission.Check(ctx, t, permission.PermTeamUpdateParent,
+			permission.Context(permTypes.CtxTeam, team.Parent),
+		)
+		if !allowed {
+			return permission.ErrUnauthorized
+		}
+	}
 	evt, err := event.New(ctx, &event.Opts{
 		Target:     teamTarget(team.Name),
 		Kind:       permission.PermTeamCreate,
@@ -398,10 +424,84 @@ func createTeam(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	case authTypes.ErrTeamAlreadyExists:
 		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
 	}
-	if err == nil {
-		w.WriteHeader(http.StatusCreated)
+	if err != nil {
+		return err
 	}
-	return err
+	if team.Parent != "" {
+		err = servicemanager.Team.SetParent(ctx, team.Name, team.Parent, team.ShareParentQuota)
+		if err != nil {
+			if rollbackErr := servicemanager.Team.Remove(ctx, team.Name); rollbackErr != nil {
+				log.Errorf("error rolling back creation of team %q: %v", team.Name, rollbackErr)
+			}
+			return handleAuthError(err)
+		}
+	}
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+// title: set team parent
+// path: /teams/{name}/parent
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// responses:
+//
+//	200: Parent updated
+//	400: Invalid data
+//	401: Unauthorized
+//	403: Forbidden
+//	404: Team not found
+func setTeamParent(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	name := r.URL.Query().Get(":name")
+	parent := InputValue(r, "parent")
+	shareQuota, _ := strconv.ParseBool(InputValue(r, "shareQuota"))
+	allowed := permission.Check(ctx, t, permission.PermTeamUpdateParent,
+		permission.Context(permTypes.CtxTeam, name),
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	team, err := servicemanager.Team.FindByName(ctx, name)
+	if err != nil {
+		if err == authTypes.ErrTeamNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	// Moving a team changes who inherits access to it, so both the current
+	// and the new parent must be managed by the caller.
+	for _, p := range []string{team.Parent, parent} {
+		if p == "" {
+			continue
+		}
+		allowed = permission.Check(ctx, t, permission.PermTeamUpdateParent,
+			permission.Context(permTypes.CtxTeam, p),
+		)
+		if !allowed {
+			return permission.ErrUnauthorized
+		}
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     teamTarget(name),
+		Kind:       permission.PermTeamUpdateParent,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, name)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = servicemanager.Team.SetParent(ctx, name, parent, shareQuota)
+	if err != nil {
+		if _, ok := err.(*quota.QuotaExceededError); ok {
+			return &errors.HTTP{Code: http.StatusForbidden, Message: fmt.Sprintf("Quota exceeded, the apps and jobs of the team don't fit the quota of the new parent: %v", err)}
+		}
+		return handleAuthError(err)
+	}
+	return nil
 }
 
 // title: remove team
@@ -490,11 +590,16 @@ func teamList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	}
 	var result []map[string]interface{}
 	for name, permissions := range permsMap {
-		result = append(result, map[string]interface{}{
+		teamData := map[string]interface{}{
 			"name":        name,
 			"tags":        teamsMap[name].Tags,
 			"permissions": permissions,
-		})
+		}
+		if parent := teamsMap[name].Parent; parent != "" {
+			teamData["parent"] = parent
+			teamData["shareParentQuota"] = teamsMap[name].ShareParentQuota
+		}
+		result = append(result, teamData)
 	}
 	w.Header().Set("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(result)
@@ -610,10 +715,13 @@ func removeUser(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	}
 
 	if userScheme, ok := app.AuthScheme.(auth.UserScheme); ok {
-		return userScheme.Remove(ctx, u)
+		err = userScheme.Remove(ctx, u)
+		if err != nil {
+			return err
+		}
 	}
-
-	return nil
+	_, err = auth.RevokeSessions(ctx, u.Email)
+	return err
 }
 
 // title: get auth scheme
@@ -750,7 +858,9 @@ type rolePermissionData struct {
 	Name         string
 	ContextType  string
 	ContextValue string
-	Group        string `json:",omitempty"`
+	Group        string     `json:",omitempty"`
+	ExpiresAt    *time.Time `json:",omitempty"`
+	Reason       string     `json:",omitempty"`
 }
 
 type apiUser struct {
@@ -805,6 +915,9 @@ func createAPIUser(ctx context.Context, perms []permTypes.Permission, user *auth
 }
 
 func expandRoleData(ctx context.Context, perms []permTypes.Permission, userRole authTypes.RoleInstance, user *apiUser, roleMap map[string]*permission.Role, includeAll bool, group string) (bool, error) {
+	if userRole.Expired(time.Now()) {
+		return false, nil
+	}
 	role := roleMap[userRole.Name]
 	if role == nil {
 		r, err := permission.FindRole(ctx, userRole.Name)
@@ -835,12 +948,17 @@ func expandRoleData(ctx context.Context, perms []permTypes.Permission, userRole
 	if !allPermsMatch {
 		return true, nil
 	}
-	user.Roles = append(user.Roles, rolePermissionData{
+	roleData := rolePermissionData{
 		Name:         userRole.Name,
 		ContextType:  string(role.ContextType),
 		ContextValue: userRole.ContextValue,
 		Group:        group,
-	})
+		Reason:       userRole.Reason,
+	}
+	if !userRole.ExpiresAt.IsZero() {
+		roleData.ExpiresAt = &userRole.ExpiresAt
+	}
+	user.Roles = append(user.Roles, roleData)
 	user.Permissions = append(user.Permissions, rolePerms...)
 	return role.ContextType == permTypes.CtxGlobal, nil
 }
@@ -1697,6 +1697,195 @@ func (s *AuthSuite) TestUpdateTeamErrorInRollback(c *check.C) {
 	c.Assert(buf.String(), check.Matches, "(?s).*error rolling back team name change in.*TestUpdateTeamErrorInRollback.*from \"team1\" to \"team9000\".*")
 }
 
+func (s *AuthSuite) TestUpdateTeamKeepsParent(c *check.C) {
+	s.mockTeamService.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return &authTypes.Team{Name: name, Parent: "corrino", ShareParentQuota: true}, nil
+	}
+	var parentCalls [][]interface{}
+	s.mockTeamService.OnSetParent = func(name, parent string, shareQuota bool) error {
+		parentCalls = append(parentCalls, []interface{}{name, parent, shareQuota})
+		return nil
+	}
+	oldTeamRenameFns := teamRenameFns
+	defer func() { teamRenameFns = oldTeamRenameFns }()
+	teamRenameFns = nil
+	body := strings.NewReader("newname=team9000")
+	request, err := http.NewRequest(http.MethodPut, "/teams/team1", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(parentCalls, check.DeepEquals, [][]interface{}{{"team9000", "corrino", true}})
+}
+
+func (s *AuthSuite) TestCreateTeamWithParent(c *check.C) {
+	s.mockTeamService.OnCreate = func(name string, _ []string, _ *authTypes.User) error {
+		c.Assert(name, check.Equals, "atreides")
+		return nil
+	}
+	var parentCalls [][]interface{}
+	s.mockTeamService.OnSetParent = func(name, parent string, shareQuota bool) error {
+		parentCalls = append(parentCalls, []interface{}{name, parent, shareQuota})
+		return nil
+	}
+	b := strings.NewReader("name=atreides&parent=corrino&shareParentQuota=true")
+	request, err := http.NewRequest(http.MethodPost, "/teams", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated, check.Commentf("body: %v", recorder.Body.String()))
+	c.Assert(parentCalls, check.DeepEquals, [][]interface{}{{"atreides", "corrino", true}})
+}
+
+func (s *AuthSuite) TestCreateTeamWithParentNotFound(c *check.C) {
+	var removed []string
+	s.mockTeamService.OnSetParent = func(_, _ string, _ bool) error {
+		return authTypes.ErrParentTeamNotFound
+	}
+	s.mockTeamService.OnRemove = func(name string) error {
+		removed = append(removed, name)
+		return nil
+	}
+	b := strings.NewReader("name=atreides&parent=corrino")
+	request, err := http.NewRequest(http.MethodPost, "/teams", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, authTypes.ErrParentTeamNotFound.Error()+"\n")
+	c.Assert(removed, check.DeepEquals, []string{"atreides"})
+}
+
+func (s *AuthSuite) TestCreateTeamWithParentUnauthorized(c *check.C) {
+	s.mockTeamService.OnCreate = func(_ string, _ []string, _ *authTypes.User) error {
+		c.Fail()
+		return nil
+	}
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "leto", permTypes.Permission{
+		Scheme:  permission.PermTeamCreate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	b := strings.NewReader("name=atreides&parent=corrino")
+	request, err := http.NewRequest(http.MethodPost, "/teams", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *AuthSuite) TestSetTeamParent(c *check.C) {
+	s.mockTeamService.OnFindByName = func(name string) (*authTypes.Team, error) {
+		c.Assert(name, check.Equals, "atreides")
+		return &authTypes.Team{Name: name}, nil
+	}
+	var parentCalls [][]interface{}
+	s.mockTeamService.OnSetParent = func(name, parent string, shareQuota bool) error {
+		parentCalls = append(parentCalls, []interface{}{name, parent, shareQuota})
+		return nil
+	}
+	b := strings.NewReader("parent=corrino&shareQuota=true")
+	request, err := http.NewRequest(http.MethodPut, "/1.30/teams/atreides/parent", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %v", recorder.Body.String()))
+	c.Assert(parentCalls, check.DeepEquals, [][]interface{}{{"atreides", "corrino", true}})
+	c.Assert(eventtest.EventDesc{
+		Target: teamTarget("atreides"),
+		Owner:  s.token.GetUserName(),
+		Kind:   "team.update.parent",
+		StartCustomData: []map[string]interface{}{
+			{"name": "parent", "value": "corrino"},
+			{"name": "shareQuota", "value": "true"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *AuthSuite) TestSetTeamParentCycle(c *check.C) {
+	s.mockTeamService.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return &authTypes.Team{Name: name}, nil
+	}
+	s.mockTeamService.OnSetParent = func(_, _ string, _ bool) error {
+		return authTypes.ErrTeamParentCycle
+	}
+	b := strings.NewReader("parent=fremen")
+	request, err := http.NewRequest(http.MethodPut, "/1.30/teams/atreides/parent", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, authTypes.ErrTeamParentCycle.Error()+"\n")
+}
+
+func (s *AuthSuite) TestSetTeamParentQuotaExceeded(c *check.C) {
+	s.mockTeamService.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return &authTypes.Team{Name: name}, nil
+	}
+	s.mockTeamService.OnSetParent = func(_, _ string, _ bool) error {
+		return &quota.QuotaExceededError{Requested: 2, Available: 1}
+	}
+	b := strings.NewReader("parent=corrino&shareQuota=true")
+	request, err := http.NewRequest(http.MethodPut, "/1.30/teams/atreides/parent", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Body.String(), check.Matches, "Quota exceeded.*\n")
+}
+
+func (s *AuthSuite) TestSetTeamParentNotFound(c *check.C) {
+	s.mockTeamService.OnFindByName = func(_ string) (*authTypes.Team, error) {
+		return nil, authTypes.ErrTeamNotFound
+	}
+	b := strings.NewReader("parent=corrino")
+	request, err := http.NewRequest(http.MethodPut, "/1.30/teams/atreides/parent", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *AuthSuite) TestSetTeamParentRequiresPermissionInCurrentParent(c *check.C) {
+	s.mockTeamService.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return &authTypes.Team{Name: name, Parent: "corrino"}, nil
+	}
+	s.mockTeamService.OnSetParent = func(_, _ string, _ bool) error {
+		c.Fail()
+		return nil
+	}
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "leto", permTypes.Permission{
+		Scheme:  permission.PermTeamUpdateParent,
+		Context: permission.Context(permTypes.CtxTeam, "atreides"),
+	}, permTypes.Permission{
+		Scheme:  permission.PermTeamUpdateParent,
+		Context: permission.Context(permTypes.CtxTeam, "fremen"),
+	})
+	b := strings.NewReader("parent=fremen")
+	request, err := http.NewRequest(http.MethodPut, "/1.30/teams/atreides/parent", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
 func (s *AuthSuite) TestTeamUsersList(c *check.C) {
 	ctx := context.TODO()
 	teamName := "team-test"
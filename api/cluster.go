@@ -15,6 +15,7 @@ import (
 	tsuruErrors "github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
 	tsuruIo "github.com/tsuru/tsuru/io"
+	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/provision/cluster"
@@ -226,7 +227,7 @@ func clusterInfo(w http.ResponseWriter, r *http.Request, t auth.Token) (err erro
 		return permission.ErrUnauthorized
 	}
 	name := r.URL.Query().Get(":name")
-	cluster, err := servicemanager.Cluster.FindByName(ctx, name)
+	provCluster, err := servicemanager.Cluster.FindByName(ctx, name)
 	if err != nil {
 		if err == provTypes.ErrClusterNotFound {
 			return &tsuruErrors.HTTP{
@@ -236,8 +237,171 @@ func clusterInfo(w http.ResponseWriter, r *http.Request, t auth.Token) (err erro
 		}
 		return err
 	}
+	expiry, err := cluster.CredentialsExpiry(provCluster)
+	if err != nil {
+		log.Errorf("unable to get credentials expiry of cluster %q: %v", provCluster.Name, err)
+	}
+	provCluster.CredentialsExpiry = expiry
 	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(cluster)
+	return json.NewEncoder(w).Encode(provCluster)
+}
+
+// title: provisioner cluster nodes
+// path: /provisioner/clusters/{name}/nodes
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: Ok
+//	204: No Content
+//	400: Listing nodes not supported by cluster provisioner
+//	401: Unauthorized
+//	404: Cluster not found
+func clusterNodes(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	allowed := permission.Check(ctx, t, permission.PermClusterReadNodes)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	name := r.URL.Query().Get(":name")
+	provCluster, err := servicemanager.Cluster.FindByName(ctx, name)
+	if err != nil {
+		if err == provTypes.ErrClusterNotFound {
+			return &tsuruErrors.HTTP{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+		}
+		return err
+	}
+	nodes, err := cluster.Nodes(ctx, provCluster)
+	if err == cluster.ErrNodesNotSupported {
+		return &tsuruErrors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return writeNodeList(w, r, t, nodes)
+}
+
+// title: rotate provisioner cluster credentials
+// path: /provisioner/clusters/{name}/credentials/rotate
+// method: POST
+// consume: application/json
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Cluster not found
+func rotateClusterCredentials(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	allowed := permission.Check(ctx, t, permission.PermClusterUpdateCredentials)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	var credentials provTypes.ClusterCredentials
+	err = ParseJSON(r, &credentials)
+	if err != nil {
+		return err
+	}
+	clusterName := r.URL.Query().Get(":name")
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeCluster, Value: clusterName},
+		Kind:       permission.PermClusterUpdateCredentials,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermClusterReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = servicemanager.Cluster.RotateCredentials(ctx, clusterName, credentials)
+	if err == provTypes.ErrClusterNotFound {
+		return &tsuruErrors.HTTP{
+			Code:    http.StatusNotFound,
+			Message: err.Error(),
+		}
+	}
+	return err
+}
+
+type evacuateClusterOptions struct {
+	Pool        string `json:"pool"`
+	Destination string `json:"destination"`
+}
+
+// title: evacuate provisioner cluster
+// path: /provisioner/clusters/{name}/evacuate
+// method: POST
+// consume: application/json
+// produce: application/x-json-stream
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Cluster or pool not found
+func evacuateCluster(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	allowed := permission.Check(ctx, t, permission.PermClusterUpdateEvacuate)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	var opts evacuateClusterOptions
+	err = ParseJSON(r, &opts)
+	if err != nil {
+		return err
+	}
+	clusterName := r.URL.Query().Get(":name")
+	evt, err := event.New(ctx, &event.Opts{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeCluster, Value: clusterName},
+		ExtraTargets: []eventTypes.ExtraTarget{
+			{Target: eventTypes.Target{Type: eventTypes.TargetTypeCluster, Value: opts.Destination}},
+			{Target: eventTypes.Target{Type: eventTypes.TargetTypePool, Value: opts.Pool}},
+		},
+		Kind:       permission.PermClusterUpdateEvacuate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermClusterReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	streamResponse := strings.HasPrefix(r.Header.Get("Accept"), "application/x-json-stream")
+	if streamResponse {
+		w.Header().Set("Content-Type", "application/x-json-stream")
+		keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+		defer keepAliveWriter.Stop()
+		writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+		evt.SetLogWriter(writer)
+	}
+	err = cluster.Evacuate(ctx, cluster.EvacuateOptions{
+		Source:      clusterName,
+		Destination: opts.Destination,
+		Pool:        opts.Pool,
+		Owner:       t,
+		Writer:      evt,
+	})
+	switch errors.Cause(err) {
+	case provTypes.ErrClusterNotFound, provTypes.ErrPoolNotFound:
+		return &tsuruErrors.HTTP{
+			Code:    http.StatusNotFound,
+			Message: err.Error(),
+		}
+	case cluster.ErrEvacuationNotSupported:
+		return &tsuruErrors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
+	return err
 }
 
 // title: delete provisioner cluster
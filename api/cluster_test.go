@@ -9,8 +9,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event/eventtest"
 	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision/cluster"
+	eventTypes "github.com/tsuru/tsuru/types/event"
 	permTypes "github.com/tsuru/tsuru/types/permission"
 	"github.com/tsuru/tsuru/types/provision"
 	check "gopkg.in/check.v1"
@@ -297,3 +303,246 @@ func (s *S) TestListProvisioners(c *check.C) {
 		{Name: "fake"},
 	})
 }
+
+func (s *S) TestClusterInfoCredentialsExpiry(c *check.C) {
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": expiresAt.Unix()}).SignedString([]byte("secret"))
+	c.Assert(err, check.IsNil)
+	s.mockService.Cluster.OnFindByName = func(name string) (*provision.Cluster, error) {
+		return &provision.Cluster{
+			Name:        "c1",
+			Addresses:   []string{"addr1"},
+			Provisioner: "fake",
+			Default:     true,
+			CustomData:  map[string]string{"token": token},
+		}, nil
+	}
+	request, err := http.NewRequest(http.MethodGet, "/1.8/provisioner/clusters/c1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	var retCluster provision.Cluster
+	err = json.Unmarshal(recorder.Body.Bytes(), &retCluster)
+	c.Assert(err, check.IsNil)
+	c.Assert(retCluster.CredentialsExpiry, check.HasLen, 1)
+	c.Assert(retCluster.CredentialsExpiry[0].Credential, check.Equals, "token")
+	c.Assert(retCluster.CredentialsExpiry[0].ExpiresAt.Equal(expiresAt), check.Equals, true)
+}
+
+func (s *S) TestClusterInfoInvalidCredentials(c *check.C) {
+	s.mockService.Cluster.OnFindByName = func(name string) (*provision.Cluster, error) {
+		return &provision.Cluster{
+			Name:        "c1",
+			Addresses:   []string{"addr1"},
+			Provisioner: "fake",
+			ClientCert:  []byte("not a certificate"),
+		}, nil
+	}
+	request, err := http.NewRequest(http.MethodGet, "/1.8/provisioner/clusters/c1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	var retCluster provision.Cluster
+	err = json.Unmarshal(recorder.Body.Bytes(), &retCluster)
+	c.Assert(err, check.IsNil)
+	c.Assert(retCluster.Name, check.Equals, "c1")
+	c.Assert(retCluster.CredentialsExpiry, check.HasLen, 0)
+}
+
+func (s *S) TestRotateClusterCredentials(c *check.C) {
+	var called bool
+	s.mockService.Cluster.OnRotateCredentials = func(name string, credentials provision.ClusterCredentials) error {
+		called = true
+		c.Assert(name, check.Equals, "c1")
+		c.Assert(credentials, check.DeepEquals, provision.ClusterCredentials{
+			ClientCert: []byte("cert"),
+			ClientKey:  []byte("key"),
+		})
+		return nil
+	}
+	body := bytes.NewBufferString(`{"clientcert": "Y2VydA==", "clientkey": "a2V5"}`)
+	request, err := http.NewRequest(http.MethodPost, "/1.30/provisioner/clusters/c1/credentials/rotate", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(called, check.Equals, true)
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeCluster, Value: "c1"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "cluster.update.credentials",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestRotateClusterCredentialsNotFound(c *check.C) {
+	s.mockService.Cluster.OnRotateCredentials = func(name string, credentials provision.ClusterCredentials) error {
+		return provision.ErrClusterNotFound
+	}
+	body := bytes.NewBufferString(`{"token": "new-token"}`)
+	request, err := http.NewRequest(http.MethodPost, "/1.30/provisioner/clusters/c1/credentials/rotate", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound, check.Commentf("body: %q", recorder.Body.String()))
+}
+
+func (s *S) TestRotateClusterCredentialsValidationError(c *check.C) {
+	s.mockService.Cluster.OnRotateCredentials = func(name string, credentials provision.ClusterCredentials) error {
+		return &tsuruErrors.ValidationError{Message: "unable to connect to cluster with new credentials: timeout"}
+	}
+	body := bytes.NewBufferString(`{"token": "new-token"}`)
+	request, err := http.NewRequest(http.MethodPost, "/1.30/provisioner/clusters/c1/credentials/rotate", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(recorder.Body.String(), check.Equals, "unable to connect to cluster with new credentials: timeout\n")
+}
+
+func (s *S) TestRotateClusterCredentialsUnauthorized(c *check.C) {
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermClusterRead,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	body := bytes.NewBufferString(`{"token": "new-token"}`)
+	request, err := http.NewRequest(http.MethodPost, "/1.30/provisioner/clusters/c1/credentials/rotate", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestEvacuateClusterNotFound(c *check.C) {
+	s.mockService.Cluster.OnFindByName = func(name string) (*provision.Cluster, error) {
+		return nil, provision.ErrClusterNotFound
+	}
+	body := bytes.NewBufferString(`{"pool": "pool1", "destination": "c2"}`)
+	request, err := http.NewRequest(http.MethodPost, "/1.30/provisioner/clusters/c1/evacuate", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeCluster, Value: "c1"},
+		ExtraTargets: []eventTypes.ExtraTarget{
+			{Target: eventTypes.Target{Type: eventTypes.TargetTypeCluster, Value: "c2"}},
+			{Target: eventTypes.Target{Type: eventTypes.TargetTypePool, Value: "pool1"}},
+		},
+		Owner:        s.token.GetUserName(),
+		Kind:         "cluster.update.evacuate",
+		ErrorMatches: "cluster not found",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestEvacuateClusterNotSupported(c *check.C) {
+	s.mockService.Cluster.OnFindByName = func(name string) (*provision.Cluster, error) {
+		return &provision.Cluster{Name: name, Provisioner: "fake"}, nil
+	}
+	s.mockService.Cluster.OnFindByPool = func(prov, pool string) (*provision.Cluster, error) {
+		return &provision.Cluster{Name: "c1", Provisioner: "fake"}, nil
+	}
+	s.mockService.Pool.OnFindByName = func(name string) (*provision.Pool, error) {
+		return &provision.Pool{Name: name}, nil
+	}
+	body := bytes.NewBufferString(`{"pool": "pool1", "destination": "c2"}`)
+	request, err := http.NewRequest(http.MethodPost, "/1.30/provisioner/clusters/c1/evacuate", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(recorder.Body.String(), check.Equals, cluster.ErrEvacuationNotSupported.Error()+"\n")
+}
+
+func (s *S) TestEvacuateClusterUnauthorized(c *check.C) {
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermClusterUpdate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	body := bytes.NewBufferString(`{"pool": "pool1", "destination": "c2"}`)
+	request, err := http.NewRequest(http.MethodPost, "/1.30/provisioner/clusters/c1/evacuate", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestClusterNodes(c *check.C) {
+	expected := []provision.Node{
+		{Name: "n1", Cluster: "c1", Pool: "pool1", Ready: true},
+		{Name: "n2", Cluster: "c1", Pool: "pool2", Unschedulable: true},
+	}
+	defer registerNodesProvisioner(expected)()
+	s.mockService.Cluster.OnFindByName = func(name string) (*provision.Cluster, error) {
+		return &provision.Cluster{Name: name, Provisioner: "nodesProv"}, nil
+	}
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermClusterReadNodes,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	request, err := http.NewRequest(http.MethodGet, "/1.30/provisioner/clusters/c1/nodes", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	var nodes provision.NodeList
+	err = json.NewDecoder(recorder.Body).Decode(&nodes)
+	c.Assert(err, check.IsNil)
+	c.Assert(nodes, check.DeepEquals, provision.NodeList{Nodes: expected})
+}
+
+func (s *S) TestClusterNodesNotFound(c *check.C) {
+	s.mockService.Cluster.OnFindByName = func(name string) (*provision.Cluster, error) {
+		return nil, provision.ErrClusterNotFound
+	}
+	request, err := http.NewRequest(http.MethodGet, "/1.30/provisioner/clusters/c1/nodes", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestClusterNodesNotSupported(c *check.C) {
+	s.mockService.Cluster.OnFindByName = func(name string) (*provision.Cluster, error) {
+		return &provision.Cluster{Name: name, Provisioner: "fake"}, nil
+	}
+	request, err := http.NewRequest(http.MethodGet, "/1.30/provisioner/clusters/c1/nodes", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, cluster.ErrNodesNotSupported.Error()+"\n")
+}
+
+func (s *S) TestClusterNodesUnauthorized(c *check.C) {
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermClusterRead,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	request, err := http.NewRequest(http.MethodGet, "/1.30/provisioner/clusters/c1/nodes", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+type groupRole struct {
+	Name    string `json:"name"`
+	Context string `json:"context"`
+}
+
+type groupRolesArgs struct {
+	Roles []groupRole `json:"roles"`
+}
+
+// title: group list
+// path: /groups
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: List groups
+//	204: No content
+//	401: Unauthorized
+func listGroups(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	if !permission.Check(ctx, t, permission.PermGroupRead) {
+		return permission.ErrUnauthorized
+	}
+	groups, err := servicemanager.AuthGroup.List(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(groups)
+}
+
+// title: set group roles
+// path: /groups/{name}/roles
+// method: PUT
+// consume: application/json
+// responses:
+//
+//	200: Roles updated
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Role not found
+func setGroupRoles(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	if !permission.Check(ctx, t, permission.PermGroupUpdate) {
+		return permission.ErrUnauthorized
+	}
+	groupName := r.URL.Query().Get(":name")
+	var args groupRolesArgs
+	err = ParseInput(r, &args)
+	if err != nil {
+		return err
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeGroup, Value: groupName},
+		Kind:       permission.PermGroupUpdate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: args,
+		Allowed:    event.Allowed(permission.PermGroupReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	groups, err := servicemanager.AuthGroup.List(ctx, []string{groupName})
+	if err != nil {
+		return err
+	}
+	current := map[authTypes.RoleInstance]struct{}{}
+	for _, group := range groups {
+		for _, roleInstance := range group.Roles {
+			current[authTypes.RoleInstance{Name: roleInstance.Name, ContextValue: roleInstance.ContextValue}] = struct{}{}
+		}
+	}
+	roles := make([]authTypes.RoleInstance, len(args.Roles))
+	for i, groupRole := range args.Roles {
+		roles[i] = authTypes.RoleInstance{Name: groupRole.Name, ContextValue: groupRole.Context}
+		if _, ok := current[roles[i]]; ok {
+			delete(current, roles[i])
+			continue
+		}
+		role, err := getRoleReturnNotFound(ctx, groupRole.Name)
+		if err != nil {
+			return err
+		}
+		if err = validateContextValue(ctx, role, groupRole.Context); err != nil {
+			return err
+		}
+		err = canUseRole(ctx, t, role, groupRole.Context)
+		if err != nil {
+			return err
+		}
+	}
+	// Roles left in current are the ones being removed from the group.
+	var removedRoles []authTypes.RoleInstance
+	for _, group := range groups {
+		for _, roleInstance := range group.Roles {
+			key := authTypes.RoleInstance{Name: roleInstance.Name, ContextValue: roleInstance.ContextValue}
+			if _, ok := current[key]; ok {
+				removedRoles = append(removedRoles, key)
+				delete(current, key)
+			}
+		}
+	}
+	if len(removedRoles) > 0 && !permission.Check(ctx, t, permission.PermRoleUpdateDissociate) {
+		return permission.ErrUnauthorized
+	}
+	for _, removed := range removedRoles {
+		role, err := permission.FindRole(ctx, removed.Name)
+		if err == permTypes.ErrRoleNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		err = canUseRole(ctx, t, role, removed.ContextValue)
+		if err != nil {
+			return err
+		}
+	}
+	return servicemanager.AuthGroup.SetRoles(ctx, groupName, roles)
+}
+
+// title: delete group
+// path: /groups/{name}
+// method: DELETE
+// responses:
+//
+//	200: Group deleted
+//	401: Unauthorized
+//	404: Group not found
+func deleteGroup(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	if !permission.Check(ctx, t, permission.PermGroupDelete) {
+		return permission.ErrUnauthorized
+	}
+	groupName := r.URL.Query().Get(":name")
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeGroup, Value: groupName},
+		Kind:       permission.PermGroupDelete,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermGroupReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = servicemanager.AuthGroup.Delete(ctx, groupName)
+	if err == authTypes.ErrGroupNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestListGroups(c *check.C) {
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermGroupRead,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.30/groups", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+	_, err = permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	err = servicemanager.AuthGroup.AddRole(context.TODO(), "sre", "pool-admin", "prod")
+	c.Assert(err, check.IsNil)
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var groups []authTypes.Group
+	err = json.NewDecoder(recorder.Body).Decode(&groups)
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.DeepEquals, []authTypes.Group{
+		{Name: "sre", Roles: []authTypes.RoleInstance{{Name: "pool-admin", ContextValue: "prod"}}},
+	})
+}
+
+func (s *S) TestListGroupsUnauthorized(c *check.C) {
+	token := userWithPermission(c)
+	req, err := http.NewRequest(http.MethodGet, "/1.30/groups", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestSetGroupRoles(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	_, err = permission.NewRole(context.TODO(), "team-member", "team", "")
+	c.Assert(err, check.IsNil)
+	err = servicemanager.AuthGroup.AddRole(context.TODO(), "sre", "team-member", s.team.Name)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader(`{"roles": [{"name": "pool-admin", "context": "test1"}, {"name": "pool-admin", "context": "test1"}]}`)
+	req, err := http.NewRequest(http.MethodPut, "/1.30/groups/sre/roles", body)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	groups, err := servicemanager.AuthGroup.List(context.TODO(), []string{"sre"})
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.DeepEquals, []authTypes.Group{
+		{Name: "sre", Roles: []authTypes.RoleInstance{{Name: "pool-admin", ContextValue: "test1"}}},
+	})
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeGroup, Value: "sre"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "group.update",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestSetGroupRolesRoleNotFound(c *check.C) {
+	body := strings.NewReader(`{"roles": [{"name": "pool-admin", "context": "test1"}]}`)
+	req, err := http.NewRequest(http.MethodPut, "/1.30/groups/sre/roles", body)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestSetGroupRolesCannotUseRole(c *check.C) {
+	role, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(context.TODO(), "pool.update")
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermGroupUpdate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	body := strings.NewReader(`{"roles": [{"name": "pool-admin", "context": "test1"}]}`)
+	req, err := http.NewRequest(http.MethodPut, "/1.30/groups/sre/roles", body)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	groups, err := servicemanager.AuthGroup.List(context.TODO(), []string{"sre"})
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.HasLen, 0)
+}
+
+func (s *S) TestSetGroupRolesRemoveWithoutDissociatePermission(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "team-member", "team", "")
+	c.Assert(err, check.IsNil)
+	err = servicemanager.AuthGroup.AddRole(context.TODO(), "sre", "team-member", s.team.Name)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermGroupUpdate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	body := strings.NewReader(`{"roles": []}`)
+	req, err := http.NewRequest(http.MethodPut, "/1.30/groups/sre/roles", body)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	groups, err := servicemanager.AuthGroup.List(context.TODO(), []string{"sre"})
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.DeepEquals, []authTypes.Group{
+		{Name: "sre", Roles: []authTypes.RoleInstance{{Name: "team-member", ContextValue: s.team.Name}}},
+	})
+}
+
+func (s *S) TestSetGroupRolesRemoveCannotUseRole(c *check.C) {
+	role, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(context.TODO(), "pool.update")
+	c.Assert(err, check.IsNil)
+	err = servicemanager.AuthGroup.AddRole(context.TODO(), "sre", "pool-admin", "test1")
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermGroupUpdate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	}, permTypes.Permission{
+		Scheme:  permission.PermRoleUpdateDissociate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	body := strings.NewReader(`{"roles": []}`)
+	req, err := http.NewRequest(http.MethodPut, "/1.30/groups/sre/roles", body)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Body.String(), check.Equals, "User not authorized to use permission pool.update(pool test1)\n")
+	groups, err := servicemanager.AuthGroup.List(context.TODO(), []string{"sre"})
+	c.Assert(err, check.IsNil)
+	c.Assert(groups[0].Roles, check.HasLen, 1)
+}
+
+func (s *S) TestDeleteGroup(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	err = servicemanager.AuthGroup.AddRole(context.TODO(), "sre", "pool-admin", "prod")
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest(http.MethodDelete, "/1.30/groups/sre", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	groups, err := servicemanager.AuthGroup.List(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.HasLen, 0)
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeGroup, Value: "sre"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "group.delete",
+	}, eventtest.HasEvent)
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
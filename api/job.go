@@ -610,6 +610,7 @@ func deleteJob(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 //	400: Invalid data
 //	401: Unauthorized
 //	404: Job not found
+//	409: Service instance is still being provisioned
 func bindJobServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	ctx := r.Context()
 	instanceName := r.URL.Query().Get(":instance")
@@ -620,8 +621,12 @@ func bindJobServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token
 	if err != nil {
 		return err
 	}
+	j, err := getJob(ctx, jobName)
+	if err != nil {
+		return err
+	}
 	allowed := permission.Check(ctx, t, permission.PermServiceInstanceUpdateBind,
-		append(permission.Contexts(permTypes.CtxTeam, instance.Teams),
+		append(permission.Contexts(permTypes.CtxTeam, instance.BindTeamsForJob(j.Name, j.TeamOwner)),
 			permission.Context(permTypes.CtxTeam, instance.TeamOwner),
 			permission.Context(permTypes.CtxServiceInstance, instance.Name),
 		)...,
@@ -630,16 +635,15 @@ func bindJobServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token
 		return permission.ErrUnauthorized
 	}
 
-	j, err := getJob(ctx, jobName)
-	if err != nil {
-		return err
-	}
 	canUpdate := permission.Check(ctx, t, permission.PermJobUpdate,
 		contextsForJob(j)...,
 	)
 	if !canUpdate {
 		return permission.ErrUnauthorized
 	}
+	if err = instance.CheckJobBindAccess(j.Name, j.TeamOwner); err != nil {
+		return &errors.HTTP{Code: http.StatusForbidden, Message: err.Error()}
+	}
 
 	err = pool.ValidatePoolService(ctx, j.Pool, []string{serviceName})
 	if err != nil {
@@ -666,6 +670,9 @@ func bindJobServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token
 
 	err = instance.BindJob(ctx, j, evt, evt, requestIDHeader(r))
 	if err != nil {
+		if httpErr := provisioningError(err); httpErr != nil {
+			return httpErr
+		}
 		status, errStatus := instance.Status(ctx, requestIDHeader(r))
 		if errStatus != nil {
 			return fmt.Errorf("%v (failed to retrieve instance status: %v)", err, errStatus)
@@ -702,7 +709,7 @@ func unbindJobServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Tok
 		return err
 	}
 	allowed := permission.Check(ctx, t, permission.PermServiceInstanceUpdateUnbind,
-		append(permission.Contexts(permTypes.CtxTeam, instance.Teams),
+		append(permission.Contexts(permTypes.CtxTeam, instance.BindTeamsForJob(j.Name, j.TeamOwner)),
 			permission.Context(permTypes.CtxTeam, instance.TeamOwner),
 			permission.Context(permTypes.CtxServiceInstance, instance.Name),
 		)...,
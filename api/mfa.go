@@ -0,0 +1,211 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// title: enroll multi-factor authentication
+// path: /users/mfa
+// method: POST
+// produce: application/json
+// responses:
+//
+//	201: Enrollment started
+//	400: Invalid data
+//	401: Unauthorized
+//	409: Already enabled
+func enrollMFA(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	scheme, ok := app.AuthScheme.(auth.MFAScheme)
+	if !ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: nonManagedSchemeMsg}
+	}
+	user, err := auth.ConvertNewUser(t.User(ctx))
+	if err != nil {
+		return err
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(user.Email),
+		Kind:       permission.PermUserUpdateMfa,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, user.Email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	enrollment, err := scheme.EnrollMFA(ctx, user)
+	if err != nil {
+		return handleAuthError(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(enrollment)
+}
+
+// title: confirm multi-factor authentication
+// path: /users/mfa/confirm
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//
+//	200: Multi-factor authentication enabled
+//	400: Invalid data
+//	401: Unauthorized
+//	409: Already enabled
+func confirmMFA(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	scheme, ok := app.AuthScheme.(auth.MFAScheme)
+	if !ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: nonManagedSchemeMsg}
+	}
+	user, err := auth.ConvertNewUser(t.User(ctx))
+	if err != nil {
+		return err
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(user.Email),
+		Kind:       permission.PermUserUpdateMfa,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, user.Email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	codes, err := scheme.ConfirmMFA(ctx, user, InputValue(r, "otp"))
+	if err != nil {
+		return handleAuthError(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string][]string{"recovery_codes": codes})
+}
+
+// title: disable multi-factor authentication
+// path: /users/mfa
+// method: DELETE
+// consume: application/x-www-form-urlencoded
+// responses:
+//
+//	200: Multi-factor authentication disabled
+//	400: Invalid data
+//	401: Unauthorized
+//	403: Forbidden
+//	404: User not found
+func disableMFA(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	scheme, ok := app.AuthScheme.(auth.MFAScheme)
+	if !ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: nonManagedSchemeMsg}
+	}
+	email := r.URL.Query().Get("user")
+	self := email == "" || email == t.GetUserName()
+	if self {
+		email = t.GetUserName()
+	} else if !permission.Check(ctx, t, permission.PermUserUpdateMfa, permission.Context(permTypes.CtxUser, email)) {
+		return permission.ErrUnauthorized
+	}
+	user, err := auth.GetUserByEmail(ctx, email)
+	if err != nil {
+		return handleAuthError(err)
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(email),
+		Kind:       permission.PermUserUpdateMfa,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	if self {
+		var otp string
+		otp, err = bodyFormValue(r, "otp")
+		if err != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+		err = scheme.VerifyMFA(ctx, user, otp)
+		if err != nil {
+			return handleAuthError(err)
+		}
+	}
+	return scheme.DisableMFA(ctx, user)
+}
+
+// title: require multi-factor authentication for role
+// path: /roles/{name}/mfa
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Role not found
+func setRoleRequireMFA(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	if !permission.Check(ctx, t, permission.PermRoleUpdateMfa) {
+		return permission.ErrUnauthorized
+	}
+	required, err := strconv.ParseBool(InputValue(r, "required"))
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "required must be true or false"}
+	}
+	roleName := r.URL.Query().Get(":name")
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: roleName},
+		Kind:       permission.PermRoleUpdateMfa,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	role, err := getRoleReturnNotFound(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	return role.SetRequireMFA(ctx, required)
+}
+
+// bodyFormValue reads a form encoded field from the request body. Unlike
+// InputValue it ignores the URL query, keeping secrets out of access logs,
+// and also works for DELETE requests, whose body net/http does not parse.
+func bodyFormValue(r *http.Request, field string) (string, error) {
+	if r.Body == nil {
+		return "", nil
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, defaultMaxMemory))
+	if err != nil {
+		return "", err
+	}
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return "", err
+	}
+	return values.Get(field), nil
+}
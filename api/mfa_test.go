@@ -0,0 +1,172 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/permission/permissiontest"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestEnrollAndConfirmMFA(c *check.C) {
+	user, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "bowie")
+	request, err := http.NewRequest(http.MethodPost, "/1.30/users/mfa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated, check.Commentf("body: %q", recorder.Body.String()))
+	var enrollment auth.MFAEnrollment
+	err = json.NewDecoder(recorder.Body).Decode(&enrollment)
+	c.Assert(err, check.IsNil)
+	c.Assert(enrollment.Secret, check.Not(check.Equals), "")
+	code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+	c.Assert(err, check.IsNil)
+	request, err = http.NewRequest(http.MethodPost, "/1.30/users/mfa/confirm", strings.NewReader("otp="+code))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	var result map[string][]string
+	err = json.NewDecoder(recorder.Body).Decode(&result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result["recovery_codes"], check.HasLen, 10)
+	dbUser, err := auth.GetUserByEmail(context.TODO(), user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.MFAEnabled(), check.Equals, true)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(user.Email),
+		Owner:  user.Email,
+		Kind:   "user.update.mfa",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestConfirmMFAInvalidCode(c *check.C) {
+	user, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "bowie")
+	_, err := nativeScheme.EnrollMFA(context.TODO(), user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodPost, "/1.30/users/mfa/confirm", strings.NewReader("otp=000000x"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+}
+
+func (s *S) TestDisableMFASelf(c *check.C) {
+	user, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "bowie")
+	enrollment, err := nativeScheme.EnrollMFA(context.TODO(), user)
+	c.Assert(err, check.IsNil)
+	code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+	c.Assert(err, check.IsNil)
+	recoveryCodes, err := nativeScheme.ConfirmMFA(context.TODO(), user, code)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodDelete, "/1.30/users/mfa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	request, err = http.NewRequest(http.MethodDelete, "/1.30/users/mfa?otp="+recoveryCodes[0], nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	request, err = http.NewRequest(http.MethodDelete, "/1.30/users/mfa", strings.NewReader("otp="+recoveryCodes[0]))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	dbUser, err := auth.GetUserByEmail(context.TODO(), user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.MFA, check.IsNil)
+}
+
+func (s *S) TestDisableMFAOtherUser(c *check.C) {
+	user, _ := permissiontest.CustomUserWithPermission(c, nativeScheme, "bowie")
+	_, err := nativeScheme.EnrollMFA(context.TODO(), user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodDelete, "/1.30/users/mfa?user="+user.Email, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	dbUser, err := auth.GetUserByEmail(context.TODO(), user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.MFA, check.IsNil)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(user.Email),
+		Owner:  s.token.GetUserName(),
+		Kind:   "user.update.mfa",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestDisableMFAOtherUserUnauthorized(c *check.C) {
+	token := userWithPermission(c)
+	request, err := http.NewRequest(http.MethodDelete, "/1.30/users/mfa?user="+s.user.Email, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestSetRoleRequireMFA(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodPut, "/1.30/roles/pool-admin/mfa", strings.NewReader("required=true"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	role, err := permission.FindRole(context.TODO(), "pool-admin")
+	c.Assert(err, check.IsNil)
+	c.Assert(role.RequireMFA, check.Equals, true)
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: "pool-admin"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "role.update.mfa",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestSetRoleRequireMFAInvalidValue(c *check.C) {
+	request, err := http.NewRequest(http.MethodPut, "/1.30/roles/pool-admin/mfa", strings.NewReader("required=maybe"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *S) TestSetRoleRequireMFANotFound(c *check.C) {
+	request, err := http.NewRequest(http.MethodPut, "/1.30/roles/unknown/mfa", strings.NewReader("required=true"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
@@ -6,10 +6,10 @@ package api
 
 import (
 	"bytes"
-	stdContext "context"
 	"encoding/json"
 	"fmt"
 	stdIO "io"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -29,9 +29,11 @@ import (
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/auth/peer"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/io"
 	"github.com/tsuru/tsuru/log"
 	tsuruNet "github.com/tsuru/tsuru/net"
+	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
 	"github.com/tsuru/tsuru/set"
 	appTypes "github.com/tsuru/tsuru/types/app"
@@ -68,7 +70,7 @@ var (
 
 func validate(token string, r *http.Request) (auth.Token, error) {
 	var t auth.Token
-	t, err := tokenByAllAuthEngines(r.Context(), token)
+	t, err := tokenByAllAuthEngines(r, token)
 	if err != nil {
 		return nil, err
 	}
@@ -90,9 +92,14 @@ func validate(token string, r *http.Request) (auth.Token, error) {
 	return t, nil
 }
 
-func tokenByAllAuthEngines(ctx stdContext.Context, token string) (auth.Token, error) {
+func tokenByAllAuthEngines(r *http.Request, token string) (auth.Token, error) {
+	ctx := r.Context()
 	t, err := app.AuthScheme.Auth(ctx, token)
 	if err == nil {
+		err = auth.TrackSession(ctx, t, sessionInfo(r))
+		if err != nil {
+			return nil, err
+		}
 		return t, nil
 	}
 
@@ -116,6 +123,17 @@ func tokenByAllAuthEngines(ctx stdContext.Context, token string) (auth.Token, er
 	return nil, err
 }
 
+func sessionInfo(r *http.Request) auth.SessionInfo {
+	clientIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		clientIP = r.RemoteAddr
+	}
+	return auth.SessionInfo{
+		ClientIP:  clientIP,
+		UserAgent: r.UserAgent(),
+	}
+}
+
 func contextClearerMiddleware(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	defer context.Clear(r)
 	next(w, r)
@@ -167,6 +185,33 @@ func setRequestIDHeaderMiddleware(w http.ResponseWriter, r *http.Request, next h
 		requestID = unparsedID.String()
 	}
 	context.SetRequestID(r, requestIDHeader, requestID)
+	*r = *r.WithContext(event.ContextWithRequestID(r.Context(), requestID))
+	next(w, r)
+}
+
+// setRequestInfoMiddleware exposes the request to the authorization policy
+// webhook, it must run after the request ID is set. Url-encoded form bodies
+// are parsed only when the webhook is enabled, multipart bodies, like
+// deploy uploads, are never read.
+func setRequestInfoMiddleware(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	requestIDHeader, _ := config.GetString("request-id-header")
+	info := permission.RequestInfo{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		RemoteAddr: r.RemoteAddr,
+		UserAgent:  r.UserAgent(),
+		RequestID:  context.GetRequestID(r, requestIDHeader),
+	}
+	if query := r.URL.Query(); len(query) > 0 {
+		info.Query = query
+	}
+	if permission.PolicyEnabled() {
+		info.Headers = permission.PolicyRequestHeaders(r.Header)
+		if getContentType(r) == "application/x-www-form-urlencoded" && parseForm(r) == nil && len(r.PostForm) > 0 {
+			info.Form = r.PostForm
+		}
+	}
+	*r = *r.WithContext(permission.ContextWithRequestInfo(r.Context(), info))
 	next(w, r)
 }
 
@@ -176,6 +221,7 @@ func setVersionHeadersMiddleware(w http.ResponseWriter, r *http.Request, next ht
 }
 
 func errorHandlingMiddleware(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	*r = *r.WithContext(permission.ContextWithDeniedChecks(r.Context()))
 	next(w, r)
 	err := context.GetRequestError(r)
 	if err != nil {
@@ -190,6 +236,16 @@ func errorHandlingMiddleware(w http.ResponseWriter, r *http.Request, next http.H
 		if errors.Cause(err) == appTypes.ErrAppNotFound {
 			code = http.StatusNotFound
 		}
+		if code == http.StatusForbidden {
+			denied := permission.LastDeniedCheck(r.Context())
+			if denied != nil && denied.Reason != "" && errors.Cause(err) == permission.ErrUnauthorized {
+				err = &tsuruErrors.HTTP{
+					Code:    code,
+					Message: fmt.Sprintf("%s: %s", permission.ErrUnauthorized.Message, denied.Reason),
+				}
+			}
+			event.AuditDenied(r.Context(), context.GetAuthToken(r), r.RemoteAddr, denied, err.Error())
+		}
 		if verbosity == 0 {
 			err = fmt.Errorf("%s", err)
 		} else {
@@ -21,9 +21,12 @@ import (
 	"github.com/tsuru/tsuru/db/storagev2"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/io"
+	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	authTypes "github.com/tsuru/tsuru/types/auth"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	permTypes "github.com/tsuru/tsuru/types/permission"
 	check "gopkg.in/check.v1"
 )
 
@@ -145,6 +148,48 @@ func (s *S) TestSetRequestIDHeaderMiddlewareNoConfig(c *check.C) {
 	c.Assert(reqID, check.Equals, "")
 }
 
+func (s *S) TestSetRequestInfoMiddleware(c *check.C) {
+	config.Set("request-id-header", "Request-ID")
+	defer config.Unset("request-id-header")
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPost, "/apps/myapp/deploy", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("User-Agent", "tsuru-client/1.0")
+	req.RemoteAddr = "10.0.0.1:41234"
+	context.SetRequestID(req, "Request-ID", "req-1")
+	h, log := doHandler()
+	setRequestInfoMiddleware(rec, req, h)
+	c.Assert(log.called, check.Equals, true)
+	c.Assert(permission.RequestInfoFromContext(req.Context()), check.DeepEquals, &permission.RequestInfo{
+		Method:     http.MethodPost,
+		Path:       "/apps/myapp/deploy",
+		RemoteAddr: "10.0.0.1:41234",
+		UserAgent:  "tsuru-client/1.0",
+		RequestID:  "req-1",
+	})
+}
+
+func (s *S) TestSetRequestInfoMiddlewareWithPolicyWebhook(c *check.C) {
+	config.Set("authorization:webhook:url", "http://localhost:8181")
+	config.Set("authorization:webhook:request-headers", []interface{}{"X-Forwarded-For"})
+	defer config.Unset("authorization")
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPost, "/apps/myapp/env?noRestart=true", strings.NewReader("envs.0.name=FOO&envs.0.value=bar"))
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Forwarded-For", "10.0.0.2")
+	req.Header.Set("X-Other", "value")
+	var name string
+	setRequestInfoMiddleware(rec, req, func(w http.ResponseWriter, r *http.Request) {
+		name = InputValue(r, "envs.0.name")
+	})
+	c.Assert(name, check.Equals, "FOO")
+	info := permission.RequestInfoFromContext(req.Context())
+	c.Assert(info.Query, check.DeepEquals, url.Values{"noRestart": {"true"}})
+	c.Assert(info.Form, check.DeepEquals, url.Values{"envs.0.name": {"FOO"}, "envs.0.value": {"bar"}})
+	c.Assert(info.Headers, check.DeepEquals, http.Header{"X-Forwarded-For": {"10.0.0.2"}})
+}
+
 func (s *S) TestSetVersionHeadersMiddleware(c *check.C) {
 	recorder := httptest.NewRecorder()
 	request, err := http.NewRequest("GET", "/", nil)
@@ -187,6 +232,64 @@ func (s *S) TestErrorHandlingMiddlewareWithHTTPError(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, 403)
 }
 
+type auditRecorder struct {
+	entries []eventTypes.AuditEntry
+}
+
+func (a *auditRecorder) Record(ctx stdContext.Context, entry eventTypes.AuditEntry) {
+	a.entries = append(a.entries, entry)
+}
+
+func (s *S) TestErrorHandlingMiddlewareAuditsDeniedPermission(c *check.C) {
+	audit := &auditRecorder{}
+	servicemanager.Audit = audit
+	defer func() { servicemanager.Audit = nil }()
+	token := userWithPermission(c)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/apps/myapp", nil)
+	c.Assert(err, check.IsNil)
+	request.RemoteAddr = "10.0.0.1:41234"
+	h := func(w http.ResponseWriter, r *http.Request) {
+		context.SetAuthToken(r, token)
+		if !permission.Check(r.Context(), token, permission.PermAppRead, permission.Context(permTypes.CtxApp, "myapp")) {
+			context.AddRequestError(r, permission.ErrUnauthorized)
+		}
+	}
+	errorHandlingMiddleware(recorder, request, h)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(audit.entries, check.HasLen, 1)
+	c.Assert(audit.entries[0].Action, check.Equals, "app.read")
+	c.Assert(audit.entries[0].Actor, check.Equals, eventTypes.AuditActor{Type: "user", Name: token.GetUserName()})
+	c.Assert(audit.entries[0].Target, check.Equals, eventTypes.AuditTarget{Type: "app", Value: "myapp"})
+	c.Assert(audit.entries[0].SourceIP, check.Equals, "10.0.0.1")
+	c.Assert(audit.entries[0].Result, check.Equals, eventTypes.AuditResultDenied)
+}
+
+func (s *S) TestErrorHandlingMiddlewarePolicyDenialReason(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result": {"allowed": false, "reason": "no deploys on fridays"}}`))
+	}))
+	defer srv.Close()
+	config.Set("authorization:webhook:url", srv.URL)
+	config.Set("authorization:webhook:permissions", []interface{}{"app.deploy"})
+	defer config.Unset("authorization")
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermAppDeploy,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("POST", "/apps/myapp/deploy", nil)
+	c.Assert(err, check.IsNil)
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if !permission.Check(r.Context(), token, permission.PermAppDeploy, permission.Context(permTypes.CtxApp, "myapp")) {
+			context.AddRequestError(r, permission.ErrUnauthorized)
+		}
+	}
+	errorHandlingMiddleware(recorder, request, h)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Body.String(), check.Equals, "You don't have permission to do this action: no deploys on fridays\n")
+}
+
 func (s *S) TestErrorHandlingMiddlewareWithValidationError(c *check.C) {
 	recorder := httptest.NewRecorder()
 	request, err := http.NewRequest("GET", "/", nil)
@@ -10,6 +10,9 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
@@ -20,8 +23,10 @@ import (
 	"github.com/tsuru/tsuru/router"
 	"github.com/tsuru/tsuru/service"
 	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
 	permTypes "github.com/tsuru/tsuru/types/permission"
 )
 
@@ -285,6 +290,95 @@ func removePermissions(w http.ResponseWriter, r *http.Request, t auth.Token) (er
 	return role.RemovePermissions(ctx, permName)
 }
 
+// title: add deny permissions
+// path: /roles/{name}/deny-permissions
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Role not found
+//	409: Permission not allowed
+func addDenyPermissions(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	if !permission.Check(ctx, t, permission.PermRoleUpdateDenyAdd) {
+		return permission.ErrUnauthorized
+	}
+	roleName := r.URL.Query().Get(":name")
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: roleName},
+		Kind:       permission.PermRoleUpdateDenyAdd,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	role, err := getRoleReturnNotFound(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	permissions, _ := InputValues(r, "permission")
+	err = role.AddDenyPermissions(ctx, permissions...)
+	if err == permTypes.ErrInvalidPermissionName {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
+	if perr, ok := err.(*permTypes.ErrPermissionNotFound); ok {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: perr.Error(),
+		}
+	}
+	if perr, ok := err.(*permTypes.ErrPermissionNotAllowed); ok {
+		return &errors.HTTP{
+			Code:    http.StatusConflict,
+			Message: perr.Error(),
+		}
+	}
+	return err
+}
+
+// title: remove deny permission
+// path: /roles/{name}/deny-permissions/{permission}
+// method: DELETE
+// responses:
+//
+//	200: Deny permission removed
+//	401: Unauthorized
+//	404: Not found
+func removeDenyPermissions(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	if !permission.Check(ctx, t, permission.PermRoleUpdateDenyRemove) {
+		return permission.ErrUnauthorized
+	}
+	roleName := r.URL.Query().Get(":name")
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: roleName},
+		Kind:       permission.PermRoleUpdateDenyRemove,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	role, err := getRoleReturnNotFound(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	return role.RemoveDenyPermissions(ctx, r.URL.Query().Get(":permission"))
+}
+
 func getRoleReturnNotFound(ctx context.Context, roleName string) (permission.Role, error) {
 	role, err := permission.FindRole(ctx, roleName)
 	if err != nil {
@@ -304,13 +398,14 @@ func canUseRole(ctx context.Context, t auth.Token, role permission.Role, context
 	if err != nil {
 		return err
 	}
-	perms := role.PermissionsFor(contextValue)
-	for _, p := range perms {
-		if !permission.CheckFromPermList(userPerms, p.Scheme, p.Context) {
-			return &errors.HTTP{
-				Code:    http.StatusForbidden,
-				Message: fmt.Sprintf("User not authorized to use permission %s", p.String()),
-			}
+	unallowed, err := auth.UnallowedRolePermission(ctx, userPerms, role, contextValue)
+	if err != nil {
+		return err
+	}
+	if unallowed != nil {
+		return &errors.HTTP{
+			Code:    http.StatusForbidden,
+			Message: fmt.Sprintf("User not authorized to use permission %s", unallowed.String()),
 		}
 	}
 	return nil
@@ -363,8 +458,32 @@ func assignRole(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	if err != nil {
 		return err
 	}
+	roleInstance, err := roleInstanceFromInput(r, roleName, contextValue)
+	if err != nil {
+		return err
+	}
+	return user.AddRoleInstance(ctx, roleInstance)
+}
 
-	return user.AddRole(ctx, roleName, contextValue)
+// roleInstanceFromInput builds the role assignment, optionally temporary when
+// expires_in, in seconds, is set. A reason may be given for temporary
+// assignments.
+func roleInstanceFromInput(r *http.Request, roleName, contextValue string) (authTypes.RoleInstance, error) {
+	roleInstance := authTypes.RoleInstance{Name: roleName, ContextValue: contextValue}
+	expiresIn := InputValue(r, "expires_in")
+	if expiresIn == "" {
+		return roleInstance, nil
+	}
+	seconds, err := strconv.Atoi(expiresIn)
+	if err != nil || seconds <= 0 {
+		return authTypes.RoleInstance{}, &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "expires_in must be a positive number of seconds",
+		}
+	}
+	roleInstance.ExpiresAt = time.Now().UTC().Add(time.Duration(seconds) * time.Second)
+	roleInstance.Reason = InputValue(r, "reason")
+	return roleInstance, nil
 }
 
 // title: dissociate role from user
@@ -735,7 +854,11 @@ func assignRoleToToken(w http.ResponseWriter, r *http.Request, t auth.Token) (er
 	if err != nil {
 		return err
 	}
-	err = servicemanager.TeamToken.AddRole(ctx, tokenID, roleName, contextValue)
+	roleInstance, err := roleInstanceFromInput(r, roleName, contextValue)
+	if err != nil {
+		return err
+	}
+	err = servicemanager.TeamToken.AddRoleInstance(ctx, tokenID, roleInstance)
 	if err == authTypes.ErrTeamTokenNotFound {
 		w.WriteHeader(http.StatusNotFound)
 		return nil
@@ -872,3 +995,111 @@ func dissociateRoleFromGroup(w http.ResponseWriter, r *http.Request, t auth.Toke
 	}
 	return servicemanager.AuthGroup.RemoveRole(ctx, groupName, roleName, contextValue)
 }
+
+// title: explain permission
+// path: /permissions/explain
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	404: User or token not found
+func explainPermission(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	permName := r.URL.Query().Get("permission")
+	if permName == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "permission is required"}
+	}
+	scheme, err := permission.SafeGet(permName)
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("%s: %q", err, permName)}
+	}
+	contexts, err := explainContexts(ctx, t, r.URL.Query()["context"])
+	if err != nil {
+		return err
+	}
+	email := r.URL.Query().Get("user")
+	tokenID := r.URL.Query().Get("token")
+	var explanation *auth.PermissionExplanation
+	if email == "" && tokenID == "" {
+		explanation, err = auth.ExplainTokenPermission(ctx, t, scheme, contexts...)
+		if err != nil {
+			return err
+		}
+	} else if email != "" {
+		if !permission.Check(ctx, t, permission.PermUserReadPermissions, permission.Context(permTypes.CtxUser, email)) {
+			return permission.ErrUnauthorized
+		}
+		user, err := auth.GetUserByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+		explanation, err = user.ExplainPermission(ctx, scheme, contexts...)
+		if err != nil {
+			return err
+		}
+	} else {
+		teamToken, err := servicemanager.TeamToken.FindByTokenID(ctx, tokenID)
+		if err == authTypes.ErrTeamTokenNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		if err != nil {
+			return err
+		}
+		isSelf := t.Engine() == "team" && tokenID == t.GetUserName()
+		if !isSelf && !permission.Check(ctx, t, permission.PermTeamTokenRead, permission.Context(permTypes.CtxTeam, teamToken.Team)) {
+			return permission.ErrUnauthorized
+		}
+		explanation, err = auth.ExplainTeamTokenPermission(ctx, teamToken, scheme, contexts...)
+		if err != nil {
+			return err
+		}
+	}
+	if !permission.Check(ctx, t, permission.PermRoleRead) {
+		explanation.SuggestedRoles = nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(explanation)
+}
+
+// explainContexts parses contexts in the "type:value" format. App and job
+// contexts are expanded to the team and pool contexts also used when checking
+// permissions on them, as long as the token is allowed to read them. Other
+// app and job contexts, including unknown ones, are kept as given.
+func explainContexts(ctx context.Context, t auth.Token, values []string) ([]permTypes.PermissionContext, error) {
+	var contexts []permTypes.PermissionContext
+	for _, value := range values {
+		ctxType, ctxValue, _ := strings.Cut(value, ":")
+		parsedType, err := permission.ParseContext(ctxType)
+		if err != nil {
+			return nil, &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+		switch parsedType {
+		case permTypes.CtxApp:
+			a, err := app.GetByName(ctx, ctxValue)
+			if err != nil && err != appTypes.ErrAppNotFound {
+				return nil, err
+			}
+			if err == appTypes.ErrAppNotFound || !permission.Check(ctx, t, permission.PermAppRead, contextsForApp(a)...) {
+				contexts = append(contexts, permission.Context(parsedType, ctxValue))
+				continue
+			}
+			contexts = append(contexts, contextsForApp(a)...)
+		case permTypes.CtxJob:
+			j, err := servicemanager.Job.GetByName(ctx, ctxValue)
+			if err != nil && err != jobTypes.ErrJobNotFound {
+				return nil, err
+			}
+			if err == jobTypes.ErrJobNotFound || !permission.Check(ctx, t, permission.PermJobRead, contextsForJob(j)...) {
+				contexts = append(contexts, permission.Context(parsedType, ctxValue))
+				continue
+			}
+			contexts = append(contexts, contextsForJob(j)...)
+		default:
+			contexts = append(contexts, permission.Context(parsedType, ctxValue))
+		}
+	}
+	return contexts, nil
+}
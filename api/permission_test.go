@@ -13,6 +13,7 @@ import (
 	"net/http/httptest"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tsuru/tsuru/app"
@@ -307,6 +308,82 @@ func (s *S) TestAddPermissionsToARole(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *S) TestAddDenyPermissionsToARole(c *check.C) {
+	ctx := context.TODO()
+	role, err := permission.NewRole(ctx, "test", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(ctx, "app")
+	c.Assert(err, check.IsNil)
+	rec := httptest.NewRecorder()
+	b := bytes.NewBufferString(`permission=app.run.shell`)
+	req, err := http.NewRequest(http.MethodPost, "/1.30/roles/test/deny-permissions", b)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermRoleUpdateDeny,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	r, err := permission.FindRole(ctx, "test")
+	c.Assert(err, check.IsNil)
+	c.Assert(r.SchemeNames, check.DeepEquals, []string{"app"})
+	c.Assert(r.DenySchemeNames, check.DeepEquals, []string{"app.run.shell"})
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: "test"},
+		Owner:  token.GetUserName(),
+		Kind:   "role.update.deny.add",
+		StartCustomData: []map[string]interface{}{
+			{"name": "permission", "value": "app.run.shell"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestAddDenyPermissionsToARoleNotAllowed(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "test", "team", "")
+	c.Assert(err, check.IsNil)
+	rec := httptest.NewRecorder()
+	b := bytes.NewBufferString(`permission=pool.create`)
+	req, err := http.NewRequest(http.MethodPost, "/1.30/roles/test/deny-permissions", b)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusConflict)
+	c.Assert(rec.Body.String(), check.Equals, "permission \"pool.create\" not allowed with context of type \"team\"\n")
+}
+
+func (s *S) TestRemoveDenyPermissionsFromRole(c *check.C) {
+	ctx := context.TODO()
+	r, err := permission.NewRole(ctx, "test", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r.AddDenyPermissions(ctx, "app.run.shell", "app.deploy")
+	c.Assert(err, check.IsNil)
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodDelete, "/1.30/roles/test/deny-permissions/app.run.shell", nil)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermRoleUpdateDenyRemove,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	r, err = permission.FindRole(ctx, "test")
+	c.Assert(err, check.IsNil)
+	c.Assert(r.DenySchemeNames, check.DeepEquals, []string{"app.deploy"})
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: "test"},
+		Owner:  token.GetUserName(),
+		Kind:   "role.update.deny.remove",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":permission", "value": "app.run.shell"},
+		},
+	}, eventtest.HasEvent)
+}
+
 func (s *S) TestAddPermissionsToARolePermissionNotFound(c *check.C) {
 	ctx := context.TODO()
 	_, err := permission.NewRole(ctx, "test", "team", "")
@@ -453,6 +530,56 @@ func (s *S) TestAssignRole(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *S) TestAssignRoleTemporary(c *check.C) {
+	ctx := context.TODO()
+	role, err := permission.NewRole(ctx, "test", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(ctx, "app.create")
+	c.Assert(err, check.IsNil)
+	_, emptyToken := permissiontest.CustomUserWithPermission(c, nativeScheme, "user2")
+	roleBody := bytes.NewBufferString(fmt.Sprintf("email=%s&context=%s&expires_in=7200&reason=incident", emptyToken.GetUserName(), s.team.Name))
+	req, err := http.NewRequest(http.MethodPost, "/roles/test/user", roleBody)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	before := time.Now()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	emptyUser, err := emptyToken.User(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(emptyUser.Roles, check.HasLen, 1)
+	c.Assert(emptyUser.Roles[0].Reason, check.Equals, "incident")
+	c.Assert(emptyUser.Roles[0].ExpiresAt.After(before.Add(2*time.Hour-time.Second)), check.Equals, true)
+	c.Assert(emptyUser.Roles[0].ExpiresAt.Before(time.Now().Add(2*time.Hour+time.Second)), check.Equals, true)
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: "test"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "role.update.assign",
+		StartCustomData: []map[string]interface{}{
+			{"name": "email", "value": emptyToken.GetUserName()},
+			{"name": "context", "value": s.team.Name},
+			{"name": "expires_in", "value": "7200"},
+			{"name": "reason", "value": "incident"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestAssignRoleInvalidExpiresIn(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "test", "team", "")
+	c.Assert(err, check.IsNil)
+	_, emptyToken := permissiontest.CustomUserWithPermission(c, nativeScheme, "user2")
+	roleBody := bytes.NewBufferString(fmt.Sprintf("email=%s&context=%s&expires_in=-1", emptyToken.GetUserName(), s.team.Name))
+	req, err := http.NewRequest(http.MethodPost, "/roles/test/user", roleBody)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "expires_in must be a positive number of seconds\n")
+}
+
 func (s *S) TestAssignRoleNotFound(c *check.C) {
 	_, emptyToken := permissiontest.CustomUserWithPermission(c, nativeScheme, "user2")
 	roleBody := bytes.NewBufferString(fmt.Sprintf("email=%s&context=myteam", emptyToken.GetUserName()))
@@ -889,6 +1016,44 @@ func (s *S) TestAssignRoleNotAuthorized(c *check.C) {
 	c.Assert(emptyUser.Roles, check.HasLen, 0)
 }
 
+func (s *S) TestAssignRoleNotAuthorizedDeniedPermission(c *check.C) {
+	role, err := permission.NewRole(context.TODO(), "developer", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(context.TODO(), "app")
+	c.Assert(err, check.IsNil)
+	contractor, err := permission.NewRole(context.TODO(), "contractor", "team", "")
+	c.Assert(err, check.IsNil)
+	err = contractor.AddPermissions(context.TODO(), "app")
+	c.Assert(err, check.IsNil)
+	err = contractor.AddDenyPermissions(context.TODO(), "app.run.shell")
+	c.Assert(err, check.IsNil)
+	_, emptyToken := permissiontest.CustomUserWithPermission(c, nativeScheme, "user2")
+	user, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "user1", permTypes.Permission{
+		Scheme:  permission.PermRoleUpdateAssign,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	err = user.AddRole(context.TODO(), "contractor", "myteam")
+	c.Assert(err, check.IsNil)
+	for _, roleName := range []string{"developer", "contractor"} {
+		roleBody := bytes.NewBufferString(fmt.Sprintf("email=%s&context=myteam", emptyToken.GetUserName()))
+		req, err := http.NewRequest(http.MethodPost, "/roles/"+roleName+"/user", roleBody)
+		c.Assert(err, check.IsNil)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Authorization", "bearer "+token.GetValue())
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, req)
+		if roleName == "developer" {
+			c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+			c.Assert(recorder.Body.String(), check.Equals, "User not authorized to use permission app.run.shell(team myteam)\n")
+		} else {
+			c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+		}
+	}
+	emptyUser, err := auth.GetUserByEmail(context.TODO(), emptyToken.GetUserName())
+	c.Assert(err, check.IsNil)
+	c.Assert(emptyUser.Roles, check.DeepEquals, []authTypes.RoleInstance{{Name: "contractor", ContextValue: "myteam"}})
+}
+
 func (s *S) TestDissociateRoleNotFound(c *check.C) {
 	_, otherToken := permissiontest.CustomUserWithPermission(c, nativeScheme, "user2")
 	url := fmt.Sprintf("/roles/test/user/%s?context=myteam", otherToken.GetUserName())
@@ -1719,3 +1884,157 @@ func (s *S) TestDissociateRoleFromAuthGroupNotAuthorized(c *check.C) {
 		},
 	})
 }
+
+func (s *S) TestExplainPermissionSelf(c *check.C) {
+	app1 := appTypes.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermAppDeploy,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	}, permTypes.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.30/permissions/explain?permission=app.deploy&context=app:myapp", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	var explanation auth.PermissionExplanation
+	err = json.NewDecoder(recorder.Body).Decode(&explanation)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Subject, check.Equals, token.GetUserName())
+	c.Assert(explanation.Permission, check.Equals, "app.deploy")
+	c.Assert(explanation.Allowed, check.Equals, true)
+	c.Assert(explanation.Contexts, check.DeepEquals, []permTypes.PermissionContext{
+		permission.Context(permTypes.CtxTeam, s.team.Name),
+		permission.Context(permTypes.CtxApp, "myapp"),
+		permission.Context(permTypes.CtxPool, app1.Pool),
+	})
+	c.Assert(explanation.Teams, check.DeepEquals, []string{s.team.Name})
+	c.Assert(explanation.Granted, check.HasLen, 1)
+	c.Assert(explanation.Granted[0].ContextType, check.Equals, permTypes.CtxTeam)
+	c.Assert(explanation.Granted[0].ContextValue, check.Equals, s.team.Name)
+}
+
+func (s *S) TestExplainPermissionAppContextWithoutRead(c *check.C) {
+	app1 := appTypes.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermAppDeploy,
+		Context: permission.Context(permTypes.CtxTeam, "otherteam"),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.30/permissions/explain?permission=app.deploy&context=app:myapp", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	var explanation auth.PermissionExplanation
+	err = json.NewDecoder(recorder.Body).Decode(&explanation)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Allowed, check.Equals, false)
+	c.Assert(explanation.Contexts, check.DeepEquals, []permTypes.PermissionContext{
+		permission.Context(permTypes.CtxApp, "myapp"),
+	})
+	c.Assert(explanation.SuggestedRoles, check.IsNil)
+}
+
+func (s *S) TestExplainPermissionOtherUser(c *check.C) {
+	role, err := permission.NewRole(context.TODO(), "deployer", "app", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	user, _ := permissiontest.CustomUserWithPermission(c, nativeScheme, "user1")
+	req, err := http.NewRequest(http.MethodGet, "/1.30/permissions/explain?permission=app.deploy&context=team:myteam&context=app:unknownapp&user="+user.Email, nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	var explanation auth.PermissionExplanation
+	err = json.NewDecoder(recorder.Body).Decode(&explanation)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Contexts, check.DeepEquals, []permTypes.PermissionContext{
+		permission.Context(permTypes.CtxTeam, "myteam"),
+		permission.Context(permTypes.CtxApp, "unknownapp"),
+	})
+	req, err = http.NewRequest(http.MethodGet, "/1.30/permissions/explain?permission=app.deploy&context=team:myteam&context=service:mysql&user="+user.Email, nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	explanation = auth.PermissionExplanation{}
+	err = json.NewDecoder(recorder.Body).Decode(&explanation)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Subject, check.Equals, user.Email)
+	c.Assert(explanation.Allowed, check.Equals, false)
+	c.Assert(explanation.Granted, check.IsNil)
+	for _, suggestion := range explanation.SuggestedRoles {
+		c.Assert(suggestion.Role, check.Not(check.Equals), "deployer")
+	}
+}
+
+func (s *S) TestExplainPermissionOtherUserUnauthorized(c *check.C) {
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermAppDeploy,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.30/permissions/explain?permission=app.deploy&user="+s.user.Email, nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestExplainPermissionTeamToken(c *check.C) {
+	role, err := permission.NewRole(context.TODO(), "deployer", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	teamToken, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		Team: s.team.Name,
+	}, s.token)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.TeamToken.AddRole(context.TODO(), teamToken.TokenID, "deployer", s.team.Name)
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest(http.MethodGet, "/1.30/permissions/explain?permission=app.deploy&context=team:"+s.team.Name+"&token="+teamToken.TokenID, nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	var explanation auth.PermissionExplanation
+	err = json.NewDecoder(recorder.Body).Decode(&explanation)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Subject, check.Equals, teamToken.TokenID)
+	c.Assert(explanation.Allowed, check.Equals, true)
+	c.Assert(explanation.Granted, check.DeepEquals, []auth.PermissionGrant{
+		{Role: "deployer", Permission: "app.deploy", ContextType: permTypes.CtxTeam, ContextValue: s.team.Name},
+	})
+}
+
+func (s *S) TestExplainPermissionInvalidInput(c *check.C) {
+	tests := []struct {
+		query   string
+		message string
+	}{
+		{query: "", message: "permission is required\n"},
+		{query: "permission=app.invalid", message: "unregistered permission: \"app.invalid\"\n"},
+		{query: "permission=app.deploy&context=invalid:x", message: "invalid context type \"invalid\"\n"},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(http.MethodGet, "/1.30/permissions/explain?"+tt.query, nil)
+		c.Assert(err, check.IsNil)
+		req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, req)
+		c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+		c.Assert(recorder.Body.String(), check.Equals, tt.message)
+	}
+}
@@ -16,8 +16,10 @@ import (
 	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/provision/pool"
+	appTypes "github.com/tsuru/tsuru/types/app"
 	eventTypes "github.com/tsuru/tsuru/types/event"
 	permTypes "github.com/tsuru/tsuru/types/permission"
+	provTypes "github.com/tsuru/tsuru/types/provision"
 )
 
 // title: pool get
@@ -54,6 +56,140 @@ func getPoolHandler(w http.ResponseWriter, r *http.Request, t auth.Token) error
 	return json.NewEncoder(w).Encode(poolInfo)
 }
 
+// title: pool capacity
+// path: /pools/{name}/capacity
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	400: Capacity not supported by pool provisioner
+//	401: Unauthorized
+//	404: Not found
+func poolCapacityHandler(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	poolName := r.URL.Query().Get(":name")
+	allowed := permission.Check(ctx, t, permission.PermPoolReadCapacity,
+		permission.Context(permTypes.CtxPool, poolName))
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	retrievedPool, err := pool.GetPoolByName(ctx, poolName)
+	if err == pool.ErrPoolNotFound {
+		return &terrors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	capacity, err := retrievedPool.Capacity(ctx)
+	if err == pool.ErrPoolCapacityNotSupported {
+		return &terrors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(capacity)
+}
+
+// title: pool nodes
+// path: /pools/{name}/nodes
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: OK
+//	204: No content
+//	400: Listing nodes not supported by pool provisioner
+//	401: Unauthorized
+//	404: Not found
+func poolNodesHandler(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	poolName := r.URL.Query().Get(":name")
+	allowed := permission.Check(ctx, t, permission.PermPoolReadNodes,
+		permission.Context(permTypes.CtxPool, poolName))
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	retrievedPool, err := pool.GetPoolByName(ctx, poolName)
+	if err == pool.ErrPoolNotFound {
+		return &terrors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	nodes, err := retrievedPool.Nodes(ctx)
+	if err == pool.ErrPoolNodesNotSupported {
+		return &terrors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	return writeNodeList(w, r, t, nodes)
+}
+
+// writeNodeList writes the nodes, hiding the units of apps the user is not
+// allowed to read.
+func writeNodeList(w http.ResponseWriter, r *http.Request, t auth.Token, nodes *provTypes.NodeList) error {
+	if len(nodes.Nodes) == 0 && len(nodes.Pending) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	canRead := readableUnitFilter(r.Context(), t)
+	for i := range nodes.Nodes {
+		units, err := filterUnits(nodes.Nodes[i].Units, canRead)
+		if err != nil {
+			return err
+		}
+		nodes.Nodes[i].Units = units
+	}
+	pending, err := filterUnits(nodes.Pending, canRead)
+	if err != nil {
+		return err
+	}
+	nodes.Pending = pending
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(nodes)
+}
+
+// readableUnitFilter returns a function reporting whether the user may read
+// the app of a unit. Units of unknown apps, or not belonging to an app, are
+// only visible to users allowed to read every app.
+func readableUnitFilter(ctx context.Context, t auth.Token) func(provTypes.Unit) (bool, error) {
+	allowed := map[string]bool{}
+	return func(u provTypes.Unit) (bool, error) {
+		if ok, cached := allowed[u.AppName]; cached {
+			return ok, nil
+		}
+		ok := permission.Check(ctx, t, permission.PermAppRead)
+		if !ok && u.AppName != "" {
+			a, err := app.GetByName(ctx, u.AppName)
+			if err != nil && err != appTypes.ErrAppNotFound {
+				return false, err
+			}
+			if err == nil {
+				ok = permission.Check(ctx, t, permission.PermAppRead, contextsForApp(a)...)
+			}
+		}
+		allowed[u.AppName] = ok
+		return ok, nil
+	}
+}
+
+func filterUnits(units []provTypes.Unit, canRead func(provTypes.Unit) (bool, error)) ([]provTypes.Unit, error) {
+	var result []provTypes.Unit
+	for _, u := range units {
+		ok, err := canRead(u)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, u)
+		}
+	}
+	return result, nil
+}
+
 // title: pool list
 // path: /pools
 // method: GET
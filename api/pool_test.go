@@ -19,11 +19,14 @@ import (
 	"github.com/tsuru/tsuru/db/storagev2"
 	"github.com/tsuru/tsuru/event/eventtest"
 	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/provision/provisiontest"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	eventTypes "github.com/tsuru/tsuru/types/event"
 	permTypes "github.com/tsuru/tsuru/types/permission"
+	provTypes "github.com/tsuru/tsuru/types/provision"
 	mongoBSON "go.mongodb.org/mongo-driver/bson"
 	check "gopkg.in/check.v1"
 )
@@ -836,3 +839,220 @@ func (s *S) TestPoolGetHandler(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(pool, check.DeepEquals, expected)
 }
+
+type capacityProvisioner struct {
+	*provisiontest.FakeProvisioner
+	plans []appTypes.Plan
+}
+
+func (p *capacityProvisioner) PoolCapacity(ctx context.Context, poolName string, plans []appTypes.Plan) (*provTypes.PoolCapacity, error) {
+	p.plans = plans
+	return &provTypes.PoolCapacity{
+		Pool:        poolName,
+		Nodes:       2,
+		Allocatable: provTypes.PoolResources{MilliCPU: 4000, Memory: 4096},
+		Requested:   provTypes.PoolResources{MilliCPU: 1000, Memory: 1024},
+		Available:   provTypes.PoolResources{MilliCPU: 3000, Memory: 3072},
+	}, nil
+}
+
+func (s *S) TestPoolCapacityHandler(c *check.C) {
+	prov := &capacityProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}
+	provision.Register("capacityProv", func() (provision.Provisioner, error) {
+		return prov, nil
+	})
+	defer provision.Unregister("capacityProv")
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "pool1", Provisioner: "capacityProv"})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermPoolReadCapacity,
+		Context: permission.Context(permTypes.CtxPool, "pool1"),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.30/pools/pool1/capacity", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	c.Assert(rec.Header().Get("Content-Type"), check.Equals, "application/json")
+	var capacity provTypes.PoolCapacity
+	err = json.NewDecoder(rec.Body).Decode(&capacity)
+	c.Assert(err, check.IsNil)
+	c.Assert(capacity, check.DeepEquals, provTypes.PoolCapacity{
+		Pool:        "pool1",
+		Nodes:       2,
+		Allocatable: provTypes.PoolResources{MilliCPU: 4000, Memory: 4096},
+		Requested:   provTypes.PoolResources{MilliCPU: 1000, Memory: 1024},
+		Available:   provTypes.PoolResources{MilliCPU: 3000, Memory: 3072},
+	})
+	c.Assert(prov.plans, check.DeepEquals, []appTypes.Plan{s.defaultPlan})
+}
+
+func (s *S) TestPoolCapacityHandlerNotSupported(c *check.C) {
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "pool1"})
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest(http.MethodGet, "/1.30/pools/pool1/capacity", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(rec.Body.String(), check.Equals, pool.ErrPoolCapacityNotSupported.Error()+"\n")
+}
+
+func (s *S) TestPoolCapacityHandlerNotFound(c *check.C) {
+	req, err := http.NewRequest(http.MethodGet, "/1.30/pools/unknown/capacity", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestPoolCapacityHandlerUnauthorized(c *check.C) {
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "pool1"})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermPoolReadCapacity,
+		Context: permission.Context(permTypes.CtxPool, "other-pool"),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.30/pools/pool1/capacity", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusForbidden)
+}
+
+type nodesProvisioner struct {
+	*provisiontest.FakeProvisioner
+	nodes   []provTypes.Node
+	pending []provTypes.Unit
+}
+
+func (p *nodesProvisioner) PoolNodes(ctx context.Context, poolName string) (*provTypes.NodeList, error) {
+	list := &provTypes.NodeList{Pending: p.pending}
+	for _, n := range p.nodes {
+		if n.Pool == poolName {
+			list.Nodes = append(list.Nodes, n)
+		}
+	}
+	return list, nil
+}
+
+func (p *nodesProvisioner) ClusterNodes(ctx context.Context, c *provTypes.Cluster) (*provTypes.NodeList, error) {
+	return &provTypes.NodeList{Nodes: p.nodes, Pending: p.pending}, nil
+}
+
+func registerNodesProvisioner(nodes []provTypes.Node, pending ...provTypes.Unit) func() {
+	prov := &nodesProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance, nodes: nodes, pending: pending}
+	provision.Register("nodesProv", func() (provision.Provisioner, error) {
+		return prov, nil
+	})
+	return func() { provision.Unregister("nodesProv") }
+}
+
+func (s *S) TestPoolNodesHandler(c *check.C) {
+	defer registerNodesProvisioner([]provTypes.Node{
+		{Name: "n1", Cluster: "c1", Pool: "pool1", Ready: true, Allocatable: provTypes.PoolResources{MilliCPU: 2000, Memory: 2048}},
+		{Name: "n2", Cluster: "c1", Pool: "pool2", Ready: true},
+	})()
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "pool1", Provisioner: "nodesProv"})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermPoolReadNodes,
+		Context: permission.Context(permTypes.CtxPool, "pool1"),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.30/pools/pool1/nodes", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	c.Assert(rec.Header().Get("Content-Type"), check.Equals, "application/json")
+	var nodes provTypes.NodeList
+	err = json.NewDecoder(rec.Body).Decode(&nodes)
+	c.Assert(err, check.IsNil)
+	c.Assert(nodes, check.DeepEquals, provTypes.NodeList{Nodes: []provTypes.Node{
+		{Name: "n1", Cluster: "c1", Pool: "pool1", Ready: true, Allocatable: provTypes.PoolResources{MilliCPU: 2000, Memory: 2048}},
+	}})
+}
+
+func (s *S) TestPoolNodesHandlerHidesUnreadableUnits(c *check.C) {
+	for _, name := range []string{"myapp", "otherapp"} {
+		err := app.CreateApp(context.TODO(), &appTypes.App{Name: name, TeamOwner: s.team.Name}, s.user)
+		c.Assert(err, check.IsNil)
+	}
+	defer registerNodesProvisioner([]provTypes.Node{
+		{Name: "n1", Cluster: "c1", Pool: "pool1", Units: []provTypes.Unit{
+			{ID: "myapp-web-1", Name: "myapp-web-1", AppName: "myapp"},
+			{ID: "otherapp-web-1", Name: "otherapp-web-1", AppName: "otherapp"},
+			{ID: "kube-proxy", Name: "kube-proxy"},
+		}},
+	},
+		provTypes.Unit{ID: "myapp-web-2", Name: "myapp-web-2", AppName: "myapp"},
+		provTypes.Unit{ID: "otherapp-web-2", Name: "otherapp-web-2", AppName: "otherapp"},
+	)()
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "pool1", Provisioner: "nodesProv"})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermPoolReadNodes,
+		Context: permission.Context(permTypes.CtxPool, "pool1"),
+	}, permTypes.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxApp, "myapp"),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.30/pools/pool1/nodes", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	var nodes provTypes.NodeList
+	err = json.NewDecoder(rec.Body).Decode(&nodes)
+	c.Assert(err, check.IsNil)
+	c.Assert(nodes.Nodes, check.HasLen, 1)
+	c.Assert(nodes.Nodes[0].Units, check.HasLen, 1)
+	c.Assert(nodes.Nodes[0].Units[0].Name, check.Equals, "myapp-web-1")
+	c.Assert(nodes.Pending, check.HasLen, 1)
+	c.Assert(nodes.Pending[0].Name, check.Equals, "myapp-web-2")
+}
+
+func (s *S) TestPoolNodesHandlerNoContent(c *check.C) {
+	defer registerNodesProvisioner(nil)()
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "pool1", Provisioner: "nodesProv"})
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest(http.MethodGet, "/1.30/pools/pool1/nodes", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *S) TestPoolNodesHandlerNotSupported(c *check.C) {
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "pool1"})
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest(http.MethodGet, "/1.30/pools/pool1/nodes", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(rec.Body.String(), check.Equals, pool.ErrPoolNodesNotSupported.Error()+"\n")
+}
+
+func (s *S) TestPoolNodesHandlerUnauthorized(c *check.C) {
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "pool1"})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermPoolReadNodes,
+		Context: permission.Context(permTypes.CtxPool, "other-pool"),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.30/pools/pool1/nodes", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusForbidden)
+}
@@ -0,0 +1,845 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	pkgErrors "github.com/pkg/errors"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	"github.com/tsuru/tsuru/set"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	"github.com/tsuru/tsuru/validation"
+)
+
+// SCIM 2.0 (RFC 7643 and RFC 7644) provisioning endpoints. Users are
+// identified by their email and groups by their name, group memberships are
+// the same ones synced from OIDC claims.
+
+const (
+	scimContentType     = "application/scim+json"
+	scimUserSchema      = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimGroupSchema     = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimListSchema      = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimErrorSchema     = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimBasePath        = "/1.30/scim/v2"
+	scimDefaultPageSize = 100
+)
+
+var scimFilterRegexp = regexp.MustCompile(`^\s*(\w+)\s+eq\s+"([^"]*)"\s*$`)
+
+var scimMemberPathRegexp = regexp.MustCompile(`^members\[\s*value\s+eq\s+"([^"]*)"\s*\]$`)
+
+type scimMeta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location"`
+}
+
+type scimValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type scimUser struct {
+	Schemas  []string    `json:"schemas"`
+	ID       string      `json:"id"`
+	UserName string      `json:"userName"`
+	Active   *bool       `json:"active,omitempty"`
+	Emails   []scimValue `json:"emails,omitempty"`
+	Groups   []scimValue `json:"groups,omitempty"`
+	Meta     *scimMeta   `json:"meta,omitempty"`
+}
+
+type scimGroup struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id"`
+	DisplayName string      `json:"displayName"`
+	Members     []scimValue `json:"members"`
+	Meta        *scimMeta   `json:"meta,omitempty"`
+}
+
+type scimListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type scimPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+type scimPatch struct {
+	Schemas    []string      `json:"schemas"`
+	Operations []scimPatchOp `json:"Operations"`
+}
+
+type scimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+type scimHTTPError struct {
+	errors.HTTP
+	scimType string
+}
+
+func newSCIMError(code int, scimType, format string, args ...interface{}) error {
+	return &scimHTTPError{
+		HTTP:     errors.HTTP{Code: code, Message: fmt.Sprintf(format, args...)},
+		scimType: scimType,
+	}
+}
+
+// scimHandler checks the scim permission and renders errors using the SCIM
+// error schema expected by identity providers.
+func scimHandler(h AuthorizationRequiredHandler) AuthorizationRequiredHandler {
+	return func(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+		err := checkSCIMPermission(r, t)
+		if err == nil {
+			err = h(w, r, t)
+		}
+		if err == nil {
+			return nil
+		}
+		scimErr := scimError{
+			Schemas: []string{scimErrorSchema},
+			Status:  strconv.Itoa(http.StatusInternalServerError),
+			Detail:  err.Error(),
+		}
+		switch e := pkgErrors.Cause(err).(type) {
+		case *scimHTTPError:
+			scimErr.Status = strconv.Itoa(e.Code)
+			scimErr.ScimType = e.scimType
+		case *errors.HTTP:
+			scimErr.Status = strconv.Itoa(e.Code)
+		case *errors.ValidationError:
+			scimErr.Status = strconv.Itoa(http.StatusBadRequest)
+		}
+		log.Errorf("failure running SCIM request %s %s (%s): %s", r.Method, r.URL.Path, scimErr.Status, err)
+		code, _ := strconv.Atoi(scimErr.Status)
+		return writeSCIM(w, code, scimErr)
+	}
+}
+
+func checkSCIMPermission(r *http.Request, t auth.Token) error {
+	if !permission.Check(r.Context(), t, permission.PermScim) {
+		return permission.ErrUnauthorized
+	}
+	return nil
+}
+
+func writeSCIM(w http.ResponseWriter, code int, data interface{}) error {
+	w.Header().Set("Content-Type", scimContentType)
+	w.WriteHeader(code)
+	return json.NewEncoder(w).Encode(data)
+}
+
+func parseSCIM(r *http.Request, dst interface{}) error {
+	err := json.NewDecoder(r.Body).Decode(dst)
+	if err != nil {
+		return newSCIMError(http.StatusBadRequest, "invalidSyntax", "unable to parse request body: %v", err)
+	}
+	return nil
+}
+
+// parseSCIMFilter parses the simple `attribute eq "value"` filters used by
+// identity providers to look up existing resources.
+func parseSCIMFilter(r *http.Request, attribute string) (string, bool, error) {
+	filter := r.URL.Query().Get("filter")
+	if filter == "" {
+		return "", false, nil
+	}
+	parts := scimFilterRegexp.FindStringSubmatch(filter)
+	if parts == nil || !strings.EqualFold(parts[1], attribute) {
+		return "", false, newSCIMError(http.StatusBadRequest, "invalidFilter", "unsupported filter %q", filter)
+	}
+	return parts[2], true, nil
+}
+
+func scimList(r *http.Request, resources []interface{}) scimListResponse {
+	startIndex, _ := strconv.Atoi(r.URL.Query().Get("startIndex"))
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count < 0 {
+		count = scimDefaultPageSize
+	}
+	page := []interface{}{}
+	if startIndex <= len(resources) {
+		end := startIndex - 1 + count
+		if end > len(resources) {
+			end = len(resources)
+		}
+		page = resources[startIndex-1 : end]
+	}
+	return scimListResponse{
+		Schemas:      []string{scimListSchema},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+func toSCIMUser(u *auth.User) scimUser {
+	active := !u.Disabled
+	groups := make([]scimValue, len(u.Groups))
+	for i, group := range u.Groups {
+		groups[i] = scimValue{Value: group, Display: group}
+	}
+	return scimUser{
+		Schemas:  []string{scimUserSchema},
+		ID:       u.Email,
+		UserName: u.Email,
+		Active:   &active,
+		Emails:   []scimValue{{Value: u.Email, Primary: true}},
+		Groups:   groups,
+		Meta:     &scimMeta{ResourceType: "User", Location: scimBasePath + "/Users/" + u.Email},
+	}
+}
+
+func scimUserNotFound(id string) error {
+	return newSCIMError(http.StatusNotFound, "", "user %q not found", id)
+}
+
+func getSCIMUser(r *http.Request) (*auth.User, error) {
+	id := r.URL.Query().Get(":id")
+	u, err := auth.GetUserByEmail(r.Context(), id)
+	if err != nil {
+		if _, ok := err.(*errors.ValidationError); ok || err == authTypes.ErrUserNotFound {
+			return nil, scimUserNotFound(id)
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+// title: scim user list
+// path: /scim/v2/Users
+// method: GET
+// produce: application/scim+json
+// responses:
+//
+//	200: List users
+//	400: Invalid filter
+//	401: Unauthorized
+func scimListUsers(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	userName, filtered, err := parseSCIMFilter(r, "userName")
+	if err != nil {
+		return err
+	}
+	var users []auth.User
+	if filtered {
+		u, getErr := auth.GetUserByEmail(ctx, userName)
+		if getErr == nil {
+			users = append(users, *u)
+		}
+	} else {
+		users, err = auth.ListUsers(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Email < users[j].Email })
+	resources := make([]interface{}, len(users))
+	for i := range users {
+		resources[i] = toSCIMUser(&users[i])
+	}
+	return writeSCIM(w, http.StatusOK, scimList(r, resources))
+}
+
+// title: scim user info
+// path: /scim/v2/Users/{id}
+// method: GET
+// produce: application/scim+json
+// responses:
+//
+//	200: User info
+//	401: Unauthorized
+//	404: User not found
+func scimGetUser(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	u, err := getSCIMUser(r)
+	if err != nil {
+		return err
+	}
+	return writeSCIM(w, http.StatusOK, toSCIMUser(u))
+}
+
+// title: scim user create
+// path: /scim/v2/Users
+// method: POST
+// consume: application/scim+json
+// produce: application/scim+json
+// responses:
+//
+//	201: User created
+//	400: Invalid data
+//	401: Unauthorized
+//	409: User already exists
+func scimCreateUser(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	var input scimUser
+	if err = parseSCIM(r, &input); err != nil {
+		return err
+	}
+	email := input.UserName
+	if !validation.ValidateEmail(email) {
+		return newSCIMError(http.StatusBadRequest, "invalidValue", "userName must be a valid email")
+	}
+	if _, err = auth.GetUserByEmail(ctx, email); err == nil {
+		return newSCIMError(http.StatusConflict, "uniqueness", "user %q already exists", email)
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(email),
+		Kind:       permission.PermUserCreate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: input,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	u := auth.User{
+		Email:    email,
+		Disabled: input.Active != nil && !*input.Active,
+	}
+	if err = u.Create(ctx); err != nil {
+		return err
+	}
+	w.Header().Set("Location", scimBasePath+"/Users/"+u.Email)
+	return writeSCIM(w, http.StatusCreated, toSCIMUser(&u))
+}
+
+// title: scim user replace
+// path: /scim/v2/Users/{id}
+// method: PUT
+// consume: application/scim+json
+// produce: application/scim+json
+// responses:
+//
+//	200: User updated
+//	400: Invalid data
+//	401: Unauthorized
+//	404: User not found
+func scimReplaceUser(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	u, err := getSCIMUser(r)
+	if err != nil {
+		return err
+	}
+	var input scimUser
+	if err = parseSCIM(r, &input); err != nil {
+		return err
+	}
+	if input.UserName != "" && input.UserName != u.Email {
+		return newSCIMError(http.StatusBadRequest, "mutability", "userName cannot be changed")
+	}
+	active := input.Active == nil || *input.Active
+	return scimSetUserActive(w, r, t, u, active, input)
+}
+
+// title: scim user update
+// path: /scim/v2/Users/{id}
+// method: PATCH
+// consume: application/scim+json
+// produce: application/scim+json
+// responses:
+//
+//	200: User updated
+//	400: Invalid data
+//	401: Unauthorized
+//	404: User not found
+func scimPatchUser(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	u, err := getSCIMUser(r)
+	if err != nil {
+		return err
+	}
+	var patch scimPatch
+	if err = parseSCIM(r, &patch); err != nil {
+		return err
+	}
+	active := !u.Disabled
+	for _, op := range patch.Operations {
+		path := strings.TrimPrefix(strings.ToLower(op.Path), strings.ToLower(scimUserSchema)+":")
+		switch strings.ToLower(op.Op) {
+		case "replace", "add":
+		case "remove":
+			if path == "" || path == "active" || path == "username" {
+				return newSCIMError(http.StatusBadRequest, "mutability", "%q cannot be removed", op.Path)
+			}
+			continue
+		default:
+			return newSCIMError(http.StatusBadRequest, "invalidValue", "unsupported operation %q", op.Op)
+		}
+		attrs := map[string]json.RawMessage{}
+		if path == "" {
+			if err = json.Unmarshal(op.Value, &attrs); err != nil {
+				return newSCIMError(http.StatusBadRequest, "invalidValue", "invalid value: %v", err)
+			}
+		} else {
+			attrs[path] = op.Value
+		}
+		// Only the active state and the user name are kept by tsuru, other
+		// attributes sent by identity providers, like name, emails or
+		// externalId, are accepted and ignored.
+		for name, value := range attrs {
+			switch strings.ToLower(name) {
+			case "active":
+				if active, err = parseSCIMBool(value); err != nil {
+					return err
+				}
+			case "username":
+				var userName string
+				if err = json.Unmarshal(value, &userName); err != nil || userName != u.Email {
+					return newSCIMError(http.StatusBadRequest, "mutability", "userName cannot be changed")
+				}
+			}
+		}
+	}
+	return scimSetUserActive(w, r, t, u, active, patch)
+}
+
+// parseSCIMBool accepts both JSON booleans and the string representation
+// sent by some identity providers.
+func parseSCIMBool(data json.RawMessage) (bool, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return false, newSCIMError(http.StatusBadRequest, "invalidValue", "invalid value: %v", err)
+	}
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b, nil
+		}
+	}
+	return false, newSCIMError(http.StatusBadRequest, "invalidValue", "active must be a boolean")
+}
+
+func scimSetUserActive(w http.ResponseWriter, r *http.Request, t auth.Token, u *auth.User, active bool, customData interface{}) (err error) {
+	ctx := r.Context()
+	if u.Disabled == !active {
+		return writeSCIM(w, http.StatusOK, toSCIMUser(u))
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(u.Email),
+		Kind:       permission.PermUserUpdate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: customData,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, u.Email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	u.Disabled = !active
+	if u.Disabled {
+		// Deactivated users must sign in again once reactivated, API keys
+		// included.
+		u.APIKey = ""
+	}
+	if err = u.Update(ctx); err != nil {
+		return err
+	}
+	if u.Disabled {
+		if _, err = auth.RevokeSessions(ctx, u.Email); err != nil {
+			return err
+		}
+	}
+	return writeSCIM(w, http.StatusOK, toSCIMUser(u))
+}
+
+// title: scim user delete
+// path: /scim/v2/Users/{id}
+// method: DELETE
+// responses:
+//
+//	204: User removed
+//	401: Unauthorized
+//	404: User not found
+func scimDeleteUser(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	u, err := getSCIMUser(r)
+	if err != nil {
+		return err
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(u.Email),
+		Kind:       permission.PermUserDelete,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, u.Email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	if userScheme, ok := app.AuthScheme.(auth.UserScheme); ok {
+		err = userScheme.Remove(ctx, u)
+	} else {
+		err = u.Delete(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err = auth.RevokeSessions(ctx, u.Email); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func groupTarget(name string) eventTypes.Target {
+	return eventTypes.Target{Type: eventTypes.TargetTypeGroup, Value: name}
+}
+
+func toSCIMGroup(name string, members []auth.User) scimGroup {
+	values := make([]scimValue, len(members))
+	for i, u := range members {
+		values[i] = scimValue{Value: u.Email, Display: u.Email}
+	}
+	return scimGroup{
+		Schemas:     []string{scimGroupSchema},
+		ID:          name,
+		DisplayName: name,
+		Members:     values,
+		Meta:        &scimMeta{ResourceType: "Group", Location: scimBasePath + "/Groups/" + name},
+	}
+}
+
+// scimGroupNames returns the groups known to tsuru, either because they have
+// members or because roles were mapped to them.
+func scimGroupNames(r *http.Request) (set.Set, error) {
+	ctx := r.Context()
+	names, err := auth.ListUserGroupNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := servicemanager.AuthGroup.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	result := set.FromSlice(names)
+	for _, group := range groups {
+		result.Add(group.Name)
+	}
+	return result, nil
+}
+
+func getSCIMGroup(r *http.Request) (string, []auth.User, error) {
+	name := r.URL.Query().Get(":id")
+	names, err := scimGroupNames(r)
+	if err != nil {
+		return "", nil, err
+	}
+	if !names.Includes(name) {
+		return "", nil, newSCIMError(http.StatusNotFound, "", "group %q not found", name)
+	}
+	members, err := auth.ListUsersWithGroup(r.Context(), name)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, members, nil
+}
+
+// title: scim group list
+// path: /scim/v2/Groups
+// method: GET
+// produce: application/scim+json
+// responses:
+//
+//	200: List groups
+//	400: Invalid filter
+//	401: Unauthorized
+func scimListGroups(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	displayName, filtered, err := parseSCIMFilter(r, "displayName")
+	if err != nil {
+		return err
+	}
+	names, err := scimGroupNames(r)
+	if err != nil {
+		return err
+	}
+	sorted := names.Sorted()
+	if filtered {
+		sorted = nil
+		if names.Includes(displayName) {
+			sorted = []string{displayName}
+		}
+	}
+	resources := make([]interface{}, len(sorted))
+	for i, name := range sorted {
+		members, err := auth.ListUsersWithGroup(r.Context(), name)
+		if err != nil {
+			return err
+		}
+		resources[i] = toSCIMGroup(name, members)
+	}
+	return writeSCIM(w, http.StatusOK, scimList(r, resources))
+}
+
+// title: scim group info
+// path: /scim/v2/Groups/{id}
+// method: GET
+// produce: application/scim+json
+// responses:
+//
+//	200: Group info
+//	401: Unauthorized
+//	404: Group not found
+func scimGetGroup(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	name, members, err := getSCIMGroup(r)
+	if err != nil {
+		return err
+	}
+	return writeSCIM(w, http.StatusOK, toSCIMGroup(name, members))
+}
+
+// title: scim group create
+// path: /scim/v2/Groups
+// method: POST
+// consume: application/scim+json
+// produce: application/scim+json
+// responses:
+//
+//	201: Group created
+//	400: Invalid data
+//	401: Unauthorized
+//	409: Group already exists
+func scimCreateGroup(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	var input scimGroup
+	if err = parseSCIM(r, &input); err != nil {
+		return err
+	}
+	name := input.DisplayName
+	if name == "" {
+		return newSCIMError(http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+	names, err := scimGroupNames(r)
+	if err != nil {
+		return err
+	}
+	if names.Includes(name) {
+		return newSCIMError(http.StatusConflict, "uniqueness", "group %q already exists", name)
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     groupTarget(name),
+		Kind:       permission.PermGroupUpdate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: input,
+		Allowed:    event.Allowed(permission.PermGroupReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	// Registering the group without roles allows it to be listed before any
+	// member is provisioned, roles are mapped to it later by admins.
+	if err = servicemanager.AuthGroup.SetRoles(ctx, name, nil); err != nil {
+		return err
+	}
+	if err = auth.AddGroupToUsers(ctx, name, scimMemberEmails(input.Members)); err != nil {
+		return err
+	}
+	members, err := auth.ListUsersWithGroup(ctx, name)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Location", scimBasePath+"/Groups/"+name)
+	return writeSCIM(w, http.StatusCreated, toSCIMGroup(name, members))
+}
+
+func scimMemberEmails(members []scimValue) []string {
+	emails := make([]string, 0, len(members))
+	for _, m := range members {
+		emails = append(emails, m.Value)
+	}
+	return emails
+}
+
+// title: scim group replace
+// path: /scim/v2/Groups/{id}
+// method: PUT
+// consume: application/scim+json
+// produce: application/scim+json
+// responses:
+//
+//	200: Group updated
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Group not found
+func scimReplaceGroup(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	name, members, err := getSCIMGroup(r)
+	if err != nil {
+		return err
+	}
+	var input scimGroup
+	if err = parseSCIM(r, &input); err != nil {
+		return err
+	}
+	if input.DisplayName != "" && input.DisplayName != name {
+		return newSCIMError(http.StatusBadRequest, "mutability", "displayName cannot be changed")
+	}
+	current := set.Set{}
+	for _, u := range members {
+		current.Add(u.Email)
+	}
+	desired := set.FromSlice(scimMemberEmails(input.Members))
+	return scimUpdateMembers(w, r, t, name, desired.Difference(current).ToList(), current.Difference(desired).ToList(), input)
+}
+
+// title: scim group update
+// path: /scim/v2/Groups/{id}
+// method: PATCH
+// consume: application/scim+json
+// produce: application/scim+json
+// responses:
+//
+//	200: Group updated
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Group not found
+func scimPatchGroup(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	name, members, err := getSCIMGroup(r)
+	if err != nil {
+		return err
+	}
+	var patch scimPatch
+	if err = parseSCIM(r, &patch); err != nil {
+		return err
+	}
+	current := set.Set{}
+	for _, u := range members {
+		current.Add(u.Email)
+	}
+	desired := set.FromSlice(current.ToList())
+	for _, op := range patch.Operations {
+		var values []scimValue
+		path := strings.TrimSpace(op.Path)
+		if parts := scimMemberPathRegexp.FindStringSubmatch(path); parts != nil {
+			values = []scimValue{{Value: parts[1]}}
+			path = "members"
+		} else if len(op.Value) > 0 {
+			if err = json.Unmarshal(op.Value, &values); err != nil {
+				return newSCIMError(http.StatusBadRequest, "invalidValue", "invalid members: %v", err)
+			}
+		}
+		if path != "members" {
+			return newSCIMError(http.StatusBadRequest, "invalidPath", "unsupported path %q", op.Path)
+		}
+		switch strings.ToLower(op.Op) {
+		case "add":
+			for _, v := range values {
+				desired.Add(v.Value)
+			}
+		case "remove":
+			if values == nil {
+				desired = set.Set{}
+			}
+			for _, v := range values {
+				delete(desired, v.Value)
+			}
+		case "replace":
+			desired = set.FromSlice(scimMemberEmails(values))
+		default:
+			return newSCIMError(http.StatusBadRequest, "invalidValue", "unsupported operation %q", op.Op)
+		}
+	}
+	return scimUpdateMembers(w, r, t, name, desired.Difference(current).ToList(), current.Difference(desired).ToList(), patch)
+}
+
+func scimUpdateMembers(w http.ResponseWriter, r *http.Request, t auth.Token, name string, added, removed []string, customData interface{}) (err error) {
+	ctx := r.Context()
+	if len(added) > 0 || len(removed) > 0 {
+		var evt *event.Event
+		evt, err = event.New(ctx, &event.Opts{
+			Target:     groupTarget(name),
+			Kind:       permission.PermGroupUpdate,
+			Owner:      t,
+			RemoteAddr: r.RemoteAddr,
+			CustomData: customData,
+			Allowed:    event.Allowed(permission.PermGroupReadEvents),
+		})
+		if err != nil {
+			return err
+		}
+		defer func() { evt.Done(ctx, err) }()
+		if err = auth.AddGroupToUsers(ctx, name, added); err != nil {
+			return err
+		}
+		if err = auth.RemoveGroupFromUsers(ctx, name, removed); err != nil {
+			return err
+		}
+	}
+	members, err := auth.ListUsersWithGroup(ctx, name)
+	if err != nil {
+		return err
+	}
+	return writeSCIM(w, http.StatusOK, toSCIMGroup(name, members))
+}
+
+// title: scim group delete
+// path: /scim/v2/Groups/{id}
+// method: DELETE
+// responses:
+//
+//	204: Group removed
+//	401: Unauthorized
+//	404: Group not found
+func scimDeleteGroup(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	name, _, err := getSCIMGroup(r)
+	if err != nil {
+		return err
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     groupTarget(name),
+		Kind:       permission.PermGroupDelete,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermGroupReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	if err = auth.RemoveGroupFromUsers(ctx, name, nil); err != nil {
+		return err
+	}
+	err = servicemanager.AuthGroup.Delete(ctx, name)
+	if err != nil && err != authTypes.ErrGroupNotFound {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
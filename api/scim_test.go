@@ -0,0 +1,293 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/permission/permissiontest"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) scimToken(c *check.C) auth.Token {
+	return userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermScim,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+}
+
+func (s *S) scimRequest(c *check.C, token auth.Token, method, path, body string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, "/1.30/scim/v2"+path, strings.NewReader(body))
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", scimContentType)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (s *S) TestSCIMCreateUser(c *check.C) {
+	token := s.scimToken(c)
+	recorder := s.scimRequest(c, token, http.MethodPost, "/Users", `{"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"], "userName": "bowie@tsuru.io", "active": true}`)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, scimContentType)
+	c.Assert(recorder.Header().Get("Location"), check.Equals, "/1.30/scim/v2/Users/bowie@tsuru.io")
+	var result scimUser
+	err := json.NewDecoder(recorder.Body).Decode(&result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.ID, check.Equals, "bowie@tsuru.io")
+	c.Assert(result.UserName, check.Equals, "bowie@tsuru.io")
+	c.Assert(*result.Active, check.Equals, true)
+	u, err := auth.GetUserByEmail(context.TODO(), "bowie@tsuru.io")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Disabled, check.Equals, false)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget("bowie@tsuru.io"),
+		Owner:  token.GetUserName(),
+		Kind:   "user.create",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestSCIMCreateUserAlreadyExists(c *check.C) {
+	u := auth.User{Email: "bowie@tsuru.io"}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	recorder := s.scimRequest(c, s.scimToken(c), http.MethodPost, "/Users", `{"userName": "bowie@tsuru.io"}`)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	var result scimError
+	err = json.NewDecoder(recorder.Body).Decode(&result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, scimError{
+		Schemas:  []string{scimErrorSchema},
+		Status:   "409",
+		ScimType: "uniqueness",
+		Detail:   `user "bowie@tsuru.io" already exists`,
+	})
+}
+
+func (s *S) TestSCIMCreateUserInvalidUserName(c *check.C) {
+	recorder := s.scimRequest(c, s.scimToken(c), http.MethodPost, "/Users", `{"userName": "bowie"}`)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *S) TestSCIMListUsersWithFilter(c *check.C) {
+	for _, email := range []string{"bowie@tsuru.io", "iggy@tsuru.io"} {
+		u := auth.User{Email: email}
+		err := u.Create(context.TODO())
+		c.Assert(err, check.IsNil)
+	}
+	token := s.scimToken(c)
+	recorder := s.scimRequest(c, token, http.MethodGet, `/Users?filter=userName+eq+"iggy@tsuru.io"`, "")
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var result struct {
+		TotalResults int
+		Resources    []scimUser
+	}
+	err := json.NewDecoder(recorder.Body).Decode(&result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.TotalResults, check.Equals, 1)
+	c.Assert(result.Resources, check.HasLen, 1)
+	c.Assert(result.Resources[0].UserName, check.Equals, "iggy@tsuru.io")
+	recorder = s.scimRequest(c, token, http.MethodGet, `/Users?filter=title+eq+"x"`, "")
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *S) TestSCIMPatchUserDeactivate(c *check.C) {
+	user, userToken := permissiontest.CustomUserWithPermission(c, nativeScheme, "bowie")
+	apiKey, err := user.RegenerateAPIKey(context.TODO())
+	c.Assert(err, check.IsNil)
+	token := s.scimToken(c)
+	body := `{"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"], "Operations": [{"op": "Replace", "value": {"active": "False"}}]}`
+	recorder := s.scimRequest(c, token, http.MethodPatch, "/Users/"+user.Email, body)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	var result scimUser
+	err = json.NewDecoder(recorder.Body).Decode(&result)
+	c.Assert(err, check.IsNil)
+	c.Assert(*result.Active, check.Equals, false)
+	u, err := auth.GetUserByEmail(context.TODO(), user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Disabled, check.Equals, true)
+	_, err = auth.APIAuth(context.TODO(), "bearer "+apiKey)
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+	_, err = userToken.Permissions(context.TODO())
+	c.Assert(err, check.Equals, auth.ErrUserDisabled)
+	_, err = nativeScheme.Auth(context.TODO(), "bearer "+userToken.GetValue())
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(user.Email),
+		Owner:  token.GetUserName(),
+		Kind:   "user.update",
+	}, eventtest.HasEvent)
+	body = `{"Operations": [{"op": "replace", "path": "active", "value": true}]}`
+	recorder = s.scimRequest(c, token, http.MethodPatch, "/Users/"+user.Email, body)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	u, err = auth.GetUserByEmail(context.TODO(), user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Disabled, check.Equals, false)
+	c.Assert(u.APIKey, check.Equals, "")
+	_, err = auth.APIAuth(context.TODO(), "bearer "+apiKey)
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+	_, err = nativeScheme.Auth(context.TODO(), "bearer "+userToken.GetValue())
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+}
+
+func (s *S) TestSCIMPatchUserOtherAttributes(c *check.C) {
+	user, _ := permissiontest.CustomUserWithPermission(c, nativeScheme, "bowie")
+	token := s.scimToken(c)
+	body := `{"Operations": [
+		{"op": "replace", "path": "name.givenName", "value": "David"},
+		{"op": "add", "path": "emails", "value": [{"value": "` + user.Email + `", "primary": true}]},
+		{"op": "remove", "path": "externalId"},
+		{"op": "replace", "value": {"externalId": "00u1", "displayName": "Bowie", "active": false}},
+		{"op": "replace", "path": "urn:ietf:params:scim:schemas:core:2.0:User:userName", "value": "` + user.Email + `"}
+	]}`
+	recorder := s.scimRequest(c, token, http.MethodPatch, "/Users/"+user.Email, body)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	u, err := auth.GetUserByEmail(context.TODO(), user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Disabled, check.Equals, true)
+	body = `{"Operations": [{"op": "replace", "path": "userName", "value": "other@tsuru.io"}]}`
+	recorder = s.scimRequest(c, token, http.MethodPatch, "/Users/"+user.Email, body)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	body = `{"Operations": [{"op": "remove", "path": "active"}]}`
+	recorder = s.scimRequest(c, token, http.MethodPatch, "/Users/"+user.Email, body)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *S) TestSCIMDeactivatedUserRequests(c *check.C) {
+	user, userToken := permissiontest.CustomUserWithPermission(c, nativeScheme, "bowie")
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/users/info", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+userToken.GetValue())
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	body := `{"Operations": [{"op": "replace", "path": "active", "value": false}]}`
+	recorder = s.scimRequest(c, s.scimToken(c), http.MethodPatch, "/Users/"+user.Email, body)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	recorder = httptest.NewRecorder()
+	req, err = http.NewRequest(http.MethodPost, "/auth/login", strings.NewReader("email="+user.Email+"&password=123456"))
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+}
+
+func (s *S) TestSCIMReplaceUser(c *check.C) {
+	u := auth.User{Email: "bowie@tsuru.io"}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	token := s.scimToken(c)
+	recorder := s.scimRequest(c, token, http.MethodPut, "/Users/bowie@tsuru.io", `{"userName": "bowie@tsuru.io", "active": false}`)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	dbUser, err := auth.GetUserByEmail(context.TODO(), u.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.Disabled, check.Equals, true)
+	recorder = s.scimRequest(c, token, http.MethodPut, "/Users/bowie@tsuru.io", `{"userName": "ziggy@tsuru.io"}`)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *S) TestSCIMDeleteUser(c *check.C) {
+	user, _ := permissiontest.CustomUserWithPermission(c, nativeScheme, "bowie")
+	token := s.scimToken(c)
+	recorder := s.scimRequest(c, token, http.MethodDelete, "/Users/"+user.Email, "")
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+	_, err := auth.GetUserByEmail(context.TODO(), user.Email)
+	c.Assert(err, check.NotNil)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(user.Email),
+		Owner:  token.GetUserName(),
+		Kind:   "user.delete",
+	}, eventtest.HasEvent)
+	recorder = s.scimRequest(c, token, http.MethodGet, "/Users/"+user.Email, "")
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, scimContentType)
+}
+
+func (s *S) TestSCIMUnauthorized(c *check.C) {
+	recorder := s.scimRequest(c, userWithPermission(c), http.MethodGet, "/Users", "")
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	var result scimError
+	err := json.NewDecoder(recorder.Body).Decode(&result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Status, check.Equals, "403")
+}
+
+func (s *S) TestSCIMGroupMembers(c *check.C) {
+	for _, email := range []string{"bowie@tsuru.io", "iggy@tsuru.io", "lou@tsuru.io"} {
+		u := auth.User{Email: email}
+		err := u.Create(context.TODO())
+		c.Assert(err, check.IsNil)
+	}
+	token := s.scimToken(c)
+	recorder := s.scimRequest(c, token, http.MethodPost, "/Groups", `{"displayName": "sre", "members": [{"value": "bowie@tsuru.io"}, {"value": "iggy@tsuru.io"}]}`)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(s.scimGroupMembers(c, token, "sre"), check.DeepEquals, []string{"bowie@tsuru.io", "iggy@tsuru.io"})
+	recorder = s.scimRequest(c, token, http.MethodPost, "/Groups", `{"displayName": "sre"}`)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	body := `{"Operations": [{"op": "add", "path": "members", "value": [{"value": "lou@tsuru.io"}]}, {"op": "remove", "path": "members[value eq \"bowie@tsuru.io\"]"}]}`
+	recorder = s.scimRequest(c, token, http.MethodPatch, "/Groups/sre", body)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(s.scimGroupMembers(c, token, "sre"), check.DeepEquals, []string{"iggy@tsuru.io", "lou@tsuru.io"})
+	recorder = s.scimRequest(c, token, http.MethodPut, "/Groups/sre", `{"displayName": "sre", "members": [{"value": "bowie@tsuru.io"}]}`)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(s.scimGroupMembers(c, token, "sre"), check.DeepEquals, []string{"bowie@tsuru.io"})
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeGroup, Value: "sre"},
+		Owner:  token.GetUserName(),
+		Kind:   "group.update",
+	}, eventtest.HasEvent)
+	recorder = s.scimRequest(c, token, http.MethodDelete, "/Groups/sre", "")
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+	users, err := auth.ListUsersWithGroup(context.TODO(), "sre")
+	c.Assert(err, check.IsNil)
+	c.Assert(users, check.HasLen, 0)
+	recorder = s.scimRequest(c, token, http.MethodGet, "/Groups/sre", "")
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestSCIMListGroupsWithFilter(c *check.C) {
+	u := auth.User{Email: "bowie@tsuru.io", Groups: []string{"sre", "dev"}}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	recorder := s.scimRequest(c, s.scimToken(c), http.MethodGet, `/Groups?filter=displayName+eq+"sre"`, "")
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var result struct {
+		TotalResults int
+		Resources    []scimGroup
+	}
+	err = json.NewDecoder(recorder.Body).Decode(&result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.TotalResults, check.Equals, 1)
+	c.Assert(result.Resources[0].DisplayName, check.Equals, "sre")
+	c.Assert(result.Resources[0].Members, check.DeepEquals, []scimValue{{Value: "bowie@tsuru.io", Display: "bowie@tsuru.io"}})
+}
+
+func (s *S) scimGroupMembers(c *check.C, token auth.Token, group string) []string {
+	recorder := s.scimRequest(c, token, http.MethodGet, "/Groups/"+group, "")
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var result scimGroup
+	err := json.NewDecoder(recorder.Body).Decode(&result)
+	c.Assert(err, check.IsNil)
+	members := make([]string, len(result.Members))
+	for i, m := range result.Members {
+		members[i] = m.Value
+	}
+	sort.Strings(members)
+	return members
+}
@@ -41,7 +41,9 @@ import (
 	_ "github.com/tsuru/tsuru/auth/native"
 	_ "github.com/tsuru/tsuru/auth/oauth"
 	_ "github.com/tsuru/tsuru/auth/oidc"
+	"github.com/tsuru/tsuru/auth/roleexpiry"
 	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/audit"
 	"github.com/tsuru/tsuru/event/webhook"
 	"github.com/tsuru/tsuru/hc"
 	"github.com/tsuru/tsuru/job"
@@ -51,6 +53,7 @@ import (
 	"github.com/tsuru/tsuru/provision/pool"
 	"github.com/tsuru/tsuru/router"
 	"github.com/tsuru/tsuru/router/rebuild"
+	"github.com/tsuru/tsuru/service"
 	"github.com/tsuru/tsuru/servicemanager"
 	"github.com/tsuru/tsuru/storage"
 	"github.com/tsuru/tsuru/tag"
@@ -149,6 +152,10 @@ func setupServices() error {
 	if err != nil {
 		return errors.Wrapf(err, "could not initialize webhook service")
 	}
+	servicemanager.Audit, err = audit.AuditService()
+	if err != nil {
+		return errors.Wrapf(err, "could not initialize audit service")
+	}
 	servicemanager.Cluster, err = cluster.ClusterService()
 	if err != nil {
 		return errors.Wrapf(err, "could not initialize cluster service")
@@ -238,9 +245,14 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.13", http.MethodDelete, "/services/{service}/instances/{instance}/apps/{app}", AuthorizationRequiredHandler(unbindServiceInstance))
 	m.Add("1.13", http.MethodPut, "/services/{service}/instances/{instance}/jobs/{job}", AuthorizationRequiredHandler(bindJobServiceInstance))
 	m.Add("1.13", http.MethodDelete, "/services/{service}/instances/{instance}/jobs/{job}", AuthorizationRequiredHandler(unbindJobServiceInstance))
+	m.Add("1.30", http.MethodPost, "/services/{service}/instances/{instance}/credentials/rotate", AuthorizationRequiredHandler(rotateServiceInstanceCredentials))
 
 	m.Add("1.0", http.MethodPut, "/services/{service}/instances/permission/{instance}/{team}", AuthorizationRequiredHandler(serviceInstanceGrantTeam))
 	m.Add("1.0", http.MethodDelete, "/services/{service}/instances/permission/{instance}/{team}", AuthorizationRequiredHandler(serviceInstanceRevokeTeam))
+	m.Add("1.30", http.MethodPut, "/services/{service}/instances/permission/{instance}/apps/{app}", AuthorizationRequiredHandler(serviceInstanceGrantApp))
+	m.Add("1.30", http.MethodDelete, "/services/{service}/instances/permission/{instance}/apps/{app}", AuthorizationRequiredHandler(serviceInstanceRevokeApp))
+	m.Add("1.30", http.MethodPut, "/services/{service}/instances/permission/{instance}/jobs/{job}", AuthorizationRequiredHandler(serviceInstanceGrantJob))
+	m.Add("1.30", http.MethodDelete, "/services/{service}/instances/permission/{instance}/jobs/{job}", AuthorizationRequiredHandler(serviceInstanceRevokeJob))
 
 	m.AddAll("1.0", "/services/{service}/proxy/{instance}", AuthorizationRequiredHandler(serviceInstanceProxy))
 	m.AddAll("1.20", "/services/{service}/resources/{instance}/{path:.*}", AuthorizationRequiredHandler(serviceInstanceProxyV2))
@@ -357,6 +369,12 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodDelete, "/users", AuthorizationRequiredHandler(removeUser))
 	m.Add("1.0", http.MethodGet, "/users/api-key", AuthorizationRequiredHandler(showAPIToken))
 	m.Add("1.0", http.MethodPost, "/users/api-key", AuthorizationRequiredHandler(regenerateAPIToken))
+	m.Add("1.30", http.MethodGet, "/users/sessions", AuthorizationRequiredHandler(listSessions))
+	m.Add("1.30", http.MethodDelete, "/users/sessions", AuthorizationRequiredHandler(revokeSessions))
+	m.Add("1.30", http.MethodDelete, "/users/sessions/{id}", AuthorizationRequiredHandler(revokeSession))
+	m.Add("1.30", http.MethodPost, "/users/mfa", AuthorizationRequiredHandler(enrollMFA))
+	m.Add("1.30", http.MethodPost, "/users/mfa/confirm", AuthorizationRequiredHandler(confirmMFA))
+	m.Add("1.30", http.MethodDelete, "/users/mfa", AuthorizationRequiredHandler(disableMFA))
 
 	m.Add("1.0", http.MethodGet, "/logs", websocket.Handler(addLogs))
 
@@ -367,6 +385,7 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.4", http.MethodGet, "/teams/{name}", AuthorizationRequiredHandler(teamInfo))
 	m.Add("1.12", http.MethodGet, "/teams/{name}/quota", AuthorizationRequiredHandler(getTeamQuota))
 	m.Add("1.12", http.MethodPut, "/teams/{name}/quota", AuthorizationRequiredHandler(changeTeamQuota))
+	m.Add("1.30", http.MethodPut, "/teams/{name}/parent", AuthorizationRequiredHandler(setTeamParent))
 	m.Add("1.17", http.MethodGet, "/teams/{name}/users", AuthorizationRequiredHandler(teamUserList))
 	m.Add("1.17", http.MethodGet, "/teams/{name}/groups", AuthorizationRequiredHandler(teamGroupList))
 
@@ -386,6 +405,8 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodPost, "/pools/{name}/team", AuthorizationRequiredHandler(addTeamToPoolHandler))
 	m.Add("1.0", http.MethodDelete, "/pools/{name}/team", AuthorizationRequiredHandler(removeTeamToPoolHandler))
 	m.Add("1.8", http.MethodGet, "/pools/{name}", AuthorizationRequiredHandler(getPoolHandler))
+	m.Add("1.30", http.MethodGet, "/pools/{name}/capacity", AuthorizationRequiredHandler(poolCapacityHandler))
+	m.Add("1.30", http.MethodGet, "/pools/{name}/nodes", AuthorizationRequiredHandler(poolNodesHandler))
 
 	m.Add("1.3", http.MethodGet, "/constraints", AuthorizationRequiredHandler(poolConstraintList))
 	m.Add("1.3", http.MethodPut, "/constraints", AuthorizationRequiredHandler(poolConstraintSet))
@@ -397,16 +418,35 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodDelete, "/roles/{name}", AuthorizationRequiredHandler(removeRole))
 	m.Add("1.0", http.MethodPost, "/roles/{name}/permissions", AuthorizationRequiredHandler(addPermissions))
 	m.Add("1.0", http.MethodDelete, "/roles/{name}/permissions/{permission}", AuthorizationRequiredHandler(removePermissions))
+	m.Add("1.30", http.MethodPost, "/roles/{name}/deny-permissions", AuthorizationRequiredHandler(addDenyPermissions))
+	m.Add("1.30", http.MethodDelete, "/roles/{name}/deny-permissions/{permission}", AuthorizationRequiredHandler(removeDenyPermissions))
+	m.Add("1.30", http.MethodPut, "/roles/{name}/mfa", AuthorizationRequiredHandler(setRoleRequireMFA))
 	m.Add("1.0", http.MethodPost, "/roles/{name}/user", AuthorizationRequiredHandler(assignRole))
 	m.Add("1.0", http.MethodDelete, "/roles/{name}/user/{email}", AuthorizationRequiredHandler(dissociateRole))
 	m.Add("1.0", http.MethodGet, "/role/default", AuthorizationRequiredHandler(listDefaultRoles))
 	m.Add("1.0", http.MethodPost, "/role/default", AuthorizationRequiredHandler(addDefaultRole))
 	m.Add("1.0", http.MethodDelete, "/role/default", AuthorizationRequiredHandler(removeDefaultRole))
 	m.Add("1.0", http.MethodGet, "/permissions", AuthorizationRequiredHandler(listPermissions))
+	m.Add("1.30", http.MethodGet, "/permissions/explain", AuthorizationRequiredHandler(explainPermission))
 	m.Add("1.6", http.MethodPost, "/roles/{name}/token", AuthorizationRequiredHandler(assignRoleToToken))
 	m.Add("1.6", http.MethodDelete, "/roles/{name}/token/{token_id}", AuthorizationRequiredHandler(dissociateRoleFromToken))
 	m.Add("1.9", http.MethodPost, "/roles/{name}/group", AuthorizationRequiredHandler(assignRoleToGroup))
 	m.Add("1.9", http.MethodDelete, "/roles/{name}/group/{group_name}", AuthorizationRequiredHandler(dissociateRoleFromGroup))
+	m.Add("1.30", http.MethodGet, "/groups", AuthorizationRequiredHandler(listGroups))
+	m.Add("1.30", http.MethodPut, "/groups/{name}/roles", AuthorizationRequiredHandler(setGroupRoles))
+	m.Add("1.30", http.MethodDelete, "/groups/{name}", AuthorizationRequiredHandler(deleteGroup))
+	m.Add("1.30", http.MethodGet, "/scim/v2/Users", AuthorizationRequiredHandler(scimHandler(scimListUsers)))
+	m.Add("1.30", http.MethodPost, "/scim/v2/Users", AuthorizationRequiredHandler(scimHandler(scimCreateUser)))
+	m.Add("1.30", http.MethodGet, "/scim/v2/Users/{id}", AuthorizationRequiredHandler(scimHandler(scimGetUser)))
+	m.Add("1.30", http.MethodPut, "/scim/v2/Users/{id}", AuthorizationRequiredHandler(scimHandler(scimReplaceUser)))
+	m.Add("1.30", http.MethodPatch, "/scim/v2/Users/{id}", AuthorizationRequiredHandler(scimHandler(scimPatchUser)))
+	m.Add("1.30", http.MethodDelete, "/scim/v2/Users/{id}", AuthorizationRequiredHandler(scimHandler(scimDeleteUser)))
+	m.Add("1.30", http.MethodGet, "/scim/v2/Groups", AuthorizationRequiredHandler(scimHandler(scimListGroups)))
+	m.Add("1.30", http.MethodPost, "/scim/v2/Groups", AuthorizationRequiredHandler(scimHandler(scimCreateGroup)))
+	m.Add("1.30", http.MethodGet, "/scim/v2/Groups/{id}", AuthorizationRequiredHandler(scimHandler(scimGetGroup)))
+	m.Add("1.30", http.MethodPut, "/scim/v2/Groups/{id}", AuthorizationRequiredHandler(scimHandler(scimReplaceGroup)))
+	m.Add("1.30", http.MethodPatch, "/scim/v2/Groups/{id}", AuthorizationRequiredHandler(scimHandler(scimPatchGroup)))
+	m.Add("1.30", http.MethodDelete, "/scim/v2/Groups/{id}", AuthorizationRequiredHandler(scimHandler(scimDeleteGroup)))
 
 	m.Add("1.0", http.MethodGet, "/debug/goroutines", AuthorizationRequiredHandler(dumpGoroutines))
 	m.Add("1.0", http.MethodGet, "/debug/pprof/", AuthorizationRequiredHandler(debugHandler(pprof.Index)))
@@ -432,6 +472,9 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.4", http.MethodPost, "/provisioner/clusters/{name}", AuthorizationRequiredHandler(updateCluster))
 	m.Add("1.3", http.MethodGet, "/provisioner/clusters", AuthorizationRequiredHandler(listClusters))
 	m.Add("1.8", http.MethodGet, "/provisioner/clusters/{name}", AuthorizationRequiredHandler(clusterInfo))
+	m.Add("1.30", http.MethodGet, "/provisioner/clusters/{name}/nodes", AuthorizationRequiredHandler(clusterNodes))
+	m.Add("1.30", http.MethodPost, "/provisioner/clusters/{name}/credentials/rotate", AuthorizationRequiredHandler(rotateClusterCredentials))
+	m.Add("1.30", http.MethodPost, "/provisioner/clusters/{name}/evacuate", AuthorizationRequiredHandler(evacuateCluster))
 	m.Add("1.3", http.MethodDelete, "/provisioner/clusters/{name}", AuthorizationRequiredHandler(deleteCluster))
 
 	m.Add("1.4", http.MethodGet, "/volumes", AuthorizationRequiredHandler(volumesList))
@@ -441,6 +484,13 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.4", http.MethodDelete, "/volumes/{name}", AuthorizationRequiredHandler(volumeDelete))
 	m.Add("1.4", http.MethodPost, "/volumes/{name}/bind", AuthorizationRequiredHandler(volumeBind))
 	m.Add("1.4", http.MethodDelete, "/volumes/{name}/bind", AuthorizationRequiredHandler(volumeUnbind))
+	m.Add("1.30", http.MethodPost, "/volumes/{name}/resize", AuthorizationRequiredHandler(volumeResize))
+	m.Add("1.30", http.MethodPut, "/volumes/{name}/team/{team}", AuthorizationRequiredHandler(volumeGrantAccess))
+	m.Add("1.30", http.MethodDelete, "/volumes/{name}/team/{team}", AuthorizationRequiredHandler(volumeRevokeAccess))
+	m.Add("1.30", http.MethodGet, "/volumes/{name}/snapshots", AuthorizationRequiredHandler(volumeSnapshotList))
+	m.Add("1.30", http.MethodPost, "/volumes/{name}/snapshots", AuthorizationRequiredHandler(volumeSnapshotCreate))
+	m.Add("1.30", http.MethodDelete, "/volumes/{name}/snapshots/{snapshot}", AuthorizationRequiredHandler(volumeSnapshotDelete))
+	m.Add("1.30", http.MethodPost, "/volumes/{name}/snapshots/{snapshot}/restore", AuthorizationRequiredHandler(volumeSnapshotRestore))
 	m.Add("1.4", http.MethodGet, "/volumeplans", AuthorizationRequiredHandler(volumePlansList))
 
 	m.Add("1.6", http.MethodGet, "/tokens", AuthorizationRequiredHandler(tokenList))
@@ -486,6 +536,7 @@ func RunServer(dry bool) http.Handler {
 		},
 	})
 	n.Use(negroni.HandlerFunc(setRequestIDHeaderMiddleware))
+	n.Use(negroni.HandlerFunc(setRequestInfoMiddleware))
 	n.Use(negroni.HandlerFunc(errorHandlingMiddleware))
 	n.Use(negroni.HandlerFunc(setVersionHeadersMiddleware))
 	n.Use(negroni.HandlerFunc(authTokenMiddleware))
@@ -582,6 +633,10 @@ func startServer(handler http.Handler) error {
 	if err != nil {
 		return errors.Wrap(err, "unable to initialize old image gc")
 	}
+	cluster.InitializeCredentialsChecker()
+	roleexpiry.Initialize()
+	service.InitializeProvisioningResumer()
+	volume.InitializeUsageCollector()
 	log.Debugf("Checking components status:")
 	results := hc.Check(ctx, "all")
 	for _, result := range results {
@@ -82,6 +82,7 @@ type serviceInput struct {
 	Password  string            `json:"password" form:"password"`
 	Endpoints map[string]string `json:"endpoints" form:"endpoints"`
 	Endpoint  string            `json:"endpoint" form:"endpoint"`
+	Protocol  string            `json:"protocol" form:"protocol"`
 }
 
 func parseService(r *http.Request) (service.Service, error) {
@@ -95,6 +96,7 @@ func parseService(r *http.Request) (service.Service, error) {
 	s.Name = inputSvc.Name
 	s.Username = inputSvc.Username
 	s.Password = inputSvc.Password
+	s.Protocol = inputSvc.Protocol
 	if len(inputSvc.Endpoints) != 0 {
 		s.Endpoint = inputSvc.Endpoints
 	} else if inputSvc.Endpoint != "" {
@@ -219,6 +221,9 @@ func serviceUpdate(w http.ResponseWriter, r *http.Request, t auth.Token) (err er
 	s.Endpoint = d.Endpoint
 	s.Password = d.Password
 	s.Username = d.Username
+	if d.Protocol != "" {
+		s.Protocol = d.Protocol
+	}
 	if len(d.OwnerTeams) != 0 {
 		s.OwnerTeams = d.OwnerTeams
 	}
@@ -46,6 +46,7 @@ func serviceIntancePermName(serviceName, instanceName string) string {
 // responses:
 //
 //	201: Service created
+//	202: Service instance is being provisioned
 //	400: Invalid data
 //	401: Unauthorized
 //	409: Service already exists
@@ -114,7 +115,12 @@ func createServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 	if err != nil {
 		return err
 	}
-	defer func() { evt.Done(ctx, err) }()
+	var provisioning bool
+	defer func() {
+		if !provisioning {
+			evt.Done(ctx, err)
+		}
+	}()
 	requestID := requestIDHeader(r)
 	err = service.CreateServiceInstance(ctx, instance, &srv, evt, requestID)
 	if err == service.ErrMultiClusterViolatingConstraint {
@@ -135,10 +141,26 @@ func createServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 			Message: err.Error(),
 		}
 	}
-	if err == nil {
-		w.WriteHeader(http.StatusCreated)
+	if paramsErr, ok := err.(*service.InvalidParametersError); ok {
+		return &tsuruErrors.ValidationError{Message: paramsErr.Error()}
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	si, err := service.GetServiceInstance(ctx, serviceName, instance.Name)
+	if err != nil {
+		return err
+	}
+	if si.IsProvisioning() {
+		// the event is finished by WaitProvisioning once the service API
+		// reports the instance as ready or failed.
+		provisioning = true
+		go si.WaitProvisioning(stdContext.WithoutCancel(ctx), evt, requestID)
+		w.WriteHeader(http.StatusAccepted)
+		return nil
+	}
+	w.WriteHeader(http.StatusCreated)
+	return nil
 }
 
 // title: service instance update
@@ -148,6 +170,7 @@ func createServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 // responses:
 //
 //	200: Service instance updated
+//	202: Service instance update accepted
 //	400: Invalid data
 //	401: Unauthorized
 //	404: Service instance not found
@@ -205,7 +228,7 @@ func updateServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 	}
 	for _, perm := range wantedPerms {
 		allowed := permission.Check(ctx, t, perm,
-			contextsForServiceInstance(si, serviceName)...,
+			contextsForServiceInstanceAccess(si, serviceName, service.AccessLevelAdmin)...,
 		)
 		if !allowed {
 			return permission.ErrUnauthorized
@@ -233,9 +256,103 @@ func updateServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 	if err != nil {
 		return err
 	}
-	defer func() { evt.Done(ctx, err) }()
+	var updating bool
+	defer func() {
+		if !updating {
+			evt.Done(ctx, err)
+		}
+	}()
 	requestID := requestIDHeader(r)
-	return si.Update(ctx, srv, *si, evt, requestID)
+	err = si.Update(ctx, srv, *si, evt, requestID)
+	if paramsErr, ok := err.(*service.InvalidParametersError); ok {
+		return &tsuruErrors.ValidationError{Message: paramsErr.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	si, err = service.GetServiceInstance(ctx, serviceName, instanceName)
+	if err != nil {
+		return err
+	}
+	if si.IsProvisioning() {
+		// the event is finished by WaitProvisioning once the service API
+		// reports the update as finished.
+		updating = true
+		go si.WaitProvisioning(stdContext.WithoutCancel(ctx), evt, requestID)
+		w.WriteHeader(http.StatusAccepted)
+	}
+	return nil
+}
+
+// title: rotate service instance credentials
+// path: /services/{service}/instances/{instance}/credentials/rotate
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/x-json-stream
+// responses:
+//
+//	200: Credentials rotated
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Service instance not found
+func rotateServiceInstanceCredentials(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	serviceName := r.URL.Query().Get(":service")
+	instanceName := r.URL.Query().Get(":instance")
+	req := struct {
+		NoRestart bool
+	}{}
+	err = ParseInput(r, &req)
+	if err != nil {
+		return err
+	}
+	si, err := getServiceInstanceOrError(ctx, serviceName, instanceName)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(ctx, t, permission.PermServiceInstanceUpdateCredentials,
+		contextsForServiceInstanceAccess(si, serviceName, service.AccessLevelAdmin)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	extraTargets := make([]eventTypes.ExtraTarget, 0, len(si.Apps)+len(si.Jobs))
+	for _, appName := range si.Apps {
+		extraTargets = append(extraTargets, eventTypes.ExtraTarget{Target: appTarget(appName)})
+	}
+	for _, jobName := range si.Jobs {
+		extraTargets = append(extraTargets, eventTypes.ExtraTarget{Target: jobTarget(jobName)})
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:       serviceInstanceTarget(serviceName, instanceName),
+		ExtraTargets: extraTargets,
+		Kind:         permission.PermServiceInstanceUpdateCredentials,
+		Owner:        t,
+		RemoteAddr:   r.RemoteAddr,
+		CustomData:   event.FormToCustomData(InputFields(r)),
+		Allowed: event.Allowed(permission.PermServiceInstanceReadEvents,
+			contextsForServiceInstance(si, serviceName)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	_, err = si.RotateCredentials(ctx, service.RotateCredentialsArgs{
+		Restart:   !req.NoRestart,
+		Writer:    evt,
+		Event:     evt,
+		RequestID: requestIDHeader(r),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(writer, "\nCredentials of instance %q were rotated.\n", instanceName)
+	return nil
 }
 
 // title: remove service instance
@@ -263,7 +380,7 @@ func removeServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
 	w.Header().Set("Content-Type", "application/x-json-stream")
 	allowed := permission.Check(ctx, t, permission.PermServiceInstanceDelete,
-		contextsForServiceInstance(serviceInstance, serviceName)...,
+		contextsForServiceInstanceAccess(serviceInstance, serviceName, service.AccessLevelAdmin)...,
 	)
 	if !allowed {
 		return permission.ErrUnauthorized
@@ -281,7 +398,12 @@ func removeServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 		return err
 	}
 	evt.SetLogWriter(writer)
-	defer func() { evt.Done(ctx, err) }()
+	var removing bool
+	defer func() {
+		if !removing {
+			evt.Done(ctx, err)
+		}
+	}()
 	requestID := requestIDHeader(r)
 	unbindAllBool, _ := strconv.ParseBool(unbindAll)
 	if unbindAllBool {
@@ -320,6 +442,15 @@ func removeServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 		}
 		return err
 	}
+	if serviceInstance.IsProvisioning() {
+		// the event is finished by WaitProvisioning once the service API
+		// reports the removal as finished.
+		evt.Write([]byte("service instance removal accepted, it will be removed once the service API finishes\n"))
+		evt.SetLogWriter(nil)
+		removing = true
+		go serviceInstance.WaitProvisioning(stdContext.WithoutCancel(ctx), evt, requestID)
+		return nil
+	}
 	evt.Write([]byte("service instance successfully removed\n"))
 	return nil
 }
@@ -449,11 +580,19 @@ func serviceInstanceStatus(w http.ResponseWriter, r *http.Request, t auth.Token)
 	if !allowed {
 		return permission.ErrUnauthorized
 	}
+	if serviceInstance.ProvisionStatus == service.ProvisionStatusFailed {
+		_, err = fmt.Fprintf(w, `Service instance "%s" failed to provision: %s`, instanceName, serviceInstance.ProvisionError)
+		return err
+	}
 	var b string
 	requestID := requestIDHeader(r)
 	if b, err = serviceInstance.Status(ctx, requestID); err != nil {
 		return errors.Wrap(err, "Could not retrieve status of service instance, error")
 	}
+	if serviceInstance.IsProvisioning() {
+		_, err = fmt.Fprintf(w, `Service instance "%s" is provisioning (%s)`, instanceName, b)
+		return err
+	}
 	_, err = fmt.Fprintf(w, `Service instance "%s" is %s`, instanceName, b)
 	return err
 }
@@ -470,6 +609,11 @@ type serviceInstanceInfo struct {
 	CustomInfo      map[string]string
 	Tags            []string
 	Parameters      map[string]interface{}
+	ProvisionStatus string
+	ProvisionError  string
+	TeamAccess      map[string]string
+	AppAccess       map[string]string
+	JobAccess       map[string]string
 }
 
 // title: service instance info
@@ -520,6 +664,11 @@ func serviceInstance(w http.ResponseWriter, r *http.Request, t auth.Token) error
 		CustomInfo:      info,
 		Tags:            serviceInstance.Tags,
 		Parameters:      serviceInstance.Parameters,
+		ProvisionStatus: serviceInstance.ProvisionStatus,
+		ProvisionError:  serviceInstance.ProvisionError,
+		TeamAccess:      serviceInstance.TeamAccess,
+		AppAccess:       serviceInstance.AppAccess,
+		JobAccess:       serviceInstance.JobAccess,
 	}
 	if sInfo.PlanName == "" {
 		sInfo.PlanName = serviceInstance.PlanName
@@ -705,7 +854,7 @@ func serviceInstanceProxy(w http.ResponseWriter, r *http.Request, t auth.Token)
 		return err
 	}
 	allowed := permission.Check(ctx, t, permission.PermServiceInstanceUpdateProxy,
-		contextsForServiceInstance(serviceInstance, serviceName)...,
+		contextsForServiceInstanceAccess(serviceInstance, serviceName, proxyAccessLevel(r))...,
 	)
 	if !allowed {
 		return permission.ErrUnauthorized
@@ -751,7 +900,7 @@ func serviceInstanceProxyV2(w http.ResponseWriter, r *http.Request, t auth.Token
 		return err
 	}
 	allowed := permission.Check(ctx, t, permission.PermServiceInstanceUpdateProxy,
-		contextsForServiceInstance(serviceInstance, serviceName)...,
+		contextsForServiceInstanceAccess(serviceInstance, serviceName, proxyAccessLevel(r))...,
 	)
 	if !allowed {
 		return permission.ErrUnauthorized
@@ -788,6 +937,7 @@ func serviceInstanceProxyV2(w http.ResponseWriter, r *http.Request, t auth.Token
 // responses:
 //
 //	200: Access granted
+//	400: Invalid access level
 //	401: Unauthorized
 //	404: Service instance not found
 func serviceInstanceGrantTeam(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
@@ -799,7 +949,7 @@ func serviceInstanceGrantTeam(w http.ResponseWriter, r *http.Request, t auth.Tok
 		return err
 	}
 	allowed := permission.Check(ctx, t, permission.PermServiceInstanceUpdateGrant,
-		contextsForServiceInstance(serviceInstance, serviceName)...,
+		contextsForServiceInstanceAccess(serviceInstance, serviceName, service.AccessLevelAdmin)...,
 	)
 	if !allowed {
 		return permission.ErrUnauthorized
@@ -818,7 +968,11 @@ func serviceInstanceGrantTeam(w http.ResponseWriter, r *http.Request, t auth.Tok
 	}
 	defer func() { evt.Done(ctx, err) }()
 	teamName := r.URL.Query().Get(":team")
-	return serviceInstance.Grant(ctx, teamName)
+	err = serviceInstance.Grant(ctx, teamName, InputValue(r, "access"))
+	if err == service.ErrInvalidAccessLevel || err == service.ErrTeamOwnerAccessLevel {
+		return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	return err
 }
 
 // title: revoke access to service instance
@@ -838,7 +992,7 @@ func serviceInstanceRevokeTeam(w http.ResponseWriter, r *http.Request, t auth.To
 		return err
 	}
 	allowed := permission.Check(ctx, t, permission.PermServiceInstanceUpdateRevoke,
-		contextsForServiceInstance(serviceInstance, serviceName)...,
+		contextsForServiceInstanceAccess(serviceInstance, serviceName, service.AccessLevelAdmin)...,
 	)
 	if !allowed {
 		return permission.ErrUnauthorized
@@ -860,6 +1014,118 @@ func serviceInstanceRevokeTeam(w http.ResponseWriter, r *http.Request, t auth.To
 	return serviceInstance.Revoke(ctx, teamName)
 }
 
+// title: grant access to service instance for an app
+// path: /services/{service}/instances/permission/{instance}/apps/{app}
+// consume: application/x-www-form-urlencoded
+// method: PUT
+// responses:
+//
+//	200: Access granted
+//	400: Invalid access level
+//	401: Unauthorized
+//	404: Service instance or app not found
+func serviceInstanceGrantApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	appName := r.URL.Query().Get(":app")
+	return serviceInstanceResourceAccess(r, t, permission.PermServiceInstanceUpdateGrant, func(si *service.ServiceInstance) error {
+		a, err := getApp(ctx, appName)
+		if err != nil {
+			return err
+		}
+		return si.GrantApp(ctx, a.Name, InputValue(r, "access"))
+	})
+}
+
+// title: revoke access to service instance for an app
+// path: /services/{service}/instances/permission/{instance}/apps/{app}
+// method: DELETE
+// responses:
+//
+//	200: Access revoked
+//	401: Unauthorized
+//	404: Service instance not found
+func serviceInstanceRevokeApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	appName := r.URL.Query().Get(":app")
+	return serviceInstanceResourceAccess(r, t, permission.PermServiceInstanceUpdateRevoke, func(si *service.ServiceInstance) error {
+		return si.RevokeApp(ctx, appName)
+	})
+}
+
+// title: grant access to service instance for a job
+// path: /services/{service}/instances/permission/{instance}/jobs/{job}
+// consume: application/x-www-form-urlencoded
+// method: PUT
+// responses:
+//
+//	200: Access granted
+//	400: Invalid access level
+//	401: Unauthorized
+//	404: Service instance or job not found
+func serviceInstanceGrantJob(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	jobName := r.URL.Query().Get(":job")
+	return serviceInstanceResourceAccess(r, t, permission.PermServiceInstanceUpdateGrant, func(si *service.ServiceInstance) error {
+		j, err := getJob(ctx, jobName)
+		if err != nil {
+			return err
+		}
+		return si.GrantJob(ctx, j.Name, InputValue(r, "access"))
+	})
+}
+
+// title: revoke access to service instance for a job
+// path: /services/{service}/instances/permission/{instance}/jobs/{job}
+// method: DELETE
+// responses:
+//
+//	200: Access revoked
+//	401: Unauthorized
+//	404: Service instance not found
+func serviceInstanceRevokeJob(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	jobName := r.URL.Query().Get(":job")
+	return serviceInstanceResourceAccess(r, t, permission.PermServiceInstanceUpdateRevoke, func(si *service.ServiceInstance) error {
+		return si.RevokeJob(ctx, jobName)
+	})
+}
+
+// serviceInstanceResourceAccess checks the permission to change the access of
+// apps and jobs to the service instance and runs fn within an event.
+func serviceInstanceResourceAccess(r *http.Request, t auth.Token, perm *permTypes.PermissionScheme, fn func(*service.ServiceInstance) error) (err error) {
+	ctx := r.Context()
+	instanceName := r.URL.Query().Get(":instance")
+	serviceName := r.URL.Query().Get(":service")
+	serviceInstance, err := getServiceInstanceOrError(ctx, serviceName, instanceName)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(ctx, t, perm,
+		contextsForServiceInstanceAccess(serviceInstance, serviceName, service.AccessLevelAdmin)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     serviceInstanceTarget(serviceName, instanceName),
+		Kind:       perm,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed: event.Allowed(permission.PermServiceInstanceReadEvents,
+			contextsForServiceInstance(serviceInstance, serviceName)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = fn(serviceInstance)
+	if err == service.ErrInvalidResourceAccessLevel {
+		return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	return err
+}
+
 func contextsForServiceInstance(si *service.ServiceInstance, serviceName string) []permTypes.PermissionContext {
 	permissionValue := serviceIntancePermName(serviceName, si.Name)
 	return append(permission.Contexts(permTypes.CtxTeam, si.Teams),
@@ -867,6 +1133,36 @@ func contextsForServiceInstance(si *service.ServiceInstance, serviceName string)
 	)
 }
 
+// proxyAccessLevel returns the access level required to proxy the request to
+// the service API: teams with read access may only issue safe requests.
+func proxyAccessLevel(r *http.Request) string {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return service.AccessLevelRead
+	}
+	return service.AccessLevelAdmin
+}
+
+// contextsForServiceInstanceAccess is like contextsForServiceInstance, but
+// only includes the teams granted, at least, the given access level.
+func contextsForServiceInstanceAccess(si *service.ServiceInstance, serviceName, level string) []permTypes.PermissionContext {
+	permissionValue := serviceIntancePermName(serviceName, si.Name)
+	return append(permission.Contexts(permTypes.CtxTeam, si.TeamsWithAccess(level)),
+		permission.Context(permTypes.CtxServiceInstance, permissionValue),
+	)
+}
+
+// provisioningError maps the errors returned when using instances still being
+// provisioned, or whose provisioning failed, to HTTP errors.
+func provisioningError(err error) error {
+	switch errors.Cause(err) {
+	case service.ErrServiceInstanceProvisioning:
+		return &tsuruErrors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	case service.ErrServiceInstanceProvisionFailed:
+		return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	return nil
+}
+
 func contextsForService(s *service.Service) []permTypes.PermissionContext {
 	return append(permission.Contexts(permTypes.CtxTeam, s.Teams),
 		permission.Context(permTypes.CtxService, s.Name),
@@ -17,6 +17,7 @@ import (
 	"sort"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/api/context"
@@ -24,6 +25,7 @@ import (
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/db/storagev2"
 	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/event/eventtest"
 	"github.com/tsuru/tsuru/io"
 	"github.com/tsuru/tsuru/permission"
@@ -511,6 +513,59 @@ func (s *ServiceInstanceSuite) TestCreateServiceInstanceReturnErrorIfTheServiceA
 	}, eventtest.HasEvent)
 }
 
+func (s *ServiceInstanceSuite) TestCreateServiceInstanceAsyncProvisioning(c *check.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	srvc := service.Service{
+		Name:       "mysqlasync",
+		Endpoint:   map[string]string{"production": ts.URL},
+		Password:   "abcde",
+		OwnerTeams: []string{s.team.Name},
+	}
+	err := service.Create(stdContext.TODO(), srvc)
+	c.Assert(err, check.IsNil)
+	params := map[string]interface{}{
+		"name":         "brainsql",
+		"service_name": "mysqlasync",
+		"owner":        s.team.Name,
+	}
+	recorder, request := makeRequestToCreateServiceInstance(params, c)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusAccepted)
+	running := true
+	timeout := time.After(5 * time.Second)
+	for running {
+		select {
+		case <-timeout:
+			c.Fatal("timeout after 5 seconds waiting for provisioning")
+		case <-time.After(50 * time.Millisecond):
+		}
+		evts, listErr := event.List(stdContext.TODO(), &event.Filter{Target: serviceInstanceTarget("mysqlasync", "brainsql"), Running: &running})
+		c.Assert(listErr, check.IsNil)
+		running = len(evts) > 0
+	}
+	c.Assert(eventtest.EventDesc{
+		Target: serviceInstanceTarget("mysqlasync", "brainsql"),
+		Owner:  s.token.GetUserName(),
+		Kind:   "service-instance.create",
+		StartCustomData: []map[string]interface{}{
+			{"name": "name", "value": "brainsql"},
+			{"name": ":service", "value": "mysqlasync"},
+			{"name": "owner", "value": s.team.Name},
+		},
+	}, eventtest.HasEvent)
+	instance, err := service.GetServiceInstance(stdContext.TODO(), "mysqlasync", "brainsql")
+	c.Assert(err, check.IsNil)
+	c.Assert(instance.ProvisionStatus, check.Equals, service.ProvisionStatusReady)
+}
+
 func (s *ServiceInstanceSuite) TestCreateInstanceWithDescription(c *check.C) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`{"DATABASE_HOST":"localhost"}`))
@@ -1008,6 +1063,47 @@ func (s *ServiceInstanceSuite) TestUpdateServiceInstancePlanParameters(c *check.
 	})
 }
 
+func (s *ServiceInstanceSuite) TestUpdateServiceInstancePlanParametersInvalidForSchema(c *check.C) {
+	var updateCalled bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && r.URL.Path == "/resources/plans" {
+			w.Write([]byte(`[{"name": "large", "schemas": {"service_instance": {"update": {"parameters": {
+				"type": "object",
+				"properties": {"replicas": {"type": "integer", "maximum": 3}}
+			}}}}}]`))
+			return
+		}
+		updateCalled = true
+	}))
+	defer ts.Close()
+	s.service.Endpoint["production"] = ts.URL
+	err := service.Update(stdContext.TODO(), *s.service)
+	c.Assert(err, check.IsNil)
+	si := service.ServiceInstance{
+		Name:        "brainsql",
+		ServiceName: "mysql",
+		Teams:       []string{s.team.Name},
+		TeamOwner:   s.team.Name,
+		PlanName:    "large",
+	}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	params := map[string]interface{}{
+		"plan":      "large",
+		"teamowner": s.team.Name,
+		"parameters": map[string]interface{}{
+			"replicas": "5",
+		},
+	}
+	recorder, request := makeRequestToUpdateServiceInstance(params, "mysql", "brainsql", s.token.GetValue(), c)
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "invalid parameters for plan \"large\":\n  - parameters.replicas: must be less than or equal to 3\n")
+	c.Assert(updateCalled, check.Equals, false)
+}
+
 func (s *ServiceInstanceSuite) TestUpdateServiceInstancePlanParametersWithoutPermission(c *check.C) {
 	si := service.ServiceInstance{
 		Name:        "brainsql",
@@ -2883,3 +2979,122 @@ func (s *ServiceInstanceSuite) TestGrantRevokeServiceToTeamWithManyInstanceName(
 	c.Assert(err, check.IsNil)
 	c.Assert(sinst.Teams, check.DeepEquals, []string{s.team.Name})
 }
+
+func (s *ServiceInstanceSuite) TestRotateServiceInstanceCredentials(c *check.C) {
+	si := service.ServiceInstance{Name: "brainsql", ServiceName: "mysql", Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodPost, "/1.30/services/mysql/instances/brainsql/credentials/rotate", strings.NewReader("noRestart=true"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
+	c.Assert(recorder.Body.String(), check.Matches, `(?s).*Credentials of instance \\"brainsql\\" were rotated.*`)
+	c.Assert(eventtest.EventDesc{
+		Target: serviceInstanceTarget("mysql", "brainsql"),
+		Owner:  s.token.GetUserName(),
+		Kind:   "service-instance.update.credentials",
+		StartCustomData: []map[string]interface{}{
+			{"name": "noRestart", "value": "true"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *ServiceInstanceSuite) TestRotateServiceInstanceCredentialsNoPermission(c *check.C) {
+	si := service.ServiceInstance{Name: "brainsql", ServiceName: "mysql"}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermServiceInstanceUpdateBind,
+		Context: permission.Context(permTypes.CtxServiceInstance, serviceIntancePermName("mysql", "brainsql")),
+	})
+	request, err := http.NewRequest(http.MethodPost, "/1.30/services/mysql/instances/brainsql/credentials/rotate", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *ServiceInstanceSuite) TestGrantServiceInstanceAccessLevel(c *check.C) {
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "mysql", TeamOwner: s.team.Name, Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	url := fmt.Sprintf("/services/mysql/instances/permission/si-test/test?:instance=si-test&:team=test&:service=mysql&access=%s", service.AccessLevelRead)
+	request, err := http.NewRequest("PUT", url, nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = serviceInstanceGrantTeam(recorder, request, s.token)
+	c.Assert(err, check.IsNil)
+	sinst, err := service.GetServiceInstance(stdContext.TODO(), si.ServiceName, si.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(sinst.Teams, check.DeepEquals, []string{s.team.Name, "test"})
+	c.Assert(sinst.TeamAccess, check.DeepEquals, map[string]string{"test": service.AccessLevelRead})
+	url = "/services/mysql/instances/permission/si-test/test?:instance=si-test&:team=test&:service=mysql&access=write"
+	request, err = http.NewRequest("PUT", url, nil)
+	c.Assert(err, check.IsNil)
+	err = serviceInstanceGrantTeam(recorder, request, s.token)
+	c.Assert(err, check.FitsTypeOf, &errors.HTTP{})
+	c.Assert(err.(*errors.HTTP).Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *ServiceInstanceSuite) TestUpdateServiceInstanceReadOnlyTeam(c *check.C) {
+	si := service.ServiceInstance{
+		Name:        "analytics",
+		ServiceName: "mysql",
+		TeamOwner:   "data",
+		Teams:       []string{"data", s.team.Name},
+		TeamAccess:  map[string]string{s.team.Name: service.AccessLevelRead},
+	}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("description=changed&teamowner=data")
+	request, err := http.NewRequest(http.MethodPut, "/services/mysql/instances/analytics", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *ServiceInstanceSuite) TestGrantServiceInstanceAppAccess(c *check.C) {
+	si := service.ServiceInstance{Name: "si-test", ServiceName: "mysql", TeamOwner: s.team.Name, Teams: []string{s.team.Name}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(stdContext.TODO(), si)
+	c.Assert(err, check.IsNil)
+	a := appTypes.App{Name: "reports", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(stdContext.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("PUT", "/1.30/services/mysql/instances/permission/si-test/apps/reports?access=read", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	sinst, err := service.GetServiceInstance(stdContext.TODO(), si.ServiceName, si.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(sinst.AppAccess, check.DeepEquals, map[string]string{"reports": service.AccessLevelRead})
+	c.Assert(sinst.CheckAppBindAccess(a.Name, a.TeamOwner), check.Equals, service.ErrAccessNotAllowsBind)
+	request, err = http.NewRequest("DELETE", "/1.30/services/mysql/instances/permission/si-test/apps/reports", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	sinst, err = service.GetServiceInstance(stdContext.TODO(), si.ServiceName, si.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(sinst.AppAccess, check.HasLen, 0)
+}
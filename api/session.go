@@ -0,0 +1,124 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+func sessionUser(r *http.Request, t auth.Token) string {
+	email := r.URL.Query().Get("user")
+	if email == "" {
+		email = t.GetUserName()
+	}
+	return email
+}
+
+// title: session list
+// path: /users/sessions
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: List sessions
+//	204: No content
+//	401: Unauthorized
+func listSessions(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	email := sessionUser(r, t)
+	if !permission.Check(ctx, t, permission.PermUserReadSessions, permission.Context(permTypes.CtxUser, email)) {
+		return permission.ErrUnauthorized
+	}
+	sessions, err := auth.ListSessions(ctx, email)
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	currentID := auth.SessionID(t.GetValue())
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(sessions)
+}
+
+// title: revoke session
+// path: /users/sessions/{id}
+// method: DELETE
+// responses:
+//
+//	200: Session revoked
+//	401: Unauthorized
+//	404: Session not found
+func revokeSession(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	email := sessionUser(r, t)
+	if !permission.Check(ctx, t, permission.PermUserUpdateSessions, permission.Context(permTypes.CtxUser, email)) {
+		return permission.ErrUnauthorized
+	}
+	id := r.URL.Query().Get(":id")
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(email),
+		Kind:       permission.PermUserUpdateSessions,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = auth.RevokeSession(ctx, email, id)
+	if err == auth.ErrSessionNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
+
+// title: revoke all sessions
+// path: /users/sessions
+// method: DELETE
+// produce: application/json
+// responses:
+//
+//	200: Sessions revoked
+//	401: Unauthorized
+func revokeSessions(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	email := sessionUser(r, t)
+	if !permission.Check(ctx, t, permission.PermUserUpdateSessions, permission.Context(permTypes.CtxUser, email)) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     userTarget(email),
+		Kind:       permission.PermUserUpdateSessions,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	revoked, err := auth.RevokeSessions(ctx, email)
+	if err != nil {
+		return err
+	}
+	evt.Logf("%d sessions revoked", revoked)
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]int{"revoked": revoked})
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission/permissiontest"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestListSessions(c *check.C) {
+	user, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "bowie")
+	request, err := http.NewRequest(http.MethodGet, "/1.30/users/sessions", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("User-Agent", "tsuru-client/1.0")
+	request.RemoteAddr = "10.0.0.1:41234"
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var sessions []auth.Session
+	err = json.NewDecoder(recorder.Body).Decode(&sessions)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 1)
+	c.Assert(sessions[0].UserEmail, check.Equals, user.Email)
+	c.Assert(sessions[0].Engine, check.Equals, "native")
+	c.Assert(sessions[0].ClientIP, check.Equals, "10.0.0.1")
+	c.Assert(sessions[0].UserAgent, check.Equals, "tsuru-client/1.0")
+	c.Assert(sessions[0].Current, check.Equals, true)
+}
+
+func (s *S) TestListSessionsOtherUserUnauthorized(c *check.C) {
+	token := userWithPermission(c)
+	request, err := http.NewRequest(http.MethodGet, "/1.30/users/sessions?user="+s.user.Email, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestRevokeSession(c *check.C) {
+	user, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "bowie")
+	err := auth.TrackSession(context.TODO(), token, auth.SessionInfo{})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodDelete, "/1.30/users/sessions/"+auth.SessionID(token.GetValue())+"?user="+user.Email, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(user.Email),
+		Owner:  s.token.GetUserName(),
+		Kind:   "user.update.sessions",
+	}, eventtest.HasEvent)
+	request, err = http.NewRequest(http.MethodGet, "/1.0/users/info", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+}
+
+func (s *S) TestRevokeSessionNotFound(c *check.C) {
+	request, err := http.NewRequest(http.MethodDelete, "/1.30/users/sessions/unknown", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestRevokeSessions(c *check.C) {
+	user, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "bowie")
+	err := auth.TrackSession(context.TODO(), token, auth.SessionInfo{})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodDelete, "/1.30/users/sessions?user="+user.Email, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Equals, "{\"revoked\":1}\n")
+	sessions, err := auth.ListSessions(context.TODO(), user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 0)
+}
+
+func (s *S) TestLogoutRevokesSession(c *check.C) {
+	user, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "bowie")
+	request, err := http.NewRequest(http.MethodDelete, "/1.0/users/tokens", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	sessions, err := auth.ListSessions(context.TODO(), user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 0)
+}
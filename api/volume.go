@@ -14,9 +14,11 @@ import (
 	"github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
 	tsuruIo "github.com/tsuru/tsuru/io"
+	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/provision/pool"
 	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
 	eventTypes "github.com/tsuru/tsuru/types/event"
 	permTypes "github.com/tsuru/tsuru/types/permission"
 	volumeTypes "github.com/tsuru/tsuru/types/volume"
@@ -49,6 +51,16 @@ func contextsForVolume(v *volumeTypes.Volume) []permTypes.PermissionContext {
 	}
 }
 
+// contextsForVolumeBind also includes the teams granted access to the
+// volume, allowing their members to bind and unbind it.
+func contextsForVolumeBind(v *volumeTypes.Volume) []permTypes.PermissionContext {
+	contexts := contextsForVolume(v)
+	for _, t := range v.Teams {
+		contexts = append(contexts, permission.Context(permTypes.CtxTeam, t.Team))
+	}
+	return contexts
+}
+
 // title: volume list
 // path: /volumes
 // method: GET
@@ -103,6 +115,10 @@ func volumeInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if err != nil {
 		return err
 	}
+	v.Usage, err = servicemanager.Volume.Usage(ctx, v)
+	if err != nil {
+		log.Errorf("unable to get usage of volume %q: %v", v.Name, err)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(&v)
 }
@@ -281,13 +297,16 @@ func volumeDelete(w http.ResponseWriter, r *http.Request, t auth.Token) (err err
 // responses:
 //
 //	200: Volume binded
+//	400: App or job in a pool of another cluster
 //	401: Unauthorized
+//	403: Team not allowed to bind the volume
 //	404: Volume not found
 //	409: Volume bind already exists
 func volumeBind(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	ctx := r.Context()
 	var bindInfo struct {
 		App        string
+		Job        string
 		MountPoint string
 		ReadOnly   bool
 		NoRestart  bool
@@ -303,10 +322,17 @@ func volumeBind(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 		}
 		return err
 	}
-	canBindVolume := permission.Check(ctx, t, permission.PermVolumeUpdateBind, contextsForVolume(dbVolume)...)
+	canBindVolume := permission.Check(ctx, t, permission.PermVolumeUpdateBind, contextsForVolumeBind(dbVolume)...)
 	if !canBindVolume {
 		return permission.ErrUnauthorized
 	}
+	if bindInfo.Job != "" {
+		return volumeBindJob(r, t, dbVolume, &volumeTypes.BindOpts{
+			JobName:    bindInfo.Job,
+			MountPoint: bindInfo.MountPoint,
+			ReadOnly:   bindInfo.ReadOnly,
+		})
+	}
 	a, err := getAppFromContext(bindInfo.App, r)
 	if err != nil {
 		return err
@@ -330,12 +356,19 @@ func volumeBind(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	err = servicemanager.Volume.BindApp(ctx, &volumeTypes.BindOpts{
 		Volume:     dbVolume,
 		AppName:    bindInfo.App,
+		TeamOwner:  a.TeamOwner,
+		Pool:       a.Pool,
 		MountPoint: bindInfo.MountPoint,
 		ReadOnly:   bindInfo.ReadOnly,
 	})
 	if err != nil || bindInfo.NoRestart {
-		if err == volumeTypes.ErrVolumeAlreadyBound {
+		switch err {
+		case volumeTypes.ErrVolumeAlreadyBound:
 			return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+		case volumeTypes.ErrVolumeBindNotAllowed, volumeTypes.ErrVolumeBindReadOnly:
+			return &errors.HTTP{Code: http.StatusForbidden, Message: err.Error()}
+		case volumeTypes.ErrVolumeBindOtherCluster:
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
 		}
 		return err
 	}
@@ -360,6 +393,7 @@ func volumeUnbind(w http.ResponseWriter, r *http.Request, t auth.Token) (err err
 	ctx := r.Context()
 	var bindInfo struct {
 		App        string
+		Job        string
 		MountPoint string
 		NoRestart  bool
 	}
@@ -374,10 +408,16 @@ func volumeUnbind(w http.ResponseWriter, r *http.Request, t auth.Token) (err err
 		}
 		return err
 	}
-	canUnbind := permission.Check(ctx, t, permission.PermVolumeUpdateUnbind, contextsForVolume(dbVolume)...)
+	canUnbind := permission.Check(ctx, t, permission.PermVolumeUpdateUnbind, contextsForVolumeBind(dbVolume)...)
 	if !canUnbind {
 		return permission.ErrUnauthorized
 	}
+	if bindInfo.Job != "" {
+		return volumeUnbindJob(r, t, dbVolume, &volumeTypes.BindOpts{
+			JobName:    bindInfo.Job,
+			MountPoint: bindInfo.MountPoint,
+		})
+	}
 	a, err := getAppFromContext(bindInfo.App, r)
 	if err != nil {
 		return err
@@ -416,3 +456,452 @@ func volumeUnbind(w http.ResponseWriter, r *http.Request, t auth.Token) (err err
 	evt.SetLogWriter(writer)
 	return app.Restart(ctx, a, "", "", evt)
 }
+
+// volumeBindJob binds the volume to a job and updates the job in the
+// provisioner, so the volume is mounted in its next runs.
+func volumeBindJob(r *http.Request, t auth.Token, dbVolume *volumeTypes.Volume, opts *volumeTypes.BindOpts) (err error) {
+	ctx := r.Context()
+	j, err := getJob(ctx, opts.JobName)
+	if err != nil {
+		return err
+	}
+	canUpdateJob := permission.Check(ctx, t, permission.PermJobUpdate, contextsForJob(j)...)
+	if !canUpdateJob {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: dbVolume.Name},
+		ExtraTargets: []eventTypes.ExtraTarget{{Target: jobTarget(j.Name)}},
+		Kind:         permission.PermVolumeUpdateBind,
+		Owner:        t,
+		RemoteAddr:   r.RemoteAddr,
+		CustomData:   event.FormToCustomData(InputFields(r)),
+		Allowed:      event.Allowed(permission.PermVolumeReadEvents, contextsForVolume(dbVolume)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	opts.Volume = dbVolume
+	opts.TeamOwner = j.TeamOwner
+	opts.Pool = j.Pool
+	err = servicemanager.Volume.BindJob(ctx, opts)
+	switch err {
+	case nil:
+	case volumeTypes.ErrVolumeAlreadyBound:
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	case volumeTypes.ErrVolumeBindNotAllowed, volumeTypes.ErrVolumeBindReadOnly:
+		return &errors.HTTP{Code: http.StatusForbidden, Message: err.Error()}
+	case volumeTypes.ErrVolumeBindOtherCluster:
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	default:
+		return err
+	}
+	return servicemanager.Job.UpdateJobProv(ctx, j)
+}
+
+func volumeUnbindJob(r *http.Request, t auth.Token, dbVolume *volumeTypes.Volume, opts *volumeTypes.BindOpts) (err error) {
+	ctx := r.Context()
+	j, err := getJob(ctx, opts.JobName)
+	if err != nil {
+		return err
+	}
+	canUpdateJob := permission.Check(ctx, t, permission.PermJobUpdate, contextsForJob(j)...)
+	if !canUpdateJob {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: dbVolume.Name},
+		ExtraTargets: []eventTypes.ExtraTarget{{Target: jobTarget(j.Name)}},
+		Kind:         permission.PermVolumeUpdateUnbind,
+		Owner:        t,
+		RemoteAddr:   r.RemoteAddr,
+		CustomData:   event.FormToCustomData(InputFields(r)),
+		Allowed:      event.Allowed(permission.PermVolumeReadEvents, contextsForVolume(dbVolume)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	opts.Volume = dbVolume
+	err = servicemanager.Volume.UnbindJob(ctx, opts)
+	if err != nil {
+		if err == volumeTypes.ErrVolumeBindNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	return servicemanager.Job.UpdateJobProv(ctx, j)
+}
+
+// title: grant access to a volume
+// path: /volumes/{name}/team/{team}
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// responses:
+//
+//	200: Access granted
+//	400: Team not found
+//	401: Unauthorized
+//	404: Volume not found
+//	409: Team already has access to this volume
+func volumeGrantAccess(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	var accessInfo struct {
+		ReadOnly bool
+	}
+	err = ParseInput(r, &accessInfo)
+	if err != nil {
+		return err
+	}
+	dbVolume, err := servicemanager.Volume.Get(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		if err == volumeTypes.ErrVolumeNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	canGrant := permission.Check(ctx, t, permission.PermVolumeUpdateGrantAccess, contextsForVolume(dbVolume)...)
+	if !canGrant {
+		return permission.ErrUnauthorized
+	}
+	team, err := servicemanager.Team.FindByName(ctx, r.URL.Query().Get(":team"))
+	if err != nil {
+		if err == authTypes.ErrTeamNotFound {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: "Team not found"}
+		}
+		return err
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: dbVolume.Name},
+		Kind:       permission.PermVolumeUpdateGrantAccess,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermVolumeReadEvents, contextsForVolume(dbVolume)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = servicemanager.Volume.GrantAccess(ctx, dbVolume, team.Name, accessInfo.ReadOnly)
+	switch err {
+	case volumeTypes.ErrVolumeTeamAlreadyAllowed, volumeTypes.ErrVolumeTeamOwnerAccess:
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	return err
+}
+
+// title: revoke access to a volume
+// path: /volumes/{name}/team/{team}
+// method: DELETE
+// responses:
+//
+//	200: Access revoked
+//	401: Unauthorized
+//	404: Volume not found
+//	409: Team does not have access to this volume
+func volumeRevokeAccess(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	dbVolume, err := servicemanager.Volume.Get(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		if err == volumeTypes.ErrVolumeNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	canRevoke := permission.Check(ctx, t, permission.PermVolumeUpdateRevokeAccess, contextsForVolume(dbVolume)...)
+	if !canRevoke {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: dbVolume.Name},
+		Kind:       permission.PermVolumeUpdateRevokeAccess,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermVolumeReadEvents, contextsForVolume(dbVolume)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = servicemanager.Volume.RevokeAccess(ctx, dbVolume, r.URL.Query().Get(":team"))
+	switch err {
+	case volumeTypes.ErrVolumeTeamNotAllowed, volumeTypes.ErrVolumeTeamOwnerAccess:
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	return err
+}
+
+// title: volume resize
+// path: /volumes/{name}/resize
+// method: POST
+// produce: application/x-json-stream
+// responses:
+//
+//	200: Volume resized
+//	400: Invalid capacity or resize not supported
+//	401: Unauthorized
+//	404: Volume not found
+func volumeResize(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	var resizeInfo struct {
+		Capacity string
+	}
+	err = ParseInput(r, &resizeInfo)
+	if err != nil {
+		return err
+	}
+	if resizeInfo.Capacity == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "capacity is required"}
+	}
+	dbVolume, err := servicemanager.Volume.Get(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		if err == volumeTypes.ErrVolumeNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	canResize := permission.Check(ctx, t, permission.PermVolumeUpdateResize, contextsForVolume(dbVolume)...)
+	if !canResize {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: dbVolume.Name},
+		Kind:       permission.PermVolumeUpdateResize,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermVolumeReadEvents, contextsForVolume(dbVolume)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	err = servicemanager.Volume.Resize(ctx, &volumeTypes.ResizeOpts{
+		Volume:   dbVolume,
+		Capacity: resizeInfo.Capacity,
+		Writer:   evt,
+	})
+	switch err {
+	case volumeTypes.ErrResizeNotSupported, volumeTypes.ErrVolumeShrinkNotAllowed, volumeTypes.ErrVolumeNotProvisioned:
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	return err
+}
+
+func volumeSnapshotHTTPError(err error) error {
+	switch err {
+	case volumeTypes.ErrVolumeSnapshotNotFound:
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	case volumeTypes.ErrSnapshotNotSupported, volumeTypes.ErrVolumeNotProvisioned:
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	case volumeTypes.ErrVolumeSnapshotNotReady:
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	return err
+}
+
+// title: volume snapshot list
+// path: /volumes/{name}/snapshots
+// method: GET
+// produce: application/json
+// responses:
+//
+//	200: List volume snapshots
+//	204: No content
+//	400: Volume snapshots not supported
+//	401: Unauthorized
+//	404: Volume not found
+func volumeSnapshotList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	dbVolume, err := servicemanager.Volume.Get(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		if err == volumeTypes.ErrVolumeNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	canRead := permission.Check(ctx, t, permission.PermVolumeRead, contextsForVolume(dbVolume)...)
+	if !canRead {
+		return permission.ErrUnauthorized
+	}
+	snapshots, err := servicemanager.Volume.ListSnapshots(ctx, dbVolume)
+	if err != nil {
+		return volumeSnapshotHTTPError(err)
+	}
+	if len(snapshots) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(snapshots)
+}
+
+// title: volume snapshot create
+// path: /volumes/{name}/snapshots
+// method: POST
+// produce: application/json
+// responses:
+//
+//	201: Volume snapshot created
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Volume not found
+func volumeSnapshotCreate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	var snapshotInfo struct {
+		Name string
+	}
+	err = ParseInput(r, &snapshotInfo)
+	if err != nil {
+		return err
+	}
+	dbVolume, err := servicemanager.Volume.Get(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		if err == volumeTypes.ErrVolumeNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	canSnapshot := permission.Check(ctx, t, permission.PermVolumeUpdateSnapshot, contextsForVolume(dbVolume)...)
+	if !canSnapshot {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: dbVolume.Name},
+		Kind:       permission.PermVolumeUpdateSnapshot,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermVolumeReadEvents, contextsForVolume(dbVolume)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	snapshot, err := servicemanager.Volume.CreateSnapshot(ctx, dbVolume, snapshotInfo.Name)
+	if err != nil {
+		return volumeSnapshotHTTPError(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(snapshot)
+}
+
+// title: volume snapshot delete
+// path: /volumes/{name}/snapshots/{snapshot}
+// method: DELETE
+// produce: application/json
+// responses:
+//
+//	200: Volume snapshot deleted
+//	401: Unauthorized
+//	404: Volume or snapshot not found
+func volumeSnapshotDelete(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	dbVolume, err := servicemanager.Volume.Get(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		if err == volumeTypes.ErrVolumeNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	canSnapshot := permission.Check(ctx, t, permission.PermVolumeUpdateSnapshot, contextsForVolume(dbVolume)...)
+	if !canSnapshot {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:     eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: dbVolume.Name},
+		Kind:       permission.PermVolumeUpdateSnapshot,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermVolumeReadEvents, contextsForVolume(dbVolume)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	err = servicemanager.Volume.DeleteSnapshot(ctx, dbVolume, r.URL.Query().Get(":snapshot"))
+	if err != nil {
+		return volumeSnapshotHTTPError(err)
+	}
+	return nil
+}
+
+// title: volume snapshot restore
+// path: /volumes/{name}/snapshots/{snapshot}/restore
+// method: POST
+// produce: application/json
+// responses:
+//
+//	201: Volume created from snapshot
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Volume or snapshot not found
+//	409: Volume already exists or snapshot not ready
+func volumeSnapshotRestore(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	var inputVolume volumeTypes.Volume
+	err = ParseInput(r, &inputVolume)
+	if err != nil {
+		return err
+	}
+	inputVolume.Plan.Opts = nil
+	inputVolume.Status = ""
+	inputVolume.SnapshotSource = nil
+	dbVolume, err := servicemanager.Volume.Get(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		if err == volumeTypes.ErrVolumeNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	canSnapshot := permission.Check(ctx, t, permission.PermVolumeUpdateSnapshot, contextsForVolume(dbVolume)...)
+	if !canSnapshot {
+		return permission.ErrUnauthorized
+	}
+	if inputVolume.TeamOwner == "" {
+		inputVolume.TeamOwner = dbVolume.TeamOwner
+	}
+	if inputVolume.Pool == "" {
+		inputVolume.Pool = dbVolume.Pool
+	}
+	canCreate := permission.Check(ctx, t, permission.PermVolumeCreate,
+		permission.Context(permTypes.CtxTeam, inputVolume.TeamOwner),
+		permission.Context(permTypes.CtxPool, inputVolume.Pool),
+	)
+	if !canCreate {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(ctx, &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: inputVolume.Name},
+		ExtraTargets: []eventTypes.ExtraTarget{{Target: eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: dbVolume.Name}}},
+		Kind:         permission.PermVolumeCreate,
+		Owner:        t,
+		RemoteAddr:   r.RemoteAddr,
+		CustomData:   event.FormToCustomData(InputFields(r)),
+		Allowed:      event.Allowed(permission.PermVolumeReadEvents, contextsForVolume(&inputVolume)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(ctx, err) }()
+	_, err = servicemanager.Volume.Get(ctx, inputVolume.Name)
+	if err == nil {
+		return &errors.HTTP{Code: http.StatusConflict, Message: "volume already exists"}
+	}
+	err = servicemanager.Volume.RestoreSnapshot(ctx, dbVolume, r.URL.Query().Get(":snapshot"), &inputVolume)
+	if err != nil {
+		return volumeSnapshotHTTPError(err)
+	}
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
@@ -7,6 +7,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	stdErrors "errors"
 	"net/http"
 	"net/http/httptest"
 	"sort"
@@ -14,13 +15,19 @@ import (
 
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event/eventtest"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/permission/permissiontest"
+	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/provision/provisiontest"
 	"github.com/tsuru/tsuru/servicemanager"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	authTypes "github.com/tsuru/tsuru/types/auth"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
 	permTypes "github.com/tsuru/tsuru/types/permission"
 	volumeTypes "github.com/tsuru/tsuru/types/volume"
 	check "gopkg.in/check.v1"
@@ -326,6 +333,53 @@ func (s *S) TestVolumeInfo(c *check.C) {
 	})
 }
 
+func (s *S) TestVolumeInfoWithUsage(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	s.mockService.VolumeService.OnBinds = func(ctx context.Context, v *volumeTypes.Volume) ([]volumeTypes.VolumeBind, error) {
+		return []volumeTypes.VolumeBind{}, nil
+	}
+	usage := &volumeTypes.VolumeUsage{CapacityBytes: 100, UsedBytes: 40, AvailableBytes: 60, Inodes: 10, InodesUsed: 1, InodesFree: 9}
+	s.mockService.VolumeService.OnUsage = func(ctx context.Context, v *volumeTypes.Volume) (*volumeTypes.VolumeUsage, error) {
+		c.Assert(v.Name, check.Equals, "v1")
+		return usage, nil
+	}
+	request, err := http.NewRequest("GET", "/1.4/volumes/v1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var result volumeTypes.Volume
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Usage, check.DeepEquals, usage)
+}
+
+func (s *S) TestVolumeInfoUsageError(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	s.mockService.VolumeService.OnBinds = func(ctx context.Context, v *volumeTypes.Volume) ([]volumeTypes.VolumeBind, error) {
+		return []volumeTypes.VolumeBind{}, nil
+	}
+	s.mockService.VolumeService.OnUsage = func(ctx context.Context, v *volumeTypes.Volume) (*volumeTypes.VolumeUsage, error) {
+		return nil, stdErrors.New("kubelet unreachable")
+	}
+	request, err := http.NewRequest("GET", "/1.4/volumes/v1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var result volumeTypes.Volume
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Name, check.Equals, "v1")
+	c.Assert(result.Usage, check.IsNil)
+}
+
 func (s *S) TestVolumeInfoNotFound(c *check.C) {
 	s.mockService.VolumeService.OnGet = func(ctx context.Context, appName string) (*volumeTypes.Volume, error) {
 		return nil, volumeTypes.ErrVolumeNotFound
@@ -878,3 +932,476 @@ func (s *S) TestVolumeUnbindNoRestart(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusOK)
 	c.Assert(recorder.Body.String(), check.Equals, "")
 }
+
+func (s *S) TestVolumeSnapshotList(c *check.C) {
+	v1 := volumeTypes.Volume{Name: "v1", Pool: s.Pool, TeamOwner: s.team.Name, Plan: volumeTypes.VolumePlan{Name: "nfs"}}
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &v1, nil
+	}
+	s.mockService.VolumeService.OnListSnapshots = func(ctx context.Context, v *volumeTypes.Volume) ([]volumeTypes.VolumeSnapshot, error) {
+		c.Assert(v.Name, check.Equals, "v1")
+		return []volumeTypes.VolumeSnapshot{{Name: "daily", Volume: "v1", ReadyToUse: true, RestoreSize: "1Gi"}}, nil
+	}
+	request, err := http.NewRequest("GET", "/1.30/volumes/v1/snapshots", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var result []volumeTypes.VolumeSnapshot
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, []volumeTypes.VolumeSnapshot{{Name: "daily", Volume: "v1", ReadyToUse: true, RestoreSize: "1Gi"}})
+}
+
+func (s *S) TestVolumeSnapshotListNotSupported(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	s.mockService.VolumeService.OnListSnapshots = func(ctx context.Context, v *volumeTypes.Volume) ([]volumeTypes.VolumeSnapshot, error) {
+		return nil, volumeTypes.ErrSnapshotNotSupported
+	}
+	request, err := http.NewRequest("GET", "/1.30/volumes/v1/snapshots", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, volumeTypes.ErrSnapshotNotSupported.Error()+"\n")
+}
+
+func (s *S) TestVolumeSnapshotCreate(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	var created string
+	s.mockService.VolumeService.OnCreateSnapshot = func(ctx context.Context, v *volumeTypes.Volume, name string) (*volumeTypes.VolumeSnapshot, error) {
+		created = name
+		return &volumeTypes.VolumeSnapshot{Name: name, Volume: v.Name}, nil
+	}
+	body := strings.NewReader(`name=daily`)
+	request, err := http.NewRequest("POST", "/1.30/volumes/v1/snapshots", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	c.Assert(created, check.Equals, "daily")
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: "v1"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "volume.update.snapshot",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":name", "value": "v1"},
+			{"name": "name", "value": "daily"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestVolumeSnapshotCreateUnauthorized(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermVolumeRead,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	body := strings.NewReader(`name=daily`)
+	request, err := http.NewRequest("POST", "/1.30/volumes/v1/snapshots", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestVolumeSnapshotDeleteNotFound(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	s.mockService.VolumeService.OnDeleteSnapshot = func(ctx context.Context, v *volumeTypes.Volume, name string) error {
+		c.Assert(name, check.Equals, "weekly")
+		return volumeTypes.ErrVolumeSnapshotNotFound
+	}
+	request, err := http.NewRequest("DELETE", "/1.30/volumes/v1/snapshots/weekly", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestVolumeSnapshotRestore(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		if name != "v1" {
+			return nil, volumeTypes.ErrVolumeNotFound
+		}
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name, Plan: volumeTypes.VolumePlan{Name: "nfs"}}, nil
+	}
+	var restored *volumeTypes.Volume
+	s.mockService.VolumeService.OnRestoreSnapshot = func(ctx context.Context, v *volumeTypes.Volume, snapshotName string, newVolume *volumeTypes.Volume) error {
+		c.Assert(v.Name, check.Equals, "v1")
+		c.Assert(snapshotName, check.Equals, "daily")
+		restored = newVolume
+		return nil
+	}
+	body := strings.NewReader(`name=v2`)
+	request, err := http.NewRequest("POST", "/1.30/volumes/v1/snapshots/daily/restore", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	c.Assert(restored, check.NotNil)
+	c.Assert(restored.Name, check.Equals, "v2")
+	c.Assert(restored.Pool, check.Equals, s.Pool)
+	c.Assert(restored.TeamOwner, check.Equals, s.team.Name)
+}
+
+func (s *S) TestVolumeSnapshotRestoreNotReady(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		if name != "v1" {
+			return nil, volumeTypes.ErrVolumeNotFound
+		}
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	s.mockService.VolumeService.OnRestoreSnapshot = func(ctx context.Context, v *volumeTypes.Volume, snapshotName string, newVolume *volumeTypes.Volume) error {
+		return volumeTypes.ErrVolumeSnapshotNotReady
+	}
+	body := strings.NewReader(`name=v2`)
+	request, err := http.NewRequest("POST", "/1.30/volumes/v1/snapshots/daily/restore", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+}
+
+func (s *S) TestVolumeResize(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	s.mockService.VolumeService.OnResize = func(ctx context.Context, opts *volumeTypes.ResizeOpts) error {
+		c.Assert(opts.Volume.Name, check.Equals, "v1")
+		c.Assert(opts.Capacity, check.Equals, "30Gi")
+		opts.Writer.Write([]byte("resized\n"))
+		return nil
+	}
+	body := strings.NewReader(`capacity=30Gi`)
+	request, err := http.NewRequest("POST", "/1.30/volumes/v1/resize", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
+	c.Assert(recorder.Body.String(), check.Matches, `\{"Message":"resized\\n","Timestamp":".*"\}\n`)
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: "v1"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "volume.update.resize",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":name", "value": "v1"},
+			{"name": "capacity", "value": "30Gi"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestVolumeResizeShrink(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	s.mockService.VolumeService.OnResize = func(ctx context.Context, opts *volumeTypes.ResizeOpts) error {
+		return volumeTypes.ErrVolumeShrinkNotAllowed
+	}
+	body := strings.NewReader(`capacity=1Gi`)
+	request, err := http.NewRequest("POST", "/1.30/volumes/v1/resize", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Matches, `.*`+volumeTypes.ErrVolumeShrinkNotAllowed.Error()+`.*`)
+}
+
+func (s *S) TestVolumeResizeMissingCapacity(c *check.C) {
+	request, err := http.NewRequest("POST", "/1.30/volumes/v1/resize", strings.NewReader(""))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "capacity is required\n")
+}
+
+func (s *S) TestVolumeBindForbiddenTeam(c *check.C) {
+	v1 := volumeTypes.Volume{Name: "v1", Pool: s.Pool, TeamOwner: s.team.Name, Plan: volumeTypes.VolumePlan{Name: "nfs"}}
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, appName string) (*volumeTypes.Volume, error) {
+		return &v1, nil
+	}
+	s.mockService.VolumeService.OnBindApp = func(ctx context.Context, opts *volumeTypes.BindOpts) error {
+		c.Assert(opts.TeamOwner, check.Equals, s.team.Name)
+		return volumeTypes.ErrVolumeBindReadOnly
+	}
+	a := appTypes.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader(`app=myapp&mountpoint=/mnt1&readonly=false&norestart=true`)
+	request, err := http.NewRequest("POST", "/1.4/volumes/v1/bind", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Body.String(), check.Equals, volumeTypes.ErrVolumeBindReadOnly.Error()+"\n")
+}
+
+func (s *S) TestVolumeGrantAccess(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: "otherteam"}, nil
+	}
+	var granted []volumeTypes.VolumeTeamAccess
+	s.mockService.VolumeService.OnGrantAccess = func(ctx context.Context, v *volumeTypes.Volume, team string, readOnly bool) error {
+		granted = append(granted, volumeTypes.VolumeTeamAccess{Team: team, ReadOnly: readOnly})
+		return nil
+	}
+	body := strings.NewReader(`readonly=true`)
+	request, err := http.NewRequest("PUT", "/1.30/volumes/v1/team/"+s.team.Name, body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(granted, check.DeepEquals, []volumeTypes.VolumeTeamAccess{{Team: s.team.Name, ReadOnly: true}})
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: "v1"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "volume.update.grant-access",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":name", "value": "v1"},
+			{"name": ":team", "value": s.team.Name},
+			{"name": "readonly", "value": "true"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestVolumeGrantAccessTeamNotFound(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	s.mockService.Team.OnFindByName = func(_ string) (*authTypes.Team, error) {
+		return nil, authTypes.ErrTeamNotFound
+	}
+	request, err := http.NewRequest("PUT", "/1.30/volumes/v1/team/unknown", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "Team not found\n")
+}
+
+func (s *S) TestVolumeGrantAccessConflict(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	request, err := http.NewRequest("PUT", "/1.30/volumes/v1/team/"+s.team.Name, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	c.Assert(recorder.Body.String(), check.Equals, volumeTypes.ErrVolumeTeamOwnerAccess.Error()+"\n")
+}
+
+func (s *S) TestVolumeRevokeAccess(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{
+			Name:      name,
+			Pool:      s.Pool,
+			TeamOwner: s.team.Name,
+			Teams:     []volumeTypes.VolumeTeamAccess{{Team: "otherteam"}},
+		}, nil
+	}
+	var revoked *volumeTypes.Volume
+	s.mockService.VolumeService.OnRevokeAccess = func(ctx context.Context, v *volumeTypes.Volume, team string) error {
+		revoked = v
+		return v.RevokeAccess(team)
+	}
+	request, err := http.NewRequest("DELETE", "/1.30/volumes/v1/team/otherteam", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(revoked.Teams, check.HasLen, 0)
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: "v1"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "volume.update.revoke-access",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":name", "value": "v1"},
+			{"name": ":team", "value": "otherteam"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestVolumeRevokeAccessNotAllowed(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	request, err := http.NewRequest("DELETE", "/1.30/volumes/v1/team/otherteam", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+}
+
+func (s *S) TestVolumeGrantAccessUnauthorized(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: "otherteam"}, nil
+	}
+	token := userWithPermission(c, permTypes.Permission{
+		Scheme:  permission.PermVolumeUpdateBind,
+		Context: permission.Context(permTypes.CtxTeam, "otherteam"),
+	})
+	request, err := http.NewRequest("PUT", "/1.30/volumes/v1/team/"+s.team.Name, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) createVolumeJob(c *check.C) (*jobTypes.Job, func()) {
+	oldProvisioner := provision.DefaultProvisioner
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	cleanup := func() {
+		provision.DefaultProvisioner = oldProvisioner
+		provision.Unregister("jobProv")
+	}
+	j := jobTypes.Job{
+		Name:      "myjob",
+		TeamOwner: s.team.Name,
+		Pool:      s.Pool,
+		Spec: jobTypes.JobSpec{
+			Schedule:  "* * * * *",
+			Container: jobTypes.ContainerInfo{OriginalImageSrc: "busybox:1.28"},
+		},
+	}
+	user, _ := auth.ConvertOldUser(s.user, nil)
+	err := servicemanager.Job.CreateJob(context.TODO(), &j, user)
+	c.Assert(err, check.IsNil)
+	return &j, cleanup
+}
+
+func (s *S) TestVolumeBindJob(c *check.C) {
+	j, cleanup := s.createVolumeJob(c)
+	defer cleanup()
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	var bindOpts *volumeTypes.BindOpts
+	s.mockService.VolumeService.OnBindJob = func(ctx context.Context, opts *volumeTypes.BindOpts) error {
+		bindOpts = opts
+		return nil
+	}
+	body := strings.NewReader(`job=myjob&mountpoint=/data&readonly=true`)
+	request, err := http.NewRequest("POST", "/1.4/volumes/v1/bind", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(bindOpts, check.NotNil)
+	c.Assert(bindOpts.Volume.Name, check.Equals, "v1")
+	c.Assert(bindOpts.JobName, check.Equals, j.Name)
+	c.Assert(bindOpts.AppName, check.Equals, "")
+	c.Assert(bindOpts.MountPoint, check.Equals, "/data")
+	c.Assert(bindOpts.ReadOnly, check.Equals, true)
+	c.Assert(bindOpts.TeamOwner, check.Equals, s.team.Name)
+	c.Assert(bindOpts.Pool, check.Equals, j.Pool)
+	c.Assert(eventtest.EventDesc{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeVolume, Value: "v1"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "volume.update.bind",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":name", "value": "v1"},
+			{"name": "job", "value": "myjob"},
+			{"name": "mountpoint", "value": "/data"},
+			{"name": "readonly", "value": "true"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestVolumeBindJobOtherCluster(c *check.C) {
+	_, cleanup := s.createVolumeJob(c)
+	defer cleanup()
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: "otherpool", TeamOwner: s.team.Name}, nil
+	}
+	s.mockService.VolumeService.OnBindJob = func(ctx context.Context, opts *volumeTypes.BindOpts) error {
+		return volumeTypes.ErrVolumeBindOtherCluster
+	}
+	body := strings.NewReader(`job=myjob&mountpoint=/data`)
+	request, err := http.NewRequest("POST", "/1.4/volumes/v1/bind", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, volumeTypes.ErrVolumeBindOtherCluster.Error()+"\n")
+}
+
+func (s *S) TestVolumeBindJobNotFound(c *check.C) {
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	body := strings.NewReader(`job=unknown&mountpoint=/data`)
+	request, err := http.NewRequest("POST", "/1.4/volumes/v1/bind", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestVolumeUnbindJob(c *check.C) {
+	j, cleanup := s.createVolumeJob(c)
+	defer cleanup()
+	s.mockService.VolumeService.OnGet = func(ctx context.Context, name string) (*volumeTypes.Volume, error) {
+		return &volumeTypes.Volume{Name: name, Pool: s.Pool, TeamOwner: s.team.Name}, nil
+	}
+	var unbindOpts *volumeTypes.BindOpts
+	s.mockService.VolumeService.OnUnbindJob = func(ctx context.Context, opts *volumeTypes.BindOpts) error {
+		unbindOpts = opts
+		return nil
+	}
+	body := strings.NewReader(`job=myjob&mountpoint=/data`)
+	request, err := http.NewRequest("DELETE", "/1.4/volumes/v1/bind", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(unbindOpts, check.NotNil)
+	c.Assert(unbindOpts.JobName, check.Equals, j.Name)
+	c.Assert(unbindOpts.MountPoint, check.Equals, "/data")
+}
@@ -689,7 +689,7 @@ func unbindVolumes(ctx context.Context, app *appTypes.App) error {
 	}
 	for _, v := range volumes {
 		var binds []volumeTypes.VolumeBind
-		binds, err = servicemanager.Volume.Binds(ctx, &v)
+		binds, err = servicemanager.Volume.BindsForApp(ctx, &v, app.Name)
 		if err != nil {
 			return errors.Wrap(err, "Unable to list volume binds for unbind")
 		}
@@ -37,7 +37,13 @@ func (t *APIToken) Engine() string {
 }
 
 func (t *APIToken) Permissions(ctx context.Context) ([]permTypes.Permission, error) {
-	return BaseTokenPermission(ctx, t)
+	u, err := GetUserByEmail(ctx, t.UserEmail)
+	if err != nil {
+		return nil, err
+	}
+	// API keys outlive the session used to retrieve them and are never
+	// verified with a second factor, so roles requiring MFA don't apply.
+	return userTokenPermission(ctx, u, false)
 }
 
 func APIAuth(ctx context.Context, header string) (*APIToken, error) {
@@ -50,7 +56,7 @@ func APIAuth(ctx context.Context, header string) (*APIToken, error) {
 	if err != nil {
 		return nil, err
 	}
-	err = usersCollection.FindOne(ctx, mongoBSON.M{"apikey": token}).Decode(&t)
+	err = usersCollection.FindOne(ctx, mongoBSON.M{"apikey": token, "disabled": mongoBSON.M{"$ne": true}}).Decode(&t)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, ErrInvalidToken
@@ -7,6 +7,9 @@ package auth
 import (
 	"context"
 
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
 	check "gopkg.in/check.v1"
 )
 
@@ -36,3 +39,34 @@ func (s *S) TestAPIAuthNotFound(c *check.C) {
 	c.Assert(t, check.IsNil)
 	c.Assert(err, check.Equals, ErrInvalidToken)
 }
+
+func (s *S) TestAPIAuthDisabledUser(c *check.C) {
+	user := User{Email: "para@xmen.com", Disabled: true}
+	err := user.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	APIKey, err := user.RegenerateAPIKey(context.TODO())
+	c.Assert(err, check.IsNil)
+	t, err := APIAuth(context.TODO(), "bearer "+APIKey)
+	c.Assert(t, check.IsNil)
+	c.Assert(err, check.Equals, ErrInvalidToken)
+}
+
+func (s *S) TestAPITokenPermissionsRequireMFA(c *check.C) {
+	user := User{Email: "para@xmen.com", MFA: &authTypes.MFA{Enabled: true, Secret: "secret"}}
+	err := user.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(context.TODO(), "pool.update")
+	c.Assert(err, check.IsNil)
+	err = r1.SetRequireMFA(context.TODO(), true)
+	c.Assert(err, check.IsNil)
+	err = user.AddRole(context.TODO(), "pool-admin", "prod")
+	c.Assert(err, check.IsNil)
+	t := &APIToken{Token: "my-token", UserEmail: user.Email}
+	perms, err := t.Permissions(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(perms, check.DeepEquals, []permTypes.Permission{
+		{Scheme: permission.PermUser, Context: permission.Context(permTypes.CtxUser, user.Email)},
+	})
+}
@@ -0,0 +1,312 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"sort"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// PermissionGrant is a role assignment including a permission, either given
+// directly or through a group.
+type PermissionGrant struct {
+	Role         string `json:",omitempty"`
+	Group        string `json:",omitempty"`
+	Permission   string
+	ContextType  permTypes.ContextType
+	ContextValue string `json:",omitempty"`
+	// InheritedFrom is the ancestor team the permission was assigned on,
+	// when it only applies through the team hierarchy.
+	InheritedFrom string `json:",omitempty"`
+	// RequireMFA is set when the role only applies to MFA verified tokens.
+	RequireMFA bool `json:",omitempty"`
+}
+
+// RoleSuggestion is a role that would grant a permission if assigned in one
+// of the context values listed.
+type RoleSuggestion struct {
+	Role          string
+	Permission    string
+	ContextType   permTypes.ContextType
+	ContextValues []string `json:",omitempty"`
+}
+
+// PermissionExplanation describes why a permission check is allowed or
+// denied for a user or team token.
+type PermissionExplanation struct {
+	Subject    string
+	Permission string
+	Contexts   []permTypes.PermissionContext
+	Allowed    bool
+	// Disabled is set when the user is disabled, disabled users have no
+	// permissions at all.
+	Disabled bool `json:",omitempty"`
+	// PolicyDenied is set when the roles allow the action but the
+	// authorization webhook denies it.
+	PolicyDenied bool              `json:",omitempty"`
+	Groups       []string          `json:",omitempty"`
+	Teams        []string          `json:",omitempty"`
+	Granted      []PermissionGrant `json:",omitempty"`
+	Denied       []PermissionGrant `json:",omitempty"`
+	// MFARequired lists the grants of roles requiring MFA which don't apply
+	// because the token isn't MFA verified.
+	MFARequired    []PermissionGrant `json:",omitempty"`
+	OtherContexts  []PermissionGrant `json:",omitempty"`
+	SuggestedRoles []RoleSuggestion  `json:",omitempty"`
+}
+
+// userSession is equivalent to a login session of the user, it's used to
+// explain the permissions of users other than the requester. The session is
+// MFA verified when the user is enrolled.
+type userSession struct {
+	user *User
+}
+
+func (s *userSession) GetValue() string {
+	return ""
+}
+
+func (s *userSession) GetUserName() string {
+	return s.user.Email
+}
+
+func (s *userSession) User(ctx context.Context) (*authTypes.User, error) {
+	return ConvertOldUser(s.user, nil)
+}
+
+func (s *userSession) Engine() string {
+	name, _ := config.GetString("auth:scheme")
+	if name == "" {
+		name = "native"
+	}
+	return name
+}
+
+func (s *userSession) Permissions(ctx context.Context) ([]permTypes.Permission, error) {
+	return userTokenPermission(ctx, s.user, s.user.MFAEnabled())
+}
+
+// ExplainPermission explains the permission check for a login session of the
+// user.
+func (u *User) ExplainPermission(ctx context.Context, scheme *permTypes.PermissionScheme, contexts ...permTypes.PermissionContext) (*PermissionExplanation, error) {
+	return ExplainTokenPermission(ctx, &userSession{user: u}, scheme, contexts...)
+}
+
+// ExplainTeamTokenPermission is the same as ExplainTokenPermission for a
+// stored team token.
+func ExplainTeamTokenPermission(ctx context.Context, token authTypes.TeamToken, scheme *permTypes.PermissionScheme, contexts ...permTypes.PermissionContext) (*PermissionExplanation, error) {
+	t := teamToken(token)
+	return ExplainTokenPermission(ctx, &t, scheme, contexts...)
+}
+
+// ExplainTokenPermission checks the permission with permission.Check,
+// reporting the role assignments behind the token permissions involved in
+// the decision.
+func ExplainTokenPermission(ctx context.Context, token Token, scheme *permTypes.PermissionScheme, contexts ...permTypes.PermissionContext) (*PermissionExplanation, error) {
+	explanation := &PermissionExplanation{
+		Subject:    token.GetUserName(),
+		Permission: schemeName(scheme),
+		Contexts:   contexts,
+	}
+	perms, err := token.Permissions(ctx)
+	if err == ErrUserDisabled {
+		explanation.Disabled = true
+		return explanation, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	u, err := ConvertNewUser(token.User(ctx))
+	if err != nil {
+		return nil, err
+	}
+	var granted []grantedPermission
+	if u.FromToken {
+		granted, err = expandRoleGrants(ctx, u.roleAssignments(nil), true)
+	} else {
+		var groups []authTypes.Group
+		groups, err = u.UserGroups()
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			explanation.Groups = append(explanation.Groups, group.Name)
+		}
+		granted, err = expandRoleGrants(ctx, u.roleAssignments(groups), true)
+	}
+	if err != nil {
+		return nil, err
+	}
+	explanation.addGrants(perms, granted, scheme, contexts)
+	explanation.Allowed = permission.Check(ctx, token, scheme, contexts...)
+	rolesAllowed := permission.CheckFromPermList(perms, scheme, contexts...)
+	explanation.PolicyDenied = rolesAllowed && !explanation.Allowed
+	if rolesAllowed || len(explanation.Denied) > 0 {
+		return explanation, nil
+	}
+	suggestions, err := suggestRoles(ctx, scheme, contexts)
+	if err != nil {
+		return nil, err
+	}
+	explanation.SuggestedRoles = suggestions
+	return explanation, nil
+}
+
+type permissionKey struct {
+	scheme  string
+	ctxType permTypes.ContextType
+	value   string
+	deny    bool
+}
+
+func keyFor(perm permTypes.Permission) permissionKey {
+	return permissionKey{
+		scheme:  perm.Scheme.FullName(),
+		ctxType: perm.Context.CtxType,
+		value:   perm.Context.Value,
+		deny:    perm.Deny,
+	}
+}
+
+// addGrants explains the token permissions, attributing each one to the role
+// assignments granting it. Grants of roles requiring MFA are only reported as
+// such when they're missing from the token permissions.
+func (e *PermissionExplanation) addGrants(perms []permTypes.Permission, granted []grantedPermission, scheme *permTypes.PermissionScheme, contexts []permTypes.PermissionContext) {
+	present := map[permissionKey]struct{}{}
+	for _, perm := range perms {
+		present[keyFor(perm)] = struct{}{}
+	}
+	mfaApplied := true
+	for _, g := range granted {
+		if _, ok := present[keyFor(g.Permission)]; g.grant.RequireMFA && !ok {
+			mfaApplied = false
+			break
+		}
+	}
+	attributions := map[permissionKey][]PermissionGrant{}
+	teams := map[string]struct{}{}
+	for _, g := range granted {
+		if g.grant.RequireMFA && !mfaApplied {
+			if g.Scheme.IsParent(scheme) && permission.CheckFromPermList([]permTypes.Permission{{Scheme: g.Scheme, Context: g.Context}}, scheme, contexts...) {
+				e.MFARequired = append(e.MFARequired, explainedGrant(g.grant, g.Permission))
+			}
+			continue
+		}
+		key := keyFor(g.Permission)
+		attributions[key] = append(attributions[key], g.grant)
+		if g.Context.CtxType == permTypes.CtxTeam && g.grant.InheritedFrom == "" && g.grant.Role != "" {
+			teams[g.Context.Value] = struct{}{}
+		}
+	}
+	for team := range teams {
+		e.Teams = append(e.Teams, team)
+	}
+	sort.Strings(e.Teams)
+	seen := map[permissionKey]struct{}{}
+	for _, perm := range perms {
+		key := keyFor(perm)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		grants := attributions[key]
+		if len(grants) == 0 {
+			grants = []PermissionGrant{{}}
+		}
+		for _, grant := range grants {
+			e.addGrant(grant, perm, scheme, contexts)
+		}
+	}
+}
+
+func explainedGrant(grant PermissionGrant, perm permTypes.Permission) PermissionGrant {
+	grant.Permission = schemeName(perm.Scheme)
+	grant.ContextType = perm.Context.CtxType
+	grant.ContextValue = perm.Context.Value
+	return grant
+}
+
+func (e *PermissionExplanation) addGrant(grant PermissionGrant, perm permTypes.Permission, scheme *permTypes.PermissionScheme, contexts []permTypes.PermissionContext) {
+	if !perm.Scheme.IsParent(scheme) {
+		return
+	}
+	grant = explainedGrant(grant, perm)
+	if perm.Deny {
+		// Denies in other contexts are not relevant to the decision.
+		if permission.CheckFromPermList([]permTypes.Permission{{Scheme: perm.Scheme, Context: perm.Context}}, scheme, contexts...) {
+			e.Denied = append(e.Denied, grant)
+		}
+		return
+	}
+	if permission.CheckFromPermList([]permTypes.Permission{perm}, scheme, contexts...) {
+		e.Granted = append(e.Granted, grant)
+	} else {
+		e.OtherContexts = append(e.OtherContexts, grant)
+	}
+}
+
+// suggestRoles lists the roles including the permission that may be assigned
+// in one of the checked contexts.
+func suggestRoles(ctx context.Context, scheme *permTypes.PermissionScheme, contexts []permTypes.PermissionContext) ([]RoleSuggestion, error) {
+	roles, err := permission.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var suggestions []RoleSuggestion
+	for _, role := range roles {
+		var roleScheme *permTypes.PermissionScheme
+		for _, perm := range role.PermissionsFor("") {
+			if perm.Scheme.IsParent(scheme) {
+				roleScheme = perm.Scheme
+				break
+			}
+		}
+		if roleScheme == nil || roleDenies(role, scheme) {
+			continue
+		}
+		suggestion := RoleSuggestion{
+			Role:        role.Name,
+			Permission:  schemeName(roleScheme),
+			ContextType: role.ContextType,
+		}
+		if role.ContextType != permTypes.CtxGlobal {
+			for _, c := range contexts {
+				if c.CtxType == role.ContextType {
+					suggestion.ContextValues = append(suggestion.ContextValues, c.Value)
+				}
+			}
+			if len(suggestion.ContextValues) == 0 {
+				continue
+			}
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].Role < suggestions[j].Role
+	})
+	return suggestions, nil
+}
+
+func roleDenies(role permission.Role, scheme *permTypes.PermissionScheme) bool {
+	for _, perm := range role.DenyPermissionsFor("") {
+		if perm.Scheme.IsParent(scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+func schemeName(scheme *permTypes.PermissionScheme) string {
+	name := scheme.FullName()
+	if name == "" {
+		return "*"
+	}
+	return name
+}
@@ -0,0 +1,247 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestUserExplainPermissionAllowed(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123", Groups: []string{"g1"}}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole(context.TODO(), "team-member", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(context.TODO(), "app")
+	c.Assert(err, check.IsNil)
+	r2, err := permission.NewRole(context.TODO(), "deployer", "app", "")
+	c.Assert(err, check.IsNil)
+	err = r2.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(context.TODO(), "team-member", "myteam")
+	c.Assert(err, check.IsNil)
+	err = servicemanager.AuthGroup.AddRole(context.TODO(), "g1", "deployer", "otherapp")
+	c.Assert(err, check.IsNil)
+	explanation, err := u.ExplainPermission(context.TODO(), permission.PermAppDeploy,
+		permission.Context(permTypes.CtxApp, "myapp"),
+		permission.Context(permTypes.CtxTeam, "myteam"),
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation, check.DeepEquals, &PermissionExplanation{
+		Subject:    "me@tsuru.com",
+		Permission: "app.deploy",
+		Contexts: []permTypes.PermissionContext{
+			permission.Context(permTypes.CtxApp, "myapp"),
+			permission.Context(permTypes.CtxTeam, "myteam"),
+		},
+		Allowed: true,
+		Groups:  []string{"g1"},
+		Teams:   []string{"myteam"},
+		Granted: []PermissionGrant{
+			{Role: "team-member", Permission: "app", ContextType: permTypes.CtxTeam, ContextValue: "myteam"},
+		},
+		OtherContexts: []PermissionGrant{
+			{Role: "deployer", Group: "g1", Permission: "app.deploy", ContextType: permTypes.CtxApp, ContextValue: "otherapp"},
+		},
+	})
+}
+
+func (s *S) TestUserExplainPermissionDenied(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole(context.TODO(), "deployer", "app", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	r2, err := permission.NewRole(context.TODO(), "team-member", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r2.AddPermissions(context.TODO(), "app")
+	c.Assert(err, check.IsNil)
+	r3, err := permission.NewRole(context.TODO(), "admin", "global", "")
+	c.Assert(err, check.IsNil)
+	err = r3.AddPermissions(context.TODO(), "*")
+	c.Assert(err, check.IsNil)
+	r4, err := permission.NewRole(context.TODO(), "pool-reader", "pool", "")
+	c.Assert(err, check.IsNil)
+	err = r4.AddPermissions(context.TODO(), "pool.read")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(context.TODO(), "deployer", "otherapp")
+	c.Assert(err, check.IsNil)
+	explanation, err := u.ExplainPermission(context.TODO(), permission.PermAppDeploy,
+		permission.Context(permTypes.CtxApp, "myapp"),
+		permission.Context(permTypes.CtxTeam, "myteam"),
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Allowed, check.Equals, false)
+	c.Assert(explanation.Granted, check.IsNil)
+	c.Assert(explanation.OtherContexts, check.DeepEquals, []PermissionGrant{
+		{Role: "deployer", Permission: "app.deploy", ContextType: permTypes.CtxApp, ContextValue: "otherapp"},
+	})
+	c.Assert(explanation.SuggestedRoles, check.DeepEquals, []RoleSuggestion{
+		{Role: "admin", Permission: "*", ContextType: permTypes.CtxGlobal},
+		{Role: "deployer", Permission: "app.deploy", ContextType: permTypes.CtxApp, ContextValues: []string{"myapp"}},
+		{Role: "team-member", Permission: "app", ContextType: permTypes.CtxTeam, ContextValues: []string{"myteam"}},
+	})
+}
+
+func (s *S) TestUserExplainPermissionDeniedByRule(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole(context.TODO(), "contractor", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(context.TODO(), "app")
+	c.Assert(err, check.IsNil)
+	err = r1.AddDenyPermissions(context.TODO(), "app.run.shell")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(context.TODO(), "contractor", "myteam")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(context.TODO(), "contractor", "otherteam")
+	c.Assert(err, check.IsNil)
+	explanation, err := u.ExplainPermission(context.TODO(), permission.PermAppRunShell,
+		permission.Context(permTypes.CtxTeam, "myteam"),
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Allowed, check.Equals, false)
+	c.Assert(explanation.Granted, check.DeepEquals, []PermissionGrant{
+		{Role: "contractor", Permission: "app", ContextType: permTypes.CtxTeam, ContextValue: "myteam"},
+	})
+	c.Assert(explanation.Denied, check.DeepEquals, []PermissionGrant{
+		{Role: "contractor", Permission: "app.run.shell", ContextType: permTypes.CtxTeam, ContextValue: "myteam"},
+	})
+	c.Assert(explanation.SuggestedRoles, check.IsNil)
+	explanation, err = u.ExplainPermission(context.TODO(), permission.PermAppDeploy,
+		permission.Context(permTypes.CtxTeam, "myteam"),
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Allowed, check.Equals, true)
+	c.Assert(explanation.Denied, check.IsNil)
+}
+
+func (s *S) TestUserExplainPermissionSelf(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	explanation, err := u.ExplainPermission(context.TODO(), permission.PermUserUpdatePassword,
+		permission.Context(permTypes.CtxUser, "me@tsuru.com"),
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Allowed, check.Equals, true)
+	c.Assert(explanation.Granted, check.DeepEquals, []PermissionGrant{
+		{Permission: "user", ContextType: permTypes.CtxUser, ContextValue: "me@tsuru.com"},
+	})
+	c.Assert(explanation.SuggestedRoles, check.IsNil)
+}
+
+func (s *S) TestExplainTeamTokenPermission(c *check.C) {
+	r1, err := permission.NewRole(context.TODO(), "deployer", "app", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	token := authTypes.TeamToken{
+		TokenID: "mytoken",
+		Team:    "myteam",
+		Roles:   []authTypes.RoleInstance{{Name: "deployer", ContextValue: "myapp"}},
+	}
+	explanation, err := ExplainTeamTokenPermission(context.TODO(), token, permission.PermAppDeploy,
+		permission.Context(permTypes.CtxApp, "myapp"),
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation, check.DeepEquals, &PermissionExplanation{
+		Subject:    "mytoken",
+		Permission: "app.deploy",
+		Contexts:   []permTypes.PermissionContext{permission.Context(permTypes.CtxApp, "myapp")},
+		Allowed:    true,
+		Granted: []PermissionGrant{
+			{Role: "deployer", Permission: "app.deploy", ContextType: permTypes.CtxApp, ContextValue: "myapp"},
+		},
+	})
+}
+
+func (s *S) TestUserExplainPermissionRequireMFA(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole(context.TODO(), "deployer", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	err = r1.SetRequireMFA(context.TODO(), true)
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(context.TODO(), "deployer", "myteam")
+	c.Assert(err, check.IsNil)
+	explanation, err := u.ExplainPermission(context.TODO(), permission.PermAppDeploy,
+		permission.Context(permTypes.CtxTeam, "myteam"),
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Allowed, check.Equals, false)
+	c.Assert(explanation.Granted, check.IsNil)
+	c.Assert(explanation.MFARequired, check.DeepEquals, []PermissionGrant{
+		{Role: "deployer", Permission: "app.deploy", ContextType: permTypes.CtxTeam, ContextValue: "myteam", RequireMFA: true},
+	})
+	u.MFA = &authTypes.MFA{Enabled: true}
+	explanation, err = u.ExplainPermission(context.TODO(), permission.PermAppDeploy,
+		permission.Context(permTypes.CtxTeam, "myteam"),
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Allowed, check.Equals, true)
+	c.Assert(explanation.MFARequired, check.IsNil)
+	c.Assert(explanation.Granted, check.DeepEquals, []PermissionGrant{
+		{Role: "deployer", Permission: "app.deploy", ContextType: permTypes.CtxTeam, ContextValue: "myteam", RequireMFA: true},
+	})
+}
+
+func (s *S) TestUserExplainPermissionDisabled(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123", Disabled: true}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	explanation, err := u.ExplainPermission(context.TODO(), permission.PermUserUpdatePassword,
+		permission.Context(permTypes.CtxUser, "me@tsuru.com"),
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation, check.DeepEquals, &PermissionExplanation{
+		Subject:    "me@tsuru.com",
+		Permission: "user.update.password",
+		Contexts:   []permTypes.PermissionContext{permission.Context(permTypes.CtxUser, "me@tsuru.com")},
+		Disabled:   true,
+	})
+}
+
+func (s *S) TestUserExplainPermissionPolicyDenied(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result": {"allowed": false}}`))
+	}))
+	defer srv.Close()
+	config.Set("authorization:webhook:url", srv.URL)
+	config.Set("authorization:webhook:permissions", []interface{}{"app.deploy"})
+	defer config.Unset("authorization")
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole(context.TODO(), "deployer", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(context.TODO(), "deployer", "myteam")
+	c.Assert(err, check.IsNil)
+	explanation, err := u.ExplainPermission(context.TODO(), permission.PermAppDeploy,
+		permission.Context(permTypes.CtxTeam, "myteam"),
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Allowed, check.Equals, false)
+	c.Assert(explanation.PolicyDenied, check.Equals, true)
+	c.Assert(explanation.Granted, check.HasLen, 1)
+	c.Assert(explanation.SuggestedRoles, check.IsNil)
+}
@@ -57,3 +57,31 @@ func (s *groupService) RemoveRole(ctx context.Context, name, roleName, contextVa
 	}
 	return s.storage.RemoveRole(ctx, name, roleName, contextValue)
 }
+
+func (s *groupService) SetRoles(ctx context.Context, name string, roles []authTypes.RoleInstance) error {
+	if name == "" {
+		return errGroupNameEmpty
+	}
+	var uniqueRoles []authTypes.RoleInstance
+	seen := map[authTypes.RoleInstance]struct{}{}
+	for _, role := range roles {
+		role = authTypes.RoleInstance{Name: role.Name, ContextValue: role.ContextValue}
+		if _, ok := seen[role]; ok {
+			continue
+		}
+		seen[role] = struct{}{}
+		_, err := permission.FindRole(ctx, role.Name)
+		if err != nil {
+			return err
+		}
+		uniqueRoles = append(uniqueRoles, role)
+	}
+	return s.storage.SetRoles(ctx, name, uniqueRoles)
+}
+
+func (s *groupService) Delete(ctx context.Context, name string) error {
+	if name == "" {
+		return errGroupNameEmpty
+	}
+	return s.storage.Delete(ctx, name)
+}
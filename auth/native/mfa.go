@@ -0,0 +1,184 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/errors"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultMFAIssuer   = "tsuru"
+	recoveryCodesCount = 10
+	// totpPeriod and totpSkew match the defaults used by totp.Validate, a
+	// code is accepted in the current time step and in the adjacent ones.
+	totpPeriod = 30
+	totpSkew   = 1
+)
+
+var (
+	ErrMFARequired       = auth.AuthenticationFailure{Message: "Authentication failed, a multi-factor authentication code is required."}
+	ErrInvalidMFACode    = auth.AuthenticationFailure{Message: "Authentication failed, invalid multi-factor authentication code."}
+	ErrMFAAlreadyEnabled = &errors.ConflictError{Message: "multi-factor authentication is already enabled"}
+	ErrMFANotEnrolled    = &errors.ValidationError{Message: "multi-factor authentication enrollment was not started"}
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func (s NativeScheme) EnrollMFA(ctx context.Context, user *auth.User) (*auth.MFAEnrollment, error) {
+	if user.MFAEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	issuer, _ := config.GetString("auth:mfa:issuer")
+	if issuer == "" {
+		issuer = defaultMFAIssuer
+	}
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: issuer, AccountName: user.Email})
+	if err != nil {
+		return nil, err
+	}
+	mfa := &authTypes.MFA{Secret: key.Secret()}
+	err = updateMFA(ctx, user, mongoBSON.M{"$set": mongoBSON.M{"mfa": mfa}})
+	if err != nil {
+		return nil, err
+	}
+	user.MFA = mfa
+	return &auth.MFAEnrollment{Secret: key.Secret(), URL: key.URL()}, nil
+}
+
+func (s NativeScheme) ConfirmMFA(ctx context.Context, user *auth.User, code string) ([]string, error) {
+	if user.MFAEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.MFA == nil || user.MFA.Secret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+	step, ok := totpStep(user.MFA.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+	codes := make([]string, recoveryCodesCount)
+	hashes := make([]string, recoveryCodesCount)
+	for i := range codes {
+		var data [10]byte
+		if _, err := rand.Read(data[:]); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(data[:]))
+		codes[i] = code[:8] + "-" + code[8:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	err := updateMFA(ctx, user, mongoBSON.M{"$set": mongoBSON.M{"mfa.enabled": true, "mfa.recoverycodes": hashes, "mfa.laststep": step}})
+	if err != nil {
+		return nil, err
+	}
+	user.MFA.Enabled = true
+	user.MFA.RecoveryCodes = hashes
+	user.MFA.LastStep = step
+	return codes, nil
+}
+
+func (s NativeScheme) DisableMFA(ctx context.Context, user *auth.User) error {
+	err := updateMFA(ctx, user, mongoBSON.M{"$unset": mongoBSON.M{"mfa": ""}})
+	if err != nil {
+		return err
+	}
+	user.MFA = nil
+	return nil
+}
+
+func (s NativeScheme) VerifyMFA(ctx context.Context, user *auth.User, code string) error {
+	if !user.MFAEnabled() {
+		return ErrMFANotEnrolled
+	}
+	return validateMFACode(ctx, user, code)
+}
+
+// validateMFACode checks a one-time password or a recovery code. Both are
+// single use: recovery codes are discarded and one-time passwords are only
+// accepted for time steps newer than the last one accepted.
+func validateMFACode(ctx context.Context, user *auth.User, code string) error {
+	if code == "" {
+		return ErrMFARequired
+	}
+	collection, err := storagev2.UsersCollection()
+	if err != nil {
+		return err
+	}
+	if step, ok := totpStep(user.MFA.Secret, code, time.Now()); ok {
+		result, err := collection.UpdateOne(ctx, mongoBSON.M{
+			"email":        user.Email,
+			"mfa.laststep": mongoBSON.M{"$not": mongoBSON.M{"$gte": step}},
+		}, mongoBSON.M{
+			"$set": mongoBSON.M{"mfa.laststep": step},
+		})
+		if err != nil {
+			return err
+		}
+		if result.ModifiedCount == 0 {
+			return ErrInvalidMFACode
+		}
+		user.MFA.LastStep = step
+		return nil
+	}
+	result, err := collection.UpdateOne(ctx, mongoBSON.M{
+		"email":             user.Email,
+		"mfa.recoverycodes": hashRecoveryCode(code),
+	}, mongoBSON.M{
+		"$pull": mongoBSON.M{"mfa.recoverycodes": hashRecoveryCode(code)},
+	})
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// totpStep returns the time step matching a one-time password, within the
+// skew accepted around t.
+func totpStep(secret, code string, t time.Time) (int64, bool) {
+	current := t.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		expected, err := totp.GenerateCode(secret, time.Unix(step*totpPeriod, 0))
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func updateMFA(ctx context.Context, user *auth.User, update mongoBSON.M) error {
+	collection, err := storagev2.UsersCollection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"email": user.Email}, update)
+	return err
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/tsuru/tsuru/auth"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) enableMFA(c *check.C) (string, []string) {
+	scheme := NativeScheme{}
+	enrollment, err := scheme.EnrollMFA(context.TODO(), s.user)
+	c.Assert(err, check.IsNil)
+	code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+	c.Assert(err, check.IsNil)
+	recoveryCodes, err := scheme.ConfirmMFA(context.TODO(), s.user, code)
+	c.Assert(err, check.IsNil)
+	return enrollment.Secret, recoveryCodes
+}
+
+func (s *S) TestEnrollMFA(c *check.C) {
+	enrollment, err := NativeScheme{}.EnrollMFA(context.TODO(), s.user)
+	c.Assert(err, check.IsNil)
+	c.Assert(enrollment.Secret, check.Not(check.Equals), "")
+	c.Assert(enrollment.URL, check.Matches, "otpauth://totp/tsuru:timeredbull@globo.com.*")
+	u, err := auth.GetUserByEmail(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.MFA.Secret, check.Equals, enrollment.Secret)
+	c.Assert(u.MFAEnabled(), check.Equals, false)
+}
+
+func (s *S) TestConfirmMFA(c *check.C) {
+	_, recoveryCodes := s.enableMFA(c)
+	c.Assert(recoveryCodes, check.HasLen, recoveryCodesCount)
+	u, err := auth.GetUserByEmail(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.MFAEnabled(), check.Equals, true)
+	c.Assert(u.MFA.RecoveryCodes, check.HasLen, recoveryCodesCount)
+	c.Assert(u.MFA.RecoveryCodes[0], check.Not(check.Equals), recoveryCodes[0])
+}
+
+func (s *S) TestConfirmMFAInvalidCode(c *check.C) {
+	scheme := NativeScheme{}
+	_, err := scheme.EnrollMFA(context.TODO(), s.user)
+	c.Assert(err, check.IsNil)
+	_, err = scheme.ConfirmMFA(context.TODO(), s.user, "000000x")
+	c.Assert(err, check.Equals, ErrInvalidMFACode)
+	u, err := auth.GetUserByEmail(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.MFAEnabled(), check.Equals, false)
+}
+
+func (s *S) TestConfirmMFANotEnrolled(c *check.C) {
+	_, err := NativeScheme{}.ConfirmMFA(context.TODO(), s.user, "123456")
+	c.Assert(err, check.Equals, ErrMFANotEnrolled)
+}
+
+func (s *S) TestEnrollMFAAlreadyEnabled(c *check.C) {
+	s.enableMFA(c)
+	_, err := NativeScheme{}.EnrollMFA(context.TODO(), s.user)
+	c.Assert(err, check.Equals, ErrMFAAlreadyEnabled)
+}
+
+func (s *S) TestNativeLoginMFA(c *check.C) {
+	secret, _ := s.enableMFA(c)
+	scheme := NativeScheme{}
+	params := map[string]string{"email": s.user.Email, "password": "123456"}
+	_, err := scheme.Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrMFARequired)
+	params["otp"] = "000000x"
+	_, err = scheme.Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrInvalidMFACode)
+	params["otp"], err = totp.GenerateCode(secret, time.Now().Add(totpPeriod*time.Second))
+	c.Assert(err, check.IsNil)
+	token, err := scheme.Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+	c.Assert(token.(*Token).MFA, check.Equals, true)
+}
+
+func (s *S) TestNativeLoginMFAReplayedCode(c *check.C) {
+	secret, _ := s.enableMFA(c)
+	scheme := NativeScheme{}
+	code, err := totp.GenerateCode(secret, time.Now().Add(totpPeriod*time.Second))
+	c.Assert(err, check.IsNil)
+	params := map[string]string{"email": s.user.Email, "password": "123456", "otp": code}
+	_, err = scheme.Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+	_, err = scheme.Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrInvalidMFACode)
+	u, err := auth.GetUserByEmail(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.MFA.LastStep, check.Not(check.Equals), int64(0))
+}
+
+func (s *S) TestNativeLoginMFARecoveryCode(c *check.C) {
+	_, recoveryCodes := s.enableMFA(c)
+	scheme := NativeScheme{}
+	params := map[string]string{"email": s.user.Email, "password": "123456", "otp": recoveryCodes[0]}
+	_, err := scheme.Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+	_, err = scheme.Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrInvalidMFACode)
+	u, err := auth.GetUserByEmail(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.MFA.RecoveryCodes, check.HasLen, recoveryCodesCount-1)
+}
+
+func (s *S) TestDisableMFA(c *check.C) {
+	s.enableMFA(c)
+	err := NativeScheme{}.DisableMFA(context.TODO(), s.user)
+	c.Assert(err, check.IsNil)
+	u, err := auth.GetUserByEmail(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.MFA, check.IsNil)
+	token, err := NativeScheme{}.Login(context.TODO(), map[string]string{"email": s.user.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	c.Assert(token.(*Token).MFA, check.Equals, false)
+}
@@ -31,9 +31,11 @@ func init() {
 }
 
 var (
-	_ auth.Scheme        = &NativeScheme{}
-	_ auth.UserScheme    = &NativeScheme{}
-	_ auth.ManagedScheme = &NativeScheme{}
+	_ auth.Scheme             = &NativeScheme{}
+	_ auth.UserScheme         = &NativeScheme{}
+	_ auth.ManagedScheme      = &NativeScheme{}
+	_ auth.MFAScheme          = &NativeScheme{}
+	_ auth.TokenRevokerScheme = &NativeScheme{}
 )
 
 func (s NativeScheme) Login(ctx context.Context, params map[string]string) (auth.Token, error) {
@@ -49,7 +51,7 @@ func (s NativeScheme) Login(ctx context.Context, params map[string]string) (auth
 	if err != nil {
 		return nil, err
 	}
-	token, err := createToken(ctx, user, password)
+	token, err := createToken(ctx, user, password, params["otp"])
 	if err != nil {
 		return nil, err
 	}
@@ -57,13 +59,31 @@ func (s NativeScheme) Login(ctx context.Context, params map[string]string) (auth
 }
 
 func (s NativeScheme) Auth(ctx context.Context, token string) (auth.Token, error) {
-	return getToken(ctx, token)
+	t, err := getToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	user, err := auth.GetUserByEmail(ctx, t.UserEmail)
+	if err != nil && err != authTypes.ErrUserNotFound {
+		return nil, err
+	}
+	if user != nil && user.Disabled {
+		return nil, auth.ErrUserDisabled
+	}
+	return t, nil
 }
 
 func (s NativeScheme) Logout(ctx context.Context, token string) error {
 	return deleteToken(ctx, token)
 }
 
+func (s NativeScheme) RevokeTokens(ctx context.Context, email string, sessionIDs ...string) error {
+	if len(sessionIDs) == 0 {
+		return deleteAllTokens(ctx, email)
+	}
+	return deleteSessionTokens(ctx, email, sessionIDs)
+}
+
 func (s NativeScheme) Create(ctx context.Context, user *auth.User) (*auth.User, error) {
 	if !validation.ValidateEmail(user.Email) {
 		return nil, ErrInvalidEmail
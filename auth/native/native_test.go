@@ -275,3 +275,44 @@ func (s *S) TestNativeRemove(c *check.C) {
 	_, err = auth.GetUserByEmail(context.TODO(), "timeredbull@globo.com")
 	c.Assert(err, check.Equals, authTypes.ErrUserNotFound)
 }
+
+func (s *S) TestNativeLoginDisabledUser(c *check.C) {
+	s.user.Disabled = true
+	err := s.user.Update(context.TODO())
+	c.Assert(err, check.IsNil)
+	scheme := NativeScheme{}
+	_, err = scheme.Login(context.TODO(), map[string]string{"email": s.user.Email, "password": "123456"})
+	c.Assert(err, check.Equals, auth.ErrUserDisabled)
+}
+
+func (s *S) TestNativeAuthDisabledUser(c *check.C) {
+	scheme := NativeScheme{}
+	token, err := scheme.Login(context.TODO(), map[string]string{"email": s.user.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	_, err = scheme.Auth(context.TODO(), "bearer "+token.GetValue())
+	c.Assert(err, check.IsNil)
+	s.user.Disabled = true
+	err = s.user.Update(context.TODO())
+	c.Assert(err, check.IsNil)
+	_, err = scheme.Auth(context.TODO(), "bearer "+token.GetValue())
+	c.Assert(err, check.Equals, auth.ErrUserDisabled)
+}
+
+func (s *S) TestNativeRevokeTokens(c *check.C) {
+	scheme := NativeScheme{}
+	params := map[string]string{"email": s.user.Email, "password": "123456"}
+	token1, err := scheme.Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+	token2, err := scheme.Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+	err = scheme.RevokeTokens(context.TODO(), s.user.Email, auth.SessionID(token1.GetValue()))
+	c.Assert(err, check.IsNil)
+	_, err = scheme.Auth(context.TODO(), "bearer "+token1.GetValue())
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+	_, err = scheme.Auth(context.TODO(), "bearer "+token2.GetValue())
+	c.Assert(err, check.IsNil)
+	err = scheme.RevokeTokens(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	_, err = scheme.Auth(context.TODO(), "bearer "+token2.GetValue())
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+}
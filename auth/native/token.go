@@ -16,6 +16,7 @@ import (
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/db/storagev2"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/set"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	permTypes "github.com/tsuru/tsuru/types/permission"
 	"github.com/tsuru/tsuru/validation"
@@ -43,6 +44,7 @@ type Token struct {
 	Creation  time.Time     `json:"creation"`
 	Expires   time.Duration `json:"expires"`
 	UserEmail string        `json:"email"`
+	MFA       bool          `json:"mfa,omitempty"`
 }
 
 func (t *Token) GetValue() string {
@@ -61,7 +63,7 @@ func (t *Token) Engine() string {
 	return "native"
 }
 func (t *Token) Permissions(ctx context.Context) ([]permTypes.Permission, error) {
-	return auth.BaseTokenPermission(ctx, t)
+	return auth.TokenPermissionWithMFA(ctx, t, t.MFA)
 }
 
 func loadConfig() error {
@@ -170,13 +172,21 @@ func checkPassword(passwordHash string, password string) error {
 	return auth.AuthenticationFailure{Message: "Authentication failed, wrong password."}
 }
 
-func createToken(ctx context.Context, u *auth.User, password string) (*Token, error) {
+func createToken(ctx context.Context, u *auth.User, password, otpCode string) (*Token, error) {
 	if u.Email == "" {
 		return nil, errors.New("User does not have an email")
 	}
 	if err := checkPassword(u.Password, password); err != nil {
 		return nil, err
 	}
+	if u.Disabled {
+		return nil, auth.ErrUserDisabled
+	}
+	if u.MFAEnabled() {
+		if err := validateMFACode(ctx, u, otpCode); err != nil {
+			return nil, err
+		}
+	}
 	collection, err := storagev2.TokensCollection()
 	if err != nil {
 		return nil, err
@@ -185,6 +195,7 @@ func createToken(ctx context.Context, u *auth.User, password string) (*Token, er
 	if err != nil {
 		return nil, err
 	}
+	token.MFA = u.MFAEnabled()
 	_, err = collection.InsertOne(ctx, token)
 	go removeOldTokens(context.WithoutCancel(ctx), u.Email)
 	return token, err
@@ -233,3 +244,33 @@ func deleteAllTokens(ctx context.Context, email string) error {
 	_, err = collection.DeleteMany(ctx, mongoBSON.M{"useremail": email})
 	return err
 }
+
+// deleteSessionTokens deletes the tokens of the user tracked as one of the
+// given sessions.
+func deleteSessionTokens(ctx context.Context, email string, sessionIDs []string) error {
+	collection, err := storagev2.TokensCollection()
+	if err != nil {
+		return err
+	}
+	cursor, err := collection.Find(ctx, mongoBSON.M{"useremail": email})
+	if err != nil {
+		return err
+	}
+	var tokens []Token
+	err = cursor.All(ctx, &tokens)
+	if err != nil {
+		return err
+	}
+	ids := set.FromSlice(sessionIDs)
+	var values []string
+	for _, t := range tokens {
+		if ids.Includes(auth.SessionID(t.Token)) {
+			values = append(values, t.Token)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	_, err = collection.DeleteMany(ctx, mongoBSON.M{"token": mongoBSON.M{"$in": values}})
+	return err
+}
@@ -39,8 +39,9 @@ var (
 		Help: "The total number of oauth request errors.",
 	})
 
-	_ auth.Scheme     = &oAuthScheme{}
-	_ auth.UserScheme = &oAuthScheme{}
+	_ auth.Scheme             = &oAuthScheme{}
+	_ auth.UserScheme         = &oAuthScheme{}
+	_ auth.TokenRevokerScheme = &oAuthScheme{}
 )
 
 type oAuthScheme struct {
@@ -177,6 +178,9 @@ func (s *oAuthScheme) handleToken(ctx context.Context, t *oauth2.Token) (*tokenW
 	if err != nil {
 		return nil, err
 	}
+	if dbUser.Disabled {
+		return nil, auth.ErrUserDisabled
+	}
 	token := tokenWrapper{Token: *t, UserEmail: user.Email}
 	err = token.save(ctx)
 	if err != nil {
@@ -197,9 +201,23 @@ func (s *oAuthScheme) Auth(ctx context.Context, header string) (auth.Token, erro
 	if !token.Token.Valid() {
 		return token, auth.ErrInvalidToken
 	}
+	user, err := auth.GetUserByEmail(ctx, token.UserEmail)
+	if err != nil && err != authTypes.ErrUserNotFound {
+		return nil, err
+	}
+	if user != nil && user.Disabled {
+		return nil, auth.ErrUserDisabled
+	}
 	return token, nil
 }
 
+func (s *oAuthScheme) RevokeTokens(ctx context.Context, email string, sessionIDs ...string) error {
+	if len(sessionIDs) == 0 {
+		return deleteAllTokens(ctx, email)
+	}
+	return deleteSessionTokens(ctx, email, sessionIDs)
+}
+
 func (s *oAuthScheme) Info(ctx context.Context) (*authTypes.SchemeInfo, error) {
 	config, err := s.loadConfig()
 	if err != nil {
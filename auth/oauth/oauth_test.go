@@ -218,3 +218,45 @@ func (s *S) TestOAuthRemove(c *check.C) {
 	_, err = auth.GetUserByEmail(context.TODO(), "rand@althor.com")
 	c.Assert(err, check.Equals, authTypes.ErrUserNotFound)
 }
+
+func (s *S) TestOAuthAuthDisabledUser(c *check.C) {
+	user := auth.User{Email: "x@x.com", Disabled: true}
+	err := user.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	existing := tokenWrapper{Token: oauth2.Token{AccessToken: "myvalidtoken"}, UserEmail: "x@x.com"}
+	err = existing.save(context.TODO())
+	c.Assert(err, check.IsNil)
+	scheme := oAuthScheme{}
+	_, err = scheme.Auth(context.TODO(), "bearer myvalidtoken")
+	c.Assert(err, check.Equals, auth.ErrUserDisabled)
+}
+
+func (s *S) TestOAuthLoginDisabledUser(c *check.C) {
+	user := auth.User{Email: "rand@althor.com", Disabled: true}
+	err := user.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	scheme := oAuthScheme{}
+	s.rsps["/token"] = `access_token=my_token`
+	s.rsps["/user"] = `{"email":"rand@althor.com"}`
+	_, err = scheme.Login(context.TODO(), map[string]string{"code": "abcdefg", "redirectUrl": "http://localhost"})
+	c.Assert(err, check.Equals, auth.ErrUserDisabled)
+}
+
+func (s *S) TestOAuthRevokeTokens(c *check.C) {
+	for _, value := range []string{"token1", "token2"} {
+		existing := tokenWrapper{Token: oauth2.Token{AccessToken: value}, UserEmail: "x@x.com"}
+		err := existing.save(context.TODO())
+		c.Assert(err, check.IsNil)
+	}
+	scheme := oAuthScheme{}
+	err := scheme.RevokeTokens(context.TODO(), "x@x.com", auth.SessionID("token1"))
+	c.Assert(err, check.IsNil)
+	_, err = scheme.Auth(context.TODO(), "bearer token1")
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+	_, err = scheme.Auth(context.TODO(), "bearer token2")
+	c.Assert(err, check.IsNil)
+	err = scheme.RevokeTokens(context.TODO(), "x@x.com")
+	c.Assert(err, check.IsNil)
+	_, err = scheme.Auth(context.TODO(), "bearer token2")
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/set"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	permTypes "github.com/tsuru/tsuru/types/permission"
 	mongoBSON "go.mongodb.org/mongo-driver/bson"
@@ -87,6 +88,36 @@ func deleteAllTokens(ctx context.Context, email string) error {
 	return err
 }
 
+// deleteSessionTokens deletes the tokens of the user tracked as one of the
+// given sessions.
+func deleteSessionTokens(ctx context.Context, email string, sessionIDs []string) error {
+	collection, err := storagev2.OAuth2TokensCollection()
+	if err != nil {
+		return err
+	}
+	cursor, err := collection.Find(ctx, mongoBSON.M{"useremail": email})
+	if err != nil {
+		return err
+	}
+	var tokens []tokenWrapper
+	err = cursor.All(ctx, &tokens)
+	if err != nil {
+		return err
+	}
+	ids := set.FromSlice(sessionIDs)
+	var values []string
+	for _, t := range tokens {
+		if ids.Includes(auth.SessionID(t.GetValue())) {
+			values = append(values, t.AccessToken)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	_, err = collection.DeleteMany(ctx, mongoBSON.M{"token.accesstoken": mongoBSON.M{"$in": values}})
+	return err
+}
+
 func (t *tokenWrapper) save(ctx context.Context) error {
 	collection, err := storagev2.OAuth2TokensCollection()
 	if err != nil {
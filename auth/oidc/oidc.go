@@ -58,6 +58,7 @@ type oidcScheme struct {
 	initialized         sync.Once
 	registrationEnabled bool
 	groupsInClaims      bool
+	groupsClaim         string
 }
 
 func (s *oidcScheme) Auth(ctx context.Context, token string) (auth.Token, error) {
@@ -97,10 +98,11 @@ func (s *oidcScheme) Auth(ctx context.Context, token string) (auth.Token, error)
 		return nil, errMissingEmailClaim
 	}
 
+	groups := s.identityGroups(identity)
 	user, err := auth.GetUserByEmail(ctx, identity.Email)
 	if err == authTypes.ErrUserNotFound {
 		if s.registrationEnabled {
-			user = &auth.User{Email: identity.Email, Groups: identity.Groups}
+			user = &auth.User{Email: identity.Email, Groups: groups}
 			err = user.Create(ctx)
 			if err != nil {
 				return nil, err
@@ -116,11 +118,13 @@ func (s *oidcScheme) Auth(ctx context.Context, token string) (auth.Token, error)
 		return nil, auth.ErrUserDisabled
 	}
 
+	// Memberships follow the identity provider, groups missing from the
+	// claims are removed from the user.
 	if s.groupsInClaims {
 		dbGroups := set.FromSlice(user.Groups)
-		providerGroups := set.FromSlice(identity.Groups)
+		providerGroups := set.FromSlice(groups)
 		if !dbGroups.Equal(providerGroups) {
-			user.Groups = identity.Groups
+			user.Groups = groups
 			err = user.Update(ctx)
 			if err != nil {
 				return nil, err
@@ -137,6 +141,28 @@ func (s *oidcScheme) Auth(ctx context.Context, token string) (auth.Token, error)
 	}, nil
 }
 
+// identityGroups returns the groups in the claim configured by
+// auth:oidc:groups-claim, "groups" by default. Identity providers may send a
+// single group as a string.
+func (s *oidcScheme) identityGroups(identity *extendedClaims) []string {
+	if s.groupsClaim == "" || s.groupsClaim == "groups" {
+		return identity.Groups
+	}
+	switch value := identity.MapClaims[s.groupsClaim].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		groups := make([]string, 0, len(value))
+		for _, group := range value {
+			if name, ok := group.(string); ok {
+				groups = append(groups, name)
+			}
+		}
+		return groups
+	}
+	return nil
+}
+
 func (s *oidcScheme) Info(ctx context.Context) (*authTypes.SchemeInfo, error) {
 	clientID, err := config.GetString("auth:oidc:client-id")
 	if err != nil {
@@ -181,6 +207,7 @@ func (s *oidcScheme) lazyInitialize(ctx context.Context) error {
 
 		s.registrationEnabled, _ = config.GetBool("auth:user-registration")
 		s.groupsInClaims, _ = config.GetBool("auth:oidc:groups-in-claims")
+		s.groupsClaim, _ = config.GetString("auth:oidc:groups-claim")
 
 		s.validClaims = map[string]interface{}{}
 		internalConfig.UnmarshalConfig("auth:oidc:valid-claims", &s.validClaims)
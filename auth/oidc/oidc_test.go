@@ -153,6 +153,38 @@ func (s *AuthSuite) TestLoginWithGroupsRSAKey(c *check.C) {
 	c.Assert(authUser.Groups, check.DeepEquals, []string{"group1", "group2"})
 }
 
+func (s *AuthSuite) TestLoginWithGroupsRemovesStaleGroups(c *check.C) {
+	s.scheme.groupsInClaims = true
+	s.scheme.groupsClaim = "roles"
+	defer func() {
+		s.scheme.groupsInClaims = false
+		s.scheme.groupsClaim = ""
+	}()
+	kid := "rsa-with-custom-groups-123"
+	privateRSAKey, err := s.generateNewPrivateRSAKey(kid)
+	c.Assert(err, check.IsNil)
+
+	userEmail := "baz@company.com"
+	user := &auth.User{Email: userEmail, Groups: []string{"group1", "old-group"}}
+	err = user.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"email":  userEmail,
+		"groups": []string{"ignored"},
+		"roles":  []string{"group1", "sre"},
+	})
+	token.Header["kid"] = kid
+	tokenString, err := token.SignedString(privateRSAKey)
+	c.Assert(err, check.IsNil)
+
+	_, err = s.scheme.Auth(context.TODO(), tokenString)
+	c.Assert(err, check.IsNil)
+
+	authUser, err := auth.GetUserByEmail(context.TODO(), userEmail)
+	c.Assert(err, check.IsNil)
+	c.Assert(authUser.Groups, check.DeepEquals, []string{"group1", "sre"})
+}
+
 func (s *AuthSuite) TestLoginWithMissingEmail(c *check.C) {
 	kid := "rsa-missing-email"
 	privateRSAKey, err := s.generateNewPrivateRSAKey(kid)
@@ -5,10 +5,16 @@
 package auth
 
 import (
+	"context"
+
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/quota"
 	"github.com/tsuru/tsuru/storage"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	quotaTypes "github.com/tsuru/tsuru/types/quota"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func UserQuotaService() (quotaTypes.LegacyQuotaService, error) {
@@ -23,6 +29,22 @@ func UserQuotaService() (quotaTypes.LegacyQuotaService, error) {
 }
 
 func TeamQuotaService() (quotaTypes.QuotaService[*authTypes.Team], error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &sharedTeamQuotaService{
+		QuotaService: &quota.QuotaService[*authTypes.Team]{Storage: dbDriver.TeamQuotaStorage},
+		storage:      dbDriver.TeamStorage,
+	}, nil
+}
+
+// ownTeamQuotaService changes the quota of the given teams, without charging
+// it to the teams they share the quota with.
+func ownTeamQuotaService() (quotaTypes.QuotaService[*authTypes.Team], error) {
 	dbDriver, err := storage.GetCurrentDbDriver()
 	if err != nil {
 		dbDriver, err = storage.GetDefaultDbDriver()
@@ -32,3 +54,166 @@ func TeamQuotaService() (quotaTypes.QuotaService[*authTypes.Team], error) {
 	}
 	return &quota.QuotaService[*authTypes.Team]{Storage: dbDriver.TeamQuotaStorage}, nil
 }
+
+// sharedTeamQuotaService charges the apps of teams sharing the quota of
+// their parent to the closest ancestor that doesn't share it. Limits are
+// always changed in the team itself.
+type sharedTeamQuotaService struct {
+	quotaTypes.QuotaService[*authTypes.Team]
+	storage authTypes.TeamStorage
+}
+
+func (s *sharedTeamQuotaService) Inc(ctx context.Context, team *authTypes.Team, delta int) error {
+	owner, err := s.quotaOwner(ctx, team)
+	if err != nil {
+		return err
+	}
+	return s.QuotaService.Inc(ctx, owner, delta)
+}
+
+func (s *sharedTeamQuotaService) Get(ctx context.Context, team *authTypes.Team) (*quotaTypes.Quota, error) {
+	owner, err := s.quotaOwner(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	return s.QuotaService.Get(ctx, owner)
+}
+
+func (s *sharedTeamQuotaService) quotaOwner(ctx context.Context, team *authTypes.Team) (*authTypes.Team, error) {
+	storedTeam, err := s.storage.FindByName(ctx, team.Name)
+	if err == authTypes.ErrTeamNotFound {
+		return team, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return teamQuotaOwner(ctx, s.storage, storedTeam)
+}
+
+// teamQuotaOwner returns the team charged for the items of team, following
+// its parents while they share the quota.
+func teamQuotaOwner(ctx context.Context, teamStorage authTypes.TeamStorage, team *authTypes.Team) (*authTypes.Team, error) {
+	visited := map[string]bool{}
+	for team.Parent != "" && team.ShareParentQuota && !visited[team.Name] {
+		visited[team.Name] = true
+		parent, err := teamStorage.FindByName(ctx, team.Parent)
+		if err == authTypes.ErrTeamNotFound {
+			return &authTypes.Team{Name: team.Parent}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		team = parent
+	}
+	return team, nil
+}
+
+// teamQuotaMove moves items in use from a quota owner to another, when a
+// team starts being charged to a different owner.
+type teamQuotaMove struct {
+	service  quotaTypes.QuotaService[*authTypes.Team]
+	from, to *authTypes.Team
+	items    int
+}
+
+// quotaMove returns the move needed for the quota owner of current to become
+// the one of updated, nil when nothing is moved.
+func (t *teamService) quotaMove(ctx context.Context, current, updated *authTypes.Team, hierarchy authTypes.TeamHierarchy) (*teamQuotaMove, error) {
+	from, err := teamQuotaOwner(ctx, t.storage, current)
+	if err != nil {
+		return nil, err
+	}
+	to, err := teamQuotaOwner(ctx, t.storage, updated)
+	if err != nil {
+		return nil, err
+	}
+	if from.Name == to.Name {
+		return nil, nil
+	}
+	teams, err := t.quotaSharingTeams(ctx, current.Name, hierarchy)
+	if err != nil {
+		return nil, err
+	}
+	items, err := countTeamQuotaItems(ctx, teams)
+	if err != nil || items == 0 {
+		return nil, err
+	}
+	service, err := ownTeamQuotaService()
+	if err != nil {
+		return nil, err
+	}
+	return &teamQuotaMove{service: service, from: from, to: to, items: items}, nil
+}
+
+// quotaSharingTeams returns the team and its descendants charged to the same
+// quota owner through it.
+func (t *teamService) quotaSharingTeams(ctx context.Context, name string, hierarchy authTypes.TeamHierarchy) ([]string, error) {
+	teams := []string{name}
+	pending := []string{name}
+	for len(pending) > 0 {
+		children := hierarchy[pending[0]]
+		pending = pending[1:]
+		if len(children) == 0 {
+			continue
+		}
+		found, err := t.storage.FindByNames(ctx, children)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range found {
+			if child.ShareParentQuota {
+				teams = append(teams, child.Name)
+				pending = append(pending, child.Name)
+			}
+		}
+	}
+	return teams, nil
+}
+
+// countTeamQuotaItems counts the apps and jobs owned by the teams, which are
+// the items charged to the team quota.
+func countTeamQuotaItems(ctx context.Context, teams []string) (int, error) {
+	filter := mongoBSON.M{"teamowner": mongoBSON.M{"$in": teams}}
+	var total int64
+	for _, getCollection := range []func() (*mongo.Collection, error){storagev2.AppsCollection, storagev2.JobsCollection} {
+		collection, err := getCollection()
+		if err != nil {
+			return 0, err
+		}
+		n, err := collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return int(total), nil
+}
+
+func (m *teamQuotaMove) apply(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+	err := m.service.Inc(ctx, m.to, m.items)
+	if err != nil {
+		return err
+	}
+	err = m.service.Inc(ctx, m.from, -m.items)
+	if err != nil {
+		if revertErr := m.service.Inc(ctx, m.to, -m.items); revertErr != nil {
+			log.Errorf("unable to move back the quota of team %q: %v", m.to.Name, revertErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *teamQuotaMove) revert(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+	err := m.service.Inc(ctx, m.from, m.items)
+	if err != nil {
+		return err
+	}
+	return m.service.Inc(ctx, m.to, -m.items)
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package roleexpiry prunes temporary role assignments from users and team
+// tokens once they expire.
+package roleexpiry
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/auth"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+)
+
+const (
+	pruneInterval    = time.Minute
+	pruneEventKind   = "role.assignment.prune"
+	expiredEventKind = "role.assignment.expire"
+)
+
+// Initialize starts the periodic removal of expired role assignments.
+func Initialize() {
+	pruner := &pruner{
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go pruner.start()
+	shutdown.Register(pruner)
+}
+
+type pruner struct {
+	quit chan struct{}
+	done chan struct{}
+}
+
+func (p *pruner) start() {
+	defer close(p.done)
+	for {
+		err := prune(context.Background(), time.Now())
+		if err != nil {
+			log.Errorf("[role-expiry] unable to prune expired roles: %v", err)
+		}
+		select {
+		case <-p.quit:
+			return
+		case <-time.After(pruneInterval):
+		}
+	}
+}
+
+func (p *pruner) Shutdown(ctx context.Context) error {
+	close(p.quit)
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// prune runs Prune holding a cluster-wide lock, so a single API instance
+// prunes the expired assignments at a time.
+func prune(ctx context.Context, now time.Time) error {
+	evt, err := event.NewInternal(ctx, &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeGlobal, Value: "role-expiry"},
+		InternalKind: pruneEventKind,
+		Allowed:      event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		if _, isLocked := err.(event.ErrEventLocked); isLocked {
+			return nil
+		}
+		return err
+	}
+	pruneErr := Prune(ctx, now)
+	if pruneErr == nil {
+		return evt.Abort(ctx)
+	}
+	err = evt.Done(ctx, pruneErr)
+	if err != nil {
+		log.Errorf("[role-expiry] unable to finish prune event: %v", err)
+	}
+	return pruneErr
+}
+
+// Prune removes the role assignments expired at the given time, emitting an
+// event for each of them.
+func Prune(ctx context.Context, now time.Time) error {
+	multi := tsuruErrors.NewMultiError()
+	removed, err := auth.RemoveExpiredRoles(ctx, now)
+	if err != nil {
+		multi.Add(err)
+	}
+	for email, roles := range removed {
+		for _, r := range roles {
+			err = expiredEvent(ctx, r, "user", email)
+			if err != nil {
+				multi.Add(err)
+			}
+		}
+	}
+	removed, err = servicemanager.TeamToken.RemoveExpiredRoles(ctx, now)
+	if err != nil {
+		multi.Add(err)
+	}
+	for tokenID, roles := range removed {
+		for _, r := range roles {
+			err = expiredEvent(ctx, r, "token", tokenID)
+			if err != nil {
+				multi.Add(err)
+			}
+		}
+	}
+	return multi.ToError()
+}
+
+func expiredEvent(ctx context.Context, r authTypes.RoleInstance, subjectType, subject string) error {
+	evt, err := event.NewInternal(ctx, &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: r.Name},
+		InternalKind: expiredEventKind,
+		CustomData: map[string]interface{}{
+			subjectType: subject,
+			"context":   r.ContextValue,
+			"expiresAt": r.ExpiresAt,
+			"reason":    r.Reason,
+		},
+		Allowed: event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	evt.Logf("role %q with context %q expired for %s %q", r.Name, r.ContextValue, subjectType, subject)
+	return evt.Done(ctx, nil)
+}
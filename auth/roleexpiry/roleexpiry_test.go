@@ -0,0 +1,135 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package roleexpiry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	"github.com/tsuru/tsuru/storage"
+	_ "github.com/tsuru/tsuru/storage/mongodb"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	check "gopkg.in/check.v1"
+)
+
+type S struct{}
+
+var _ = check.Suite(&S{})
+
+func Test(t *testing.T) {
+	check.TestingT(t)
+}
+
+func (s *S) SetUpSuite(c *check.C) {
+	config.Set("log:disable-syslog", true)
+	config.Set("database:driver", "mongodb")
+	config.Set("database:url", "127.0.0.1:27017?maxPoolSize=100")
+	config.Set("database:name", "tsuru_auth_roleexpiry_test")
+	storagev2.Reset()
+	var err error
+	servicemanager.TeamToken, err = auth.TeamTokenService()
+	c.Assert(err, check.IsNil)
+	servicemanager.AuthGroup, err = auth.GroupService()
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) SetUpTest(c *check.C) {
+	err := storagev2.ClearAllCollections(nil)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TearDownSuite(c *check.C) {
+	storagev2.ClearAllCollections(nil)
+}
+
+func (s *S) TestPrune(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	u := auth.User{Email: "me@tsuru.com", Password: "123456"}
+	err = u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	err = u.AddRoleInstance(context.TODO(), authTypes.RoleInstance{Name: "pool-admin", ContextValue: "prod", ExpiresAt: now.Add(-time.Minute), Reason: "incident"})
+	c.Assert(err, check.IsNil)
+	err = u.AddRoleInstance(context.TODO(), authTypes.RoleInstance{Name: "pool-admin", ContextValue: "dev", ExpiresAt: now.Add(time.Minute)})
+	c.Assert(err, check.IsNil)
+	err = Prune(context.TODO(), now)
+	c.Assert(err, check.IsNil)
+	dbUser, err := auth.GetUserByEmail(context.TODO(), u.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.Roles, check.HasLen, 1)
+	c.Assert(dbUser.Roles[0].ContextValue, check.Equals, "dev")
+	evts, err := event.List(context.TODO(), &event.Filter{KindNames: []string{expiredEventKind}})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Target, check.DeepEquals, eventTypes.Target{Type: eventTypes.TargetTypeRole, Value: "pool-admin"})
+	c.Assert(evts[0].Log(), check.Matches, `(?s).*role "pool-admin" with context "prod" expired for user "me@tsuru.com".*`)
+	err = Prune(context.TODO(), now)
+	c.Assert(err, check.IsNil)
+	evts, err = event.List(context.TODO(), &event.Filter{KindNames: []string{expiredEventKind}})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+}
+
+func (s *S) TestPruneTeamTokens(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	dbDriver, err := storage.GetDefaultDbDriver()
+	c.Assert(err, check.IsNil)
+	token := authTypes.TeamToken{Token: "abc", TokenID: "mytoken", Team: "myteam"}
+	err = dbDriver.TeamTokenStorage.Insert(context.TODO(), token)
+	c.Assert(err, check.IsNil)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	err = servicemanager.TeamToken.AddRoleInstance(context.TODO(), token.TokenID, authTypes.RoleInstance{Name: "pool-admin", ContextValue: "prod", ExpiresAt: now.Add(-time.Minute)})
+	c.Assert(err, check.IsNil)
+	err = servicemanager.TeamToken.AddRole(context.TODO(), token.TokenID, "pool-admin", "dev")
+	c.Assert(err, check.IsNil)
+	err = Prune(context.TODO(), now)
+	c.Assert(err, check.IsNil)
+	dbToken, err := servicemanager.TeamToken.FindByTokenID(context.TODO(), token.TokenID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbToken.Roles, check.DeepEquals, []authTypes.RoleInstance{{Name: "pool-admin", ContextValue: "dev"}})
+	evts, err := event.List(context.TODO(), &event.Filter{KindNames: []string{expiredEventKind}})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Log(), check.Matches, `(?s).*role "pool-admin" with context "prod" expired for token "mytoken".*`)
+}
+
+func (s *S) TestPruneLocked(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	u := auth.User{Email: "me@tsuru.com", Password: "123456"}
+	err = u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	err = u.AddRoleInstance(context.TODO(), authTypes.RoleInstance{Name: "pool-admin", ContextValue: "prod", ExpiresAt: now.Add(-time.Minute)})
+	c.Assert(err, check.IsNil)
+	evt, err := event.NewInternal(context.TODO(), &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeGlobal, Value: "role-expiry"},
+		InternalKind: pruneEventKind,
+		Allowed:      event.Allowed(permission.PermRoleReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = prune(context.TODO(), now)
+	c.Assert(err, check.IsNil)
+	dbUser, err := auth.GetUserByEmail(context.TODO(), u.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.Roles, check.HasLen, 1)
+	err = evt.Abort(context.TODO())
+	c.Assert(err, check.IsNil)
+	err = prune(context.TODO(), now)
+	c.Assert(err, check.IsNil)
+	dbUser, err = auth.GetUserByEmail(context.TODO(), u.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.Roles, check.HasLen, 0)
+}
@@ -36,6 +36,29 @@ type ManagedScheme interface {
 	ChangePassword(ctx context.Context, token Token, oldPassword string, newPassword string) error
 }
 
+// MFAScheme is implemented by schemes handling time-based one-time passwords
+// as a second authentication factor.
+type MFAScheme interface {
+	UserScheme
+	EnrollMFA(ctx context.Context, user *User) (*MFAEnrollment, error)
+	ConfirmMFA(ctx context.Context, user *User, code string) ([]string, error)
+	VerifyMFA(ctx context.Context, user *User, code string) error
+	DisableMFA(ctx context.Context, user *User) error
+}
+
+// TokenRevokerScheme is implemented by schemes storing the tokens they
+// issue, so revoking sessions also invalidates the tokens behind them.
+type TokenRevokerScheme interface {
+	// RevokeTokens deletes the tokens issued to the user, only the ones of
+	// the given session ids when any is given.
+	RevokeTokens(ctx context.Context, email string, sessionIDs ...string) error
+}
+
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+}
+
 type AuthenticationFailure struct {
 	Message string
 }
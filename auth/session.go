@@ -0,0 +1,254 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/db/storagev2"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// sessionTouchInterval limits how often the last use of a session is
+	// written, avoiding a database write on every request.
+	sessionTouchInterval = time.Minute
+	// sessionIdleExpiration is how long an unused session is kept, it's
+	// tracked again if its token is still valid.
+	sessionIdleExpiration = 30 * 24 * time.Hour
+	// revokedSessionRetention must outlive the tokens schemes are unable to
+	// delete, like the ones issued by OpenID providers, otherwise a revoked
+	// token would be tracked as a new session. Stored tokens are deleted
+	// when their sessions are revoked.
+	revokedSessionRetention = 90 * 24 * time.Hour
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+var trackedSessions = &trackedSessionCache{}
+
+// trackedSessionCache holds the sessions recently tracked by this API
+// instance, so the session of a token is read at most once per
+// sessionTouchInterval. Revoking sessions through this instance drops them
+// from the cache, revocations made by other instances are noticed once the
+// entry expires.
+type trackedSessionCache struct {
+	sync.Mutex
+	sessions map[string]trackedSession
+	prunedAt time.Time
+}
+
+type trackedSession struct {
+	email     string
+	info      SessionInfo
+	expiresAt time.Time
+}
+
+func (c *trackedSessionCache) tracked(id string, info SessionInfo) bool {
+	c.Lock()
+	defer c.Unlock()
+	session, ok := c.sessions[id]
+	return ok && session.info == info && time.Now().Before(session.expiresAt)
+}
+
+func (c *trackedSessionCache) set(id, email string, info SessionInfo, expiresAt time.Time) {
+	c.Lock()
+	defer c.Unlock()
+	now := time.Now()
+	if c.sessions == nil {
+		c.sessions = map[string]trackedSession{}
+	}
+	if now.Sub(c.prunedAt) > sessionTouchInterval {
+		for k, v := range c.sessions {
+			if now.After(v.expiresAt) {
+				delete(c.sessions, k)
+			}
+		}
+		c.prunedAt = now
+	}
+	c.sessions[id] = trackedSession{email: email, info: info, expiresAt: expiresAt}
+}
+
+// invalidate drops the sessions of the user, only the given ones if any.
+func (c *trackedSessionCache) invalidate(email string, ids ...string) {
+	c.Lock()
+	defer c.Unlock()
+	if len(ids) > 0 {
+		for _, id := range ids {
+			delete(c.sessions, id)
+		}
+		return
+	}
+	for k, v := range c.sessions {
+		if v.email == email {
+			delete(c.sessions, k)
+		}
+	}
+}
+
+// Session is a login token issued by an auth scheme, tracked so users and
+// admins are able to list and revoke it.
+type Session struct {
+	ID         string    `json:"id" bson:"_id"`
+	UserEmail  string    `json:"email"`
+	Engine     string    `json:"engine"`
+	ClientIP   string    `json:"clientIP"`
+	UserAgent  string    `json:"userAgent"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	Revoked    bool      `json:"-"`
+	ExpireAt   time.Time `json:"-" bson:",omitempty"`
+	Current    bool      `json:"current,omitempty" bson:"-"`
+}
+
+type SessionInfo struct {
+	ClientIP  string
+	UserAgent string
+}
+
+// SessionID returns the identifier of the session for a token, the token
+// itself is never stored.
+func SessionID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TrackSession records the use of a token issued by an auth scheme. It
+// returns ErrInvalidToken when the session was revoked.
+func TrackSession(ctx context.Context, t Token, info SessionInfo) error {
+	id := SessionID(t.GetValue())
+	if trackedSessions.tracked(id, info) {
+		return nil
+	}
+	collection, err := storagev2.SessionsCollection()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	var session Session
+	err = collection.FindOne(ctx, mongoBSON.M{"_id": id}).Decode(&session)
+	if err == mongo.ErrNoDocuments {
+		_, err = collection.InsertOne(ctx, Session{
+			ID:         id,
+			UserEmail:  t.GetUserName(),
+			Engine:     t.Engine(),
+			ClientIP:   info.ClientIP,
+			UserAgent:  info.UserAgent,
+			CreatedAt:  now,
+			LastUsedAt: now,
+			ExpireAt:   now.Add(sessionIdleExpiration),
+		})
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		if err == nil {
+			trackedSessions.set(id, t.GetUserName(), info, now.Add(sessionTouchInterval))
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if session.Revoked {
+		return ErrInvalidToken
+	}
+	if now.Sub(session.LastUsedAt) < sessionTouchInterval && session.ClientIP == info.ClientIP && session.UserAgent == info.UserAgent {
+		trackedSessions.set(id, session.UserEmail, info, session.LastUsedAt.Add(sessionTouchInterval))
+		return nil
+	}
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": id, "revoked": mongoBSON.M{"$ne": true}}, mongoBSON.M{
+		"$set": mongoBSON.M{
+			"clientip":   info.ClientIP,
+			"useragent":  info.UserAgent,
+			"lastusedat": now,
+			"expireat":   now.Add(sessionIdleExpiration),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	trackedSessions.set(id, session.UserEmail, info, now.Add(sessionTouchInterval))
+	return nil
+}
+
+// ListSessions returns the active sessions of a user, most recently used
+// first.
+func ListSessions(ctx context.Context, email string) ([]Session, error) {
+	collection, err := storagev2.SessionsCollection()
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find().SetSort(mongoBSON.D{{Key: "lastusedat", Value: -1}})
+	cursor, err := collection.Find(ctx, mongoBSON.M{"useremail": email, "revoked": mongoBSON.M{"$ne": true}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var sessions []Session
+	err = cursor.All(ctx, &sessions)
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RevokeSession invalidates a session of the user, requests using its token
+// are rejected from then on.
+func RevokeSession(ctx context.Context, email, id string) error {
+	n, err := revokeSessions(ctx, email, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeSessions invalidates all sessions of the user, returning how many
+// were revoked. Tokens stored by the schemes are deleted even when they were
+// never used, so they aren't tracked as sessions.
+func RevokeSessions(ctx context.Context, email string) (int, error) {
+	return revokeSessions(ctx, email)
+}
+
+func revokeSessions(ctx context.Context, email string, ids ...string) (int, error) {
+	collection, err := storagev2.SessionsCollection()
+	if err != nil {
+		return 0, err
+	}
+	filter := mongoBSON.M{"useremail": email, "revoked": mongoBSON.M{"$ne": true}}
+	if len(ids) > 0 {
+		filter["_id"] = mongoBSON.M{"$in": ids}
+	}
+	result, err := collection.UpdateMany(ctx, filter, mongoBSON.M{
+		"$set": mongoBSON.M{
+			"revoked":  true,
+			"expireat": time.Now().UTC().Add(revokedSessionRetention),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	trackedSessions.invalidate(email, ids...)
+	if len(ids) > 0 && result.ModifiedCount == 0 {
+		return 0, nil
+	}
+	for _, scheme := range schemes {
+		if revoker, ok := scheme.(TokenRevokerScheme); ok {
+			err = revoker.RevokeTokens(ctx, email, ids...)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	return int(result.ModifiedCount), nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/db/storagev2"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestTrackSession(c *check.C) {
+	t := &APIToken{Token: "my-token", UserEmail: s.user.Email}
+	err := TrackSession(context.TODO(), t, SessionInfo{ClientIP: "10.0.0.1", UserAgent: "tsuru-client/1.0"})
+	c.Assert(err, check.IsNil)
+	sessions, err := ListSessions(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 1)
+	c.Assert(sessions[0].ID, check.Equals, SessionID("my-token"))
+	c.Assert(sessions[0].ID, check.Not(check.Matches), ".*my-token.*")
+	c.Assert(sessions[0].Engine, check.Equals, "apikey")
+	c.Assert(sessions[0].ClientIP, check.Equals, "10.0.0.1")
+	c.Assert(sessions[0].UserAgent, check.Equals, "tsuru-client/1.0")
+	c.Assert(sessions[0].CreatedAt.IsZero(), check.Equals, false)
+	err = TrackSession(context.TODO(), t, SessionInfo{ClientIP: "10.0.0.2", UserAgent: "tsuru-client/1.0"})
+	c.Assert(err, check.IsNil)
+	sessions, err = ListSessions(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 1)
+	c.Assert(sessions[0].ClientIP, check.Equals, "10.0.0.2")
+}
+
+func (s *S) TestTrackSessionRevoked(c *check.C) {
+	t := &APIToken{Token: "my-token", UserEmail: s.user.Email}
+	err := TrackSession(context.TODO(), t, SessionInfo{})
+	c.Assert(err, check.IsNil)
+	err = RevokeSession(context.TODO(), s.user.Email, SessionID("my-token"))
+	c.Assert(err, check.IsNil)
+	err = TrackSession(context.TODO(), t, SessionInfo{})
+	c.Assert(err, check.Equals, ErrInvalidToken)
+	sessions, err := ListSessions(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 0)
+}
+
+func (s *S) TestTrackSessionCached(c *check.C) {
+	t := &APIToken{Token: "my-token", UserEmail: s.user.Email}
+	info := SessionInfo{ClientIP: "10.0.0.1"}
+	err := TrackSession(context.TODO(), t, info)
+	c.Assert(err, check.IsNil)
+	collection, err := storagev2.SessionsCollection()
+	c.Assert(err, check.IsNil)
+	_, err = collection.DeleteMany(context.TODO(), mongoBSON.M{})
+	c.Assert(err, check.IsNil)
+	err = TrackSession(context.TODO(), t, info)
+	c.Assert(err, check.IsNil)
+	sessions, err := ListSessions(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 0)
+	err = TrackSession(context.TODO(), t, SessionInfo{ClientIP: "10.0.0.2"})
+	c.Assert(err, check.IsNil)
+	sessions, err = ListSessions(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 1)
+	c.Assert(sessions[0].ClientIP, check.Equals, "10.0.0.2")
+}
+
+func (s *S) TestRevokeSessionNotFound(c *check.C) {
+	t := &APIToken{Token: "my-token", UserEmail: s.user.Email}
+	err := TrackSession(context.TODO(), t, SessionInfo{})
+	c.Assert(err, check.IsNil)
+	err = RevokeSession(context.TODO(), "other@globo.com", SessionID("my-token"))
+	c.Assert(err, check.Equals, ErrSessionNotFound)
+	err = RevokeSession(context.TODO(), s.user.Email, "unknown")
+	c.Assert(err, check.Equals, ErrSessionNotFound)
+}
+
+func (s *S) TestRevokeSessions(c *check.C) {
+	for _, value := range []string{"token1", "token2"} {
+		err := TrackSession(context.TODO(), &APIToken{Token: value, UserEmail: s.user.Email}, SessionInfo{})
+		c.Assert(err, check.IsNil)
+	}
+	err := TrackSession(context.TODO(), &APIToken{Token: "token3", UserEmail: "other@globo.com"}, SessionInfo{})
+	c.Assert(err, check.IsNil)
+	revoked, err := RevokeSessions(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(revoked, check.Equals, 2)
+	sessions, err := ListSessions(context.TODO(), s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 0)
+	sessions, err = ListSessions(context.TODO(), "other@globo.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 1)
+}
+
+type revokerScheme struct {
+	Scheme
+	revoked map[string][]string
+}
+
+func (s *revokerScheme) RevokeTokens(ctx context.Context, email string, sessionIDs ...string) error {
+	s.revoked[email] = append(s.revoked[email], sessionIDs...)
+	return nil
+}
+
+func (s *S) TestRevokeSessionsRevokesTokens(c *check.C) {
+	scheme := &revokerScheme{revoked: map[string][]string{}}
+	RegisterScheme("revoker", scheme)
+	defer UnregisterScheme("revoker")
+	err := TrackSession(context.TODO(), &APIToken{Token: "token1", UserEmail: s.user.Email}, SessionInfo{})
+	c.Assert(err, check.IsNil)
+	err = RevokeSession(context.TODO(), s.user.Email, "unknown")
+	c.Assert(err, check.Equals, ErrSessionNotFound)
+	c.Assert(scheme.revoked, check.HasLen, 0)
+	err = RevokeSession(context.TODO(), s.user.Email, SessionID("token1"))
+	c.Assert(err, check.IsNil)
+	c.Assert(scheme.revoked, check.DeepEquals, map[string][]string{s.user.Email: {SessionID("token1")}})
+	scheme.revoked = map[string][]string{}
+	revoked, err := RevokeSessions(context.TODO(), "other@globo.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(revoked, check.Equals, 0)
+	c.Assert(scheme.revoked, check.DeepEquals, map[string][]string{"other@globo.com": nil})
+}
@@ -55,6 +55,8 @@ func (s *S) TearDownSuite(c *check.C) {
 func (s *S) SetUpTest(c *check.C) {
 	err := storagev2.ClearAllCollections(nil)
 	c.Assert(err, check.IsNil)
+	teamHierarchies.invalidate()
+	trackedSessions = &trackedSessionCache{}
 	s.user = &User{Email: "timeredbull@globo.com", Password: "123456"}
 	s.user.Create(context.TODO())
 	s.hashed = s.user.Password
@@ -8,6 +8,8 @@ import (
 	"context"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tsuru/config"
@@ -28,8 +30,46 @@ import (
 //	Keys and values can contain only lowercase letters, numeric characters, underscores, and dashes. All characters must use UTF-8 encoding, and international characters are allowed. Keys must start with a lowercase letter or international character.
 var teamNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_\-]{1,62}$`)
 
+// teamHierarchyTTL bounds how long a hierarchy changed by another API
+// instance may be used, changes made by this instance are seen right away.
+const teamHierarchyTTL = 30 * time.Second
+
+var teamHierarchies = &teamHierarchyCache{}
+
+// teamHierarchyCache holds the team hierarchy, which is read to expand the
+// team permissions of every token.
+type teamHierarchyCache struct {
+	sync.Mutex
+	hierarchy authTypes.TeamHierarchy
+	expiresAt time.Time
+}
+
+func (c *teamHierarchyCache) get() authTypes.TeamHierarchy {
+	c.Lock()
+	defer c.Unlock()
+	if time.Now().After(c.expiresAt) {
+		return nil
+	}
+	return c.hierarchy
+}
+
+func (c *teamHierarchyCache) set(hierarchy authTypes.TeamHierarchy) {
+	c.Lock()
+	defer c.Unlock()
+	c.hierarchy = hierarchy
+	c.expiresAt = time.Now().Add(teamHierarchyTTL)
+}
+
+func (c *teamHierarchyCache) invalidate() {
+	c.Lock()
+	defer c.Unlock()
+	c.hierarchy = nil
+	c.expiresAt = time.Time{}
+}
+
 type teamService struct {
-	storage authTypes.TeamStorage
+	storage   authTypes.TeamStorage
+	hierarchy *teamHierarchyCache
 }
 
 func TeamService() (authTypes.TeamService, error) {
@@ -41,7 +81,8 @@ func TeamService() (authTypes.TeamService, error) {
 		}
 	}
 	return &teamService{
-		storage: dbDriver.TeamStorage,
+		storage:   dbDriver.TeamStorage,
+		hierarchy: teamHierarchies,
 	}, nil
 }
 
@@ -151,7 +192,123 @@ func (t *teamService) Remove(ctx context.Context, teamName string) error {
 	if len(serviceInstanceNames) > 0 {
 		return &authTypes.ErrTeamStillUsed{ServiceInstances: serviceInstanceNames}
 	}
-	return t.storage.Delete(ctx, authTypes.Team{Name: teamName})
+	hierarchy, err := t.loadHierarchy(ctx)
+	if err != nil {
+		return err
+	}
+	if children := hierarchy[teamName]; len(children) > 0 {
+		return &authTypes.ErrTeamStillUsed{Teams: children}
+	}
+	err = t.storage.Delete(ctx, authTypes.Team{Name: teamName})
+	if err != nil {
+		return err
+	}
+	t.invalidateHierarchy()
+	return nil
+}
+
+// SetParent places the team below parent, an empty parent detaches the team.
+// Only teams with a parent may share its quota. When the team ends up
+// charged to another quota owner, the items in use by the team, and by the
+// descendants sharing its quota, are moved to the new owner, which must have
+// room for them.
+func (t *teamService) SetParent(ctx context.Context, name, parent string, shareQuota bool) error {
+	team, err := t.storage.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if parent == "" {
+		shareQuota = false
+	} else {
+		if parent == team.Name {
+			return authTypes.ErrTeamParentCycle
+		}
+		_, err = t.storage.FindByName(ctx, parent)
+		if err == authTypes.ErrTeamNotFound {
+			return authTypes.ErrParentTeamNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+	hierarchy, err := t.loadHierarchy(ctx)
+	if err != nil {
+		return err
+	}
+	for _, descendant := range hierarchy.Descendants(team.Name) {
+		if descendant == parent {
+			return authTypes.ErrTeamParentCycle
+		}
+	}
+	updated := *team
+	updated.Parent = parent
+	updated.ShareParentQuota = shareQuota
+	move, err := t.quotaMove(ctx, team, &updated, hierarchy)
+	if err != nil {
+		return err
+	}
+	err = move.apply(ctx)
+	if err != nil {
+		return err
+	}
+	err = t.storage.Update(ctx, updated)
+	if err != nil {
+		if revertErr := move.revert(ctx); revertErr != nil {
+			log.Errorf("unable to move back the quota of team %q: %v", team.Name, revertErr)
+		}
+		return err
+	}
+	t.invalidateHierarchy()
+	return nil
+}
+
+// Hierarchy returns the team hierarchy, cached for teamHierarchyTTL.
+func (t *teamService) Hierarchy(ctx context.Context) (authTypes.TeamHierarchy, error) {
+	if t.hierarchy != nil {
+		if hierarchy := t.hierarchy.get(); hierarchy != nil {
+			return hierarchy, nil
+		}
+	}
+	hierarchy, err := t.loadHierarchy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if t.hierarchy != nil {
+		t.hierarchy.set(hierarchy)
+	}
+	return hierarchy, nil
+}
+
+func (t *teamService) loadHierarchy(ctx context.Context) (authTypes.TeamHierarchy, error) {
+	teams, err := t.storage.FindWithParent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hierarchy := authTypes.TeamHierarchy{}
+	for _, team := range teams {
+		hierarchy[team.Parent] = append(hierarchy[team.Parent], team.Name)
+	}
+	return hierarchy, nil
+}
+
+func (t *teamService) invalidateHierarchy() {
+	if t.hierarchy != nil {
+		t.hierarchy.invalidate()
+	}
+}
+
+// RenameTeamParent moves the children of a renamed team to its new name.
+func RenameTeamParent(ctx context.Context, oldName, newName string) error {
+	collection, err := storagev2.TeamsCollection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.UpdateMany(ctx, mongoBSON.M{"parent": oldName}, mongoBSON.M{"$set": mongoBSON.M{"parent": newName}})
+	if err != nil {
+		return err
+	}
+	teamHierarchies.invalidate()
+	return nil
 }
 
 func (t *teamService) validate(team authTypes.Team) error {
@@ -8,7 +8,9 @@ import (
 	"context"
 
 	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/servicemanager"
 	authTypes "github.com/tsuru/tsuru/types/auth"
+	"github.com/tsuru/tsuru/types/quota"
 	mongoBSON "go.mongodb.org/mongo-driver/bson"
 	check "gopkg.in/check.v1"
 )
@@ -128,6 +130,9 @@ func (s *S) TestTeamServiceRemove(c *check.C) {
 	teamName := "atreides"
 	ts := &teamService{
 		storage: &authTypes.MockTeamStorage{
+			OnFindWithParent: func() ([]authTypes.Team, error) {
+				return nil, nil
+			},
 			OnDelete: func(t authTypes.Team) error {
 				c.Assert(t.Name, check.Equals, teamName)
 				return nil
@@ -179,6 +184,229 @@ func (s *S) TestTeamServiceRemoveWithServiceInstances(c *check.C) {
 	c.Assert(err, check.ErrorMatches, "Service instances: service01/vladimir")
 }
 
+func (s *S) TestTeamServiceRemoveWithChildTeams(c *check.C) {
+	ts := &teamService{
+		storage: &authTypes.MockTeamStorage{
+			OnFindWithParent: func() ([]authTypes.Team, error) {
+				return []authTypes.Team{{Name: "feyd", Parent: "harkonnen"}, {Name: "rabban", Parent: "harkonnen"}}, nil
+			},
+			OnDelete: func(t authTypes.Team) error {
+				c.Fail()
+				return nil
+			},
+		},
+	}
+	err := ts.Remove(context.TODO(), "harkonnen")
+	c.Assert(err, check.ErrorMatches, "Child teams: feyd, rabban")
+}
+
+func (s *S) TestTeamServiceSetParent(c *check.C) {
+	var updated []authTypes.Team
+	ts := &teamService{
+		storage: &authTypes.MockTeamStorage{
+			OnFindByName: func(name string) (*authTypes.Team, error) {
+				return &authTypes.Team{Name: name, Tags: []string{"tag1"}}, nil
+			},
+			OnFindWithParent: func() ([]authTypes.Team, error) {
+				return nil, nil
+			},
+			OnUpdate: func(t authTypes.Team) error {
+				updated = append(updated, t)
+				return nil
+			},
+		},
+	}
+	err := ts.SetParent(context.TODO(), "atreides", "corrino", true)
+	c.Assert(err, check.IsNil)
+	err = ts.SetParent(context.TODO(), "atreides", "", true)
+	c.Assert(err, check.IsNil)
+	c.Assert(updated, check.DeepEquals, []authTypes.Team{
+		{Name: "atreides", Tags: []string{"tag1"}, Parent: "corrino", ShareParentQuota: true},
+		{Name: "atreides", Tags: []string{"tag1"}},
+	})
+}
+
+func (s *S) TestTeamServiceSetParentNotFound(c *check.C) {
+	ts := &teamService{
+		storage: &authTypes.MockTeamStorage{
+			OnFindByName: func(name string) (*authTypes.Team, error) {
+				if name == "atreides" {
+					return &authTypes.Team{Name: name}, nil
+				}
+				return nil, authTypes.ErrTeamNotFound
+			},
+		},
+	}
+	err := ts.SetParent(context.TODO(), "atreides", "corrino", false)
+	c.Assert(err, check.Equals, authTypes.ErrParentTeamNotFound)
+	err = ts.SetParent(context.TODO(), "corrino", "atreides", false)
+	c.Assert(err, check.Equals, authTypes.ErrTeamNotFound)
+}
+
+func (s *S) TestTeamServiceSetParentCycle(c *check.C) {
+	ts := &teamService{
+		storage: &authTypes.MockTeamStorage{
+			OnFindByName: func(name string) (*authTypes.Team, error) {
+				return &authTypes.Team{Name: name}, nil
+			},
+			OnFindWithParent: func() ([]authTypes.Team, error) {
+				return []authTypes.Team{
+					{Name: "atreides", Parent: "corrino"},
+					{Name: "fremen", Parent: "atreides"},
+				}, nil
+			},
+			OnUpdate: func(t authTypes.Team) error {
+				c.Fail()
+				return nil
+			},
+		},
+	}
+	err := ts.SetParent(context.TODO(), "corrino", "fremen", false)
+	c.Assert(err, check.Equals, authTypes.ErrTeamParentCycle)
+	err = ts.SetParent(context.TODO(), "corrino", "corrino", false)
+	c.Assert(err, check.Equals, authTypes.ErrTeamParentCycle)
+}
+
+func (s *S) TestTeamServiceHierarchy(c *check.C) {
+	ts := &teamService{
+		storage: &authTypes.MockTeamStorage{
+			OnFindWithParent: func() ([]authTypes.Team, error) {
+				return []authTypes.Team{
+					{Name: "atreides", Parent: "corrino"},
+					{Name: "harkonnen", Parent: "corrino"},
+					{Name: "fremen", Parent: "atreides"},
+				}, nil
+			},
+		},
+	}
+	hierarchy, err := ts.Hierarchy(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(hierarchy, check.DeepEquals, authTypes.TeamHierarchy{
+		"corrino":  {"atreides", "harkonnen"},
+		"atreides": {"fremen"},
+	})
+}
+
+func (s *S) TestTeamServiceHierarchyCached(c *check.C) {
+	var calls int
+	ts := &teamService{
+		storage: &authTypes.MockTeamStorage{
+			OnFindByName: func(name string) (*authTypes.Team, error) {
+				return &authTypes.Team{Name: name}, nil
+			},
+			OnFindWithParent: func() ([]authTypes.Team, error) {
+				calls++
+				return []authTypes.Team{{Name: "atreides", Parent: "corrino"}}, nil
+			},
+			OnUpdate: func(t authTypes.Team) error {
+				return nil
+			},
+		},
+		hierarchy: &teamHierarchyCache{},
+	}
+	for i := 0; i < 2; i++ {
+		hierarchy, err := ts.Hierarchy(context.TODO())
+		c.Assert(err, check.IsNil)
+		c.Assert(hierarchy, check.DeepEquals, authTypes.TeamHierarchy{"corrino": {"atreides"}})
+	}
+	c.Assert(calls, check.Equals, 1)
+	err := ts.SetParent(context.TODO(), "fremen", "atreides", false)
+	c.Assert(err, check.IsNil)
+	c.Assert(calls, check.Equals, 2)
+	_, err = ts.Hierarchy(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(calls, check.Equals, 3)
+}
+
+func (s *S) TestRenameTeamParent(c *check.C) {
+	err := servicemanager.Team.Create(context.TODO(), "corrino", nil, &authTypes.User{Email: s.user.Email})
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Team.Create(context.TODO(), "atreides", nil, &authTypes.User{Email: s.user.Email})
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Team.SetParent(context.TODO(), "atreides", "corrino", true)
+	c.Assert(err, check.IsNil)
+	err = RenameTeamParent(context.TODO(), "corrino", "shaddam")
+	c.Assert(err, check.IsNil)
+	team, err := servicemanager.Team.FindByName(context.TODO(), "atreides")
+	c.Assert(err, check.IsNil)
+	c.Assert(team.Parent, check.Equals, "shaddam")
+	c.Assert(team.ShareParentQuota, check.Equals, true)
+}
+
+func (s *S) TestTeamQuotaServiceSharedWithParent(c *check.C) {
+	for _, name := range []string{"corrino", "atreides", "fremen"} {
+		err := servicemanager.Team.Create(context.TODO(), name, nil, &authTypes.User{Email: s.user.Email})
+		c.Assert(err, check.IsNil)
+	}
+	err := servicemanager.Team.SetParent(context.TODO(), "atreides", "corrino", true)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Team.SetParent(context.TODO(), "fremen", "atreides", true)
+	c.Assert(err, check.IsNil)
+	qs, err := TeamQuotaService()
+	c.Assert(err, check.IsNil)
+	err = qs.SetLimit(context.TODO(), &authTypes.Team{Name: "corrino"}, 2)
+	c.Assert(err, check.IsNil)
+	err = qs.Inc(context.TODO(), &authTypes.Team{Name: "fremen"}, 1)
+	c.Assert(err, check.IsNil)
+	err = qs.Inc(context.TODO(), &authTypes.Team{Name: "atreides"}, 1)
+	c.Assert(err, check.IsNil)
+	err = qs.Inc(context.TODO(), &authTypes.Team{Name: "corrino"}, 1)
+	c.Assert(err, check.FitsTypeOf, &quota.QuotaExceededError{})
+	q, err := qs.Get(context.TODO(), &authTypes.Team{Name: "fremen"})
+	c.Assert(err, check.IsNil)
+	c.Assert(*q, check.DeepEquals, quota.Quota{Limit: 2, InUse: 2})
+	team, err := servicemanager.Team.FindByName(context.TODO(), "fremen")
+	c.Assert(err, check.IsNil)
+	c.Assert(team.Quota, check.DeepEquals, quota.UnlimitedQuota)
+}
+
+func (s *S) TestTeamServiceSetParentMovesQuota(c *check.C) {
+	for _, name := range []string{"corrino", "atreides", "fremen"} {
+		err := servicemanager.Team.Create(context.TODO(), name, nil, &authTypes.User{Email: s.user.Email})
+		c.Assert(err, check.IsNil)
+	}
+	err := servicemanager.Team.SetParent(context.TODO(), "fremen", "atreides", true)
+	c.Assert(err, check.IsNil)
+	appsCollection, err := storagev2.AppsCollection()
+	c.Assert(err, check.IsNil)
+	_, err = appsCollection.InsertMany(context.TODO(), []interface{}{
+		mongoBSON.M{"name": "arrakis", "teamowner": "atreides"},
+		mongoBSON.M{"name": "sietch", "teamowner": "fremen"},
+	})
+	c.Assert(err, check.IsNil)
+	qs, err := TeamQuotaService()
+	c.Assert(err, check.IsNil)
+	err = qs.Inc(context.TODO(), &authTypes.Team{Name: "atreides"}, 1)
+	c.Assert(err, check.IsNil)
+	err = qs.Inc(context.TODO(), &authTypes.Team{Name: "fremen"}, 1)
+	c.Assert(err, check.IsNil)
+	err = qs.SetLimit(context.TODO(), &authTypes.Team{Name: "corrino"}, 1)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Team.SetParent(context.TODO(), "atreides", "corrino", true)
+	c.Assert(err, check.FitsTypeOf, &quota.QuotaExceededError{})
+	team, err := servicemanager.Team.FindByName(context.TODO(), "atreides")
+	c.Assert(err, check.IsNil)
+	c.Assert(team.Parent, check.Equals, "")
+	err = qs.SetLimit(context.TODO(), &authTypes.Team{Name: "corrino"}, 3)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Team.SetParent(context.TODO(), "atreides", "corrino", true)
+	c.Assert(err, check.IsNil)
+	q, err := qs.Get(context.TODO(), &authTypes.Team{Name: "fremen"})
+	c.Assert(err, check.IsNil)
+	c.Assert(*q, check.DeepEquals, quota.Quota{Limit: 3, InUse: 2})
+	team, err = servicemanager.Team.FindByName(context.TODO(), "atreides")
+	c.Assert(err, check.IsNil)
+	c.Assert(team.Quota.InUse, check.Equals, 0)
+	err = servicemanager.Team.SetParent(context.TODO(), "atreides", "corrino", false)
+	c.Assert(err, check.IsNil)
+	q, err = qs.Get(context.TODO(), &authTypes.Team{Name: "atreides"})
+	c.Assert(err, check.IsNil)
+	c.Assert(q.InUse, check.Equals, 2)
+	team, err = servicemanager.Team.FindByName(context.TODO(), "corrino")
+	c.Assert(err, check.IsNil)
+	c.Assert(team.Quota.InUse, check.Equals, 0)
+}
+
 func (s *S) TestTeamServiceList(c *check.C) {
 	teams := []authTypes.Team{
 		{Name: "corrino"},
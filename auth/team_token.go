@@ -59,7 +59,7 @@ func (t *teamToken) Engine() string {
 }
 
 func (t *teamToken) Permissions(ctx context.Context) ([]permTypes.Permission, error) {
-	return expandRolePermissions(ctx, t.Roles)
+	return expandRolePermissions(ctx, t.Roles, true)
 }
 
 type teamTokenService struct {
@@ -155,7 +155,11 @@ func (s *teamTokenService) Create(ctx context.Context, args authTypes.TeamTokenC
 }
 
 func (s *teamTokenService) AddRole(ctx context.Context, tokenID string, roleName, contextValue string) error {
-	_, err := permission.FindRole(ctx, roleName)
+	return s.AddRoleInstance(ctx, tokenID, authTypes.RoleInstance{Name: roleName, ContextValue: contextValue})
+}
+
+func (s *teamTokenService) AddRoleInstance(ctx context.Context, tokenID string, roleInstance authTypes.RoleInstance) error {
+	_, err := permission.FindRole(ctx, roleInstance.Name)
 	if err != nil {
 		return err
 	}
@@ -163,19 +167,19 @@ func (s *teamTokenService) AddRole(ctx context.Context, tokenID string, roleName
 	if err != nil {
 		return err
 	}
-
-	newRoleInstance := authTypes.RoleInstance{
-		Name:         roleName,
-		ContextValue: contextValue,
-	}
-
-	for _, currentRole := range token.Roles {
-		if currentRole == newRoleInstance {
+	temporary := !roleInstance.ExpiresAt.IsZero()
+	for i := 0; i < len(token.Roles); i++ {
+		currentRole := token.Roles[i]
+		if currentRole == roleInstance {
 			return nil
 		}
+		if temporary && !currentRole.ExpiresAt.IsZero() &&
+			currentRole.Name == roleInstance.Name && currentRole.ContextValue == roleInstance.ContextValue {
+			token.Roles = append(token.Roles[:i], token.Roles[i+1:]...)
+			i--
+		}
 	}
-
-	token.Roles = append(token.Roles, newRoleInstance)
+	token.Roles = append(token.Roles, roleInstance)
 	return s.storage.Update(ctx, *token)
 }
 
@@ -194,6 +198,34 @@ func (s *teamTokenService) RemoveRole(ctx context.Context, tokenID string, roleN
 	return s.storage.Update(ctx, *token)
 }
 
+// RemoveExpiredRoles removes the temporary role assignments expired at the
+// given time from all team tokens, returning the removed assignments by token
+// id.
+func (s *teamTokenService) RemoveExpiredRoles(ctx context.Context, now time.Time) (map[string][]authTypes.RoleInstance, error) {
+	tokens, err := s.storage.FindByTeams(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	removed := map[string][]authTypes.RoleInstance{}
+	for _, token := range tokens {
+		hasExpired := false
+		for _, r := range token.Roles {
+			hasExpired = hasExpired || r.Expired(now)
+		}
+		if !hasExpired {
+			continue
+		}
+		roles, err := s.storage.PullExpiredRoles(ctx, token.TokenID, now)
+		if err != nil {
+			return nil, err
+		}
+		if len(roles) > 0 {
+			removed[token.TokenID] = roles
+		}
+	}
+	return removed, nil
+}
+
 func (s *teamTokenService) FindByTokenID(ctx context.Context, tokenID string) (authTypes.TeamToken, error) {
 	t, err := s.storage.FindByTokenID(ctx, tokenID)
 	if err != nil {
@@ -296,13 +328,11 @@ func canUseRole(ctx context.Context, userPerms []permTypes.Permission, roleName,
 	if err != nil {
 		return false, err
 	}
-	perms := role.PermissionsFor(contextValue)
-	for _, p := range perms {
-		if !permission.CheckFromPermList(userPerms, p.Scheme, p.Context) {
-			return false, nil
-		}
+	unallowed, err := UnallowedRolePermission(ctx, userPerms, role, contextValue)
+	if err != nil {
+		return false, err
 	}
-	return true, nil
+	return unallowed == nil, nil
 }
 
 func canViewTokenValue(ctx context.Context, userPerms []permTypes.Permission, teamToken *authTypes.TeamToken) (bool, error) {
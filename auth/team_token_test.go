@@ -171,6 +171,49 @@ func (s *S) Test_TeamTokenService_AddRoleTwice(c *check.C) {
 	})
 }
 
+func (s *S) Test_TeamTokenService_AddRoleInstanceTemporary(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "app-deployer", "app", "")
+	c.Assert(err, check.IsNil)
+	token, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Millisecond)
+	err = servicemanager.TeamToken.AddRole(context.TODO(), token.TokenID, "app-deployer", "myapp")
+	c.Assert(err, check.IsNil)
+	err = servicemanager.TeamToken.AddRoleInstance(context.TODO(), token.TokenID, authTypes.RoleInstance{Name: "app-deployer", ContextValue: "myapp2", ExpiresAt: expiresAt.Add(-time.Minute)})
+	c.Assert(err, check.IsNil)
+	err = servicemanager.TeamToken.AddRoleInstance(context.TODO(), token.TokenID, authTypes.RoleInstance{Name: "app-deployer", ContextValue: "myapp2", ExpiresAt: expiresAt, Reason: "release"})
+	c.Assert(err, check.IsNil)
+	dbToken, err := servicemanager.TeamToken.FindByTokenID(context.TODO(), token.TokenID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbToken.Roles, check.DeepEquals, []authTypes.RoleInstance{
+		{Name: "app-deployer", ContextValue: "myapp"},
+		{Name: "app-deployer", ContextValue: "myapp2", ExpiresAt: expiresAt, Reason: "release"},
+	})
+}
+
+func (s *S) Test_TeamTokenService_RemoveExpiredRoles(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "app-deployer", "app", "")
+	c.Assert(err, check.IsNil)
+	token, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	expired := authTypes.RoleInstance{Name: "app-deployer", ContextValue: "myapp2", ExpiresAt: now.Add(-time.Minute)}
+	err = servicemanager.TeamToken.AddRole(context.TODO(), token.TokenID, "app-deployer", "myapp")
+	c.Assert(err, check.IsNil)
+	err = servicemanager.TeamToken.AddRoleInstance(context.TODO(), token.TokenID, expired)
+	c.Assert(err, check.IsNil)
+	removed, err := servicemanager.TeamToken.RemoveExpiredRoles(context.TODO(), now)
+	c.Assert(err, check.IsNil)
+	c.Assert(removed, check.DeepEquals, map[string][]authTypes.RoleInstance{
+		token.TokenID: {expired},
+	})
+	dbToken, err := servicemanager.TeamToken.FindByTokenID(context.TODO(), token.TokenID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbToken.Roles, check.DeepEquals, []authTypes.RoleInstance{
+		{Name: "app-deployer", ContextValue: "myapp"},
+	})
+}
+
 func (s *S) Test_TeamTokenService_AddRole_TokenNotFound(c *check.C) {
 	_, err := permission.NewRole(context.TODO(), "app-deployer", "app", "")
 	c.Assert(err, check.IsNil)
@@ -53,10 +53,26 @@ func ParseToken(header string) (string, error) {
 	return value, ErrInvalidToken
 }
 
+// BaseTokenPermission returns the permissions of the token user. Roles
+// requiring MFA are left out, tokens that verified a second factor must use
+// TokenPermissionWithMFA instead.
 func BaseTokenPermission(ctx context.Context, t Token) ([]permTypes.Permission, error) {
+	return TokenPermissionWithMFA(ctx, t, false)
+}
+
+// TokenPermissionWithMFA returns the permissions of the token user, roles
+// requiring MFA only grant permissions when mfaVerified is set.
+func TokenPermissionWithMFA(ctx context.Context, t Token, mfaVerified bool) ([]permTypes.Permission, error) {
 	u, err := ConvertNewUser(t.User(ctx))
 	if err != nil {
 		return nil, err
 	}
-	return u.Permissions(ctx)
+	return userTokenPermission(ctx, u, mfaVerified)
+}
+
+func userTokenPermission(ctx context.Context, u *User, mfaVerified bool) ([]permTypes.Permission, error) {
+	if u.Disabled {
+		return nil, ErrUserDisabled
+	}
+	return u.PermissionsWithMFA(ctx, mfaVerified)
 }
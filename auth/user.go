@@ -24,6 +24,8 @@ import (
 	"github.com/tsuru/tsuru/types/quota"
 	"github.com/tsuru/tsuru/validation"
 	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type User struct {
@@ -35,6 +37,7 @@ type User struct {
 	Groups    []string                 `bson:",omitempty"`
 	FromToken bool                     `bson:",omitempty"`
 	Disabled  bool                     `bson:",omitempty"`
+	MFA       *authTypes.MFA           `bson:",omitempty"`
 
 	APIKeyLastAccess   time.Time `bson:"apikey_last_access"`
 	APIKeyUsageCounter int64     `bson:"apikey_usage_counter"`
@@ -73,6 +76,61 @@ func ListUsersWithRolesAndContext(ctx context.Context, roles []string, context s
 	return listUsers(ctx, mongoBSON.M{"roles": mongoBSON.M{"$elemMatch": mongoBSON.M{"contextvalue": context, "name": mongoBSON.M{"$in": roles}}}})
 }
 
+func ListUsersWithGroup(ctx context.Context, group string) ([]User, error) {
+	return listUsers(ctx, mongoBSON.M{"groups": group})
+}
+
+// ListUserGroupNames returns the names of all groups users are members of.
+func ListUserGroupNames(ctx context.Context) ([]string, error) {
+	usersCollection, err := storagev2.UsersCollection()
+	if err != nil {
+		return nil, err
+	}
+	values, err := usersCollection.Distinct(ctx, "groups", mongoBSON.M{})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for _, v := range values {
+		if name, ok := v.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// AddGroupToUsers adds the group membership to the users with the given
+// emails.
+func AddGroupToUsers(ctx context.Context, group string, emails []string) error {
+	if len(emails) == 0 {
+		return nil
+	}
+	usersCollection, err := storagev2.UsersCollection()
+	if err != nil {
+		return err
+	}
+	_, err = usersCollection.UpdateMany(ctx, mongoBSON.M{"email": mongoBSON.M{"$in": emails}}, mongoBSON.M{"$addToSet": mongoBSON.M{"groups": group}})
+	return err
+}
+
+// RemoveGroupFromUsers removes the group membership from the users with the
+// given emails, or from every user when no email is given.
+func RemoveGroupFromUsers(ctx context.Context, group string, emails []string) error {
+	usersCollection, err := storagev2.UsersCollection()
+	if err != nil {
+		return err
+	}
+	filter := mongoBSON.M{"groups": group}
+	if emails != nil {
+		if len(emails) == 0 {
+			return nil
+		}
+		filter["email"] = mongoBSON.M{"$in": emails}
+	}
+	_, err = usersCollection.UpdateMany(ctx, filter, mongoBSON.M{"$pull": mongoBSON.M{"groups": group}})
+	return err
+}
+
 func GetUserByEmail(ctx context.Context, email string) (*User, error) {
 	if !validation.ValidateEmail(email) {
 		return nil, &tsuruErrors.ValidationError{Message: "invalid email"}
@@ -169,22 +227,148 @@ func (u *User) reload(ctx context.Context) error {
 	return usersCollection.FindOne(ctx, mongoBSON.M{"email": u.Email}).Decode(u)
 }
 
-func expandRolePermissions(ctx context.Context, roleInstances []authTypes.RoleInstance) ([]permTypes.Permission, error) {
-	var permissions []permTypes.Permission
+// expandRolePermissions returns the permissions granted by the role
+// instances, roles requiring MFA are skipped unless mfaVerified is set.
+func expandRolePermissions(ctx context.Context, roleInstances []authTypes.RoleInstance, mfaVerified bool) ([]permTypes.Permission, error) {
+	assignments := make([]roleAssignment, len(roleInstances))
+	for i, r := range roleInstances {
+		assignments[i] = roleAssignment{RoleInstance: r}
+	}
+	granted, err := expandRoleGrants(ctx, assignments, mfaVerified)
+	if err != nil {
+		return nil, err
+	}
+	permissions := make([]permTypes.Permission, len(granted))
+	for i, g := range granted {
+		permissions[i] = g.Permission
+	}
+	return permissions, nil
+}
+
+// roleAssignment is a role instance assigned either directly or through the
+// named group.
+type roleAssignment struct {
+	authTypes.RoleInstance
+	group string
+}
+
+// grantedPermission is a permission along with the role assignment it comes
+// from.
+type grantedPermission struct {
+	permTypes.Permission
+	grant PermissionGrant
+}
+
+func expandRoleGrants(ctx context.Context, assignments []roleAssignment, mfaVerified bool) ([]grantedPermission, error) {
+	var granted []grantedPermission
 	roles := make(map[string]*permission.Role)
-	for _, roleData := range roleInstances {
-		role := roles[roleData.Name]
+	now := time.Now()
+	for _, assignment := range assignments {
+		if assignment.Expired(now) {
+			continue
+		}
+		role := roles[assignment.Name]
 		if role == nil {
-			foundRole, err := permission.FindRole(ctx, roleData.Name)
+			foundRole, err := permission.FindRole(ctx, assignment.Name)
 			if err != nil && err != permTypes.ErrRoleNotFound {
 				return nil, err
 			}
 			role = &foundRole
-			roles[roleData.Name] = role
+			roles[assignment.Name] = role
+		}
+		if role.RequireMFA && !mfaVerified {
+			continue
+		}
+		grant := PermissionGrant{Role: assignment.Name, Group: assignment.group, RequireMFA: role.RequireMFA}
+		perms := append(role.PermissionsFor(assignment.ContextValue), role.DenyPermissionsFor(assignment.ContextValue)...)
+		for _, perm := range perms {
+			granted = append(granted, grantedPermission{Permission: perm, grant: grant})
 		}
-		permissions = append(permissions, role.PermissionsFor(roleData.ContextValue)...)
 	}
-	return permissions, nil
+	return expandTeamDescendants(ctx, granted)
+}
+
+// expandTeamDescendants repeats the permissions granted on a team for each
+// of its descendants in the team hierarchy.
+func expandTeamDescendants(ctx context.Context, granted []grantedPermission) ([]grantedPermission, error) {
+	hasTeamPermissions := false
+	for _, g := range granted {
+		if g.Context.CtxType == permTypes.CtxTeam {
+			hasTeamPermissions = true
+			break
+		}
+	}
+	if !hasTeamPermissions || servicemanager.Team == nil {
+		return granted, nil
+	}
+	hierarchy, err := servicemanager.Team.Hierarchy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range granted {
+		if g.Context.CtxType != permTypes.CtxTeam {
+			continue
+		}
+		for _, team := range hierarchy.Descendants(g.Context.Value) {
+			inherited := g
+			inherited.Context = permission.Context(permTypes.CtxTeam, team)
+			inherited.grant.InheritedFrom = g.Context.Value
+			granted = append(granted, inherited)
+		}
+	}
+	return granted, nil
+}
+
+// UnallowedRolePermission returns the first permission the role grants in
+// the context value that isn't allowed by perms, nil when every permission
+// is allowed. Permissions are checked along with the team hierarchy and a
+// role including a scheme is only allowed when the descendant schemes denied
+// in perms are also denied by the role itself.
+func UnallowedRolePermission(ctx context.Context, perms []permTypes.Permission, role permission.Role, contextValue string) (*permTypes.Permission, error) {
+	granted, err := expandRoleGrants(ctx, []roleAssignment{{RoleInstance: authTypes.RoleInstance{Name: role.Name, ContextValue: contextValue}}}, true)
+	if err != nil {
+		return nil, err
+	}
+	var roleDenies []permTypes.Permission
+	for _, g := range granted {
+		if g.Deny {
+			roleDenies = append(roleDenies, g.Permission)
+		}
+	}
+	for _, g := range granted {
+		p := g.Permission
+		if p.Deny {
+			continue
+		}
+		if !permission.CheckFromPermList(perms, p.Scheme, p.Context) {
+			return &p, nil
+		}
+		for _, deny := range perms {
+			if !deny.Deny || !p.Scheme.IsParent(deny.Scheme) || !contextsOverlap(p.Context, deny.Context) {
+				continue
+			}
+			if !deniedByRole(roleDenies, p, deny) {
+				return &permTypes.Permission{Scheme: deny.Scheme, Context: p.Context}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func contextsOverlap(a, b permTypes.PermissionContext) bool {
+	return a.CtxType == permTypes.CtxGlobal || b.CtxType == permTypes.CtxGlobal || a == b
+}
+
+func deniedByRole(roleDenies []permTypes.Permission, granted, deny permTypes.Permission) bool {
+	for _, roleDeny := range roleDenies {
+		if !roleDeny.Scheme.IsParent(deny.Scheme) {
+			continue
+		}
+		if roleDeny.Context.CtxType == permTypes.CtxGlobal || roleDeny.Context == granted.Context || roleDeny.Context == deny.Context {
+			return true
+		}
+	}
+	return false
 }
 
 func (u *User) UserGroups() ([]authTypes.Group, error) {
@@ -199,27 +383,70 @@ func (u *User) UserGroups() ([]authTypes.Group, error) {
 	return groups, nil
 }
 
+// Permissions returns the user permissions, leaving out roles requiring MFA.
 func (u *User) Permissions(ctx context.Context) ([]permTypes.Permission, error) {
-	groups, err := u.UserGroups()
+	return u.PermissionsWithMFA(ctx, false)
+}
+
+// PermissionsWithMFA returns the user permissions, roles requiring MFA only
+// grant permissions when mfaVerified is set.
+func (u *User) PermissionsWithMFA(ctx context.Context, mfaVerified bool) ([]permTypes.Permission, error) {
+	granted, err := u.grantedPermissions(ctx, mfaVerified)
 	if err != nil {
 		return nil, err
 	}
-	allRoles := u.Roles
-	for _, group := range groups {
-		allRoles = append(allRoles, group.Roles...)
+	permissions := make([]permTypes.Permission, len(granted))
+	for i, g := range granted {
+		permissions[i] = g.Permission
 	}
-	permissions, err := expandRolePermissions(ctx, allRoles)
+	return permissions, nil
+}
+
+// grantedPermissions returns the user permissions along with the role
+// assignments, direct or from groups, granting them. Every user is implicitly
+// allowed to manage itself.
+func (u *User) grantedPermissions(ctx context.Context, mfaVerified bool) ([]grantedPermission, error) {
+	groups, err := u.UserGroups()
 	if err != nil {
 		return nil, err
 	}
-	return append([]permTypes.Permission{{
+	granted, err := expandRoleGrants(ctx, u.roleAssignments(groups), mfaVerified)
+	if err != nil {
+		return nil, err
+	}
+	return append([]grantedPermission{{Permission: permTypes.Permission{
 		Scheme:  permission.PermUser,
 		Context: permission.Context(permTypes.CtxUser, u.Email),
-	}}, permissions...), nil
+	}}}, granted...), nil
+}
+
+func (u *User) roleAssignments(groups []authTypes.Group) []roleAssignment {
+	var assignments []roleAssignment
+	for _, r := range u.Roles {
+		assignments = append(assignments, roleAssignment{RoleInstance: r})
+	}
+	for _, group := range groups {
+		for _, r := range group.Roles {
+			assignments = append(assignments, roleAssignment{RoleInstance: r, group: group.Name})
+		}
+	}
+	return assignments
+}
+
+// MFAEnabled reports whether the user confirmed the enrollment in multi-factor
+// authentication.
+func (u *User) MFAEnabled() bool {
+	return u.MFA != nil && u.MFA.Enabled
 }
 
 func (u *User) AddRole(ctx context.Context, roleName string, contextValue string) error {
-	_, err := permission.FindRole(ctx, roleName)
+	return u.AddRoleInstance(ctx, authTypes.RoleInstance{Name: roleName, ContextValue: contextValue})
+}
+
+// AddRoleInstance assigns a role to the user, a temporary assignment replaces
+// a previous temporary assignment of the same role and context value.
+func (u *User) AddRoleInstance(ctx context.Context, roleInstance authTypes.RoleInstance) error {
+	_, err := permission.FindRole(ctx, roleInstance.Name)
 	if err != nil {
 		return err
 	}
@@ -227,14 +454,33 @@ func (u *User) AddRole(ctx context.Context, roleName string, contextValue string
 	if err != nil {
 		return err
 	}
+	// Order matters in $addToSet, that's why bson.D is used instead of
+	// bson.M.
+	roleDoc := mongoBSON.D([]mongoBSON.E{
+		{Key: "name", Value: roleInstance.Name},
+		{Key: "contextvalue", Value: roleInstance.ContextValue},
+	})
+	if !roleInstance.ExpiresAt.IsZero() {
+		_, err = usersCollection.UpdateOne(ctx, mongoBSON.M{"email": u.Email}, mongoBSON.M{
+			"$pull": mongoBSON.M{
+				"roles": mongoBSON.M{
+					"name":         roleInstance.Name,
+					"contextvalue": roleInstance.ContextValue,
+					"expiresat":    mongoBSON.M{"$exists": true},
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		roleDoc = append(roleDoc, mongoBSON.E{Key: "expiresat", Value: roleInstance.ExpiresAt})
+		if roleInstance.Reason != "" {
+			roleDoc = append(roleDoc, mongoBSON.E{Key: "reason", Value: roleInstance.Reason})
+		}
+	}
 	_, err = usersCollection.UpdateOne(ctx, mongoBSON.M{"email": u.Email}, mongoBSON.M{
 		"$addToSet": mongoBSON.M{
-			// Order matters in $addToSet, that's why bson.D is used instead
-			// of bson.M.
-			"roles": mongoBSON.D([]mongoBSON.E{
-				{Key: "name", Value: roleName},
-				{Key: "contextvalue", Value: contextValue},
-			}),
+			"roles": roleDoc,
 		},
 	})
 	if err != nil {
@@ -243,6 +489,45 @@ func (u *User) AddRole(ctx context.Context, roleName string, contextValue string
 	return u.reload(ctx)
 }
 
+// RemoveExpiredRoles removes the temporary role assignments expired at the
+// given time from all users, returning the removed assignments by user email.
+func RemoveExpiredRoles(ctx context.Context, now time.Time) (map[string][]authTypes.RoleInstance, error) {
+	users, err := listUsers(ctx, mongoBSON.M{"roles.expiresat": mongoBSON.M{"$lte": now}})
+	if err != nil {
+		return nil, err
+	}
+	usersCollection, err := storagev2.UsersCollection()
+	if err != nil {
+		return nil, err
+	}
+	removed := map[string][]authTypes.RoleInstance{}
+	for _, u := range users {
+		// The removed assignments are taken from the document as it was
+		// right before the update, so concurrent changes are not reported.
+		var previous User
+		err = usersCollection.FindOneAndUpdate(ctx, mongoBSON.M{
+			"email":           u.Email,
+			"roles.expiresat": mongoBSON.M{"$lte": now},
+		}, mongoBSON.M{
+			"$pull": mongoBSON.M{
+				"roles": mongoBSON.M{"expiresat": mongoBSON.M{"$lte": now}},
+			},
+		}, options.FindOneAndUpdate().SetReturnDocument(options.Before)).Decode(&previous)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range previous.Roles {
+			if r.Expired(now) {
+				removed[u.Email] = append(removed[u.Email], r)
+			}
+		}
+	}
+	return removed, nil
+}
+
 func UpdateRoleFromAllUsers(ctx context.Context, roleName, newRoleName, permissionCtx, desc string) error {
 	role, err := permission.FindRole(ctx, roleName)
 	if err != nil {
@@ -7,6 +7,7 @@ package auth
 import (
 	"context"
 	"sort"
+	"time"
 
 	"github.com/tsuru/tsuru/db/storagev2"
 	"github.com/tsuru/tsuru/errors"
@@ -134,6 +135,38 @@ func (l roleInstanceList) Less(i, j int) bool {
 	}
 }
 
+func (s *S) TestUserGroupMemberships(c *check.C) {
+	u1 := User{Email: "wolverine@xmen.com", Groups: []string{"xmen"}}
+	err := u1.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	u2 := User{Email: "storm@xmen.com"}
+	err = u2.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	err = AddGroupToUsers(context.TODO(), "avengers", []string{u1.Email, u2.Email, "unknown@xmen.com"})
+	c.Assert(err, check.IsNil)
+	users, err := ListUsersWithGroup(context.TODO(), "avengers")
+	c.Assert(err, check.IsNil)
+	c.Assert(users, check.HasLen, 2)
+	names, err := ListUserGroupNames(context.TODO())
+	c.Assert(err, check.IsNil)
+	sort.Strings(names)
+	c.Assert(names, check.DeepEquals, []string{"avengers", "xmen"})
+	err = RemoveGroupFromUsers(context.TODO(), "avengers", []string{u2.Email})
+	c.Assert(err, check.IsNil)
+	users, err = ListUsersWithGroup(context.TODO(), "avengers")
+	c.Assert(err, check.IsNil)
+	c.Assert(users, check.HasLen, 1)
+	c.Assert(users[0].Email, check.Equals, u1.Email)
+	err = RemoveGroupFromUsers(context.TODO(), "avengers", nil)
+	c.Assert(err, check.IsNil)
+	users, err = ListUsersWithGroup(context.TODO(), "avengers")
+	c.Assert(err, check.IsNil)
+	c.Assert(users, check.HasLen, 0)
+	dbUser, err := GetUserByEmail(context.TODO(), u1.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.Groups, check.DeepEquals, []string{"xmen"})
+}
+
 func (s *S) TestUserAddRole(c *check.C) {
 	_, err := permission.NewRole(context.TODO(), "r1", "app", "")
 	c.Assert(err, check.IsNil)
@@ -287,6 +320,184 @@ func (s *S) TestUserPermissionsIncludeGroups(c *check.C) {
 	})
 }
 
+func (s *S) TestUserAddRoleInstanceTemporary(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(context.TODO(), "pool.update")
+	c.Assert(err, check.IsNil)
+	expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Millisecond)
+	err = u.AddRoleInstance(context.TODO(), authTypes.RoleInstance{Name: "pool-admin", ContextValue: "prod", ExpiresAt: expiresAt.Add(-time.Minute), Reason: "incident"})
+	c.Assert(err, check.IsNil)
+	err = u.AddRoleInstance(context.TODO(), authTypes.RoleInstance{Name: "pool-admin", ContextValue: "prod", ExpiresAt: expiresAt, Reason: "incident 2"})
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Roles, check.DeepEquals, []authTypes.RoleInstance{
+		{Name: "pool-admin", ContextValue: "prod", ExpiresAt: expiresAt, Reason: "incident 2"},
+	})
+	perms, err := u.Permissions(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(perms, check.HasLen, 2)
+	c.Assert(perms[1], check.DeepEquals, permTypes.Permission{Scheme: permission.PermPoolUpdate, Context: permission.Context(permTypes.CtxPool, "prod")})
+	err = u.RemoveRole(context.TODO(), "pool-admin", "prod")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Roles, check.HasLen, 0)
+}
+
+func (s *S) TestUserPermissionsIgnoreExpiredRoles(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(context.TODO(), "pool.update")
+	c.Assert(err, check.IsNil)
+	err = u.AddRoleInstance(context.TODO(), authTypes.RoleInstance{Name: "pool-admin", ContextValue: "prod", ExpiresAt: time.Now().Add(-time.Minute)})
+	c.Assert(err, check.IsNil)
+	perms, err := u.Permissions(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(perms, check.DeepEquals, []permTypes.Permission{
+		{Scheme: permission.PermUser, Context: permission.Context(permTypes.CtxUser, u.Email)},
+	})
+}
+
+func (s *S) TestUserPermissionsWithMFA(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err := u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(context.TODO(), "pool.update")
+	c.Assert(err, check.IsNil)
+	err = r1.SetRequireMFA(context.TODO(), true)
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(context.TODO(), "pool-admin", "prod")
+	c.Assert(err, check.IsNil)
+	perms, err := u.PermissionsWithMFA(context.TODO(), false)
+	c.Assert(err, check.IsNil)
+	c.Assert(perms, check.DeepEquals, []permTypes.Permission{
+		{Scheme: permission.PermUser, Context: permission.Context(permTypes.CtxUser, u.Email)},
+	})
+	perms, err = u.Permissions(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(perms, check.DeepEquals, []permTypes.Permission{
+		{Scheme: permission.PermUser, Context: permission.Context(permTypes.CtxUser, u.Email)},
+	})
+	perms, err = u.PermissionsWithMFA(context.TODO(), true)
+	c.Assert(err, check.IsNil)
+	c.Assert(perms, check.DeepEquals, []permTypes.Permission{
+		{Scheme: permission.PermUser, Context: permission.Context(permTypes.CtxUser, u.Email)},
+		{Scheme: permission.PermPoolUpdate, Context: permission.Context(permTypes.CtxPool, "prod")},
+	})
+}
+
+func (s *S) TestUserPermissionsInheritedByChildTeams(c *check.C) {
+	for _, name := range []string{"corrino", "atreides", "fremen", "harkonnen"} {
+		err := servicemanager.Team.Create(context.TODO(), name, nil, &authTypes.User{Email: "me@tsuru.com"})
+		c.Assert(err, check.IsNil)
+	}
+	err := servicemanager.Team.SetParent(context.TODO(), "atreides", "corrino", false)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Team.SetParent(context.TODO(), "fremen", "atreides", false)
+	c.Assert(err, check.IsNil)
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err = u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole(context.TODO(), "team-deployer", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	err = r1.AddDenyPermissions(context.TODO(), "app.deploy.rollback")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(context.TODO(), "team-deployer", "atreides")
+	c.Assert(err, check.IsNil)
+	perms, err := u.Permissions(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(perms, check.DeepEquals, []permTypes.Permission{
+		{Scheme: permission.PermUser, Context: permission.Context(permTypes.CtxUser, u.Email)},
+		{Scheme: permission.PermAppDeploy, Context: permission.Context(permTypes.CtxTeam, "atreides")},
+		{Scheme: permission.PermAppDeployRollback, Context: permission.Context(permTypes.CtxTeam, "atreides"), Deny: true},
+		{Scheme: permission.PermAppDeploy, Context: permission.Context(permTypes.CtxTeam, "fremen")},
+		{Scheme: permission.PermAppDeployRollback, Context: permission.Context(permTypes.CtxTeam, "fremen"), Deny: true},
+	})
+}
+
+func (s *S) TestUnallowedRolePermission(c *check.C) {
+	for _, name := range []string{"atreides", "fremen"} {
+		err := servicemanager.Team.Create(context.TODO(), name, nil, &authTypes.User{Email: "me@tsuru.com"})
+		c.Assert(err, check.IsNil)
+	}
+	err := servicemanager.Team.SetParent(context.TODO(), "fremen", "atreides", false)
+	c.Assert(err, check.IsNil)
+	contractor, err := permission.NewRole(context.TODO(), "contractor", "team", "")
+	c.Assert(err, check.IsNil)
+	err = contractor.AddPermissions(context.TODO(), "app")
+	c.Assert(err, check.IsNil)
+	err = contractor.AddDenyPermissions(context.TODO(), "app.run.shell")
+	c.Assert(err, check.IsNil)
+	developer, err := permission.NewRole(context.TODO(), "developer", "team", "")
+	c.Assert(err, check.IsNil)
+	err = developer.AddPermissions(context.TODO(), "app")
+	c.Assert(err, check.IsNil)
+	deployer, err := permission.NewRole(context.TODO(), "deployer", "team", "")
+	c.Assert(err, check.IsNil)
+	err = deployer.AddPermissions(context.TODO(), "app.deploy")
+	c.Assert(err, check.IsNil)
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err = u.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(context.TODO(), "contractor", "atreides")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(context.TODO(), "developer", "fremen")
+	c.Assert(err, check.IsNil)
+	perms, err := u.Permissions(context.TODO())
+	c.Assert(err, check.IsNil)
+	unallowed, err := UnallowedRolePermission(context.TODO(), perms, developer, "atreides")
+	c.Assert(err, check.IsNil)
+	c.Assert(unallowed, check.DeepEquals, &permTypes.Permission{Scheme: permission.PermAppRunShell, Context: permission.Context(permTypes.CtxTeam, "atreides")})
+	unallowed, err = UnallowedRolePermission(context.TODO(), perms, contractor, "atreides")
+	c.Assert(err, check.IsNil)
+	c.Assert(unallowed, check.IsNil)
+	unallowed, err = UnallowedRolePermission(context.TODO(), perms, deployer, "atreides")
+	c.Assert(err, check.IsNil)
+	c.Assert(unallowed, check.IsNil)
+	unallowed, err = UnallowedRolePermission(context.TODO(), perms, developer, "otherteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(unallowed, check.DeepEquals, &permTypes.Permission{Scheme: permission.PermApp, Context: permission.Context(permTypes.CtxTeam, "otherteam")})
+}
+
+func (s *S) TestRemoveExpiredRoles(c *check.C) {
+	_, err := permission.NewRole(context.TODO(), "pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	u1 := User{Email: "me@tsuru.com", Password: "123"}
+	err = u1.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	u2 := User{Email: "other@tsuru.com", Password: "123"}
+	err = u2.Create(context.TODO())
+	c.Assert(err, check.IsNil)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	expired := authTypes.RoleInstance{Name: "pool-admin", ContextValue: "prod", ExpiresAt: now.Add(-time.Minute), Reason: "incident"}
+	valid := authTypes.RoleInstance{Name: "pool-admin", ContextValue: "dev", ExpiresAt: now.Add(time.Minute)}
+	err = u1.AddRoleInstance(context.TODO(), expired)
+	c.Assert(err, check.IsNil)
+	err = u1.AddRole(context.TODO(), "pool-admin", "staging")
+	c.Assert(err, check.IsNil)
+	err = u2.AddRoleInstance(context.TODO(), valid)
+	c.Assert(err, check.IsNil)
+	removed, err := RemoveExpiredRoles(context.TODO(), now)
+	c.Assert(err, check.IsNil)
+	c.Assert(removed, check.DeepEquals, map[string][]authTypes.RoleInstance{
+		"me@tsuru.com": {expired},
+	})
+	dbUser, err := GetUserByEmail(context.TODO(), "me@tsuru.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.Roles, check.DeepEquals, []authTypes.RoleInstance{{Name: "pool-admin", ContextValue: "staging"}})
+	dbUser, err = GetUserByEmail(context.TODO(), "other@tsuru.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbUser.Roles, check.DeepEquals, []authTypes.RoleInstance{valid})
+}
+
 func (s *S) TestUserPermissionsWithRemovedRole(c *check.C) {
 	role, err := permission.NewRole(context.TODO(), "test", "team", "")
 	c.Assert(err, check.IsNil)
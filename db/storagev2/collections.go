@@ -60,6 +60,10 @@ func UsersCollection() (*mongo.Collection, error) {
 	return Collection("users")
 }
 
+func SessionsCollection() (*mongo.Collection, error) {
+	return Collection("sessions")
+}
+
 func TeamTokensCollection() (*mongo.Collection, error) {
 	return Collection("team_tokens")
 }
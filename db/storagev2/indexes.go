@@ -138,6 +138,19 @@ var EnsureIndexes = []EnsureIndex{
 		},
 	},
 
+	{
+		Collection: "sessions",
+		Indexes: []mongo.IndexModel{
+			{
+				Keys: mongoBSON.D{{Key: "useremail", Value: 1}},
+			},
+			{
+				Keys:    mongoBSON.D{{Key: "expireat", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(1),
+			},
+		},
+	},
+
 	{
 		Collection: "team_tokens",
 		Indexes: []mongo.IndexModel{
@@ -173,6 +186,16 @@ var EnsureIndexes = []EnsureIndex{
 		},
 	},
 
+	{
+		Collection: "teams",
+		Indexes: []mongo.IndexModel{
+			{
+				Keys:    mongoBSON.D{{Key: "parent", Value: 1}},
+				Options: options.Index().SetPartialFilterExpression(mongoBSON.M{"parent": mongoBSON.M{"$gt": ""}}),
+			},
+		},
+	},
+
 	{
 		Collection: "auth_groups",
 		Indexes: []mongo.IndexModel{
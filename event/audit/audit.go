@@ -0,0 +1,300 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package audit writes finished events and requests denied by permission
+// checks to an append-only stream, as JSON lines, so they can be shipped to a
+// SIEM regardless of the event TTL.
+//
+// Entries are numbered and written in order, but delivery is best effort:
+// they are buffered in memory and lost if the API process crashes. Entries
+// not accepted by the sink after a few retries, or not enqueued in time
+// because the buffer stays full, are appended to the file set in
+// audit:spill-file, or dropped and counted when there's none. The sequence
+// numbers allow the SIEM to detect missing entries.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/log"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+)
+
+var (
+	_ eventTypes.AuditService = &auditService{}
+
+	defaultBufferSize     = 1000
+	defaultEnqueueTimeout = 100 * time.Millisecond
+	defaultMaxRetries     = 5
+	minRetryInterval      = time.Second
+	maxRetryInterval      = 30 * time.Second
+)
+
+// Sink is the destination of the audit stream, each call receives a single
+// JSON encoded entry terminated by a new line.
+type Sink interface {
+	Write(line []byte) error
+	Close() error
+}
+
+// AuditService returns the audit stream configured in the audit:sink
+// setting, or nil when the stream is disabled.
+func AuditService() (eventTypes.AuditService, error) {
+	sinkName, _ := config.GetString("audit:sink")
+	if sinkName == "" {
+		return nil, nil
+	}
+	sink, err := newSink(sinkName)
+	if err != nil {
+		return nil, err
+	}
+	bufferSize, err := config.GetInt("audit:buffer-size")
+	if err != nil || bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	s := newAuditService(sink, bufferSize)
+	if timeout, err := config.GetDuration("audit:enqueue-timeout"); err == nil && timeout > 0 {
+		s.enqueueTimeout = timeout
+	}
+	if retries, err := config.GetInt("audit:max-retries"); err == nil && retries >= 0 {
+		s.maxRetries = retries
+	}
+	if path, _ := config.GetString("audit:spill-file"); path != "" {
+		s.spill, err = openFileSink(path)
+		if err != nil {
+			sink.Close()
+			return nil, err
+		}
+	}
+	err = s.initMetrics()
+	if err != nil {
+		s.closeSinks()
+		return nil, err
+	}
+	go s.run()
+	shutdown.Register(s)
+	return s, nil
+}
+
+func newSink(name string) (Sink, error) {
+	switch name {
+	case "file":
+		return newFileSink()
+	case "syslog":
+		return newSyslogSink()
+	case "http":
+		return newHTTPSink()
+	}
+	return nil, errors.Errorf("invalid audit sink %q, it must be one of file, syslog or http", name)
+}
+
+func newAuditService(sink Sink, bufferSize int) *auditService {
+	s := &auditService{
+		sink:           sink,
+		entryCh:        make(chan eventTypes.AuditEntry, bufferSize),
+		quitCh:         make(chan struct{}),
+		doneCh:         make(chan struct{}),
+		enqueueTimeout: defaultEnqueueTimeout,
+		maxRetries:     defaultMaxRetries,
+		// Starting from the current time keeps the sequence increasing
+		// across restarts of the same instance.
+		sequence: uint64(time.Now().UnixNano()),
+		auditTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "tsuru_audit_entries_total",
+			Help: "The total number of entries written to the audit sink",
+		}),
+		auditError: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "tsuru_audit_entries_error",
+			Help: "The total number of failed writes to the audit sink",
+		}),
+		auditSpilled: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "tsuru_audit_entries_spilled",
+			Help: "The total number of entries written to the audit spill file instead of the sink",
+		}),
+		auditDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "tsuru_audit_entries_dropped",
+			Help: "The total number of entries neither written to the audit sink nor to the spill file",
+		}),
+	}
+	s.auditQueue = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "tsuru_audit_entries_queue_current",
+		Help: "The current number of entries waiting to be written to the audit sink",
+	}, func() float64 {
+		return float64(len(s.entryCh))
+	})
+	return s
+}
+
+type auditService struct {
+	sink           Sink
+	spill          Sink
+	entryCh        chan eventTypes.AuditEntry
+	quitCh         chan struct{}
+	doneCh         chan struct{}
+	mu             sync.Mutex
+	sequence       uint64
+	enqueueTimeout time.Duration
+	maxRetries     int
+
+	auditTotal   prometheus.Counter
+	auditError   prometheus.Counter
+	auditSpilled prometheus.Counter
+	auditDropped prometheus.Counter
+	auditQueue   prometheus.Collector
+}
+
+func (s *auditService) initMetrics() error {
+	for _, c := range []prometheus.Collector{
+		s.auditTotal,
+		s.auditError,
+		s.auditSpilled,
+		s.auditDropped,
+		s.auditQueue,
+	} {
+		err := prometheus.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *auditService) Shutdown(ctx context.Context) error {
+	prometheus.Unregister(s.auditTotal)
+	prometheus.Unregister(s.auditError)
+	prometheus.Unregister(s.auditSpilled)
+	prometheus.Unregister(s.auditDropped)
+	prometheus.Unregister(s.auditQueue)
+	s.mu.Lock()
+	close(s.quitCh)
+	s.mu.Unlock()
+	select {
+	case <-s.doneCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return s.closeSinks()
+}
+
+func (s *auditService) closeSinks() error {
+	err := s.sink.Close()
+	if s.spill != nil {
+		if spillErr := s.spill.Close(); err == nil {
+			err = spillErr
+		}
+	}
+	return err
+}
+
+// Record enqueues an entry, assigning its sequence number. It waits at most
+// the enqueue timeout for room in the buffer, the entry is spilled otherwise
+// so callers, like events being finished, are never held by the sink.
+func (s *auditService) Record(ctx context.Context, entry eventTypes.AuditEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sequence++
+	entry.Sequence = s.sequence
+	select {
+	case <-s.quitCh:
+		s.spillEntry(entry, errors.New("audit service is shutting down"))
+		return
+	default:
+	}
+	select {
+	case s.entryCh <- entry:
+		return
+	default:
+	}
+	timer := time.NewTimer(s.enqueueTimeout)
+	defer timer.Stop()
+	select {
+	case s.entryCh <- entry:
+	case <-timer.C:
+		s.spillEntry(entry, errors.New("audit buffer is full"))
+	}
+}
+
+func (s *auditService) run() {
+	defer close(s.doneCh)
+	for {
+		select {
+		case entry := <-s.entryCh:
+			s.write(entry)
+		case <-s.quitCh:
+			for {
+				select {
+				case entry := <-s.entryCh:
+					s.write(entry)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// write retries until the sink accepts the entry, up to the max retries,
+// later entries wait so the stream order is kept. The entry is spilled when
+// retries are exhausted.
+func (s *auditService) write(entry eventTypes.AuditEntry) {
+	line, err := encodeEntry(entry)
+	if err != nil {
+		s.auditDropped.Inc()
+		log.Errorf("[audit] unable to encode entry %d: %v", entry.Sequence, err)
+		return
+	}
+	interval := minRetryInterval
+	for retry := 0; ; retry++ {
+		err = s.sink.Write(line)
+		if err == nil {
+			s.auditTotal.Inc()
+			return
+		}
+		s.auditError.Inc()
+		if retry >= s.maxRetries {
+			break
+		}
+		log.Errorf("[audit] error writing entry %d, retrying in %v: %v", entry.Sequence, interval, err)
+		time.Sleep(interval)
+		interval *= 2
+		if interval > maxRetryInterval {
+			interval = maxRetryInterval
+		}
+	}
+	s.spillEntry(entry, err)
+}
+
+// spillEntry appends an entry not written to the sink to the spill file, it's
+// dropped when there's no spill file or it's not writable.
+func (s *auditService) spillEntry(entry eventTypes.AuditEntry, reason error) {
+	if s.spill != nil {
+		line, err := encodeEntry(entry)
+		if err == nil {
+			err = s.spill.Write(line)
+		}
+		if err == nil {
+			s.auditSpilled.Inc()
+			log.Errorf("[audit] entry %d written to the spill file: %v", entry.Sequence, reason)
+			return
+		}
+		reason = errors.Wrapf(err, "unable to write to the spill file after %v", reason)
+	}
+	s.auditDropped.Inc()
+	log.Errorf("[audit] entry %d for %q on %s %q dropped: %v", entry.Sequence, entry.Action, entry.Target.Type, entry.Target.Value, reason)
+}
+
+func encodeEntry(entry eventTypes.AuditEntry) ([]byte, error) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tsuru/config"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	check "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type S struct{}
+
+var _ = check.Suite(&S{})
+
+func (s *S) SetUpTest(c *check.C) {
+	minRetryInterval = time.Millisecond
+}
+
+func (s *S) TearDownTest(c *check.C) {
+	config.Unset("audit")
+}
+
+type fakeSink struct {
+	mu       sync.Mutex
+	lines    [][]byte
+	failures int
+	closed   bool
+}
+
+func (s *fakeSink) Write(line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.lines = append(s.lines, line)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSink) entries(c *check.C) []eventTypes.AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make([]eventTypes.AuditEntry, len(s.lines))
+	for i, line := range s.lines {
+		c.Assert(strings.HasSuffix(string(line), "\n"), check.Equals, true)
+		err := json.Unmarshal(line, &entries[i])
+		c.Assert(err, check.IsNil)
+	}
+	return entries
+}
+
+func (s *S) TestRecordKeepsOrder(c *check.C) {
+	sink := &fakeSink{failures: 3}
+	svc := newAuditService(sink, 10)
+	go svc.run()
+	for _, id := range []string{"evt1", "evt2", "evt3"} {
+		svc.Record(context.TODO(), eventTypes.AuditEntry{EventID: id, Action: "app.deploy"})
+	}
+	err := svc.Shutdown(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(sink.closed, check.Equals, true)
+	entries := sink.entries(c)
+	c.Assert(entries, check.HasLen, 3)
+	for i, id := range []string{"evt1", "evt2", "evt3"} {
+		c.Assert(entries[i].EventID, check.Equals, id)
+		if i > 0 {
+			c.Assert(entries[i].Sequence, check.Equals, entries[i-1].Sequence+1)
+		}
+	}
+}
+
+func (s *S) TestRecordAfterShutdown(c *check.C) {
+	sink := &fakeSink{}
+	svc := newAuditService(sink, 10)
+	go svc.run()
+	err := svc.Shutdown(context.TODO())
+	c.Assert(err, check.IsNil)
+	svc.Record(context.TODO(), eventTypes.AuditEntry{EventID: "evt1"})
+	c.Assert(sink.entries(c), check.HasLen, 0)
+}
+
+func (s *S) TestWriteSpillsAfterMaxRetries(c *check.C) {
+	sink := &fakeSink{failures: 10}
+	spill := &fakeSink{}
+	svc := newAuditService(sink, 10)
+	svc.maxRetries = 2
+	svc.spill = spill
+	go svc.run()
+	svc.Record(context.TODO(), eventTypes.AuditEntry{EventID: "evt1"})
+	svc.Record(context.TODO(), eventTypes.AuditEntry{EventID: "evt2"})
+	err := svc.Shutdown(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(sink.entries(c), check.HasLen, 0)
+	entries := spill.entries(c)
+	c.Assert(entries, check.HasLen, 2)
+	c.Assert(entries[0].EventID, check.Equals, "evt1")
+	c.Assert(entries[1].EventID, check.Equals, "evt2")
+	c.Assert(spill.closed, check.Equals, true)
+}
+
+func (s *S) TestRecordBufferFull(c *check.C) {
+	sink := &fakeSink{}
+	spill := &fakeSink{}
+	svc := newAuditService(sink, 1)
+	svc.enqueueTimeout = time.Millisecond
+	svc.spill = spill
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, id := range []string{"evt1", "evt2", "evt3"} {
+			svc.Record(context.TODO(), eventTypes.AuditEntry{EventID: id})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		c.Fatal("timeout waiting for entries to be recorded")
+	}
+	entries := spill.entries(c)
+	c.Assert(entries, check.HasLen, 2)
+	c.Assert(entries[0].EventID, check.Equals, "evt2")
+	c.Assert(entries[1].EventID, check.Equals, "evt3")
+	go svc.run()
+	err := svc.Shutdown(context.TODO())
+	c.Assert(err, check.IsNil)
+	entries = sink.entries(c)
+	c.Assert(entries, check.HasLen, 1)
+	c.Assert(entries[0].EventID, check.Equals, "evt1")
+}
+
+func (s *S) TestAuditServiceDisabled(c *check.C) {
+	svc, err := AuditService()
+	c.Assert(err, check.IsNil)
+	c.Assert(svc, check.IsNil)
+}
+
+func (s *S) TestAuditServiceInvalidSink(c *check.C) {
+	config.Set("audit:sink", "kafka")
+	_, err := AuditService()
+	c.Assert(err, check.ErrorMatches, `invalid audit sink "kafka".*`)
+}
+
+func (s *S) TestFileSink(c *check.C) {
+	path := filepath.Join(c.MkDir(), "audit.log")
+	config.Set("audit:file", path)
+	sink, err := newFileSink()
+	c.Assert(err, check.IsNil)
+	err = sink.Write([]byte("{\"sequence\":1}\n"))
+	c.Assert(err, check.IsNil)
+	err = sink.Close()
+	c.Assert(err, check.IsNil)
+	sink, err = newFileSink()
+	c.Assert(err, check.IsNil)
+	err = sink.Write([]byte("{\"sequence\":2}\n"))
+	c.Assert(err, check.IsNil)
+	err = sink.Close()
+	c.Assert(err, check.IsNil)
+	data, err := os.ReadFile(path)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "{\"sequence\":1}\n{\"sequence\":2}\n")
+}
+
+func (s *S) TestAuditServiceSpillFile(c *check.C) {
+	dir := c.MkDir()
+	config.Set("audit:sink", "file")
+	config.Set("audit:file", filepath.Join(dir, "audit.log"))
+	config.Set("audit:spill-file", filepath.Join(dir, "spill.log"))
+	config.Set("audit:max-retries", 0)
+	svc, err := AuditService()
+	c.Assert(err, check.IsNil)
+	as := svc.(*auditService)
+	c.Assert(as.maxRetries, check.Equals, 0)
+	as.spillEntry(eventTypes.AuditEntry{Sequence: 1, EventID: "evt1"}, errors.New("sink unavailable"))
+	err = as.Shutdown(context.TODO())
+	c.Assert(err, check.IsNil)
+	data, err := os.ReadFile(filepath.Join(dir, "spill.log"))
+	c.Assert(err, check.IsNil)
+	c.Assert(strings.Contains(string(data), `"event_id":"evt1"`), check.Equals, true)
+}
+
+func (s *S) TestFileSinkWithoutPath(c *check.C) {
+	_, err := newFileSink()
+	c.Assert(err, check.ErrorMatches, "audit:file is required by the file audit sink")
+}
+
+func (s *S) TestHTTPSink(c *check.C) {
+	var body []byte
+	var req *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		req = r
+	}))
+	defer srv.Close()
+	config.Set("audit:http:url", srv.URL)
+	config.Set("audit:http:headers", map[interface{}]interface{}{"Authorization": "Splunk abc"})
+	sink, err := newHTTPSink()
+	c.Assert(err, check.IsNil)
+	err = sink.Write([]byte("{\"sequence\":1}\n"))
+	c.Assert(err, check.IsNil)
+	c.Assert(string(body), check.Equals, "{\"sequence\":1}\n")
+	c.Assert(req.Method, check.Equals, http.MethodPost)
+	c.Assert(req.Header.Get("Content-Type"), check.Equals, "application/x-ndjson")
+	c.Assert(req.Header.Get("Authorization"), check.Equals, "Splunk abc")
+}
+
+func (s *S) TestHTTPSinkErrorStatus(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("try later"))
+	}))
+	defer srv.Close()
+	config.Set("audit:http:url", srv.URL)
+	sink, err := newHTTPSink()
+	c.Assert(err, check.IsNil)
+	err = sink.Write([]byte("{}\n"))
+	c.Assert(err, check.ErrorMatches, "invalid status code 503 from audit sink: try later")
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	tsuruNet "github.com/tsuru/tsuru/net"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+type fileSink struct {
+	file *os.File
+}
+
+func newFileSink() (Sink, error) {
+	path, err := config.GetString("audit:file")
+	if err != nil {
+		return nil, errors.New("audit:file is required by the file audit sink")
+	}
+	return openFileSink(path)
+}
+
+func openFileSink(path string) (Sink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{file: file}, nil
+}
+
+func (s *fileSink) Write(line []byte) error {
+	_, err := s.file.Write(line)
+	return err
+}
+
+func (s *fileSink) Close() error {
+	return s.file.Close()
+}
+
+type httpSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newHTTPSink() (Sink, error) {
+	url, err := config.GetString("audit:http:url")
+	if err != nil {
+		return nil, errors.New("audit:http:url is required by the http audit sink")
+	}
+	timeout, err := config.GetDuration("audit:http:timeout")
+	if err != nil || timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+	client := *tsuruNet.Dial15Full60ClientWithPool
+	client.Timeout = timeout
+	headers := map[string]string{}
+	if raw, err := config.Get("audit:http:headers"); err == nil {
+		rawHeaders, ok := raw.(map[interface{}]interface{})
+		if !ok {
+			return nil, errors.New("audit:http:headers must be a map of header names to values")
+		}
+		for k, v := range rawHeaders {
+			headers[fmt.Sprint(k)] = fmt.Sprint(v)
+		}
+	}
+	return &httpSink{url: url, headers: headers, client: &client}, nil
+}
+
+func (s *httpSink) Write(line []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(line))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	rsp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(rsp.Body, 1024))
+		return errors.Errorf("invalid status code %d from audit sink: %s", rsp.StatusCode, data)
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	return nil
+}
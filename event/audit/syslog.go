@@ -0,0 +1,45 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package audit
+
+import (
+	"log/syslog"
+
+	"github.com/tsuru/config"
+)
+
+const defaultSyslogTag = "tsuru-audit"
+
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+// newSyslogSink connects to the syslog daemon at audit:syslog:address, using
+// the local one when it's not set.
+func newSyslogSink() (Sink, error) {
+	network, _ := config.GetString("audit:syslog:network")
+	address, _ := config.GetString("audit:syslog:address")
+	tag, _ := config.GetString("audit:syslog:tag")
+	if tag == "" {
+		tag = defaultSyslogTag
+	}
+	w, err := syslog.Dial(network, address, syslog.LOG_AUTH|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{w: w}, nil
+}
+
+func (s *syslogSink) Write(line []byte) error {
+	_, err := s.w.Write(line)
+	return err
+}
+
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
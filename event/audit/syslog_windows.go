@@ -0,0 +1,11 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import "github.com/pkg/errors"
+
+func newSyslogSink() (Sink, error) {
+	return nil, errors.New("syslog doesn't work on Windows")
+}
@@ -22,6 +22,7 @@ import (
 	internalConfig "github.com/tsuru/tsuru/config"
 	"github.com/tsuru/tsuru/db/storagev2"
 	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	eventTypes "github.com/tsuru/tsuru/types/event"
@@ -200,6 +201,19 @@ func getThrottling(t *eventTypes.Target, k *eventTypes.Kind, allTargets bool) *T
 	return nil
 }
 
+type requestIDCtxKey struct{}
+
+// ContextWithRequestID returns a context whose events are tagged with the
+// ID of the API request that originated them.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, requestID)
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDCtxKey{}).(string)
+	return requestID
+}
+
 type Event struct {
 	eventTypes.EventData
 	logMu     sync.Mutex
@@ -292,6 +306,9 @@ func (f *Filter) toQuery() (mongoBSON.M, error) {
 	andBlock := []mongoBSON.M{}
 	if f.Permissions != nil {
 		for _, p := range f.Permissions {
+			if p.Deny {
+				continue
+			}
 			permMap[p.Scheme.FullName()] = append(permMap[p.Scheme.FullName()], p.Context)
 		}
 		var permOrBlock []mongoBSON.M
@@ -768,13 +785,7 @@ func newEvtOnce(ctx context.Context, opts *Opts) (evt *Event, err error) {
 			o.Type = eventTypes.OwnerTypeInternal
 		}
 	} else {
-		if token, ok := opts.Owner.(authTypes.NamedToken); ok {
-			o.Type = eventTypes.OwnerTypeToken
-			o.Name = token.GetTokenName()
-		} else {
-			o.Type = eventTypes.OwnerTypeUser
-			o.Name = opts.Owner.GetUserName()
-		}
+		o = tokenOwner(opts.Owner)
 	}
 
 	collection, err := storagev2.EventsCollection()
@@ -802,10 +813,7 @@ func newEvtOnce(ctx context.Context, opts *Opts) (evt *Event, err error) {
 		return nil, err
 	}
 
-	sourceIP := ""
-	if opts.RemoteAddr != "" {
-		sourceIP, _, _ = net.SplitHostPort(opts.RemoteAddr)
-	}
+	sourceIP := remoteIP(opts.RemoteAddr)
 
 	evt = &Event{
 		EventData: eventTypes.EventData{
@@ -817,6 +825,7 @@ func newEvtOnce(ctx context.Context, opts *Opts) (evt *Event, err error) {
 			Kind:            k,
 			Owner:           o,
 			SourceIP:        sourceIP,
+			RequestID:       requestIDFromContext(ctx),
 			StartCustomData: raw,
 			LockUpdateTime:  now,
 			Running:         true,
@@ -1127,6 +1136,9 @@ func (e *Event) done(ctx context.Context, evtErr error, customData interface{},
 		e.fillLegacyLog()
 		eventDuration.WithLabelValues(e.Kind.Name).Observe(time.Since(e.StartTime).Seconds())
 		eventCurrent.WithLabelValues(e.Kind.Name).Dec()
+		if !abort && servicemanager.Audit != nil {
+			servicemanager.Audit.Record(ctx, e.auditEntry())
+		}
 		if err != nil {
 			log.Errorf("[events] error marking event as done - %#v: %s", e, err)
 		} else {
@@ -1184,6 +1196,83 @@ func (e *Event) done(ctx context.Context, evtErr error, customData interface{},
 	return err
 }
 
+func tokenOwner(token auth.Token) eventTypes.Owner {
+	if named, ok := token.(authTypes.NamedToken); ok {
+		return eventTypes.Owner{Type: eventTypes.OwnerTypeToken, Name: named.GetTokenName()}
+	}
+	return eventTypes.Owner{Type: eventTypes.OwnerTypeUser, Name: token.GetUserName()}
+}
+
+func remoteIP(remoteAddr string) string {
+	if remoteAddr == "" {
+		return ""
+	}
+	ip, _, _ := net.SplitHostPort(remoteAddr)
+	return ip
+}
+
+// AuditDenied writes to the audit stream a request rejected because the
+// owner lacks the permission, such requests don't create events.
+func AuditDenied(ctx context.Context, owner auth.Token, remoteAddr string, denied *permission.DeniedCheck, reason string) {
+	if servicemanager.Audit == nil || owner == nil || denied == nil {
+		return
+	}
+	o := tokenOwner(owner)
+	now := time.Now().UTC()
+	entry := eventTypes.AuditEntry{
+		Version:   eventTypes.AuditSchemaVersion,
+		RequestID: requestIDFromContext(ctx),
+		Time:      now,
+		StartTime: now,
+		Actor:     eventTypes.AuditActor{Type: string(o.Type), Name: o.Name},
+		Action:    denied.Scheme.FullName(),
+		SourceIP:  remoteIP(remoteAddr),
+		Result:    eventTypes.AuditResultDenied,
+		Error:     reason,
+	}
+	if instance, err := servicemanager.InstanceTracker.CurrentInstance(ctx); err == nil {
+		entry.Instance = instance.Name
+	}
+	for i, c := range denied.Contexts {
+		target := eventTypes.AuditTarget{Type: string(c.CtxType), Value: c.Value}
+		if i == 0 {
+			entry.Target = target
+		} else {
+			entry.ExtraTargets = append(entry.ExtraTargets, target)
+		}
+	}
+	servicemanager.Audit.Record(ctx, entry)
+}
+
+func (e *Event) auditEntry() eventTypes.AuditEntry {
+	entry := eventTypes.AuditEntry{
+		Version:   eventTypes.AuditSchemaVersion,
+		Instance:  e.Instance.Name,
+		EventID:   e.UniqueID.Hex(),
+		RequestID: e.RequestID,
+		Time:      e.EndTime,
+		StartTime: e.StartTime,
+		Actor:     eventTypes.AuditActor{Type: string(e.Owner.Type), Name: e.Owner.Name},
+		Action:    e.Kind.Name,
+		Target:    eventTypes.AuditTarget{Type: string(e.Target.Type), Value: e.Target.Value},
+		SourceIP:  e.SourceIP,
+		Result:    eventTypes.AuditResultSuccess,
+		Error:     e.Error,
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	for _, t := range e.ExtraTargets {
+		entry.ExtraTargets = append(entry.ExtraTargets, eventTypes.AuditTarget{Type: string(t.Target.Type), Value: t.Target.Value})
+	}
+	if e.CancelInfo.Canceled {
+		entry.Result = eventTypes.AuditResultCanceled
+	} else if e.Error != "" {
+		entry.Result = eventTypes.AuditResultFailure
+	}
+	return entry
+}
+
 func (e *Event) Log() string {
 	if len(e.StructuredLog) == 0 {
 		return e.EventData.Log
@@ -25,6 +25,7 @@ import (
 	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/safe"
+	"github.com/tsuru/tsuru/servicemanager"
 	servicemock "github.com/tsuru/tsuru/servicemanager/mock"
 	eventTypes "github.com/tsuru/tsuru/types/event"
 	permTypes "github.com/tsuru/tsuru/types/permission"
@@ -1735,3 +1736,97 @@ func (s *S) TestEventOwnerEmailWithAppOwner(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(evt.OwnerEmail(), check.Equals, "")
 }
+
+type fakeAuditService struct {
+	entries []eventTypes.AuditEntry
+}
+
+func (s *fakeAuditService) Record(ctx context.Context, entry eventTypes.AuditEntry) {
+	s.entries = append(s.entries, entry)
+}
+
+func (s *S) TestDoneRecordsAuditEntry(c *check.C) {
+	audit := &fakeAuditService{}
+	servicemanager.Audit = audit
+	defer func() { servicemanager.Audit = nil }()
+	ctx := ContextWithRequestID(context.TODO(), "req-1")
+	evt, err := New(ctx, &Opts{
+		Target:       eventTypes.Target{Type: "app", Value: "myapp"},
+		ExtraTargets: []eventTypes.ExtraTarget{{Target: eventTypes.Target{Type: "pool", Value: "prod"}}},
+		Kind:         permission.PermAppUpdateEnvSet,
+		Owner:        s.token,
+		RemoteAddr:   "10.0.0.1:41234",
+		CustomData:   map[string]string{"SECRET": "value"},
+		Allowed:      Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.RequestID, check.Equals, "req-1")
+	err = evt.Done(ctx, errors.New("deploy failed"))
+	c.Assert(err, check.IsNil)
+	c.Assert(audit.entries, check.DeepEquals, []eventTypes.AuditEntry{{
+		Version:      eventTypes.AuditSchemaVersion,
+		Instance:     evt.Instance.Name,
+		EventID:      evt.UniqueID.Hex(),
+		RequestID:    "req-1",
+		Time:         evt.EndTime,
+		StartTime:    evt.StartTime,
+		Actor:        eventTypes.AuditActor{Type: "user", Name: s.token.GetUserName()},
+		Action:       "app.update.env.set",
+		Target:       eventTypes.AuditTarget{Type: "app", Value: "myapp"},
+		ExtraTargets: []eventTypes.AuditTarget{{Type: "pool", Value: "prod"}},
+		SourceIP:     "10.0.0.1",
+		Result:       eventTypes.AuditResultFailure,
+		Error:        "deploy failed",
+	}})
+}
+
+func (s *S) TestAuditDenied(c *check.C) {
+	audit := &fakeAuditService{}
+	servicemanager.Audit = audit
+	defer func() { servicemanager.Audit = nil }()
+	ctx := ContextWithRequestID(context.TODO(), "req-1")
+	AuditDenied(ctx, s.token, "10.0.0.1:41234", &permission.DeniedCheck{
+		Scheme: permission.PermAppDeploy,
+		Contexts: []permTypes.PermissionContext{
+			permission.Context(permTypes.CtxApp, "myapp"),
+			permission.Context(permTypes.CtxTeam, "myteam"),
+		},
+	}, "You don't have permission to do this action")
+	c.Assert(audit.entries, check.HasLen, 1)
+	entry := audit.entries[0]
+	c.Assert(entry.Time.IsZero(), check.Equals, false)
+	entry.Time = time.Time{}
+	entry.StartTime = time.Time{}
+	instance, err := servicemanager.InstanceTracker.CurrentInstance(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(entry, check.DeepEquals, eventTypes.AuditEntry{
+		Version:      eventTypes.AuditSchemaVersion,
+		Instance:     instance.Name,
+		RequestID:    "req-1",
+		Actor:        eventTypes.AuditActor{Type: "user", Name: s.token.GetUserName()},
+		Action:       "app.deploy",
+		Target:       eventTypes.AuditTarget{Type: "app", Value: "myapp"},
+		ExtraTargets: []eventTypes.AuditTarget{{Type: "team", Value: "myteam"}},
+		SourceIP:     "10.0.0.1",
+		Result:       eventTypes.AuditResultDenied,
+		Error:        "You don't have permission to do this action",
+	})
+	AuditDenied(ctx, s.token, "", nil, "forbidden")
+	c.Assert(audit.entries, check.HasLen, 1)
+}
+
+func (s *S) TestAbortDoesNotRecordAuditEntry(c *check.C) {
+	audit := &fakeAuditService{}
+	servicemanager.Audit = audit
+	defer func() { servicemanager.Audit = nil }()
+	evt, err := New(context.TODO(), &Opts{
+		Target:  eventTypes.Target{Type: "app", Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Abort(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(audit.entries, check.HasLen, 0)
+}
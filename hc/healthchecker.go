@@ -9,6 +9,7 @@ package hc
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -20,13 +21,22 @@ const HealthCheckOK = "WORKING"
 
 var ErrDisabledComponent = errors.New("disabled component")
 
-var checkers []healthChecker
+var (
+	checkersMu sync.RWMutex
+	checkers   []healthChecker
+	sources    []checkerSource
+)
 
 type healthChecker struct {
 	name  string
 	check func(ctx context.Context) error
 }
 
+type checkerSource struct {
+	name   string
+	source CheckerSource
+}
+
 // Result represents a result of a processed healthcheck call. It will contain
 // the name of the healthchecker and the status returned in the checker
 // call.
@@ -37,36 +47,107 @@ type Result struct {
 }
 
 // AddChecker adds a new checker to the internal list of checkers. Checkers
-// added to this list can then be checked using the Check function.
+// added to this list can then be checked using the Check function. Adding a
+// checker with the name of an existing one replaces it.
 func AddChecker(name string, check func(ctx context.Context) error) {
+	checkersMu.Lock()
+	defer checkersMu.Unlock()
 	checker := healthChecker{name: name, check: check}
+	for i := range checkers {
+		if checkers[i].name == name {
+			checkers[i] = checker
+			return
+		}
+	}
 	checkers = append(checkers, checker)
 }
 
+// RemoveChecker removes the checker with the given name, if any.
+func RemoveChecker(name string) {
+	checkersMu.Lock()
+	defer checkersMu.Unlock()
+	for i := range checkers {
+		if checkers[i].name == name {
+			checkers = append(checkers[:i], checkers[i+1:]...)
+			return
+		}
+	}
+}
+
+// CheckerSource returns checkers that can't be registered up front, like the
+// ones for items stored in the database. Sources are resolved on every
+// Check call, so every API instance reports the same checkers.
+type CheckerSource func(ctx context.Context) (map[string]func(ctx context.Context) error, error)
+
+// AddCheckerSource adds a source of checkers. Checkers from sources are only
+// run when requested by name, they are left out when checking "all". A
+// failure resolving the source is reported as a failed check with the given
+// name.
+func AddCheckerSource(name string, source CheckerSource) {
+	checkersMu.Lock()
+	defer checkersMu.Unlock()
+	sources = append(sources, checkerSource{name: name, source: source})
+}
+
 // Check check the status of registered checkers matching names and return a
 // list of results.
 func Check(ctx context.Context, names ...string) []Result {
-	results := make([]Result, 0, len(checkers))
+	checkersMu.RLock()
+	registered := make([]healthChecker, len(checkers))
+	copy(registered, checkers)
+	registeredSources := make([]checkerSource, len(sources))
+	copy(registeredSources, sources)
+	checkersMu.RUnlock()
+	results := make([]Result, 0, len(registered))
 	nameSet := set.FromSlice(names)
 	isAll := nameSet.Includes("all")
-	for _, checker := range checkers {
+	pending := set.FromSlice(names)
+	delete(pending, "all")
+	for _, checker := range registered {
 		if !isAll && !nameSet.Includes(checker.name) {
 			continue
 		}
+		delete(pending, checker.name)
+		results = append(results, runChecker(ctx, checker)...)
+	}
+	if len(pending) == 0 {
+		return results
+	}
+	for _, src := range registeredSources {
 		startTime := time.Now()
-		if err := checker.check(ctx); err != nil && err != ErrDisabledComponent {
+		sourceCheckers, err := src.source(ctx)
+		if err != nil {
 			results = append(results, Result{
-				Name:     checker.name,
+				Name:     src.name,
 				Status:   "fail - " + err.Error(),
 				Duration: time.Since(startTime),
 			})
-		} else if err == nil {
-			results = append(results, Result{
-				Name:     checker.name,
-				Status:   HealthCheckOK,
-				Duration: time.Since(startTime),
-			})
+			continue
+		}
+		for _, name := range pending.Sorted() {
+			if check, ok := sourceCheckers[name]; ok {
+				delete(pending, name)
+				results = append(results, runChecker(ctx, healthChecker{name: name, check: check})...)
+			}
 		}
 	}
 	return results
 }
+
+func runChecker(ctx context.Context, checker healthChecker) []Result {
+	startTime := time.Now()
+	if err := checker.check(ctx); err != nil && err != ErrDisabledComponent {
+		return []Result{{
+			Name:     checker.name,
+			Status:   "fail - " + err.Error(),
+			Duration: time.Since(startTime),
+		}}
+	} else if err == nil {
+		return []Result{{
+			Name:     checker.name,
+			Status:   HealthCheckOK,
+			Duration: time.Since(startTime),
+		}}
+	}
+	return nil
+}
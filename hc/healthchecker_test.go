@@ -22,6 +22,7 @@ var _ = check.Suite(HCSuite{})
 
 func (HCSuite) SetUpTest(c *check.C) {
 	checkers = nil
+	sources = nil
 }
 
 func (HCSuite) TestCheckAll(c *check.C) {
@@ -62,6 +63,67 @@ func (HCSuite) TestCheckFiltered(c *check.C) {
 	c.Assert(result, check.DeepEquals, expected)
 }
 
+func (HCSuite) TestAddCheckerReplacesExisting(c *check.C) {
+	AddChecker("checker1", failingChecker)
+	AddChecker("checker2", successChecker)
+	AddChecker("checker1", successChecker)
+	result := Check(context.TODO(), "all")
+	c.Assert(result, check.HasLen, 2)
+	c.Assert(result[0].Name, check.Equals, "checker1")
+	c.Assert(result[0].Status, check.Equals, HealthCheckOK)
+	c.Assert(result[1].Name, check.Equals, "checker2")
+}
+
+func (HCSuite) TestRemoveChecker(c *check.C) {
+	AddChecker("checker1", successChecker)
+	AddChecker("checker2", failingChecker)
+	RemoveChecker("checker2")
+	RemoveChecker("unknown")
+	result := Check(context.TODO(), "all")
+	c.Assert(result, check.HasLen, 1)
+	c.Assert(result[0].Name, check.Equals, "checker1")
+}
+
+func (HCSuite) TestCheckerSource(c *check.C) {
+	AddChecker("checker1", successChecker)
+	var items []string
+	AddCheckerSource("item-*", func(ctx context.Context) (map[string]func(ctx context.Context) error, error) {
+		result := map[string]func(ctx context.Context) error{}
+		for _, item := range items {
+			result["item-"+item] = failingChecker
+		}
+		return result, nil
+	})
+	result := Check(context.TODO(), "item-a")
+	c.Assert(result, check.HasLen, 0)
+	items = []string{"b", "a"}
+	result = Check(context.TODO(), "item-a")
+	c.Assert(result, check.HasLen, 1)
+	c.Assert(result[0].Name, check.Equals, "item-a")
+	c.Assert(result[0].Status, check.Equals, "fail - something went wrong")
+	result = Check(context.TODO(), "all")
+	c.Assert(result, check.HasLen, 1)
+	c.Assert(result[0].Name, check.Equals, "checker1")
+	result = Check(context.TODO(), "all", "item-b")
+	c.Assert(result, check.HasLen, 2)
+	c.Assert(result[0].Name, check.Equals, "checker1")
+	c.Assert(result[1].Name, check.Equals, "item-b")
+}
+
+func (HCSuite) TestCheckerSourceFailure(c *check.C) {
+	AddChecker("checker1", successChecker)
+	AddCheckerSource("item-*", func(ctx context.Context) (map[string]func(ctx context.Context) error, error) {
+		return nil, errors.New("db down")
+	})
+	result := Check(context.TODO(), "all")
+	c.Assert(result, check.HasLen, 1)
+	c.Assert(result[0].Name, check.Equals, "checker1")
+	result = Check(context.TODO(), "item-a")
+	c.Assert(result, check.HasLen, 1)
+	c.Assert(result[0].Name, check.Equals, "item-*")
+	c.Assert(result[0].Status, check.Equals, "fail - db down")
+}
+
 func successChecker(ctx context.Context) error {
 	return nil
 }
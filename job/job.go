@@ -31,6 +31,7 @@ import (
 	bindTypes "github.com/tsuru/tsuru/types/bind"
 	jobTypes "github.com/tsuru/tsuru/types/job"
 	provTypes "github.com/tsuru/tsuru/types/provision"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
 	mongoBSON "go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -105,6 +106,10 @@ func (*jobService) RemoveJob(ctx context.Context, job *jobTypes.Job) error {
 		return jobTypes.ErrJobNotFound
 	}
 
+	if err = unbindVolumes(ctx, job); err != nil {
+		return err
+	}
+
 	servicemanager.TeamQuota.Inc(ctx, &authTypes.Team{Name: job.TeamOwner}, -1)
 	var user *auth.User
 	if user, err = auth.GetUserByEmail(ctx, job.Owner); err == nil {
@@ -113,6 +118,30 @@ func (*jobService) RemoveJob(ctx context.Context, job *jobTypes.Job) error {
 	return nil
 }
 
+func unbindVolumes(ctx context.Context, job *jobTypes.Job) error {
+	volumes, err := servicemanager.Volume.ListByJob(ctx, job.Name)
+	if err != nil {
+		return errors.Wrap(err, "Unable to list volumes for unbind")
+	}
+	for _, v := range volumes {
+		binds, err := servicemanager.Volume.BindsForJob(ctx, &v, job.Name)
+		if err != nil {
+			return errors.Wrap(err, "Unable to list volume binds for unbind")
+		}
+		for _, b := range binds {
+			err = servicemanager.Volume.UnbindJob(ctx, &volumeTypes.BindOpts{
+				Volume:     &v,
+				JobName:    job.Name,
+				MountPoint: b.ID.MountPoint,
+			})
+			if err != nil {
+				return errors.Wrapf(err, "Unable to unbind volume %q in %q", v.Name, b.ID.MountPoint)
+			}
+		}
+	}
+	return nil
+}
+
 func (*jobService) RemoveJobProv(ctx context.Context, job *jobTypes.Job) error {
 	prov, err := getProvisioner(ctx, job)
 	if err != nil {
@@ -23,6 +23,7 @@ import (
 	jobTypes "github.com/tsuru/tsuru/types/job"
 	provisionTypes "github.com/tsuru/tsuru/types/provision"
 	"github.com/tsuru/tsuru/types/quota"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
 	"gopkg.in/check.v1"
 )
 
@@ -199,6 +200,40 @@ func (s *S) TestDeleteJobFromDB(c *check.C) {
 	c.Assert(err, check.Equals, jobTypes.ErrJobNotFound)
 }
 
+func (s *S) TestDeleteJobUnbindsVolumes(c *check.C) {
+	newJob := jobTypes.Job{
+		Name:      "some-job",
+		TeamOwner: s.team.Name,
+		Pool:      s.Pool,
+		Spec: jobTypes.JobSpec{
+			Schedule: "* * * * *",
+			Container: jobTypes.ContainerInfo{
+				OriginalImageSrc: "alpine:latest",
+				Command:          []string{"echo", "hello!"},
+			},
+		},
+	}
+	err := servicemanager.Job.CreateJob(context.TODO(), &newJob, s.user)
+	c.Assert(err, check.IsNil)
+	s.mockService.VolumeService.OnListByJob = func(ctx context.Context, jobName string) ([]volumeTypes.Volume, error) {
+		c.Assert(jobName, check.Equals, newJob.Name)
+		return []volumeTypes.Volume{{Name: "v1"}}, nil
+	}
+	s.mockService.VolumeService.OnBindsForJob = func(ctx context.Context, v *volumeTypes.Volume, jobName string) ([]volumeTypes.VolumeBind, error) {
+		return []volumeTypes.VolumeBind{
+			{ID: volumeTypes.VolumeBindID{Job: jobName, Volume: v.Name, MountPoint: "/data"}},
+		}, nil
+	}
+	var unbound []string
+	s.mockService.VolumeService.OnUnbindJob = func(ctx context.Context, opts *volumeTypes.BindOpts) error {
+		unbound = append(unbound, opts.Volume.Name+":"+opts.JobName+":"+opts.MountPoint)
+		return nil
+	}
+	err = servicemanager.Job.RemoveJob(context.TODO(), &newJob)
+	c.Assert(err, check.IsNil)
+	c.Assert(unbound, check.DeepEquals, []string{"v1:some-job:/data"})
+}
+
 func (s *S) TestIncreaseDecreaseQuotaForJob(c *check.C) {
 	var userinUseNow *int
 	var teaminUseNow *int
@@ -0,0 +1,54 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package permission
+
+import (
+	"context"
+	"sync"
+
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// DeniedCheck is a permission check that failed while handling a request, so
+// a rejected request can be reported along with the permission it lacked.
+// Reason is set when the policy webhook denied the check and explained why.
+type DeniedCheck struct {
+	Scheme   *permTypes.PermissionScheme
+	Contexts []permTypes.PermissionContext
+	Reason   string
+}
+
+type deniedChecks struct {
+	mu   sync.Mutex
+	last *DeniedCheck
+}
+
+type deniedChecksCtxKey struct{}
+
+// ContextWithDeniedChecks returns a context where the checks denied by Check
+// are kept, the last one is available through LastDeniedCheck.
+func ContextWithDeniedChecks(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deniedChecksCtxKey{}, &deniedChecks{})
+}
+
+func LastDeniedCheck(ctx context.Context) *DeniedCheck {
+	checks, ok := ctx.Value(deniedChecksCtxKey{}).(*deniedChecks)
+	if !ok {
+		return nil
+	}
+	checks.mu.Lock()
+	defer checks.mu.Unlock()
+	return checks.last
+}
+
+func recordDeniedCheck(ctx context.Context, scheme *permTypes.PermissionScheme, contexts []permTypes.PermissionContext, reason string) {
+	checks, ok := ctx.Value(deniedChecksCtxKey{}).(*deniedChecks)
+	if !ok {
+		return
+	}
+	checks.mu.Lock()
+	defer checks.mu.Unlock()
+	checks.last = &DeniedCheck{Scheme: scheme, Contexts: contexts, Reason: reason}
+}
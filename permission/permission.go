@@ -51,49 +51,124 @@ type Token interface {
 func ContextsFromListForPermission(perms []permTypes.Permission, scheme *permTypes.PermissionScheme, ctxTypes ...permTypes.ContextType) []permTypes.PermissionContext {
 	var contexts []permTypes.PermissionContext
 	for _, perm := range perms {
-		if perm.Scheme.IsParent(scheme) {
-			if len(ctxTypes) > 0 {
-				for _, t := range ctxTypes {
-					if t == perm.Context.CtxType {
-						contexts = append(contexts, perm.Context)
-					}
+		if perm.Deny || !perm.Scheme.IsParent(scheme) {
+			continue
+		}
+		if len(ctxTypes) > 0 {
+			for _, t := range ctxTypes {
+				if t == perm.Context.CtxType {
+					contexts = append(contexts, perm.Context)
 				}
-			} else {
-				contexts = append(contexts, perm.Context)
 			}
+		} else {
+			contexts = append(contexts, perm.Context)
 		}
 	}
-	return contexts
+	return removeDeniedContexts(perms, scheme, contexts)
 }
 
+// removeDeniedContexts filters out contexts where the permission is denied,
+// a global deny removes every context. The global context is also removed
+// when scoped denies exist, as it would otherwise be taken as unrestricted
+// access to the denied contexts.
+func removeDeniedContexts(perms []permTypes.Permission, scheme *permTypes.PermissionScheme, contexts []permTypes.PermissionContext) []permTypes.PermissionContext {
+	var filtered []permTypes.PermissionContext
+	for _, ctx := range contexts {
+		if ctx.CtxType == permTypes.CtxGlobal && hasDeny(perms, scheme) {
+			continue
+		}
+		if !isDenied(perms, scheme, ctx) {
+			filtered = append(filtered, ctx)
+		}
+	}
+	return filtered
+}
+
+// ContextsForPermission returns the contexts where the token is allowed the
+// permission, consistent with Check: contexts rejected by the policy webhook
+// are left out.
 func ContextsForPermission(ctx context.Context, token Token, scheme *permTypes.PermissionScheme, ctxTypes ...permTypes.ContextType) []permTypes.PermissionContext {
 	perms, err := token.Permissions(ctx)
 	if err != nil {
 		return []permTypes.PermissionContext{}
 	}
-	return ContextsFromListForPermission(perms, scheme, ctxTypes...)
+	contexts := ContextsFromListForPermission(perms, scheme, ctxTypes...)
+	return policyAllowedContexts(ctx, token, scheme, contexts)
 }
 
 func Check(ctx context.Context, token Token, scheme *permTypes.PermissionScheme, contexts ...permTypes.PermissionContext) bool {
+	allowed, reason := allows(ctx, token, scheme, contexts)
+	if !allowed {
+		recordDeniedCheck(ctx, scheme, contexts, reason)
+	}
+	return allowed
+}
+
+func allows(ctx context.Context, token Token, scheme *permTypes.PermissionScheme, contexts []permTypes.PermissionContext) (bool, string) {
 	perms, err := token.Permissions(ctx)
 	if err != nil {
 		log.Errorf("unable to read token permissions: %v", err)
-		return false
+		return false, ""
+	}
+	if !CheckFromPermList(perms, scheme, contexts...) {
+		return false, ""
 	}
-	return CheckFromPermList(perms, scheme, contexts...)
+	return policyAllows(ctx, token, scheme, contexts)
 }
 
+// CheckFromPermList reports whether perms allow the scheme in at least one of
+// the contexts. Each context is evaluated on its own: an allow through one
+// context is not cancelled by a deny on another, but a global deny applies
+// to all of them.
 func CheckFromPermList(perms []permTypes.Permission, scheme *permTypes.PermissionScheme, contexts ...permTypes.PermissionContext) bool {
+	if isDenied(perms, scheme) {
+		return false
+	}
 	for _, perm := range perms {
-		if perm.Scheme.IsParent(scheme) {
-			if perm.Context.CtxType == permTypes.CtxGlobal {
+		if perm.Deny || !perm.Scheme.IsParent(scheme) {
+			continue
+		}
+		if perm.Context.CtxType == permTypes.CtxGlobal && len(contexts) == 0 {
+			return true
+		}
+		for _, ctx := range contexts {
+			if matchesContext(perm, []permTypes.PermissionContext{ctx}) && !isDenied(perms, scheme, ctx) {
 				return true
 			}
-			for _, ctx := range contexts {
-				if ctx.CtxType == perm.Context.CtxType && ctx.Value == perm.Context.Value {
-					return true
-				}
-			}
+		}
+	}
+	return false
+}
+
+// isDenied reports whether a deny entry in perms covers the scheme in the
+// given context. Global denies apply to every context, and are the only ones
+// considered when no context is given.
+func isDenied(perms []permTypes.Permission, scheme *permTypes.PermissionScheme, contexts ...permTypes.PermissionContext) bool {
+	for _, perm := range perms {
+		if perm.Deny && perm.Scheme.IsParent(scheme) && matchesContext(perm, contexts) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDeny reports whether any deny entry in perms covers the scheme.
+func hasDeny(perms []permTypes.Permission, scheme *permTypes.PermissionScheme) bool {
+	for _, perm := range perms {
+		if perm.Deny && perm.Scheme.IsParent(scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesContext(perm permTypes.Permission, contexts []permTypes.PermissionContext) bool {
+	if perm.Context.CtxType == permTypes.CtxGlobal {
+		return true
+	}
+	for _, ctx := range contexts {
+		if ctx.CtxType == perm.Context.CtxType && ctx.Value == perm.Context.Value {
+			return true
 		}
 	}
 	return false
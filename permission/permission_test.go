@@ -70,6 +70,89 @@ func (s *S) TestCheck(c *check.C) {
 	c.Assert(Check(ctx, t, PermAppUpdateEnvUnset), check.Equals, true)
 }
 
+func (s *S) TestCheckDeny(c *check.C) {
+	ctx := context.TODO()
+	t := &userToken{
+		permissions: []permTypes.Permission{
+			{Scheme: PermApp, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}},
+			{Scheme: PermApp, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team2"}},
+			{Scheme: PermAppRun, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}, Deny: true},
+		},
+	}
+	c.Assert(Check(ctx, t, PermAppDeploy, permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}), check.Equals, true)
+	c.Assert(Check(ctx, t, PermAppRun, permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}), check.Equals, false)
+	c.Assert(Check(ctx, t, PermAppRunShell, permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}), check.Equals, false)
+	c.Assert(Check(ctx, t, PermAppRunShell, permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team2"}), check.Equals, true)
+	c.Assert(Check(ctx, t, PermApp, permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}), check.Equals, true)
+	c.Assert(ContextsForPermission(ctx, t, PermAppRunShell), check.DeepEquals, []permTypes.PermissionContext{
+		{CtxType: permTypes.CtxTeam, Value: "team2"},
+	})
+}
+
+func (s *S) TestCheckGlobalDeny(c *check.C) {
+	ctx := context.TODO()
+	t := &userToken{
+		permissions: []permTypes.Permission{
+			{Scheme: PermAll, Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal}},
+			{Scheme: PermAppRunShell, Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal}, Deny: true},
+		},
+	}
+	c.Assert(Check(ctx, t, PermAppDeploy, permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}), check.Equals, true)
+	c.Assert(Check(ctx, t, PermAppRunShell, permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}), check.Equals, false)
+	c.Assert(Check(ctx, t, PermAppRunShell), check.Equals, false)
+	c.Assert(ContextsForPermission(ctx, t, PermAppRunShell), check.IsNil)
+}
+
+func (s *S) TestCheckDenyOtherContext(c *check.C) {
+	ctx := context.TODO()
+	t := &userToken{
+		permissions: []permTypes.Permission{
+			{Scheme: PermAppDeploy, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "teamB"}},
+			{Scheme: PermAppDeploy, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "teamA"}, Deny: true},
+		},
+	}
+	c.Assert(Check(ctx, t, PermAppDeploy,
+		permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "teamA"},
+		permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "teamB"},
+	), check.Equals, true)
+	c.Assert(Check(ctx, t, PermAppDeploy, permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "teamA"}), check.Equals, false)
+}
+
+func (s *S) TestCheckGlobalAllowTeamDeny(c *check.C) {
+	ctx := context.TODO()
+	t := &userToken{
+		permissions: []permTypes.Permission{
+			{Scheme: PermApp, Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal}},
+			{Scheme: PermAppDeploy, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "teamA"}, Deny: true},
+		},
+	}
+	c.Assert(Check(ctx, t, PermAppDeploy, permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "teamA"}), check.Equals, false)
+	c.Assert(Check(ctx, t, PermAppDeploy, permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "teamB"}), check.Equals, true)
+	c.Assert(Check(ctx, t, PermAppDeploy), check.Equals, true)
+	c.Assert(ContextsForPermission(ctx, t, PermAppDeploy), check.IsNil)
+	c.Assert(ContextsForPermission(ctx, t, PermAppRead), check.DeepEquals, []permTypes.PermissionContext{
+		{CtxType: permTypes.CtxGlobal},
+	})
+}
+
+func (s *S) TestCheckRecordsDeniedCheck(c *check.C) {
+	ctx := ContextWithDeniedChecks(context.TODO())
+	t := &userToken{
+		permissions: []permTypes.Permission{
+			{Scheme: PermAppUpdate, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}},
+		},
+	}
+	c.Assert(LastDeniedCheck(ctx), check.IsNil)
+	c.Assert(Check(ctx, t, PermAppUpdate, Context(permTypes.CtxTeam, "team1")), check.Equals, true)
+	c.Assert(LastDeniedCheck(ctx), check.IsNil)
+	c.Assert(Check(ctx, t, PermAppDeploy, Context(permTypes.CtxTeam, "team1")), check.Equals, false)
+	c.Assert(LastDeniedCheck(ctx), check.DeepEquals, &DeniedCheck{
+		Scheme:   PermAppDeploy,
+		Contexts: []permTypes.PermissionContext{Context(permTypes.CtxTeam, "team1")},
+	})
+	c.Assert(LastDeniedCheck(context.TODO()), check.IsNil)
+}
+
 func (s *S) TestCheckSuperToken(c *check.C) {
 	ctx := context.TODO()
 	t := &userToken{
@@ -91,13 +91,21 @@ var (
 	PermClusterDelete                    = PermissionRegistry.get("cluster.delete")                      // [global]
 	PermClusterRead                      = PermissionRegistry.get("cluster.read")                        // [global]
 	PermClusterReadEvents                = PermissionRegistry.get("cluster.read.events")                 // [global]
+	PermClusterReadNodes                 = PermissionRegistry.get("cluster.read.nodes")                  // [global]
 	PermClusterUpdate                    = PermissionRegistry.get("cluster.update")                      // [global]
+	PermClusterUpdateCredentials         = PermissionRegistry.get("cluster.update.credentials")          // [global]
+	PermClusterUpdateEvacuate            = PermissionRegistry.get("cluster.update.evacuate")             // [global]
 	PermDebug                            = PermissionRegistry.get("debug")                               // [global]
 	PermEventBlock                       = PermissionRegistry.get("event-block")                         // [global]
 	PermEventBlockAdd                    = PermissionRegistry.get("event-block.add")                     // [global]
 	PermEventBlockRead                   = PermissionRegistry.get("event-block.read")                    // [global]
 	PermEventBlockReadEvents             = PermissionRegistry.get("event-block.read.events")             // [global]
 	PermEventBlockRemove                 = PermissionRegistry.get("event-block.remove")                  // [global]
+	PermGroup                            = PermissionRegistry.get("group")                               // [global]
+	PermGroupDelete                      = PermissionRegistry.get("group.delete")                        // [global]
+	PermGroupRead                        = PermissionRegistry.get("group.read")                          // [global]
+	PermGroupReadEvents                  = PermissionRegistry.get("group.read.events")                   // [global]
+	PermGroupUpdate                      = PermissionRegistry.get("group.update")                        // [global]
 	PermJob                              = PermissionRegistry.get("job")                                 // [global team pool job]
 	PermJobCreate                        = PermissionRegistry.get("job.create")                          // [global team]
 	PermJobDelete                        = PermissionRegistry.get("job.delete")                          // [global team pool job]
@@ -127,8 +135,10 @@ var (
 	PermPoolCreate                       = PermissionRegistry.get("pool.create")                         // [global]
 	PermPoolDelete                       = PermissionRegistry.get("pool.delete")                         // [global pool]
 	PermPoolRead                         = PermissionRegistry.get("pool.read")                           // [global pool]
+	PermPoolReadCapacity                 = PermissionRegistry.get("pool.read.capacity")                  // [global pool]
 	PermPoolReadConstraints              = PermissionRegistry.get("pool.read.constraints")               // [global pool]
 	PermPoolReadEvents                   = PermissionRegistry.get("pool.read.events")                    // [global pool]
+	PermPoolReadNodes                    = PermissionRegistry.get("pool.read.nodes")                     // [global pool]
 	PermPoolUpdate                       = PermissionRegistry.get("pool.update")                         // [global pool]
 	PermPoolUpdateConstraints            = PermissionRegistry.get("pool.update.constraints")             // [global pool]
 	PermPoolUpdateConstraintsSet         = PermissionRegistry.get("pool.update.constraints.set")         // [global pool]
@@ -147,8 +157,12 @@ var (
 	PermRoleUpdateAssign                 = PermissionRegistry.get("role.update.assign")                  // [global]
 	PermRoleUpdateContext                = PermissionRegistry.get("role.update.context")                 // [global]
 	PermRoleUpdateContextType            = PermissionRegistry.get("role.update.context.type")            // [global]
+	PermRoleUpdateDeny                   = PermissionRegistry.get("role.update.deny")                    // [global]
+	PermRoleUpdateDenyAdd                = PermissionRegistry.get("role.update.deny.add")                // [global]
+	PermRoleUpdateDenyRemove             = PermissionRegistry.get("role.update.deny.remove")             // [global]
 	PermRoleUpdateDescription            = PermissionRegistry.get("role.update.description")             // [global]
 	PermRoleUpdateDissociate             = PermissionRegistry.get("role.update.dissociate")              // [global]
+	PermRoleUpdateMfa                    = PermissionRegistry.get("role.update.mfa")                     // [global]
 	PermRoleUpdateName                   = PermissionRegistry.get("role.update.name")                    // [global]
 	PermRoleUpdatePermission             = PermissionRegistry.get("role.update.permission")              // [global]
 	PermRoleUpdatePermissionAdd          = PermissionRegistry.get("role.update.permission.add")          // [global]
@@ -159,6 +173,7 @@ var (
 	PermRouterRead                       = PermissionRegistry.get("router.read")                         // [global router]
 	PermRouterReadEvents                 = PermissionRegistry.get("router.read.events")                  // [global router]
 	PermRouterUpdate                     = PermissionRegistry.get("router.update")                       // [global router]
+	PermScim                             = PermissionRegistry.get("scim")                                // [global]
 	PermService                          = PermissionRegistry.get("service")                             // [global service team]
 	PermServiceInstance                  = PermissionRegistry.get("service-instance")                    // [global service-instance team]
 	PermServiceInstanceCreate            = PermissionRegistry.get("service-instance.create")             // [global team]
@@ -168,6 +183,7 @@ var (
 	PermServiceInstanceReadStatus        = PermissionRegistry.get("service-instance.read.status")        // [global service-instance team]
 	PermServiceInstanceUpdate            = PermissionRegistry.get("service-instance.update")             // [global service-instance team]
 	PermServiceInstanceUpdateBind        = PermissionRegistry.get("service-instance.update.bind")        // [global service-instance team]
+	PermServiceInstanceUpdateCredentials = PermissionRegistry.get("service-instance.update.credentials") // [global service-instance team]
 	PermServiceInstanceUpdateDescription = PermissionRegistry.get("service-instance.update.description") // [global service-instance team]
 	PermServiceInstanceUpdateGrant       = PermissionRegistry.get("service-instance.update.grant")       // [global service-instance team]
 	PermServiceInstanceUpdateParameters  = PermissionRegistry.get("service-instance.update.parameters")  // [global service-instance team]
@@ -200,17 +216,22 @@ var (
 	PermTeamTokenRead                    = PermissionRegistry.get("team.token.read")                     // [global team]
 	PermTeamTokenUpdate                  = PermissionRegistry.get("team.token.update")                   // [global team]
 	PermTeamUpdate                       = PermissionRegistry.get("team.update")                         // [global team]
+	PermTeamUpdateParent                 = PermissionRegistry.get("team.update.parent")                  // [global team]
 	PermTeamUpdateQuota                  = PermissionRegistry.get("team.update.quota")                   // [global team]
 	PermUser                             = PermissionRegistry.get("user")                                // [global user]
 	PermUserCreate                       = PermissionRegistry.get("user.create")                         // [global]
 	PermUserDelete                       = PermissionRegistry.get("user.delete")                         // [global user]
 	PermUserRead                         = PermissionRegistry.get("user.read")                           // [global user]
 	PermUserReadEvents                   = PermissionRegistry.get("user.read.events")                    // [global user]
+	PermUserReadPermissions              = PermissionRegistry.get("user.read.permissions")               // [global user]
 	PermUserReadQuota                    = PermissionRegistry.get("user.read.quota")                     // [global user]
+	PermUserReadSessions                 = PermissionRegistry.get("user.read.sessions")                  // [global user]
 	PermUserUpdate                       = PermissionRegistry.get("user.update")                         // [global user]
+	PermUserUpdateMfa                    = PermissionRegistry.get("user.update.mfa")                     // [global user]
 	PermUserUpdatePassword               = PermissionRegistry.get("user.update.password")                // [global user]
 	PermUserUpdateQuota                  = PermissionRegistry.get("user.update.quota")                   // [global user]
 	PermUserUpdateReset                  = PermissionRegistry.get("user.update.reset")                   // [global user]
+	PermUserUpdateSessions               = PermissionRegistry.get("user.update.sessions")                // [global user]
 	PermVolume                           = PermissionRegistry.get("volume")                              // [global volume team pool]
 	PermVolumeCreate                     = PermissionRegistry.get("volume.create")                       // [global team pool]
 	PermVolumeDelete                     = PermissionRegistry.get("volume.delete")                       // [global volume team pool]
@@ -218,6 +239,10 @@ var (
 	PermVolumeReadEvents                 = PermissionRegistry.get("volume.read.events")                  // [global volume team pool]
 	PermVolumeUpdate                     = PermissionRegistry.get("volume.update")                       // [global volume team pool]
 	PermVolumeUpdateBind                 = PermissionRegistry.get("volume.update.bind")                  // [global volume team pool]
+	PermVolumeUpdateGrantAccess          = PermissionRegistry.get("volume.update.grant-access")          // [global volume team pool]
+	PermVolumeUpdateResize               = PermissionRegistry.get("volume.update.resize")                // [global volume team pool]
+	PermVolumeUpdateRevokeAccess         = PermissionRegistry.get("volume.update.revoke-access")         // [global volume team pool]
+	PermVolumeUpdateSnapshot             = PermissionRegistry.get("volume.update.snapshot")              // [global volume team pool]
 	PermVolumeUpdateUnbind               = PermissionRegistry.get("volume.update.unbind")                // [global volume team pool]
 	PermWebhook                          = PermissionRegistry.get("webhook")                             // [global team]
 	PermWebhookCreate                    = PermissionRegistry.get("webhook.create")                      // [global team]
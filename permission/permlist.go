@@ -94,6 +94,7 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"team.token.update",
 	"team.read.quota",
 	"team.update.quota",
+	"team.update.parent",
 ).addWithCtx(
 	"user", []permTypes.ContextType{permTypes.CtxUser},
 ).addWithCtx(
@@ -101,10 +102,14 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 ).add(
 	"user.delete",
 	"user.read.events",
+	"user.read.permissions",
 	"user.read.quota",
+	"user.read.sessions",
 	"user.update.quota",
 	"user.update.password",
 	"user.update.reset",
+	"user.update.sessions",
+	"user.update.mfa",
 ).addWithCtx(
 	"apikey", []permTypes.ContextType{permTypes.CtxUser},
 ).add(
@@ -141,6 +146,7 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"service-instance.update.teamowner",
 	"service-instance.update.plan",
 	"service-instance.update.parameters",
+	"service-instance.update.credentials",
 ).add(
 	"role.create",
 	"role.delete",
@@ -152,8 +158,18 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"role.update.context.type",
 	"role.update.permission.add",
 	"role.update.permission.remove",
+	"role.update.deny.add",
+	"role.update.deny.remove",
+	"role.update.mfa",
 	"role.default.create",
 	"role.default.delete",
+).add(
+	"group.read",
+	"group.read.events",
+	"group.update",
+	"group.delete",
+).add(
+	"scim",
 ).add(
 	"platform.create",
 	"platform.delete",
@@ -173,6 +189,8 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"pool.update.team.remove",
 	"pool.update.constraints.set",
 	"pool.read.constraints",
+	"pool.read.capacity",
+	"pool.read.nodes",
 	"pool.delete",
 ).add(
 	"debug",
@@ -184,8 +202,11 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 ).add(
 	"cluster.admin",
 	"cluster.read.events",
+	"cluster.read.nodes",
 	"cluster.create",
 	"cluster.update",
+	"cluster.update.credentials",
+	"cluster.update.evacuate",
 	"cluster.delete",
 ).addWithCtx(
 	"volume", []permTypes.ContextType{permTypes.CtxVolume, permTypes.CtxTeam, permTypes.CtxPool},
@@ -195,6 +216,10 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"volume.read.events",
 	"volume.update.bind",
 	"volume.update.unbind",
+	"volume.update.snapshot",
+	"volume.update.resize",
+	"volume.update.grant-access",
+	"volume.update.revoke-access",
 	"volume.delete",
 ).addWithCtx(
 	"webhook", []permTypes.ContextType{permTypes.CtxTeam},
@@ -0,0 +1,249 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package permission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/log"
+	tsuruNet "github.com/tsuru/tsuru/net"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const defaultPolicyTimeout = 5 * time.Second
+
+// RequestInfo describes the API request being authorized, it's sent to the
+// policy webhook along with the permission. Only the headers listed in
+// authorization:webhook:request-headers are included.
+type RequestInfo struct {
+	Method     string      `json:"method"`
+	Path       string      `json:"path"`
+	Query      url.Values  `json:"query,omitempty"`
+	Form       url.Values  `json:"form,omitempty"`
+	Headers    http.Header `json:"headers,omitempty"`
+	RemoteAddr string      `json:"remoteAddr,omitempty"`
+	UserAgent  string      `json:"userAgent,omitempty"`
+	RequestID  string      `json:"requestID,omitempty"`
+}
+
+type requestInfoCtxKey struct{}
+
+func ContextWithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoCtxKey{}, info)
+}
+
+func RequestInfoFromContext(ctx context.Context) *RequestInfo {
+	info, ok := ctx.Value(requestInfoCtxKey{}).(RequestInfo)
+	if !ok {
+		return nil
+	}
+	return &info
+}
+
+// PolicyInput is the document sent to the policy webhook, wrapped in an
+// "input" key so it can be posted directly to the OPA data API. Filter is set
+// when the contexts are being listed rather than checked: each context is
+// evaluated on its own and the webhook may answer with the allowed ones.
+type PolicyInput struct {
+	User       string         `json:"user,omitempty"`
+	Engine     string         `json:"engine,omitempty"`
+	Permission string         `json:"permission"`
+	Contexts   []PolicyTarget `json:"contexts"`
+	Filter     bool           `json:"filter,omitempty"`
+	Request    *RequestInfo   `json:"request,omitempty"`
+}
+
+type PolicyTarget struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// PolicyDecision is the result expected from the policy webhook, either as
+// the "result" key, like OPA responses, or as the whole response body. A
+// plain boolean result is accepted as well. When filtering, AllowedContexts
+// lists the contexts allowed, if absent Allowed applies to all of them.
+type PolicyDecision struct {
+	Allowed         bool           `json:"allowed"`
+	Reason          string         `json:"reason,omitempty"`
+	AllowedContexts []PolicyTarget `json:"allowedContexts,omitempty"`
+}
+
+type identifiedToken interface {
+	GetUserName() string
+	Engine() string
+}
+
+// policyAllows consults the webhook configured in authorization:webhook,
+// after the role check already allowed the action. Only permissions under
+// one of the schemes in authorization:webhook:permissions are sent. The
+// reason given by the webhook is returned when it denies the action.
+func policyAllows(ctx context.Context, token Token, scheme *permTypes.PermissionScheme, contexts []permTypes.PermissionContext) (bool, string) {
+	if !PolicyEnabled() || !policyApplies(scheme) {
+		return true, ""
+	}
+	decision := consultPolicy(ctx, token, scheme, contexts, false)
+	return decision.Allowed, decision.Reason
+}
+
+// policyAllowedContexts returns the contexts allowed by the webhook, asking
+// for all of them in a single call.
+func policyAllowedContexts(ctx context.Context, token Token, scheme *permTypes.PermissionScheme, contexts []permTypes.PermissionContext) []permTypes.PermissionContext {
+	if len(contexts) == 0 || !PolicyEnabled() || !policyApplies(scheme) {
+		return contexts
+	}
+	decision := consultPolicy(ctx, token, scheme, contexts, true)
+	if decision.AllowedContexts == nil {
+		if decision.Allowed {
+			return contexts
+		}
+		return nil
+	}
+	allowed := make(map[PolicyTarget]struct{}, len(decision.AllowedContexts))
+	for _, target := range decision.AllowedContexts {
+		allowed[target] = struct{}{}
+	}
+	var result []permTypes.PermissionContext
+	for _, c := range contexts {
+		if _, ok := allowed[PolicyTarget{Type: string(c.CtxType), Value: c.Value}]; ok {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func consultPolicy(ctx context.Context, token Token, scheme *permTypes.PermissionScheme, contexts []permTypes.PermissionContext, filter bool) *PolicyDecision {
+	url, _ := config.GetString("authorization:webhook:url")
+	input := PolicyInput{
+		Permission: scheme.FullName(),
+		Contexts:   make([]PolicyTarget, len(contexts)),
+		Filter:     filter,
+		Request:    RequestInfoFromContext(ctx),
+	}
+	if t, ok := token.(identifiedToken); ok {
+		input.User = t.GetUserName()
+		input.Engine = t.Engine()
+	}
+	for i, c := range contexts {
+		input.Contexts[i] = PolicyTarget{Type: string(c.CtxType), Value: c.Value}
+	}
+	decision, err := callPolicyWebhook(ctx, url, input)
+	if err != nil {
+		failOpen, _ := config.GetBool("authorization:webhook:fail-open")
+		log.Errorf("[authorization webhook] unable to check %q for %q, allowed: %v: %v", input.Permission, input.User, failOpen, err)
+		return &PolicyDecision{Allowed: failOpen}
+	}
+	if !decision.Allowed && decision.AllowedContexts == nil {
+		log.Debugf("[authorization webhook] %q denied for %q: %s", input.Permission, input.User, decision.Reason)
+	}
+	return decision
+}
+
+// PolicyEnabled reports whether a policy webhook is configured, so callers
+// only gather request details when they may be sent.
+func PolicyEnabled() bool {
+	webhookURL, _ := config.GetString("authorization:webhook:url")
+	return webhookURL != ""
+}
+
+// PolicyRequestHeaders returns the request headers sent to the policy
+// webhook, as listed in authorization:webhook:request-headers. Credentials
+// are never sent, even if listed.
+func PolicyRequestHeaders(header http.Header) http.Header {
+	names, _ := config.GetList("authorization:webhook:request-headers")
+	var headers http.Header
+	for _, name := range names {
+		name = http.CanonicalHeaderKey(name)
+		if name == "Authorization" || name == "Cookie" {
+			continue
+		}
+		if values := header.Values(name); len(values) > 0 {
+			if headers == nil {
+				headers = http.Header{}
+			}
+			headers[name] = values
+		}
+	}
+	return headers
+}
+
+func policyApplies(scheme *permTypes.PermissionScheme) bool {
+	names, _ := config.GetList("authorization:webhook:permissions")
+	for _, name := range names {
+		subR := PermissionRegistry.getSubRegistry(name)
+		if subR != nil && subR.PermissionScheme.IsParent(scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+func callPolicyWebhook(ctx context.Context, url string, input PolicyInput) (*PolicyDecision, error) {
+	body, err := json.Marshal(map[string]PolicyInput{"input": input})
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := config.GetDuration("authorization:webhook:timeout")
+	if err != nil || timeout <= 0 {
+		timeout = defaultPolicyTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if raw, err := config.Get("authorization:webhook:headers"); err == nil {
+		headers, _ := raw.(map[interface{}]interface{})
+		for k, v := range headers {
+			req.Header.Set(fmt.Sprint(k), fmt.Sprint(v))
+		}
+	}
+	rsp, err := tsuruNet.Dial15Full60ClientWithPool.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	data, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("invalid status code %d: %s", rsp.StatusCode, data)
+	}
+	return parsePolicyDecision(data)
+}
+
+func parsePolicyDecision(data []byte) (*PolicyDecision, error) {
+	var wrapped struct {
+		Result json.RawMessage `json:"result"`
+	}
+	err := json.Unmarshal(data, &wrapped)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse policy decision")
+	}
+	if len(wrapped.Result) == 0 {
+		wrapped.Result = data
+	}
+	var allowed bool
+	if json.Unmarshal(wrapped.Result, &allowed) == nil {
+		return &PolicyDecision{Allowed: allowed}, nil
+	}
+	var decision PolicyDecision
+	err = json.Unmarshal(wrapped.Result, &decision)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse policy decision")
+	}
+	return &decision, nil
+}
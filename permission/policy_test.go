@@ -0,0 +1,187 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package permission
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/config"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+type namedUserToken struct {
+	userToken
+	name string
+}
+
+func (t *namedUserToken) GetUserName() string {
+	return t.name
+}
+
+func (t *namedUserToken) Engine() string {
+	return "native"
+}
+
+func (s *S) policyServer(c *check.C, response string, inputs *[]PolicyInput) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input PolicyInput `json:"input"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&body)
+		c.Check(err, check.IsNil)
+		*inputs = append(*inputs, body.Input)
+		w.Write([]byte(response))
+	}))
+}
+
+func (s *S) TestCheckPolicyWebhookDenies(c *check.C) {
+	var inputs []PolicyInput
+	srv := s.policyServer(c, `{"result": {"allowed": false, "reason": "no deploys on fridays"}}`, &inputs)
+	defer srv.Close()
+	config.Set("authorization:webhook:url", srv.URL)
+	config.Set("authorization:webhook:permissions", []interface{}{"app.deploy"})
+	defer config.Unset("authorization")
+	t := &namedUserToken{name: "me@tsuru.io", userToken: userToken{permissions: []permTypes.Permission{
+		{Scheme: PermApp, Context: Context(permTypes.CtxTeam, "team1")},
+	}}}
+	ctx := ContextWithRequestInfo(context.TODO(), RequestInfo{Method: http.MethodPost, Path: "/1.0/apps/myapp/deploy", RequestID: "req-1"})
+	ctx = ContextWithDeniedChecks(ctx)
+	c.Assert(Check(ctx, t, PermAppDeploy, Context(permTypes.CtxTeam, "team1")), check.Equals, false)
+	c.Assert(LastDeniedCheck(ctx), check.DeepEquals, &DeniedCheck{
+		Scheme:   PermAppDeploy,
+		Contexts: []permTypes.PermissionContext{Context(permTypes.CtxTeam, "team1")},
+		Reason:   "no deploys on fridays",
+	})
+	c.Assert(inputs, check.DeepEquals, []PolicyInput{{
+		User:       "me@tsuru.io",
+		Engine:     "native",
+		Permission: "app.deploy",
+		Contexts:   []PolicyTarget{{Type: "team", Value: "team1"}},
+		Request:    &RequestInfo{Method: http.MethodPost, Path: "/1.0/apps/myapp/deploy", RequestID: "req-1"},
+	}})
+	c.Assert(Check(ctx, t, PermAppUpdateEnvSet, Context(permTypes.CtxTeam, "team1")), check.Equals, true)
+	c.Assert(inputs, check.HasLen, 1)
+}
+
+func (s *S) TestCheckPolicyWebhookAllows(c *check.C) {
+	var inputs []PolicyInput
+	srv := s.policyServer(c, `{"result": true}`, &inputs)
+	defer srv.Close()
+	config.Set("authorization:webhook:url", srv.URL)
+	config.Set("authorization:webhook:permissions", []interface{}{"app"})
+	defer config.Unset("authorization")
+	t := &userToken{permissions: []permTypes.Permission{
+		{Scheme: PermApp, Context: Context(permTypes.CtxTeam, "team1")},
+	}}
+	c.Assert(Check(context.TODO(), t, PermAppDeploy, Context(permTypes.CtxTeam, "team1")), check.Equals, true)
+	c.Assert(inputs, check.HasLen, 1)
+}
+
+func (s *S) TestCheckPolicyWebhookNotCalledWhenRoleDenies(c *check.C) {
+	var inputs []PolicyInput
+	srv := s.policyServer(c, `{"allowed": true}`, &inputs)
+	defer srv.Close()
+	config.Set("authorization:webhook:url", srv.URL)
+	config.Set("authorization:webhook:permissions", []interface{}{"app"})
+	defer config.Unset("authorization")
+	t := &userToken{}
+	c.Assert(Check(context.TODO(), t, PermAppDeploy, Context(permTypes.CtxTeam, "team1")), check.Equals, false)
+	c.Assert(inputs, check.HasLen, 0)
+}
+
+func (s *S) TestCheckPolicyWebhookUnavailable(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	config.Set("authorization:webhook:url", srv.URL)
+	config.Set("authorization:webhook:permissions", []interface{}{"app.deploy"})
+	defer config.Unset("authorization")
+	t := &userToken{permissions: []permTypes.Permission{
+		{Scheme: PermAppDeploy, Context: Context(permTypes.CtxGlobal, "")},
+	}}
+	c.Assert(Check(context.TODO(), t, PermAppDeploy), check.Equals, false)
+	config.Set("authorization:webhook:fail-open", true)
+	c.Assert(Check(context.TODO(), t, PermAppDeploy), check.Equals, true)
+}
+
+func (s *S) TestContextsForPermissionPolicyWebhook(c *check.C) {
+	var inputs []PolicyInput
+	srv := s.policyServer(c, `{"result": {"allowedContexts": [{"type": "team", "value": "team1"}]}}`, &inputs)
+	defer srv.Close()
+	config.Set("authorization:webhook:url", srv.URL)
+	config.Set("authorization:webhook:permissions", []interface{}{"app.deploy"})
+	defer config.Unset("authorization")
+	t := &userToken{permissions: []permTypes.Permission{
+		{Scheme: PermApp, Context: Context(permTypes.CtxTeam, "team1")},
+		{Scheme: PermApp, Context: Context(permTypes.CtxTeam, "team2")},
+	}}
+	contexts := ContextsForPermission(context.TODO(), t, PermAppDeploy, permTypes.CtxTeam)
+	c.Assert(contexts, check.DeepEquals, []permTypes.PermissionContext{Context(permTypes.CtxTeam, "team1")})
+	c.Assert(inputs, check.DeepEquals, []PolicyInput{{
+		Permission: "app.deploy",
+		Contexts:   []PolicyTarget{{Type: "team", Value: "team1"}, {Type: "team", Value: "team2"}},
+		Filter:     true,
+	}})
+	contexts = ContextsForPermission(context.TODO(), t, PermAppUpdateEnvSet, permTypes.CtxTeam)
+	c.Assert(contexts, check.DeepEquals, []permTypes.PermissionContext{
+		Context(permTypes.CtxTeam, "team1"),
+		Context(permTypes.CtxTeam, "team2"),
+	})
+	c.Assert(inputs, check.HasLen, 1)
+}
+
+func (s *S) TestContextsForPermissionPolicyWebhookPlainDecision(c *check.C) {
+	var inputs []PolicyInput
+	srv := s.policyServer(c, `{"result": false}`, &inputs)
+	defer srv.Close()
+	config.Set("authorization:webhook:url", srv.URL)
+	config.Set("authorization:webhook:permissions", []interface{}{"app.deploy"})
+	defer config.Unset("authorization")
+	t := &userToken{permissions: []permTypes.Permission{
+		{Scheme: PermApp, Context: Context(permTypes.CtxTeam, "team1")},
+		{Scheme: PermApp, Context: Context(permTypes.CtxTeam, "team2")},
+	}}
+	contexts := ContextsForPermission(context.TODO(), t, PermAppDeploy, permTypes.CtxTeam)
+	c.Assert(contexts, check.HasLen, 0)
+	c.Assert(inputs, check.HasLen, 1)
+}
+
+func (s *S) TestPolicyRequestHeaders(c *check.C) {
+	config.Set("authorization:webhook:request-headers", []interface{}{"x-forwarded-for", "Authorization", "X-Missing"})
+	defer config.Unset("authorization")
+	headers := PolicyRequestHeaders(http.Header{
+		"X-Forwarded-For": {"10.0.0.1"},
+		"Authorization":   {"bearer secret"},
+		"X-Other":         {"value"},
+	})
+	c.Assert(headers, check.DeepEquals, http.Header{"X-Forwarded-For": {"10.0.0.1"}})
+	config.Unset("authorization")
+	c.Assert(PolicyRequestHeaders(http.Header{"X-Forwarded-For": {"10.0.0.1"}}), check.IsNil)
+}
+
+func (s *S) TestParsePolicyDecision(c *check.C) {
+	tests := []struct {
+		body     string
+		expected PolicyDecision
+	}{
+		{`{"result": true}`, PolicyDecision{Allowed: true}},
+		{`{"result": false}`, PolicyDecision{}},
+		{`{"result": {"allowed": false, "reason": "frozen"}}`, PolicyDecision{Reason: "frozen"}},
+		{`{"allowed": true}`, PolicyDecision{Allowed: true}},
+		{`{}`, PolicyDecision{}},
+	}
+	for _, tt := range tests {
+		decision, err := parsePolicyDecision([]byte(tt.body))
+		c.Check(err, check.IsNil)
+		c.Check(*decision, check.DeepEquals, tt.expected)
+	}
+	_, err := parsePolicyDecision([]byte(`not json`))
+	c.Assert(err, check.NotNil)
+}
@@ -21,7 +21,13 @@ type Role struct {
 	ContextType permTypes.ContextType `json:"context"`
 	Description string
 	SchemeNames []string `json:"scheme_names,omitempty"`
-	Events      []string `json:"events,omitempty"`
+	// DenySchemeNames are permissions excluded from the role, even if they
+	// are children of a permission in SchemeNames.
+	DenySchemeNames []string `json:"deny_scheme_names,omitempty"`
+	Events          []string `json:"events,omitempty"`
+	// RequireMFA restricts the role to users authenticated with a second
+	// factor.
+	RequireMFA bool `json:"require_mfa,omitempty" bson:",omitempty"`
 }
 
 func NewRole(ctx context.Context, name string, permissionCtx string, description string) (Role, error) {
@@ -184,6 +190,50 @@ func DestroyRole(ctx context.Context, name string) error {
 }
 
 func (r *Role) AddPermissions(ctx context.Context, permNames ...string) error {
+	err := r.validatePermissions(permNames)
+	if err != nil {
+		return err
+	}
+	collection, err := storagev2.RolesCollection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": r.Name}, mongoBSON.M{"$addToSet": mongoBSON.M{"schemenames": mongoBSON.M{"$each": permNames}}})
+	if err != nil {
+		return err
+	}
+	dbRole, err := FindRole(ctx, r.Name)
+	if err != nil {
+		return err
+	}
+	r.SchemeNames = dbRole.SchemeNames
+	return nil
+}
+
+// AddDenyPermissions excludes permissions from the role. Denied permissions
+// take precedence over allowed ones when checking permissions.
+func (r *Role) AddDenyPermissions(ctx context.Context, permNames ...string) error {
+	err := r.validatePermissions(permNames)
+	if err != nil {
+		return err
+	}
+	collection, err := storagev2.RolesCollection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": r.Name}, mongoBSON.M{"$addToSet": mongoBSON.M{"denyschemenames": mongoBSON.M{"$each": permNames}}})
+	if err != nil {
+		return err
+	}
+	dbRole, err := FindRole(ctx, r.Name)
+	if err != nil {
+		return err
+	}
+	r.DenySchemeNames = dbRole.DenySchemeNames
+	return nil
+}
+
+func (r *Role) validatePermissions(permNames []string) error {
 	for _, permName := range permNames {
 		if permName == "" {
 			return permTypes.ErrInvalidPermissionName
@@ -209,11 +259,15 @@ func (r *Role) AddPermissions(ctx context.Context, permNames ...string) error {
 			}
 		}
 	}
+	return nil
+}
+
+func (r *Role) RemovePermissions(ctx context.Context, permNames ...string) error {
 	collection, err := storagev2.RolesCollection()
 	if err != nil {
 		return err
 	}
-	_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": r.Name}, mongoBSON.M{"$addToSet": mongoBSON.M{"schemenames": mongoBSON.M{"$each": permNames}}})
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": r.Name}, mongoBSON.M{"$pullAll": mongoBSON.M{"schemenames": permNames}})
 	if err != nil {
 		return err
 	}
@@ -225,12 +279,12 @@ func (r *Role) AddPermissions(ctx context.Context, permNames ...string) error {
 	return nil
 }
 
-func (r *Role) RemovePermissions(ctx context.Context, permNames ...string) error {
+func (r *Role) RemoveDenyPermissions(ctx context.Context, permNames ...string) error {
 	collection, err := storagev2.RolesCollection()
 	if err != nil {
 		return err
 	}
-	_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": r.Name}, mongoBSON.M{"$pullAll": mongoBSON.M{"schemenames": permNames}})
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"_id": r.Name}, mongoBSON.M{"$pullAll": mongoBSON.M{"denyschemenames": permNames}})
 	if err != nil {
 		return err
 	}
@@ -238,15 +292,22 @@ func (r *Role) RemovePermissions(ctx context.Context, permNames ...string) error
 	if err != nil {
 		return err
 	}
-	r.SchemeNames = dbRole.SchemeNames
+	r.DenySchemeNames = dbRole.DenySchemeNames
 	return nil
 }
 
 func (r *Role) filterValidSchemes() permTypes.PermissionSchemeList {
-	schemes := make(permTypes.PermissionSchemeList, 0, len(r.SchemeNames))
-	sort.Strings(r.SchemeNames)
-	for i := 0; i < len(r.SchemeNames); i++ {
-		schemeName := r.SchemeNames[i]
+	var schemes permTypes.PermissionSchemeList
+	r.SchemeNames, schemes = filterValidSchemeNames(r.SchemeNames)
+	r.DenySchemeNames, _ = filterValidSchemeNames(r.DenySchemeNames)
+	return schemes
+}
+
+func filterValidSchemeNames(schemeNames []string) ([]string, permTypes.PermissionSchemeList) {
+	schemes := make(permTypes.PermissionSchemeList, 0, len(schemeNames))
+	sort.Strings(schemeNames)
+	for i := 0; i < len(schemeNames); i++ {
+		schemeName := schemeNames[i]
 		if schemeName == "*" {
 			schemeName = ""
 		}
@@ -254,17 +315,29 @@ func (r *Role) filterValidSchemes() permTypes.PermissionSchemeList {
 		if scheme == nil {
 			// permission schemes might be removed or renamed, invalid entries
 			// in the database shouldn't be a problem.
-			r.SchemeNames = append(r.SchemeNames[:i], r.SchemeNames[i+1:]...)
+			schemeNames = append(schemeNames[:i], schemeNames[i+1:]...)
 			i--
 			continue
 		}
 		schemes = append(schemes, &scheme.PermissionScheme)
 	}
-	return schemes
+	return schemeNames, schemes
 }
 
 func (r *Role) PermissionsFor(contextValue string) []permTypes.Permission {
 	schemes := r.filterValidSchemes()
+	return r.permissionsFor(schemes, contextValue, false)
+}
+
+// DenyPermissionsFor returns the permissions excluded by the role, with Deny
+// set, for the given context value.
+func (r *Role) DenyPermissionsFor(contextValue string) []permTypes.Permission {
+	var schemes permTypes.PermissionSchemeList
+	r.DenySchemeNames, schemes = filterValidSchemeNames(r.DenySchemeNames)
+	return r.permissionsFor(schemes, contextValue, true)
+}
+
+func (r *Role) permissionsFor(schemes permTypes.PermissionSchemeList, contextValue string, deny bool) []permTypes.Permission {
 	permissions := make([]permTypes.Permission, len(schemes))
 	for i, scheme := range schemes {
 		permissions[i] = permTypes.Permission{
@@ -273,6 +346,7 @@ func (r *Role) PermissionsFor(contextValue string) []permTypes.Permission {
 				CtxType: r.ContextType,
 				Value:   contextValue,
 			},
+			Deny: deny,
 		}
 	}
 	return permissions
@@ -319,6 +393,22 @@ func (r *Role) RemoveEvent(ctx context.Context, eventName string) error {
 	return nil
 }
 
+func (r *Role) SetRequireMFA(ctx context.Context, required bool) error {
+	collection, err := storagev2.RolesCollection()
+	if err != nil {
+		return err
+	}
+	result, err := collection.UpdateOne(ctx, mongoBSON.M{"_id": r.Name}, mongoBSON.M{"$set": mongoBSON.M{"requiremfa": required}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return permTypes.ErrRoleNotFound
+	}
+	r.RequireMFA = required
+	return nil
+}
+
 func (r *Role) Update(ctx context.Context) error {
 	collection, err := storagev2.RolesCollection()
 	if err != nil {
@@ -342,7 +432,7 @@ func (r *Role) Add(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	insertRole := Role{Name: name, ContextType: r.ContextType, Description: r.Description, SchemeNames: r.SchemeNames, Events: r.Events}
+	insertRole := Role{Name: name, ContextType: r.ContextType, Description: r.Description, SchemeNames: r.SchemeNames, DenySchemeNames: r.DenySchemeNames, Events: r.Events, RequireMFA: r.RequireMFA}
 	_, err = collection.InsertOne(ctx, insertRole)
 	if mongo.IsDuplicateKeyError(err) {
 		return permTypes.ErrRoleAlreadyExists
@@ -122,6 +122,42 @@ func (s *S) TestRemovePermissions(c *check.C) {
 	c.Assert(dbR.SchemeNames, check.DeepEquals, expected)
 }
 
+func (s *S) TestRoleAddDenyPermissions(c *check.C) {
+	r, err := NewRole(context.TODO(), "myrole", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r.AddPermissions(context.TODO(), "app")
+	c.Assert(err, check.IsNil)
+	err = r.AddDenyPermissions(context.TODO(), "app.run.shell", "app.update.env.unset")
+	c.Assert(err, check.IsNil)
+	expected := []string{"app.run.shell", "app.update.env.unset"}
+	c.Assert(r.DenySchemeNames, check.DeepEquals, expected)
+	c.Assert(r.SchemeNames, check.DeepEquals, []string{"app"})
+	dbR, err := FindRole(context.TODO(), "myrole")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbR.DenySchemeNames, check.DeepEquals, expected)
+	err = r.AddDenyPermissions(context.TODO(), "pool.create")
+	c.Assert(err, check.ErrorMatches, `permission "pool.create" not allowed with context of type "team"`)
+	err = r.RemoveDenyPermissions(context.TODO(), "app.run.shell")
+	c.Assert(err, check.IsNil)
+	c.Assert(r.DenySchemeNames, check.DeepEquals, []string{"app.update.env.unset"})
+}
+
+func (s *S) TestDenyPermissionsFor(c *check.C) {
+	r, err := NewRole(context.TODO(), "myrole", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r.AddPermissions(context.TODO(), "app")
+	c.Assert(err, check.IsNil)
+	err = r.AddDenyPermissions(context.TODO(), "app.run.shell")
+	c.Assert(err, check.IsNil)
+	r.DenySchemeNames = append(r.DenySchemeNames, "invalidxxx")
+	c.Assert(r.DenyPermissionsFor("something"), check.DeepEquals, []permTypes.Permission{
+		{Scheme: PermissionRegistry.get("app.run.shell"), Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "something"}, Deny: true},
+	})
+	c.Assert(r.PermissionsFor("something"), check.DeepEquals, []permTypes.Permission{
+		{Scheme: PermissionRegistry.get("app"), Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "something"}},
+	})
+}
+
 func (s *S) TestDestroyRole(c *check.C) {
 	_, err := NewRole(context.TODO(), "myrole", "team", "")
 	c.Assert(err, check.IsNil)
@@ -178,6 +214,25 @@ func (s *S) TestRoleRemoveEvent(c *check.C) {
 	c.Assert(dbR.Events, check.DeepEquals, []string{})
 }
 
+func (s *S) TestRoleSetRequireMFA(c *check.C) {
+	r, err := NewRole(context.TODO(), "myrole", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r.SetRequireMFA(context.TODO(), true)
+	c.Assert(err, check.IsNil)
+	c.Assert(r.RequireMFA, check.Equals, true)
+	dbR, err := FindRole(context.TODO(), "myrole")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbR.RequireMFA, check.Equals, true)
+	err = dbR.SetRequireMFA(context.TODO(), false)
+	c.Assert(err, check.IsNil)
+	dbR, err = FindRole(context.TODO(), "myrole")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbR.RequireMFA, check.Equals, false)
+	notFound := Role{Name: "unknown"}
+	err = notFound.SetRequireMFA(context.TODO(), true)
+	c.Assert(err, check.Equals, permTypes.ErrRoleNotFound)
+}
+
 func (s *S) TestListRolesWithEvents(c *check.C) {
 	_, err := NewRole(context.TODO(), "myrole1", "team", "")
 	c.Assert(err, check.IsNil)
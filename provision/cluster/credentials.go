@@ -0,0 +1,273 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/shutdown"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/servicemanager"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+)
+
+const (
+	CredentialClientCert           = "clientcert"
+	CredentialToken                = "token"
+	CredentialKubeConfigClientCert = "kubeconfig.clientcert"
+	CredentialKubeConfigToken      = "kubeconfig.token"
+
+	credentialsCheckInterval        = time.Hour
+	credentialsExpiryEventKind      = "cluster.credentials.expiry"
+	defaultCredentialsExpiryWarning = 14 * 24 * time.Hour
+)
+
+// CredentialsValidator is implemented by provisioners able to check whether a
+// cluster is reachable with its credentials before they are stored.
+type CredentialsValidator interface {
+	ValidateClusterCredentials(ctx context.Context, c *provTypes.Cluster) error
+}
+
+func init() {
+	event.SetThrottling(event.ThrottlingSpec{
+		TargetType: eventTypes.TargetTypeCluster,
+		KindName:   credentialsExpiryEventKind,
+		Time:       24 * time.Hour,
+		Max:        1,
+	})
+}
+
+// CredentialsExpiry returns when each credential of the cluster with a known
+// validity expires. Tokens are only considered when they are JWTs carrying an
+// exp claim.
+func CredentialsExpiry(c *provTypes.Cluster) ([]provTypes.ClusterCredentialExpiry, error) {
+	var result []provTypes.ClusterCredentialExpiry
+	add := func(credential string, expiresAt *time.Time, err error) error {
+		if err != nil {
+			return errors.Wrapf(err, "unable to parse %s of cluster %q", credential, c.Name)
+		}
+		if expiresAt != nil {
+			result = append(result, provTypes.ClusterCredentialExpiry{Credential: credential, ExpiresAt: *expiresAt})
+		}
+		return nil
+	}
+	if len(c.ClientCert) > 0 {
+		expiresAt, err := certificateExpiry(c.ClientCert)
+		if err = add(CredentialClientCert, expiresAt, err); err != nil {
+			return nil, err
+		}
+	}
+	if token := c.CustomData["token"]; token != "" {
+		if err := add(CredentialToken, tokenExpiry(token), nil); err != nil {
+			return nil, err
+		}
+	}
+	if c.KubeConfig != nil {
+		if data := c.KubeConfig.AuthInfo.ClientCertificateData; len(data) > 0 {
+			expiresAt, err := certificateExpiry(data)
+			if err = add(CredentialKubeConfigClientCert, expiresAt, err); err != nil {
+				return nil, err
+			}
+		}
+		if token := c.KubeConfig.AuthInfo.Token; token != "" {
+			if err := add(CredentialKubeConfigToken, tokenExpiry(token), nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+func certificateExpiry(data []byte) (*time.Time, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &cert.NotAfter, nil
+}
+
+func tokenExpiry(token string) *time.Time {
+	claims := jwt.MapClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(token, claims)
+	if err != nil {
+		return nil
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return nil
+	}
+	return &exp.Time
+}
+
+func (s *clusterService) RotateCredentials(ctx context.Context, name string, credentials provTypes.ClusterCredentials) error {
+	if len(credentials.CaCert) == 0 && len(credentials.ClientCert) == 0 && len(credentials.ClientKey) == 0 &&
+		credentials.Token == "" && credentials.KubeConfig == nil {
+		return errors.WithStack(&tsuruErrors.ValidationError{Message: provTypes.ErrClusterCredentialsRequired.Error()})
+	}
+	if (len(credentials.ClientCert) == 0) != (len(credentials.ClientKey) == 0) {
+		return errors.WithStack(&tsuruErrors.ValidationError{Message: "clientcert and clientkey must be rotated together"})
+	}
+	current, err := s.storage.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	rotated := *current
+	rotated.CustomData = map[string]string{}
+	for k, v := range current.CustomData {
+		rotated.CustomData[k] = v
+	}
+	if credentials.KubeConfig != nil {
+		rotated.KubeConfig = credentials.KubeConfig
+	}
+	if len(credentials.CaCert) > 0 {
+		rotated.CaCert = credentials.CaCert
+	}
+	if len(credentials.ClientCert) > 0 {
+		rotated.ClientCert = credentials.ClientCert
+		rotated.ClientKey = credentials.ClientKey
+	}
+	if credentials.Token != "" {
+		rotated.CustomData["token"] = credentials.Token
+	}
+	err = s.validate(rotated, false)
+	if err != nil {
+		return err
+	}
+	prov, err := provision.Get(rotated.Provisioner)
+	if err != nil {
+		return err
+	}
+	if validator, ok := prov.(CredentialsValidator); ok {
+		err = validator.ValidateClusterCredentials(ctx, &rotated)
+		if err != nil {
+			return errors.WithStack(&tsuruErrors.ValidationError{Message: fmt.Sprintf("unable to connect to cluster with new credentials: %v", err)})
+		}
+	}
+	return s.save(ctx, rotated)
+}
+
+func credentialsExpiryWarning() time.Duration {
+	days, err := config.GetInt("clusters:credentials-expiry-warning-days")
+	if err != nil || days <= 0 {
+		return defaultCredentialsExpiryWarning
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// InitializeCredentialsChecker starts the periodic check that emits warning
+// events for clusters with credentials about to expire.
+func InitializeCredentialsChecker() {
+	checker := &credentialsChecker{
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go checker.start()
+	shutdown.Register(checker)
+}
+
+type credentialsChecker struct {
+	quit chan struct{}
+	done chan struct{}
+}
+
+func (c *credentialsChecker) start() {
+	defer close(c.done)
+	for {
+		err := checkCredentialsExpiry(context.Background(), time.Now())
+		if err != nil {
+			log.Errorf("[cluster-credentials] unable to check credentials expiry: %v", err)
+		}
+		select {
+		case <-c.quit:
+			return
+		case <-time.After(credentialsCheckInterval):
+		}
+	}
+}
+
+func (c *credentialsChecker) Shutdown(ctx context.Context) error {
+	close(c.quit)
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+func checkCredentialsExpiry(ctx context.Context, now time.Time) error {
+	clusters, err := servicemanager.Cluster.List(ctx)
+	if err != nil {
+		if err == provTypes.ErrNoCluster {
+			return nil
+		}
+		return err
+	}
+	warning := credentialsExpiryWarning()
+	multi := tsuruErrors.NewMultiError()
+	for i := range clusters {
+		expiry, err := CredentialsExpiry(&clusters[i])
+		if err != nil {
+			multi.Add(err)
+			continue
+		}
+		var expiring []provTypes.ClusterCredentialExpiry
+		for _, e := range expiry {
+			if e.ExpiresAt.Sub(now) <= warning {
+				expiring = append(expiring, e)
+			}
+		}
+		if len(expiring) == 0 {
+			continue
+		}
+		err = warnCredentialsExpiry(ctx, clusters[i].Name, expiring, now)
+		if err != nil {
+			multi.Add(err)
+		}
+	}
+	return multi.ToError()
+}
+
+func warnCredentialsExpiry(ctx context.Context, clusterName string, expiring []provTypes.ClusterCredentialExpiry, now time.Time) error {
+	evt, err := event.NewInternal(ctx, &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeCluster, Value: clusterName},
+		InternalKind: credentialsExpiryEventKind,
+		Allowed:      event.Allowed(permission.PermClusterReadEvents),
+	})
+	if err != nil {
+		if _, ok := err.(event.ErrThrottled); ok {
+			return nil
+		}
+		return err
+	}
+	sort.Slice(expiring, func(i, j int) bool {
+		return expiring[i].ExpiresAt.Before(expiring[j].ExpiresAt)
+	})
+	for _, e := range expiring {
+		if e.ExpiresAt.After(now) {
+			evt.Logf("WARNING: %s of cluster %q expires at %s", e.Credential, clusterName, e.ExpiresAt.Format(time.RFC3339))
+		} else {
+			evt.Logf("WARNING: %s of cluster %q expired at %s", e.Credential, clusterName, e.ExpiresAt.Format(time.RFC3339))
+		}
+	}
+	return evt.Done(ctx, errors.Errorf("cluster %q has %d credential(s) about to expire", clusterName, len(expiring)))
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/servicemanager"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+)
+
+func testCertificate(c *check.C, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, check.IsNil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "tsuru"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, check.IsNil)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func testToken(c *check.C, expiresAt time.Time) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": expiresAt.Unix()}).SignedString([]byte("secret"))
+	c.Assert(err, check.IsNil)
+	return token
+}
+
+func (s *S) TestCredentialsExpiry(c *check.C) {
+	certExpiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tokenExpiry := time.Date(2029, 6, 1, 0, 0, 0, 0, time.UTC)
+	clust := &provTypes.Cluster{
+		Name:       "c1",
+		ClientCert: testCertificate(c, certExpiry),
+		CustomData: map[string]string{"token": testToken(c, tokenExpiry)},
+		KubeConfig: &provTypes.KubeConfig{},
+	}
+	clust.KubeConfig.AuthInfo.ClientCertificateData = testCertificate(c, tokenExpiry)
+	clust.KubeConfig.AuthInfo.Token = "opaque-token"
+	expiry, err := CredentialsExpiry(clust)
+	c.Assert(err, check.IsNil)
+	c.Assert(expiry, check.HasLen, 3)
+	c.Assert(expiry[0].Credential, check.Equals, CredentialClientCert)
+	c.Assert(expiry[0].ExpiresAt.Equal(certExpiry), check.Equals, true)
+	c.Assert(expiry[1].Credential, check.Equals, CredentialToken)
+	c.Assert(expiry[1].ExpiresAt.Equal(tokenExpiry), check.Equals, true)
+	c.Assert(expiry[2].Credential, check.Equals, CredentialKubeConfigClientCert)
+	c.Assert(expiry[2].ExpiresAt.Equal(tokenExpiry), check.Equals, true)
+}
+
+func (s *S) TestCredentialsExpiryNoCredentials(c *check.C) {
+	expiry, err := CredentialsExpiry(&provTypes.Cluster{Name: "c1", CustomData: map[string]string{"token": "opaque"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(expiry, check.IsNil)
+}
+
+func (s *S) TestCredentialsExpiryInvalidCertificate(c *check.C) {
+	_, err := CredentialsExpiry(&provTypes.Cluster{Name: "c1", ClientCert: []byte("invalid")})
+	c.Assert(err, check.ErrorMatches, `unable to parse clientcert of cluster "c1": no PEM data found`)
+}
+
+type credentialsProv struct {
+	clusterProv
+	validateErr error
+	validated   *provTypes.Cluster
+}
+
+func (p *credentialsProv) ValidateClusterCredentials(ctx context.Context, c *provTypes.Cluster) error {
+	p.validated = c
+	return p.validateErr
+}
+
+func (s *S) TestClusterServiceRotateCredentials(c *check.C) {
+	inst := credentialsProv{clusterProv: clusterProv{FakeProvisioner: provisiontest.ProvisionerInstance}}
+	provision.Register("fake-cluster", func() (provision.Provisioner, error) {
+		return &inst, nil
+	})
+	defer provision.Unregister("fake-cluster")
+	current := provTypes.Cluster{
+		Name:        "c1",
+		Addresses:   []string{"addr1"},
+		Provisioner: "fake-cluster",
+		Default:     true,
+		CaCert:      []byte("ca"),
+		ClientCert:  []byte("old-cert"),
+		ClientKey:   []byte("old-key"),
+		CustomData:  map[string]string{"token": "old-token", "namespace": "tsuru"},
+	}
+	var saved *provTypes.Cluster
+	cs := &clusterService{
+		storage: &provTypes.MockClusterStorage{
+			OnFindByName: func(name string) (*provTypes.Cluster, error) {
+				c.Assert(name, check.Equals, "c1")
+				return &current, nil
+			},
+			OnUpsert: func(clust provTypes.Cluster) error {
+				saved = &clust
+				return nil
+			},
+		},
+	}
+	err := cs.RotateCredentials(context.TODO(), "c1", provTypes.ClusterCredentials{
+		ClientCert: []byte("new-cert"),
+		ClientKey:  []byte("new-key"),
+		Token:      "new-token",
+	})
+	c.Assert(err, check.IsNil)
+	expected := provTypes.Cluster{
+		Name:        "c1",
+		Addresses:   []string{"addr1"},
+		Provisioner: "fake-cluster",
+		Default:     true,
+		CaCert:      []byte("ca"),
+		ClientCert:  []byte("new-cert"),
+		ClientKey:   []byte("new-key"),
+		CustomData:  map[string]string{"token": "new-token", "namespace": "tsuru"},
+	}
+	c.Assert(*inst.validated, check.DeepEquals, expected)
+	c.Assert(*saved, check.DeepEquals, expected)
+	c.Assert(*inst.callCluster, check.DeepEquals, expected)
+	c.Assert(current.CustomData["token"], check.Equals, "old-token")
+}
+
+func (s *S) TestClusterServiceRotateCredentialsConnectionFailure(c *check.C) {
+	inst := credentialsProv{
+		clusterProv: clusterProv{FakeProvisioner: provisiontest.ProvisionerInstance},
+		validateErr: errors.New("connection refused"),
+	}
+	provision.Register("fake-cluster", func() (provision.Provisioner, error) {
+		return &inst, nil
+	})
+	defer provision.Unregister("fake-cluster")
+	cs := &clusterService{
+		storage: &provTypes.MockClusterStorage{
+			OnFindByName: func(name string) (*provTypes.Cluster, error) {
+				return &provTypes.Cluster{Name: "c1", Provisioner: "fake-cluster", Default: true}, nil
+			},
+			OnUpsert: func(clust provTypes.Cluster) error {
+				c.Fatal("credentials must not be saved")
+				return nil
+			},
+		},
+	}
+	err := cs.RotateCredentials(context.TODO(), "c1", provTypes.ClusterCredentials{Token: "new-token"})
+	c.Assert(err, check.ErrorMatches, "unable to connect to cluster with new credentials: connection refused")
+	_, ok := errors.Cause(err).(*tsuruErrors.ValidationError)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(inst.callCluster, check.IsNil)
+}
+
+func (s *S) TestClusterServiceRotateCredentialsValidation(c *check.C) {
+	cs := &clusterService{
+		storage: &provTypes.MockClusterStorage{},
+	}
+	err := cs.RotateCredentials(context.TODO(), "c1", provTypes.ClusterCredentials{})
+	c.Assert(err, check.ErrorMatches, provTypes.ErrClusterCredentialsRequired.Error())
+	err = cs.RotateCredentials(context.TODO(), "c1", provTypes.ClusterCredentials{ClientCert: []byte("cert")})
+	c.Assert(err, check.ErrorMatches, "clientcert and clientkey must be rotated together")
+}
+
+func (s *S) TestCheckCredentialsExpiry(c *check.C) {
+	config.Set("clusters:credentials-expiry-warning-days", 10)
+	defer config.Unset("clusters")
+	now := time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC)
+	servicemanager.Cluster = &provTypes.MockClusterService{
+		OnList: func() ([]provTypes.Cluster, error) {
+			return []provTypes.Cluster{
+				{Name: "expiring", ClientCert: testCertificate(c, now.Add(5*24*time.Hour))},
+				{Name: "valid", CustomData: map[string]string{"token": testToken(c, now.Add(30*24*time.Hour))}},
+			}, nil
+		},
+	}
+	err := checkCredentialsExpiry(context.TODO(), now)
+	c.Assert(err, check.IsNil)
+	evts, err := event.List(context.TODO(), &event.Filter{KindNames: []string{credentialsExpiryEventKind}})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Target, check.DeepEquals, eventTypes.Target{Type: eventTypes.TargetTypeCluster, Value: "expiring"})
+	c.Assert(evts[0].Error, check.Equals, `cluster "expiring" has 1 credential(s) about to expire`)
+	c.Assert(evts[0].Log(), check.Matches, `(?s).*WARNING: clientcert of cluster "expiring" expires at 2029-01-06T00:00:00Z.*`)
+	err = checkCredentialsExpiry(context.TODO(), now)
+	c.Assert(err, check.IsNil)
+	evts, err = event.List(context.TODO(), &event.Filter{KindNames: []string{credentialsExpiryEventKind}})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+}
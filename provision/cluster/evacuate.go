@@ -0,0 +1,308 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/auth"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+)
+
+var ErrEvacuationNotSupported = errors.New("cluster provisioner does not support evacuation")
+
+// EvacuationProvisioner is implemented by provisioners able to move apps and
+// jobs between clusters.
+type EvacuationProvisioner interface {
+	// PrepareAppEvacuation deploys the app running in the source cluster in
+	// the destination cluster, before its pool is moved there. The routes and
+	// the units in the source cluster are kept. It reports whether the app is
+	// in the source cluster.
+	PrepareAppEvacuation(ctx context.Context, a *appTypes.App, source, destination *provTypes.Cluster, w io.Writer) (bool, error)
+	// EvacuateApp moves the routes of the app to the cluster currently
+	// serving its pool and removes the app from the source cluster. It
+	// reports whether there was anything left to move.
+	EvacuateApp(ctx context.Context, a *appTypes.App, source *provTypes.Cluster, w io.Writer) (bool, error)
+	// EvacuateJob creates the job in the cluster currently serving its pool
+	// and removes it from the source cluster. It reports whether there was
+	// anything left to move.
+	EvacuateJob(ctx context.Context, j *jobTypes.Job, source *provTypes.Cluster, w io.Writer) (bool, error)
+	// CleanupEvacuatedPool removes what is left from the pool in the source
+	// cluster after all its apps and jobs are moved.
+	CleanupEvacuatedPool(ctx context.Context, pool string, source *provTypes.Cluster) error
+}
+
+type EvacuateOptions struct {
+	Source      string
+	Destination string
+	Pool        string
+	Owner       auth.Token
+	Writer      io.Writer
+}
+
+// Evacuate moves a pool from the source cluster to the destination cluster
+// along with its apps and jobs. Apps are first deployed in the destination
+// cluster and the pool is only re-targeted once all of them are running
+// there, then routes are moved, jobs are recreated and everything is removed
+// from the source cluster. Each app and job is tracked by its own event.
+// Running it again after a failure resumes from what is still in the source
+// cluster.
+func Evacuate(ctx context.Context, opts EvacuateOptions) error {
+	w := opts.Writer
+	if w == nil {
+		w = io.Discard
+	}
+	if opts.Source == opts.Destination {
+		return errors.WithStack(&tsuruErrors.ValidationError{Message: "source and destination clusters must be different"})
+	}
+	if opts.Pool == "" {
+		return errors.WithStack(&tsuruErrors.ValidationError{Message: "pool is mandatory"})
+	}
+	source, err := servicemanager.Cluster.FindByName(ctx, opts.Source)
+	if err != nil {
+		return err
+	}
+	destination, err := servicemanager.Cluster.FindByName(ctx, opts.Destination)
+	if err != nil {
+		return err
+	}
+	if source.Provisioner != destination.Provisioner {
+		return errors.WithStack(&tsuruErrors.ValidationError{Message: "source and destination clusters must use the same provisioner"})
+	}
+	pool, err := servicemanager.Pool.FindByName(ctx, opts.Pool)
+	if err != nil {
+		return err
+	}
+	current, err := servicemanager.Cluster.FindByPool(ctx, source.Provisioner, pool.Name)
+	if err != nil {
+		return err
+	}
+	if current.Name != source.Name && current.Name != destination.Name {
+		return errors.WithStack(&tsuruErrors.ValidationError{Message: fmt.Sprintf("pool %q is served by cluster %q", pool.Name, current.Name)})
+	}
+	if containsPool(source.Pools, pool.Name) && len(withoutPool(source.Pools, pool.Name)) == 0 {
+		return errors.WithStack(&tsuruErrors.ValidationError{Message: fmt.Sprintf("cluster %q would be left without pools, set it as default or add another pool first", source.Name)})
+	}
+	prov, err := provision.Get(source.Provisioner)
+	if err != nil {
+		return err
+	}
+	evacuationProv, ok := prov.(EvacuationProvisioner)
+	if !ok {
+		return ErrEvacuationNotSupported
+	}
+	apps, err := servicemanager.App.List(ctx, &appTypes.Filter{Pool: pool.Name})
+	if err != nil {
+		return err
+	}
+	jobs, err := servicemanager.Job.List(ctx, &jobTypes.Filter{Pool: pool.Name})
+	if err != nil {
+		return err
+	}
+	err = checkEvacuationVolumes(ctx, apps, jobs)
+	if err != nil {
+		return err
+	}
+	evacuations := make([]*appEvacuation, 0, len(apps))
+	defer func() {
+		for _, e := range evacuations {
+			e.done(ctx)
+		}
+	}()
+	for _, a := range apps {
+		var e *appEvacuation
+		e, err = newAppEvacuation(ctx, a, source, opts.Owner, w)
+		if err != nil {
+			return errors.Wrapf(err, "unable to evacuate app %q", a.Name)
+		}
+		evacuations = append(evacuations, e)
+	}
+	multi := tsuruErrors.NewMultiError()
+	if current.Name == source.Name {
+		for _, e := range evacuations {
+			fmt.Fprintf(w, "---- Deploying app %q in cluster %q ----\n", e.app.Name, destination.Name)
+			e.moved, e.err = evacuationProv.PrepareAppEvacuation(ctx, e.app, source, destination, e.evt)
+			if e.err != nil {
+				fmt.Fprintf(w, "---- Failed to evacuate app %q: %v ----\n", e.app.Name, e.err)
+				multi.Add(errors.Wrapf(e.err, "unable to evacuate app %q", e.app.Name))
+			}
+		}
+		if multi.Len() > 0 {
+			return multi.ToError()
+		}
+		fmt.Fprintf(w, "---- Moving pool %q to cluster %q ----\n", pool.Name, destination.Name)
+	}
+	err = retargetPool(ctx, pool.Name, source, destination)
+	if err != nil {
+		return err
+	}
+	for _, e := range evacuations {
+		fmt.Fprintf(w, "---- Evacuating app %q ----\n", e.app.Name)
+		var moved bool
+		moved, e.err = evacuationProv.EvacuateApp(ctx, e.app, source, e.evt)
+		e.moved = e.moved || moved
+		if e.err == nil && !e.moved {
+			fmt.Fprintf(w, " ---> App %q is not in cluster %q, skipping\n", e.app.Name, source.Name)
+		}
+		if e.err != nil {
+			fmt.Fprintf(w, "---- Failed to evacuate app %q: %v ----\n", e.app.Name, e.err)
+			multi.Add(errors.Wrapf(e.err, "unable to evacuate app %q", e.app.Name))
+		}
+	}
+	for i := range jobs {
+		err = evacuateJob(ctx, evacuationProv, &jobs[i], source, opts.Owner, w)
+		if err != nil {
+			fmt.Fprintf(w, "---- Failed to evacuate job %q: %v ----\n", jobs[i].Name, err)
+			multi.Add(errors.Wrapf(err, "unable to evacuate job %q", jobs[i].Name))
+		}
+	}
+	if multi.Len() > 0 {
+		return multi.ToError()
+	}
+	fmt.Fprintf(w, "---- Cleaning up pool %q in cluster %q ----\n", pool.Name, source.Name)
+	return evacuationProv.CleanupEvacuatedPool(ctx, pool.Name, source)
+}
+
+// checkEvacuationVolumes refuses evacuating pools with apps or jobs bound to
+// volumes, as their data can't be moved to another cluster.
+func checkEvacuationVolumes(ctx context.Context, apps []*appTypes.App, jobs []jobTypes.Job) error {
+	for _, a := range apps {
+		volumes, err := servicemanager.Volume.ListByApp(ctx, a.Name)
+		if err != nil {
+			return err
+		}
+		if len(volumes) > 0 {
+			return errors.WithStack(&tsuruErrors.ValidationError{Message: fmt.Sprintf("app %q has bound volumes, its data can't be moved to another cluster", a.Name)})
+		}
+	}
+	for _, j := range jobs {
+		volumes, err := servicemanager.Volume.ListByJob(ctx, j.Name)
+		if err != nil {
+			return err
+		}
+		if len(volumes) > 0 {
+			return errors.WithStack(&tsuruErrors.ValidationError{Message: fmt.Sprintf("job %q has bound volumes, its data can't be moved to another cluster", j.Name)})
+		}
+	}
+	return nil
+}
+
+// retargetPool adds the pool to the destination cluster, unless it is the
+// default one, and removes it from the source cluster. Steps already done by
+// a previous run are skipped.
+func retargetPool(ctx context.Context, pool string, source, destination *provTypes.Cluster) error {
+	if !destination.Default && !containsPool(destination.Pools, pool) {
+		destination.Pools = append(destination.Pools, pool)
+		err := servicemanager.Cluster.Update(ctx, *destination)
+		if err != nil {
+			return err
+		}
+	}
+	if containsPool(source.Pools, pool) {
+		source.Pools = withoutPool(source.Pools, pool)
+		return servicemanager.Cluster.Update(ctx, *source)
+	}
+	return nil
+}
+
+func containsPool(pools []string, pool string) bool {
+	for _, p := range pools {
+		if p == pool {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutPool(pools []string, pool string) []string {
+	var result []string
+	for _, p := range pools {
+		if p != pool {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// appEvacuation holds the event tracking the evacuation of an app, which is
+// kept running, and so locking the app, until the app is moved.
+type appEvacuation struct {
+	app   *appTypes.App
+	evt   *event.Event
+	moved bool
+	err   error
+}
+
+func newAppEvacuation(ctx context.Context, a *appTypes.App, source *provTypes.Cluster, owner auth.Token, w io.Writer) (*appEvacuation, error) {
+	evt, err := event.New(ctx, &event.Opts{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeApp, Value: a.Name},
+		ExtraTargets: []eventTypes.ExtraTarget{
+			{Target: eventTypes.Target{Type: eventTypes.TargetTypeCluster, Value: source.Name}},
+		},
+		Kind:  permission.PermClusterUpdateEvacuate,
+		Owner: owner,
+		Allowed: event.Allowed(permission.PermAppReadEvents, append(permission.Contexts(permTypes.CtxTeam, a.Teams),
+			permission.Context(permTypes.CtxApp, a.Name),
+			permission.Context(permTypes.CtxPool, a.Pool),
+		)...),
+	})
+	if err != nil {
+		return nil, err
+	}
+	evt.SetLogWriter(w)
+	return &appEvacuation{app: a, evt: evt}, nil
+}
+
+func (e *appEvacuation) done(ctx context.Context) {
+	var err error
+	if e.err == nil && !e.moved {
+		err = e.evt.Abort(ctx)
+	} else {
+		err = e.evt.Done(ctx, e.err)
+	}
+	if err != nil {
+		log.Errorf("unable to finish evacuation event of app %q: %v", e.app.Name, err)
+	}
+}
+
+func evacuateJob(ctx context.Context, prov EvacuationProvisioner, j *jobTypes.Job, source *provTypes.Cluster, owner auth.Token, w io.Writer) (err error) {
+	evt, err := event.New(ctx, &event.Opts{
+		Target: eventTypes.Target{Type: eventTypes.TargetTypeJob, Value: j.Name},
+		ExtraTargets: []eventTypes.ExtraTarget{
+			{Target: eventTypes.Target{Type: eventTypes.TargetTypeCluster, Value: source.Name}},
+		},
+		Kind:  permission.PermClusterUpdateEvacuate,
+		Owner: owner,
+		Allowed: event.Allowed(permission.PermJobReadEvents,
+			permission.Context(permTypes.CtxTeam, j.TeamOwner),
+			permission.Context(permTypes.CtxJob, j.Name),
+			permission.Context(permTypes.CtxPool, j.Pool),
+		),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "---- Evacuating job %q ----\n", j.Name)
+	evt.SetLogWriter(w)
+	moved, err := prov.EvacuateJob(ctx, j, source, evt)
+	if err == nil && !moved {
+		fmt.Fprintf(w, " ---> Job %q is not in cluster %q, skipping\n", j.Name, source.Name)
+		return evt.Abort(ctx)
+	}
+	return evt.Done(ctx, err)
+}
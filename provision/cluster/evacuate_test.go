@@ -0,0 +1,363 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/auth/native"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/permission/permissiontest"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+	check "gopkg.in/check.v1"
+)
+
+type evacuationProv struct {
+	clusterProv
+	prepared      []string
+	evacuated     []string
+	evacuatedJobs []string
+	failApp       string
+	failJob       string
+	missing       map[string]bool
+	cleaned       string
+}
+
+func (p *evacuationProv) PrepareAppEvacuation(ctx context.Context, a *appTypes.App, source, destination *provTypes.Cluster, w io.Writer) (bool, error) {
+	if a.Name == p.failApp {
+		return false, errors.New("units not ready")
+	}
+	if p.missing[a.Name] {
+		return false, nil
+	}
+	p.prepared = append(p.prepared, a.Name)
+	return true, nil
+}
+
+func (p *evacuationProv) EvacuateApp(ctx context.Context, a *appTypes.App, source *provTypes.Cluster, w io.Writer) (bool, error) {
+	if p.missing[a.Name] {
+		return false, nil
+	}
+	p.evacuated = append(p.evacuated, a.Name)
+	return true, nil
+}
+
+func (p *evacuationProv) EvacuateJob(ctx context.Context, j *jobTypes.Job, source *provTypes.Cluster, w io.Writer) (bool, error) {
+	if j.Name == p.failJob {
+		return false, errors.New("invalid schedule")
+	}
+	p.evacuatedJobs = append(p.evacuatedJobs, j.Name)
+	return true, nil
+}
+
+func (p *evacuationProv) CleanupEvacuatedPool(ctx context.Context, pool string, source *provTypes.Cluster) error {
+	p.cleaned = pool
+	return nil
+}
+
+func (s *S) setupEvacuation(c *check.C, prov provision.Provisioner, updated *[]provTypes.Cluster) auth.Token {
+	provision.Register("fake-cluster", func() (provision.Provisioner, error) {
+		return prov, nil
+	})
+	clusters := map[string]*provTypes.Cluster{
+		"c1": {Name: "c1", Provisioner: "fake-cluster", Pools: []string{"p1", "p2"}},
+		"c2": {Name: "c2", Provisioner: "fake-cluster", Pools: []string{"p3"}},
+	}
+	servicemanager.Cluster = &provTypes.MockClusterService{
+		OnFindByName: func(name string) (*provTypes.Cluster, error) {
+			if clust, ok := clusters[name]; ok {
+				return clust, nil
+			}
+			return nil, provTypes.ErrClusterNotFound
+		},
+		OnFindByPool: func(prov, pool string) (*provTypes.Cluster, error) {
+			return clusters["c1"], nil
+		},
+		OnUpdate: func(clust provTypes.Cluster) error {
+			*updated = append(*updated, clust)
+			return nil
+		},
+	}
+	servicemanager.Pool = &provTypes.MockPoolService{
+		OnFindByName: func(name string) (*provTypes.Pool, error) {
+			return &provTypes.Pool{Name: name}, nil
+		},
+	}
+	servicemanager.App = &appTypes.MockAppService{
+		OnList: func(filter *appTypes.Filter) ([]*appTypes.App, error) {
+			c.Assert(filter.Pool, check.Equals, "p1")
+			return []*appTypes.App{
+				{Name: "myapp", Pool: "p1", Teams: []string{"team1"}},
+				{Name: "otherapp", Pool: "p1", Teams: []string{"team1"}},
+			}, nil
+		},
+	}
+	servicemanager.Job = &jobTypes.MockJobService{
+		OnList: func(filter *jobTypes.Filter) ([]jobTypes.Job, error) {
+			c.Assert(filter.Pool, check.Equals, "p1")
+			return []jobTypes.Job{{Name: "myjob", Pool: "p1", TeamOwner: "team1"}}, nil
+		},
+	}
+	servicemanager.Volume = &volumeTypes.MockVolumeService{}
+	_, token := permissiontest.CustomUserWithPermission(c, auth.ManagedScheme(native.NativeScheme{}), "evacuator", permTypes.Permission{
+		Scheme:  permission.PermClusterUpdateEvacuate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	return token
+}
+
+func (s *S) TestEvacuate(c *check.C) {
+	inst := evacuationProv{clusterProv: clusterProv{FakeProvisioner: provisiontest.ProvisionerInstance}}
+	var updated []provTypes.Cluster
+	token := s.setupEvacuation(c, &inst, &updated)
+	defer provision.Unregister("fake-cluster")
+	err := Evacuate(context.TODO(), EvacuateOptions{
+		Source:      "c1",
+		Destination: "c2",
+		Pool:        "p1",
+		Owner:       token,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(updated, check.HasLen, 2)
+	c.Assert(updated[0].Name, check.Equals, "c2")
+	c.Assert(updated[0].Pools, check.DeepEquals, []string{"p3", "p1"})
+	c.Assert(updated[1].Name, check.Equals, "c1")
+	c.Assert(updated[1].Pools, check.DeepEquals, []string{"p2"})
+	c.Assert(inst.prepared, check.DeepEquals, []string{"myapp", "otherapp"})
+	c.Assert(inst.evacuated, check.DeepEquals, []string{"myapp", "otherapp"})
+	c.Assert(inst.evacuatedJobs, check.DeepEquals, []string{"myjob"})
+	c.Assert(inst.cleaned, check.Equals, "p1")
+	evts, err := event.List(context.TODO(), &event.Filter{
+		Target:   eventTypes.Target{Type: eventTypes.TargetTypeApp, Value: "myapp"},
+		KindType: eventTypes.KindTypePermission,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Kind.Name, check.Equals, permission.PermClusterUpdateEvacuate.FullName())
+	c.Assert(evts[0].Running, check.Equals, false)
+	c.Assert(evts[0].Error, check.Equals, "")
+	evts, err = event.List(context.TODO(), &event.Filter{
+		Target:   eventTypes.Target{Type: eventTypes.TargetTypeJob, Value: "myjob"},
+		KindType: eventTypes.KindTypePermission,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Error, check.Equals, "")
+}
+
+func (s *S) TestEvacuateToDefaultCluster(c *check.C) {
+	inst := evacuationProv{clusterProv: clusterProv{FakeProvisioner: provisiontest.ProvisionerInstance}}
+	var updated []provTypes.Cluster
+	token := s.setupEvacuation(c, &inst, &updated)
+	defer provision.Unregister("fake-cluster")
+	destination, err := servicemanager.Cluster.FindByName(context.TODO(), "c2")
+	c.Assert(err, check.IsNil)
+	destination.Default = true
+	destination.Pools = nil
+	err = Evacuate(context.TODO(), EvacuateOptions{
+		Source:      "c1",
+		Destination: "c2",
+		Pool:        "p1",
+		Owner:       token,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(updated, check.HasLen, 1)
+	c.Assert(updated[0].Name, check.Equals, "c1")
+	c.Assert(updated[0].Pools, check.DeepEquals, []string{"p2"})
+}
+
+func (s *S) TestEvacuateKeepsPoolWhenDeployFails(c *check.C) {
+	inst := evacuationProv{
+		clusterProv: clusterProv{FakeProvisioner: provisiontest.ProvisionerInstance},
+		failApp:     "otherapp",
+		missing:     map[string]bool{"myapp": true},
+	}
+	var updated []provTypes.Cluster
+	token := s.setupEvacuation(c, &inst, &updated)
+	defer provision.Unregister("fake-cluster")
+	err := Evacuate(context.TODO(), EvacuateOptions{
+		Source:      "c1",
+		Destination: "c2",
+		Pool:        "p1",
+		Owner:       token,
+	})
+	c.Assert(err, check.ErrorMatches, `(?s).*unable to evacuate app "otherapp": units not ready.*`)
+	c.Assert(updated, check.HasLen, 0)
+	c.Assert(inst.evacuated, check.IsNil)
+	c.Assert(inst.evacuatedJobs, check.IsNil)
+	c.Assert(inst.cleaned, check.Equals, "")
+	evts, err := event.List(context.TODO(), &event.Filter{
+		Target:   eventTypes.Target{Type: eventTypes.TargetTypeApp, Value: "myapp"},
+		KindType: eventTypes.KindTypePermission,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 0)
+	evts, err = event.List(context.TODO(), &event.Filter{
+		Target:   eventTypes.Target{Type: eventTypes.TargetTypeApp, Value: "otherapp"},
+		KindType: eventTypes.KindTypePermission,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Error, check.Equals, "units not ready")
+}
+
+func (s *S) TestEvacuateResumesAfterPoolMoved(c *check.C) {
+	inst := evacuationProv{
+		clusterProv: clusterProv{FakeProvisioner: provisiontest.ProvisionerInstance},
+		failJob:     "myjob",
+	}
+	var updated []provTypes.Cluster
+	token := s.setupEvacuation(c, &inst, &updated)
+	defer provision.Unregister("fake-cluster")
+	clusterService := servicemanager.Cluster.(*provTypes.MockClusterService)
+	source, err := clusterService.OnFindByName("c1")
+	c.Assert(err, check.IsNil)
+	source.Pools = []string{"p2"}
+	destination, err := clusterService.OnFindByName("c2")
+	c.Assert(err, check.IsNil)
+	destination.Pools = []string{"p3", "p1"}
+	clusterService.OnFindByPool = func(prov, pool string) (*provTypes.Cluster, error) {
+		return destination, nil
+	}
+	err = Evacuate(context.TODO(), EvacuateOptions{
+		Source:      "c1",
+		Destination: "c2",
+		Pool:        "p1",
+		Owner:       token,
+	})
+	c.Assert(err, check.ErrorMatches, `(?s).*unable to evacuate job "myjob": invalid schedule.*`)
+	c.Assert(updated, check.HasLen, 0)
+	c.Assert(inst.prepared, check.IsNil)
+	c.Assert(inst.evacuated, check.DeepEquals, []string{"myapp", "otherapp"})
+	c.Assert(inst.cleaned, check.Equals, "")
+}
+
+func (s *S) TestEvacuateResumesPoolRemovalFromSource(c *check.C) {
+	inst := evacuationProv{clusterProv: clusterProv{FakeProvisioner: provisiontest.ProvisionerInstance}}
+	var updated []provTypes.Cluster
+	token := s.setupEvacuation(c, &inst, &updated)
+	defer provision.Unregister("fake-cluster")
+	clusterService := servicemanager.Cluster.(*provTypes.MockClusterService)
+	destination, err := clusterService.OnFindByName("c2")
+	c.Assert(err, check.IsNil)
+	destination.Pools = []string{"p3", "p1"}
+	clusterService.OnFindByPool = func(prov, pool string) (*provTypes.Cluster, error) {
+		return destination, nil
+	}
+	for i := 0; i < 2; i++ {
+		err = Evacuate(context.TODO(), EvacuateOptions{
+			Source:      "c1",
+			Destination: "c2",
+			Pool:        "p1",
+			Owner:       token,
+		})
+		c.Assert(err, check.IsNil)
+	}
+	c.Assert(updated, check.HasLen, 1)
+	c.Assert(updated[0].Name, check.Equals, "c1")
+	c.Assert(updated[0].Pools, check.DeepEquals, []string{"p2"})
+	c.Assert(destination.Pools, check.DeepEquals, []string{"p3", "p1"})
+}
+
+func (s *S) TestEvacuateLastSourcePool(c *check.C) {
+	inst := evacuationProv{clusterProv: clusterProv{FakeProvisioner: provisiontest.ProvisionerInstance}}
+	var updated []provTypes.Cluster
+	token := s.setupEvacuation(c, &inst, &updated)
+	defer provision.Unregister("fake-cluster")
+	source, err := servicemanager.Cluster.FindByName(context.TODO(), "c1")
+	c.Assert(err, check.IsNil)
+	source.Pools = []string{"p1"}
+	err = Evacuate(context.TODO(), EvacuateOptions{
+		Source:      "c1",
+		Destination: "c2",
+		Pool:        "p1",
+		Owner:       token,
+	})
+	c.Assert(err, check.ErrorMatches, `cluster "c1" would be left without pools, set it as default or add another pool first`)
+	c.Assert(updated, check.HasLen, 0)
+	c.Assert(inst.prepared, check.IsNil)
+}
+
+func (s *S) TestEvacuateAppWithVolumes(c *check.C) {
+	inst := evacuationProv{clusterProv: clusterProv{FakeProvisioner: provisiontest.ProvisionerInstance}}
+	var updated []provTypes.Cluster
+	token := s.setupEvacuation(c, &inst, &updated)
+	defer provision.Unregister("fake-cluster")
+	servicemanager.Volume = &volumeTypes.MockVolumeService{
+		OnListByApp: func(ctx context.Context, appName string) ([]volumeTypes.Volume, error) {
+			if appName == "otherapp" {
+				return []volumeTypes.Volume{{Name: "vol1"}}, nil
+			}
+			return nil, nil
+		},
+	}
+	err := Evacuate(context.TODO(), EvacuateOptions{
+		Source:      "c1",
+		Destination: "c2",
+		Pool:        "p1",
+		Owner:       token,
+	})
+	c.Assert(err, check.ErrorMatches, `app "otherapp" has bound volumes, its data can't be moved to another cluster`)
+	_, ok := errors.Cause(err).(*tsuruErrors.ValidationError)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(updated, check.HasLen, 0)
+	c.Assert(inst.prepared, check.IsNil)
+}
+
+func (s *S) TestEvacuateJobWithVolumes(c *check.C) {
+	inst := evacuationProv{clusterProv: clusterProv{FakeProvisioner: provisiontest.ProvisionerInstance}}
+	var updated []provTypes.Cluster
+	token := s.setupEvacuation(c, &inst, &updated)
+	defer provision.Unregister("fake-cluster")
+	servicemanager.Volume = &volumeTypes.MockVolumeService{
+		OnListByJob: func(ctx context.Context, jobName string) ([]volumeTypes.Volume, error) {
+			return []volumeTypes.Volume{{Name: "vol1"}}, nil
+		},
+	}
+	err := Evacuate(context.TODO(), EvacuateOptions{
+		Source:      "c1",
+		Destination: "c2",
+		Pool:        "p1",
+		Owner:       token,
+	})
+	c.Assert(err, check.ErrorMatches, `job "myjob" has bound volumes, its data can't be moved to another cluster`)
+	c.Assert(updated, check.HasLen, 0)
+	c.Assert(inst.prepared, check.IsNil)
+}
+
+func (s *S) TestEvacuateNotSupported(c *check.C) {
+	inst := clusterProv{FakeProvisioner: provisiontest.ProvisionerInstance}
+	var updated []provTypes.Cluster
+	token := s.setupEvacuation(c, &inst, &updated)
+	defer provision.Unregister("fake-cluster")
+	err := Evacuate(context.TODO(), EvacuateOptions{
+		Source:      "c1",
+		Destination: "c2",
+		Pool:        "p1",
+		Owner:       token,
+	})
+	c.Assert(err, check.Equals, ErrEvacuationNotSupported)
+	c.Assert(updated, check.HasLen, 0)
+}
+
+func (s *S) TestEvacuateValidation(c *check.C) {
+	err := Evacuate(context.TODO(), EvacuateOptions{Source: "c1", Destination: "c1", Pool: "p1"})
+	c.Assert(err, check.ErrorMatches, "source and destination clusters must be different")
+	err = Evacuate(context.TODO(), EvacuateOptions{Source: "c1", Destination: "c2"})
+	c.Assert(err, check.ErrorMatches, "pool is mandatory")
+}
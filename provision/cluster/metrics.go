@@ -16,6 +16,7 @@ var (
 	desc        = prometheus.NewDesc("tsuru_cluster_info", "Basic information about existing clusters", []string{"provisioner", "name"}, nil)
 	poolsDesc   = prometheus.NewDesc("tsuru_cluster_pool", "information about related pool that are inside the cluster", []string{"name", "pool"}, nil)
 	failureDesc = prometheus.NewDesc("tsuru_cluster_fetch_fail", "indicates whether failed to get clusters", []string{}, nil)
+	expiryDesc  = prometheus.NewDesc("tsuru_cluster_credential_expiry_timestamp_seconds", "unix time when a credential used to access the cluster expires", []string{"name", "credential"}, nil)
 )
 
 func init() {
@@ -28,6 +29,7 @@ func (c *clustersMetricCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- desc
 	ch <- poolsDesc
 	ch <- failureDesc
+	ch <- expiryDesc
 }
 
 func (c *clustersMetricCollector) Collect(ch chan<- prometheus.Metric) {
@@ -50,5 +52,14 @@ func (c *clustersMetricCollector) Collect(ch chan<- prometheus.Metric) {
 		for _, pool := range cluster.Pools {
 			ch <- prometheus.MustNewConstMetric(poolsDesc, prometheus.GaugeValue, float64(1), cluster.Name, pool)
 		}
+
+		expiry, err := CredentialsExpiry(&cluster)
+		if err != nil {
+			log.Errorf("Could not get credentials expiry: %s", err.Error())
+			continue
+		}
+		for _, e := range expiry {
+			ch <- prometheus.MustNewConstMetric(expiryDesc, prometheus.GaugeValue, float64(e.ExpiresAt.Unix()), cluster.Name, e.Credential)
+		}
 	}
 }
@@ -5,6 +5,8 @@
 package cluster
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tsuru/tsuru/servicemanager"
@@ -108,3 +110,35 @@ func (s *S) TestClusterMetricsErrors(c *check.C) {
 	c.Assert(metrics, check.HasLen, 1)
 	c.Assert(metrics[0].GetGauge().GetValue(), check.Equals, float64(1))
 }
+
+func (s *S) TestClusterMetricsCredentialsExpiry(c *check.C) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	servicemanager.Cluster = &provTypes.MockClusterService{
+		OnList: func() ([]provTypes.Cluster, error) {
+			return []provTypes.Cluster{
+				{
+					Name:        "my-cluster",
+					Provisioner: "k8s",
+					ClientCert:  testCertificate(c, expiresAt),
+				},
+			}, nil
+		},
+	}
+
+	prometheusRegistry := prometheus.NewRegistry()
+	prometheusRegistry.MustRegister(&clustersMetricCollector{})
+
+	metricGroups, err := prometheusRegistry.Gather()
+	c.Assert(err, check.IsNil)
+	c.Assert(metricGroups, check.HasLen, 3)
+	c.Assert(metricGroups[0].GetName(), check.Equals, "tsuru_cluster_credential_expiry_timestamp_seconds")
+	metrics := metricGroups[0].Metric
+	c.Assert(metrics, check.HasLen, 1)
+	c.Assert(metrics[0].GetGauge().GetValue(), check.Equals, float64(expiresAt.Unix()))
+	labels := metrics[0].GetLabel()
+	c.Assert(labels, check.HasLen, 2)
+	c.Assert(labels[0].GetName(), check.Equals, "credential")
+	c.Assert(labels[0].GetValue(), check.Equals, "clientcert")
+	c.Assert(labels[1].GetName(), check.Equals, "name")
+	c.Assert(labels[1].GetValue(), check.Equals, "my-cluster")
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/provision"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+)
+
+var ErrNodesNotSupported = errors.New("cluster provisioner does not support listing nodes")
+
+// Nodes returns the nodes of the cluster, the units running on them and the
+// units not yet scheduled.
+func Nodes(ctx context.Context, c *provTypes.Cluster) (*provTypes.NodeList, error) {
+	prov, err := provision.Get(c.Provisioner)
+	if err != nil {
+		return nil, err
+	}
+	nodeProv, ok := prov.(provision.NodeProvisioner)
+	if !ok {
+		return nil, ErrNodesNotSupported
+	}
+	return nodeProv.ClusterNodes(ctx, c)
+}
@@ -126,7 +126,10 @@ func (p *kubernetesProvisioner) GetAutoScale(ctx context.Context, a *appTypes.Ap
 	if err != nil {
 		return nil, err
 	}
+	return getAutoScale(ctx, client, a)
+}
 
+func getAutoScale(ctx context.Context, client *ClusterClient, a *appTypes.App) ([]provTypes.AutoScaleSpec, error) {
 	kedaClient, err := KEDAClientForConfig(client.restConfig)
 	if err != nil {
 		return nil, err
@@ -268,13 +271,13 @@ func hpaToSpec(hpa autoscalingv2.HorizontalPodAutoscaler) provTypes.AutoScaleSpe
 	return spec
 }
 
-func (p *kubernetesProvisioner) deleteAllAutoScale(ctx context.Context, a *appTypes.App) error {
-	scaleSpecs, err := p.GetAutoScale(ctx, a)
+func deleteAllAutoScale(ctx context.Context, client *ClusterClient, a *appTypes.App) error {
+	scaleSpecs, err := getAutoScale(ctx, client, a)
 	if err != nil {
 		return err
 	}
 	for _, spec := range scaleSpecs {
-		err = p.RemoveAutoScale(ctx, a, spec.Process)
+		err = removeAutoScale(ctx, client, a, spec.Process)
 		if err != nil {
 			return err
 		}
@@ -303,7 +306,10 @@ func (p *kubernetesProvisioner) RemoveAutoScale(ctx context.Context, a *appTypes
 	if err != nil {
 		return err
 	}
+	return removeAutoScale(ctx, client, a, process)
+}
 
+func removeAutoScale(ctx context.Context, client *ClusterClient, a *appTypes.App, process string) error {
 	ns, err := client.AppNamespace(ctx, a)
 	if err != nil {
 		return err
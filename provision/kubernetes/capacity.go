@@ -0,0 +1,183 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ provision.PoolCapacityProvisioner = &kubernetesProvisioner{}
+
+func (p *kubernetesProvisioner) PoolCapacity(ctx context.Context, pool string, plans []appTypes.Plan) (*provTypes.PoolCapacity, error) {
+	client, err := clusterForPool(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	factors, err := requirementsFactorsForPool(client, pool)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := nodesForPool(ctx, client, pool)
+	if err != nil {
+		return nil, err
+	}
+	capacity := &provTypes.PoolCapacity{
+		Pool:             pool,
+		Nodes:            len(nodes),
+		CPUOvercommit:    factors.cpuOvercommit(),
+		MemoryOvercommit: factors.memoryOvercommit(),
+	}
+	nodeNames := map[string]struct{}{}
+	for _, node := range nodes {
+		nodeNames[node.Name] = struct{}{}
+		capacity.Allocatable.MilliCPU += node.Status.Allocatable.Cpu().MilliValue()
+		capacity.Allocatable.Memory += node.Status.Allocatable.Memory().Value()
+	}
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	apps := map[string]*appTypes.App{}
+	appCapacities := map[[2]string]*provTypes.AppCapacity{}
+	for _, pod := range pods.Items {
+		if _, ok := nodeNames[pod.Spec.NodeName]; !ok {
+			continue
+		}
+		if pod.Status.Phase == apiv1.PodSucceeded || pod.Status.Phase == apiv1.PodFailed {
+			continue
+		}
+		requests := podRequests(&pod)
+		capacity.Requested.MilliCPU += requests.MilliCPU
+		capacity.Requested.Memory += requests.Memory
+		labelSet := labelSetFromMeta(&pod.ObjectMeta)
+		appName := labelSet.AppName()
+		if appName == "" || labelSet.IsIsolatedRun() {
+			continue
+		}
+		a, ok := apps[appName]
+		if !ok {
+			a, err = servicemanager.App.GetByName(ctx, appName)
+			if err != nil && err != appTypes.ErrAppNotFound {
+				return nil, err
+			}
+			apps[appName] = a
+		}
+		var planName string
+		if a != nil {
+			plan, err := planForProcess(ctx, a, labelSet.AppProcess())
+			if err != nil {
+				return nil, err
+			}
+			planName = plan.Name
+		}
+		key := [2]string{appName, planName}
+		appCapacity, ok := appCapacities[key]
+		if !ok {
+			appCapacity = &provTypes.AppCapacity{App: appName, Plan: planName}
+			appCapacities[key] = appCapacity
+		}
+		appCapacity.Units++
+		appCapacity.Requested.MilliCPU += requests.MilliCPU
+		appCapacity.Requested.Memory += requests.Memory
+	}
+	for _, appCapacity := range appCapacities {
+		capacity.Apps = append(capacity.Apps, *appCapacity)
+	}
+	sort.Slice(capacity.Apps, func(i, j int) bool {
+		if capacity.Apps[i].App == capacity.Apps[j].App {
+			return capacity.Apps[i].Plan < capacity.Apps[j].Plan
+		}
+		return capacity.Apps[i].App < capacity.Apps[j].App
+	})
+	capacity.Available = provTypes.PoolResources{
+		MilliCPU: max(capacity.Allocatable.MilliCPU-capacity.Requested.MilliCPU, 0),
+		Memory:   max(capacity.Allocatable.Memory-capacity.Requested.Memory, 0),
+	}
+	for _, plan := range plans {
+		capacity.Headroom = append(capacity.Headroom, planHeadroom(plan, factors, capacity.Available))
+	}
+	return capacity, nil
+}
+
+func requirementsFactorsForPool(client *ClusterClient, pool string) (requirementsFactors, error) {
+	overCommit, err := client.OvercommitFactor(pool)
+	if err != nil {
+		return requirementsFactors{}, errors.WithMessage(err, "misconfigured cluster overcommit factor")
+	}
+	cpuOverCommit, err := client.CPUOvercommitFactor(pool)
+	if err != nil {
+		return requirementsFactors{}, errors.WithMessage(err, "misconfigured cluster cpu overcommit factor")
+	}
+	memoryOverCommit, err := client.MemoryOvercommitFactor(pool)
+	if err != nil {
+		return requirementsFactors{}, errors.WithMessage(err, "misconfigured cluster memory overcommit factor")
+	}
+	return requirementsFactors{
+		overCommit:       overCommit,
+		cpuOverCommit:    cpuOverCommit,
+		memoryOverCommit: memoryOverCommit,
+	}, nil
+}
+
+// nodesForPool returns the schedulable nodes used by units of the pool.
+func nodesForPool(ctx context.Context, client *ClusterClient, pool string) ([]apiv1.Node, error) {
+	selector, err := poolNodeSelector(client, pool)
+	if err != nil {
+		return nil, err
+	}
+	nodeList, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var nodes []apiv1.Node
+	for _, node := range nodeList.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func podRequests(pod *apiv1.Pod) provTypes.PoolResources {
+	var requests provTypes.PoolResources
+	for _, c := range pod.Spec.Containers {
+		requests.MilliCPU += c.Resources.Requests.Cpu().MilliValue()
+		requests.Memory += c.Resources.Requests.Memory().Value()
+	}
+	return requests
+}
+
+func planHeadroom(plan appTypes.Plan, factors requirementsFactors, available provTypes.PoolResources) provTypes.PlanHeadroom {
+	headroom := provTypes.PlanHeadroom{Plan: plan.Name}
+	if cpuMilli := int64(plan.GetMilliCPU()); cpuMilli > 0 {
+		cpuRequests := factors.cpuRequests(cpuMilli)
+		headroom.UnitRequests.MilliCPU = cpuRequests.MilliValue()
+	}
+	if memory := plan.GetMemory(); memory > 0 {
+		memoryRequests := factors.memoryRequests(memory)
+		headroom.UnitRequests.Memory = memoryRequests.Value()
+	}
+	headroom.Units = -1
+	if headroom.UnitRequests.MilliCPU > 0 {
+		headroom.Units = available.MilliCPU / headroom.UnitRequests.MilliCPU
+	}
+	if headroom.UnitRequests.Memory > 0 {
+		units := available.Memory / headroom.UnitRequests.Memory
+		if headroom.Units < 0 || units < headroom.Units {
+			headroom.Units = units
+		}
+	}
+	return headroom
+}
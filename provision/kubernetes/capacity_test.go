@@ -0,0 +1,130 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tsuru/tsuru/app"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (s *S) createCapacityNode(name, pool string, unschedulable bool, cpu, memory string) {
+	_, err := s.client.CoreV1().Nodes().Create(context.TODO(), &apiv1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"tsuru.io/pool": pool},
+		},
+		Spec: apiv1.NodeSpec{Unschedulable: unschedulable},
+		Status: apiv1.NodeStatus{
+			Allocatable: apiv1.ResourceList{
+				apiv1.ResourceCPU:    resource.MustParse(cpu),
+				apiv1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(s.t, err)
+}
+
+func (s *S) createCapacityPod(name, node string, labels map[string]string, phase apiv1.PodPhase, cpu, memory string) {
+	_, err := s.client.CoreV1().Pods("default").Create(context.TODO(), &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    labels,
+		},
+		Spec: apiv1.PodSpec{
+			NodeName: node,
+			Containers: []apiv1.Container{{
+				Name: name,
+				Resources: apiv1.ResourceRequirements{
+					Requests: apiv1.ResourceList{
+						apiv1.ResourceCPU:    resource.MustParse(cpu),
+						apiv1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+		Status: apiv1.PodStatus{Phase: phase},
+	}, metav1.CreateOptions{})
+	require.NoError(s.t, err)
+}
+
+func (s *S) setupCapacityCluster() {
+	a := &appTypes.App{Name: "myapp", TeamOwner: s.team.Name, Pool: "test-default"}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	require.NoError(s.t, err)
+	s.createCapacityNode("n1", "test-default", false, "4", "8Gi")
+	s.createCapacityNode("n2", "test-default", true, "4", "8Gi")
+	s.createCapacityNode("n3", "pool1", false, "4", "8Gi")
+	appLabels := map[string]string{
+		"tsuru.io/is-tsuru":    "true",
+		"tsuru.io/app-name":    "myapp",
+		"tsuru.io/app-process": "web",
+	}
+	s.createCapacityPod("myapp-web-1", "n1", appLabels, apiv1.PodRunning, "1", "1Gi")
+	s.createCapacityPod("other", "n1", nil, apiv1.PodRunning, "500m", "512Mi")
+	s.createCapacityPod("finished", "n1", nil, apiv1.PodSucceeded, "1", "1Gi")
+	s.createCapacityPod("pool1-pod", "n3", nil, apiv1.PodRunning, "1", "1Gi")
+}
+
+func (s *S) TestPoolCapacity(c *check.C) {
+	s.setupCapacityCluster()
+	plans := []appTypes.Plan{
+		{Name: "c2m1", CPUMilli: 2000, Memory: 1024 * 1024 * 1024},
+		{Name: "nolimits"},
+	}
+	capacity, err := s.p.PoolCapacity(context.TODO(), "test-default", plans)
+	require.NoError(s.t, err)
+	require.Equal(s.t, &provTypes.PoolCapacity{
+		Pool:             "test-default",
+		Nodes:            1,
+		Allocatable:      provTypes.PoolResources{MilliCPU: 4000, Memory: 8 * 1024 * 1024 * 1024},
+		Requested:        provTypes.PoolResources{MilliCPU: 1500, Memory: 1536 * 1024 * 1024},
+		Available:        provTypes.PoolResources{MilliCPU: 2500, Memory: 6656 * 1024 * 1024},
+		CPUOvercommit:    1,
+		MemoryOvercommit: 1,
+		Apps: []provTypes.AppCapacity{
+			{App: "myapp", Plan: "default", Units: 1, Requested: provTypes.PoolResources{MilliCPU: 1000, Memory: 1024 * 1024 * 1024}},
+		},
+		Headroom: []provTypes.PlanHeadroom{
+			{Plan: "c2m1", UnitRequests: provTypes.PoolResources{MilliCPU: 2000, Memory: 1024 * 1024 * 1024}, Units: 1},
+			{Plan: "nolimits", Units: -1},
+		},
+	}, capacity)
+}
+
+func (s *S) TestPoolCapacityWithOvercommit(c *check.C) {
+	s.clusterClient.CustomData[cpuOvercommitClusterKey] = "4"
+	s.clusterClient.CustomData["test-default:"+memoryOvercommitClusterKey] = "2"
+	s.setupCapacityCluster()
+	plans := []appTypes.Plan{
+		{Name: "c2m1", CPUMilli: 2000, Memory: 1024 * 1024 * 1024},
+	}
+	capacity, err := s.p.PoolCapacity(context.TODO(), "test-default", plans)
+	require.NoError(s.t, err)
+	require.Equal(s.t, 4.0, capacity.CPUOvercommit)
+	require.Equal(s.t, 2.0, capacity.MemoryOvercommit)
+	require.Equal(s.t, []provTypes.PlanHeadroom{
+		{Plan: "c2m1", UnitRequests: provTypes.PoolResources{MilliCPU: 500, Memory: 512 * 1024 * 1024}, Units: 5},
+	}, capacity.Headroom)
+}
+
+func (s *S) TestPoolCapacitySinglePool(c *check.C) {
+	s.clusterClient.CustomData[singlePoolKey] = "true"
+	s.setupCapacityCluster()
+	capacity, err := s.p.PoolCapacity(context.TODO(), "test-default", nil)
+	require.NoError(s.t, err)
+	require.Equal(s.t, 2, capacity.Nodes)
+	require.Equal(s.t, provTypes.PoolResources{MilliCPU: 8000, Memory: 16 * 1024 * 1024 * 1024}, capacity.Allocatable)
+	require.Equal(s.t, provTypes.PoolResources{MilliCPU: 2500, Memory: 2560 * 1024 * 1024}, capacity.Requested)
+	require.Nil(s.t, capacity.Headroom)
+}
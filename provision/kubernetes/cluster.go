@@ -36,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	vpaclientset "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -124,6 +125,10 @@ var (
 	}
 )
 
+var DynamicClientForConfig = func(conf *rest.Config) (dynamic.Interface, error) {
+	return dynamic.NewForConfig(conf)
+}
+
 var ClientForConfig = func(conf *rest.Config) (kubernetes.Interface, error) {
 	return kubernetes.NewForConfig(conf)
 }
@@ -697,7 +702,24 @@ func clustersForApps(ctx context.Context, apps []*appTypes.App) ([]clusterApp, e
 	return result, nil
 }
 
+type poolClusterContextKey struct{}
+
+type poolCluster struct {
+	pool    string
+	cluster *provTypes.Cluster
+}
+
+// contextWithPoolCluster returns a context in which clusterForPool resolves
+// pool to the given cluster instead of the one currently serving it, used to
+// deploy apps in a cluster before their pool is moved there.
+func contextWithPoolCluster(ctx context.Context, pool string, c *provTypes.Cluster) context.Context {
+	return context.WithValue(ctx, poolClusterContextKey{}, poolCluster{pool: pool, cluster: c})
+}
+
 func clusterForPool(ctx context.Context, pool string) (*ClusterClient, error) {
+	if override, ok := ctx.Value(poolClusterContextKey{}).(poolCluster); ok && override.pool == pool {
+		return NewClusterClient(override.cluster)
+	}
 	clust, err := servicemanager.Cluster.FindByPool(ctx, provisionerName, pool)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,204 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/provision/cluster"
+	tsuruv1 "github.com/tsuru/tsuru/provision/kubernetes/pkg/apis/tsuru/v1"
+	"github.com/tsuru/tsuru/router/rebuild"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ cluster.EvacuationProvisioner = &kubernetesProvisioner{}
+
+// PrepareAppEvacuation deploys the current version of the app in the
+// destination cluster, keeping the routes and the units in the source
+// cluster. Apps without resources in the source cluster are skipped, which
+// allows resuming an interrupted evacuation.
+func (p *kubernetesProvisioner) PrepareAppEvacuation(ctx context.Context, a *appTypes.App, source, destination *provTypes.Cluster, w io.Writer) (bool, error) {
+	if w == nil {
+		w = io.Discard
+	}
+	oldClient, err := NewClusterClient(source)
+	if err != nil {
+		return false, err
+	}
+	_, found, err := sourceAppResource(ctx, oldClient, a)
+	if err != nil || !found {
+		return false, err
+	}
+	newClient, err := NewClusterClient(destination)
+	if err != nil {
+		return false, err
+	}
+	versions, err := versionsForAppProcess(ctx, oldClient, a, "", false)
+	if err != nil {
+		return false, err
+	}
+	if len(versions) > 1 {
+		return false, &tsuruErrors.ValidationError{Message: "can't evacuate app with multiple versions, please unify them and try again"}
+	}
+	autoScaleSpecs, err := getAutoScale(ctx, oldClient, a)
+	if err != nil {
+		return false, err
+	}
+	ctx = contextWithPoolCluster(ctx, a.Pool, destination)
+	fmt.Fprintf(w, " ---> Provisioning app %q in cluster %q\n", a.Name, newClient.Name)
+	err = ensureAppCustomResourceSynced(ctx, newClient, a)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range versions {
+		fmt.Fprintf(w, " ---> Deploying version %d in cluster %q\n", v.Version(), newClient.Name)
+		err = p.Restart(ctx, a, "", v, w)
+		if err != nil {
+			return false, err
+		}
+	}
+	for _, spec := range autoScaleSpecs {
+		err = setAutoScale(ctx, newClient, a, spec, false)
+		if err != nil {
+			return false, errors.Wrapf(err, "unable to set autoscale for process %q", spec.Process)
+		}
+	}
+	return true, nil
+}
+
+// EvacuateApp moves the routes of the app to the cluster now serving its
+// pool, where it was deployed by PrepareAppEvacuation, and then removes the
+// app resources from the source cluster. Apps without resources in the
+// source cluster are skipped, which allows resuming an interrupted
+// evacuation.
+func (p *kubernetesProvisioner) EvacuateApp(ctx context.Context, a *appTypes.App, source *provTypes.Cluster, w io.Writer) (bool, error) {
+	if w == nil {
+		w = io.Discard
+	}
+	oldClient, err := NewClusterClient(source)
+	if err != nil {
+		return false, err
+	}
+	tsuruApp, found, err := sourceAppResource(ctx, oldClient, a)
+	if err != nil || !found {
+		return false, err
+	}
+	newClient, err := clusterForPool(ctx, a.Pool)
+	if err != nil {
+		return false, err
+	}
+	if newClient.Name == oldClient.Name {
+		return false, errors.Errorf("pool %q is still served by cluster %q", a.Pool, oldClient.Name)
+	}
+	fmt.Fprintf(w, " ---> Moving routes to cluster %q\n", newClient.Name)
+	err = rebuild.RebuildRoutes(ctx, rebuild.RebuildRoutesOpts{App: a, Writer: w})
+	if err != nil {
+		return false, err
+	}
+	fmt.Fprintf(w, " ---> Removing app resources from cluster %q\n", oldClient.Name)
+	err = p.removeResources(ctx, oldClient, tsuruApp, a)
+	if err != nil {
+		return false, err
+	}
+	tclient, err := TsuruClientForConfig(oldClient.restConfig)
+	if err != nil {
+		return false, err
+	}
+	err = tclient.TsuruV1().Apps(oldClient.Namespace()).Delete(ctx, a.Name, metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+func sourceAppResource(ctx context.Context, client *ClusterClient, a *appTypes.App) (*tsuruv1.App, bool, error) {
+	tclient, err := TsuruClientForConfig(client.restConfig)
+	if err != nil {
+		return nil, false, err
+	}
+	tsuruApp, err := tclient.TsuruV1().Apps(client.Namespace()).Get(ctx, a.Name, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+	return tsuruApp, true, nil
+}
+
+// EvacuateJob creates the job in the cluster now serving its pool and then
+// removes it from the source cluster. Jobs without a cron job in the source
+// cluster are skipped, which allows resuming an interrupted evacuation. Units
+// already running in the source cluster are left to finish.
+func (p *kubernetesProvisioner) EvacuateJob(ctx context.Context, j *jobTypes.Job, source *provTypes.Cluster, w io.Writer) (bool, error) {
+	if w == nil {
+		w = io.Discard
+	}
+	oldClient, err := NewClusterClient(source)
+	if err != nil {
+		return false, err
+	}
+	_, err = getCronJobWithFallback(ctx, oldClient, j, oldClient.PoolNamespace(j.Pool))
+	if k8sErrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	newClient, err := clusterForPool(ctx, j.Pool)
+	if err != nil {
+		return false, err
+	}
+	if newClient.Name == oldClient.Name {
+		return false, errors.Errorf("pool %q is still served by cluster %q", j.Pool, oldClient.Name)
+	}
+	fmt.Fprintf(w, " ---> Provisioning job %q in cluster %q\n", j.Name, newClient.Name)
+	err = p.EnsureJob(ctx, j)
+	if err != nil {
+		return false, err
+	}
+	fmt.Fprintf(w, " ---> Removing job resources from cluster %q\n", oldClient.Name)
+	err = destroyJobResources(ctx, oldClient, j)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CleanupEvacuatedPool removes the namespace used by the pool in the source
+// cluster. Nothing is removed when the namespace is shared among pools, and
+// it fails if jobs are still present in the namespace.
+func (p *kubernetesProvisioner) CleanupEvacuatedPool(ctx context.Context, pool string, source *provTypes.Cluster) error {
+	client, err := NewClusterClient(source)
+	if err != nil {
+		return err
+	}
+	ns := client.PoolNamespace(pool)
+	if ns == client.PoolNamespace("") || ns == client.Namespace() {
+		return nil
+	}
+	cronJobs, err := client.BatchV1().CronJobs(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: "tsuru.io/job-name",
+	})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+	if err == nil && len(cronJobs.Items) > 0 {
+		return errors.Errorf("namespace %q still has %d jobs, refusing to remove it", ns, len(cronJobs.Items))
+	}
+	err = client.CoreV1().Namespaces().Delete(ctx, ns, metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (s *S) TestEvacuateAppNotInSourceCluster(c *check.C) {
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	moved, err := s.p.EvacuateApp(context.TODO(), a, s.clusterClient.Cluster, nil)
+	require.NoError(s.t, err)
+	require.False(s.t, moved)
+}
+
+func (s *S) TestPrepareAppEvacuationNotInSourceCluster(c *check.C) {
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	moved, err := s.p.PrepareAppEvacuation(context.TODO(), a, s.clusterClient.Cluster, &provTypes.Cluster{Name: "c2"}, nil)
+	require.NoError(s.t, err)
+	require.False(s.t, moved)
+}
+
+func (s *S) TestClusterForPoolOverride(c *check.C) {
+	destination := *s.clusterClient.Cluster
+	destination.Name = "c2"
+	ctx := contextWithPoolCluster(context.TODO(), "pool1", &destination)
+	client, err := clusterForPool(ctx, "pool1")
+	require.NoError(s.t, err)
+	require.Equal(s.t, "c2", client.Name)
+	client, err = clusterForPool(ctx, "test-default")
+	require.NoError(s.t, err)
+	require.Equal(s.t, "c1", client.Name)
+}
+
+func (s *S) TestEvacuateJobNotInSourceCluster(c *check.C) {
+	j := &jobTypes.Job{Name: "myjob", Pool: "test-default", TeamOwner: "admin"}
+	moved, err := s.p.EvacuateJob(context.TODO(), j, s.clusterClient.Cluster, nil)
+	require.NoError(s.t, err)
+	require.False(s.t, moved)
+}
+
+func (s *S) TestEvacuateJobPoolStillInSourceCluster(c *check.C) {
+	j := &jobTypes.Job{Name: "myjob", Pool: "test-default", TeamOwner: "admin"}
+	_, err := s.client.BatchV1().CronJobs(s.client.PoolNamespace(j.Pool)).Create(context.TODO(), &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "myjob", Labels: map[string]string{"tsuru.io/job-name": "myjob"}},
+	}, metav1.CreateOptions{})
+	require.NoError(s.t, err)
+	moved, err := s.p.EvacuateJob(context.TODO(), j, s.clusterClient.Cluster, nil)
+	require.EqualError(s.t, err, `pool "test-default" is still served by cluster "c1"`)
+	require.False(s.t, moved)
+}
+
+func (s *S) TestEvacuateAppPoolStillInSourceCluster(c *check.C) {
+	a, _, rollback := s.mock.DefaultReactions(c)
+	defer rollback()
+	moved, err := s.p.EvacuateApp(context.TODO(), a, s.clusterClient.Cluster, nil)
+	require.EqualError(s.t, err, `pool "test-default" is still served by cluster "c1"`)
+	require.False(s.t, moved)
+	_, err = s.client.TsuruV1().Apps(s.clusterClient.Namespace()).Get(context.TODO(), a.Name, metav1.GetOptions{})
+	require.NoError(s.t, err)
+}
+
+func (s *S) TestCleanupEvacuatedPool(c *check.C) {
+	config.Set("kubernetes:use-pool-namespaces", true)
+	defer config.Unset("kubernetes:use-pool-namespaces")
+	ns := s.client.PoolNamespace("pool1")
+	_, err := s.client.CoreV1().Namespaces().Create(context.TODO(), &apiv1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: ns},
+	}, metav1.CreateOptions{})
+	require.NoError(s.t, err)
+	err = s.p.CleanupEvacuatedPool(context.TODO(), "pool1", s.clusterClient.Cluster)
+	require.NoError(s.t, err)
+	_, err = s.client.CoreV1().Namespaces().Get(context.TODO(), ns, metav1.GetOptions{})
+	require.True(s.t, k8sErrors.IsNotFound(err))
+	err = s.p.CleanupEvacuatedPool(context.TODO(), "pool1", s.clusterClient.Cluster)
+	require.NoError(s.t, err)
+}
+
+func (s *S) TestCleanupEvacuatedPoolWithJobs(c *check.C) {
+	config.Set("kubernetes:use-pool-namespaces", true)
+	defer config.Unset("kubernetes:use-pool-namespaces")
+	ns := s.client.PoolNamespace("pool1")
+	_, err := s.client.CoreV1().Namespaces().Create(context.TODO(), &apiv1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: ns},
+	}, metav1.CreateOptions{})
+	require.NoError(s.t, err)
+	_, err = s.client.BatchV1().CronJobs(ns).Create(context.TODO(), &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "myjob", Labels: map[string]string{"tsuru.io/job-name": "myjob"}},
+	}, metav1.CreateOptions{})
+	require.NoError(s.t, err)
+	err = s.p.CleanupEvacuatedPool(context.TODO(), "pool1", s.clusterClient.Cluster)
+	require.EqualError(s.t, err, `namespace "`+ns+`" still has 1 jobs, refusing to remove it`)
+	_, err = s.client.CoreV1().Namespaces().Get(context.TODO(), ns, metav1.GetOptions{})
+	require.NoError(s.t, err)
+}
+
+func (s *S) TestCleanupEvacuatedPoolSharedNamespace(c *check.C) {
+	ns := s.client.PoolNamespace("pool1")
+	_, err := s.client.CoreV1().Namespaces().Create(context.TODO(), &apiv1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: ns},
+	}, metav1.CreateOptions{})
+	require.NoError(s.t, err)
+	err = s.p.CleanupEvacuatedPool(context.TODO(), "pool1", s.clusterClient.Cluster)
+	require.NoError(s.t, err)
+	_, err = s.client.CoreV1().Namespaces().Get(context.TODO(), ns, metav1.GetOptions{})
+	require.NoError(s.t, err)
+}
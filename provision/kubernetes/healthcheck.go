@@ -0,0 +1,147 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/hc"
+	"github.com/tsuru/tsuru/servicemanager"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	appCRDName = "apps.tsuru.io"
+
+	clusterHealthCheckTimeout = 10 * time.Second
+	clustersHealthCheckName   = "kubernetes-clusters"
+)
+
+// namespaceVerbs are the operations tsuru runs on namespaces when managing
+// pools and apps.
+var namespaceVerbs = []string{"get", "create", "update"}
+
+func init() {
+	hc.AddChecker(clustersHealthCheckName, anyClusterHealthCheck)
+	hc.AddCheckerSource(clusterHealthCheckName("*"), clusterHealthCheckers)
+}
+
+func clusterHealthCheckName(clusterName string) string {
+	return "cluster-" + clusterName
+}
+
+// clusterHealthCheckers returns a checker for each kubernetes cluster
+// registered in tsuru. Clusters are listed on every call, so clusters added
+// through any API instance are checked by all of them.
+func clusterHealthCheckers(ctx context.Context) (map[string]func(ctx context.Context) error, error) {
+	clusters, err := servicemanager.Cluster.FindByProvisioner(ctx, provisionerName)
+	if err == provTypes.ErrNoCluster {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]func(ctx context.Context) error, len(clusters))
+	for _, c := range clusters {
+		clusterName := c.Name
+		result[clusterHealthCheckName(clusterName)] = func(ctx context.Context) error {
+			return clusterHealthCheck(ctx, clusterName)
+		}
+	}
+	return result, nil
+}
+
+func healthCheckClient(c *provTypes.Cluster) (*ClusterClient, error) {
+	client, err := NewClusterClient(c)
+	if err != nil {
+		return nil, err
+	}
+	err = client.SetTimeout(clusterHealthCheckTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func checkClusterReachable(client *ClusterClient) error {
+	_, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return errors.Wrap(err, "api server unreachable")
+	}
+	return nil
+}
+
+// clusterHealthCheck verifies that the API server of the cluster is reachable,
+// that tsuru is allowed to manage namespaces and that the custom resource
+// definitions it depends on are installed.
+func clusterHealthCheck(ctx context.Context, clusterName string) error {
+	c, err := servicemanager.Cluster.FindByName(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	client, err := healthCheckClient(c)
+	if err != nil {
+		return err
+	}
+	err = checkClusterReachable(client)
+	if err != nil {
+		return err
+	}
+	for _, verb := range namespaceVerbs {
+		review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Verb:     verb,
+					Resource: "namespaces",
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return errors.Wrap(err, "unable to review namespace permissions")
+		}
+		if !review.Status.Allowed {
+			return errors.Errorf("not allowed to %s namespaces", verb)
+		}
+	}
+	exists, err := crdExists(ctx, client, appCRDName)
+	if err != nil {
+		return errors.Wrap(err, "unable to check custom resource definitions")
+	}
+	if !exists {
+		return errors.Errorf("custom resource definition %q not found", appCRDName)
+	}
+	return nil
+}
+
+// anyClusterHealthCheck fails only when none of the kubernetes clusters is
+// reachable, meaning this tsuru instance cannot handle any app.
+func anyClusterHealthCheck(ctx context.Context) error {
+	clusters, err := servicemanager.Cluster.FindByProvisioner(ctx, provisionerName)
+	if err == provTypes.ErrNoCluster || (err == nil && len(clusters) == 0) {
+		return hc.ErrDisabledComponent
+	}
+	if err != nil {
+		return err
+	}
+	var failures []string
+	for i := range clusters {
+		c := &clusters[i]
+		client, err := healthCheckClient(c)
+		if err == nil {
+			err = checkClusterReachable(client)
+		}
+		if err == nil {
+			return nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", c.Name, err))
+	}
+	return errors.Errorf("unable to reach any cluster: %s", strings.Join(failures, "; "))
+}
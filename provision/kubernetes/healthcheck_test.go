@@ -0,0 +1,110 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tsuru/tsuru/hc"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func (s *S) allowAccessReviews(denied ...string) {
+	s.client.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		for _, verb := range denied {
+			if review.Spec.ResourceAttributes.Verb == verb {
+				review.Status.Allowed = false
+			}
+		}
+		return true, review, nil
+	})
+}
+
+func (s *S) findClusterByName() {
+	s.mockService.Cluster.OnFindByName = func(name string) (*provTypes.Cluster, error) {
+		if name != s.clusterClient.Name {
+			return nil, provTypes.ErrClusterNotFound
+		}
+		return s.clusterClient.Cluster, nil
+	}
+}
+
+func (s *S) TestClusterHealthCheck(c *check.C) {
+	s.findClusterByName()
+	s.allowAccessReviews()
+	_, err := s.client.ApiextensionsV1().CustomResourceDefinitions().Create(context.TODO(), appCustomResourceDefinition(), metav1.CreateOptions{})
+	require.NoError(s.t, err)
+	err = clusterHealthCheck(context.TODO(), s.clusterClient.Name)
+	require.NoError(s.t, err)
+}
+
+func (s *S) TestClusterHealthCheckMissingCRD(c *check.C) {
+	s.findClusterByName()
+	s.allowAccessReviews()
+	err := clusterHealthCheck(context.TODO(), s.clusterClient.Name)
+	require.EqualError(s.t, err, `custom resource definition "apps.tsuru.io" not found`)
+}
+
+func (s *S) TestClusterHealthCheckNamespacePermissionDenied(c *check.C) {
+	s.findClusterByName()
+	s.allowAccessReviews("update")
+	err := clusterHealthCheck(context.TODO(), s.clusterClient.Name)
+	require.EqualError(s.t, err, "not allowed to update namespaces")
+}
+
+func (s *S) TestClusterHealthCheckUnreachable(c *check.C) {
+	s.findClusterByName()
+	s.client.PrependReactor("get", "version", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	err := clusterHealthCheck(context.TODO(), s.clusterClient.Name)
+	require.EqualError(s.t, err, "api server unreachable: connection refused")
+}
+
+func (s *S) TestClusterHealthCheckers(c *check.C) {
+	s.findClusterByName()
+	s.allowAccessReviews()
+	_, err := s.client.ApiextensionsV1().CustomResourceDefinitions().Create(context.TODO(), appCustomResourceDefinition(), metav1.CreateOptions{})
+	require.NoError(s.t, err)
+	results := hc.Check(context.TODO(), "all")
+	for _, result := range results {
+		require.NotEqual(s.t, "cluster-c1", result.Name)
+	}
+	results = hc.Check(context.TODO(), "cluster-c1")
+	require.Len(s.t, results, 1)
+	require.Equal(s.t, hc.HealthCheckOK, results[0].Status)
+	s.mockService.Cluster.OnFindByProvisioner = func(provName string) ([]provTypes.Cluster, error) {
+		return nil, provTypes.ErrNoCluster
+	}
+	results = hc.Check(context.TODO(), "cluster-c1")
+	require.Len(s.t, results, 0)
+}
+
+func (s *S) TestAnyClusterHealthCheck(c *check.C) {
+	err := anyClusterHealthCheck(context.TODO())
+	require.NoError(s.t, err)
+	s.client.PrependReactor("get", "version", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	err = anyClusterHealthCheck(context.TODO())
+	require.EqualError(s.t, err, "unable to reach any cluster: c1: api server unreachable: connection refused")
+}
+
+func (s *S) TestAnyClusterHealthCheckNoClusters(c *check.C) {
+	s.mockService.Cluster.OnFindByProvisioner = func(provName string) ([]provTypes.Cluster, error) {
+		return nil, provTypes.ErrNoCluster
+	}
+	err := anyClusterHealthCheck(context.TODO())
+	require.Equal(s.t, hc.ErrDisabledComponent, err)
+}
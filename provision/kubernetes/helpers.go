@@ -112,6 +112,10 @@ func volumeClaimName(name string) string {
 	return fmt.Sprintf("%s-tsuru-claim", name)
 }
 
+func volumeSnapshotName(volume, snapshot string) string {
+	return fmt.Sprintf("%s-tsuru-snap-%s", volume, snapshot)
+}
+
 func waitFor(ctx context.Context, fn func() (bool, error), onCancel func() error) error {
 	start := time.Now()
 	for {
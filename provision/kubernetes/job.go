@@ -64,7 +64,7 @@ var (
 	}, []string{"job_name"})
 )
 
-func buildJobSpec(job *jobTypes.Job, client *ClusterClient, labels, annotations map[string]string) (batchv1.JobSpec, error) {
+func buildJobSpec(ctx context.Context, job *jobTypes.Job, client *ClusterClient, labels, annotations map[string]string) (batchv1.JobSpec, error) {
 	disableSecrets := client.disableSecrets(job.Pool)
 
 	jSpec := job.Spec
@@ -126,6 +126,11 @@ func buildJobSpec(job *jobTypes.Job, client *ClusterClient, labels, annotations
 		imageURL = jSpec.Container.OriginalImageSrc
 	}
 
+	volumes, mounts, err := createVolumesForJob(ctx, client, job)
+	if err != nil {
+		return batchv1.JobSpec{}, err
+	}
+
 	return batchv1.JobSpec{
 		Parallelism:             jSpec.Parallelism,
 		BackoffLimit:            jSpec.BackoffLimit,
@@ -141,13 +146,15 @@ func buildJobSpec(job *jobTypes.Job, client *ClusterClient, labels, annotations
 				RestartPolicy: "OnFailure",
 				Containers: []apiv1.Container{
 					{
-						Name:      "job",
-						Image:     imageURL,
-						Command:   jSpec.Container.Command,
-						Resources: requirements,
-						Env:       envs,
+						Name:         "job",
+						Image:        imageURL,
+						Command:      jSpec.Container.Command,
+						Resources:    requirements,
+						Env:          envs,
+						VolumeMounts: mounts,
 					},
 				},
+				Volumes:            volumes,
 				ServiceAccountName: serviceAccountNameForJob(*job),
 			},
 		},
@@ -219,7 +226,7 @@ func findAllCronJobsForJob(ctx context.Context, client *ClusterClient, jobName,
 
 func ensureCronjob(ctx context.Context, client *ClusterClient, job *jobTypes.Job) error {
 	labels, annotations := buildMetadata(ctx, job)
-	jobSpec, err := buildJobSpec(job, client, labels, annotations)
+	jobSpec, err := buildJobSpec(ctx, job, client, labels, annotations)
 	if err != nil {
 		return err
 	}
@@ -495,8 +502,13 @@ func (p *kubernetesProvisioner) DestroyJob(ctx context.Context, job *jobTypes.Jo
 	if err != nil {
 		return err
 	}
+	return destroyJobResources(ctx, client, job)
+}
+
+func destroyJobResources(ctx context.Context, client *ClusterClient, job *jobTypes.Job) error {
 	namespace := client.PoolNamespace(job.Pool)
-	if err = client.CoreV1().ServiceAccounts(namespace).Delete(ctx, serviceAccountNameForJob(*job), metav1.DeleteOptions{}); err != nil && !k8sErrors.IsNotFound(err) {
+	err := client.CoreV1().ServiceAccounts(namespace).Delete(ctx, serviceAccountNameForJob(*job), metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
 		return err
 	}
 
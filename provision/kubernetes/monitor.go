@@ -54,6 +54,7 @@ type clusterController struct {
 	vpaInformerFactory vpaInformers.SharedInformerFactory
 	podInformer        v1informers.PodInformer
 	serviceInformer    v1informers.ServiceInformer
+	nodeInformer       v1informers.NodeInformer
 	vpaInformer        vpaV1Informers.VerticalPodAutoscalerInformer
 	jobsInformer       jobsInformer.JobInformer
 	eventsInformer     v1informers.EventInformer
@@ -249,6 +250,22 @@ func (c *clusterController) getServiceInformer() (v1informers.ServiceInformer, e
 	return c.serviceInformer, err
 }
 
+func (c *clusterController) getNodeInformer() (v1informers.NodeInformer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.nodeInformer == nil {
+		err := c.withInformerFactory(func(factory informers.SharedInformerFactory) {
+			c.nodeInformer = factory.Core().V1().Nodes()
+			c.nodeInformer.Informer()
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := c.waitForSync(c.nodeInformer.Informer())
+	return c.nodeInformer, err
+}
+
 func (c *clusterController) getVPAInformer() (vpaV1Informers.VerticalPodAutoscalerInformer, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -0,0 +1,161 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/provision"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var _ provision.NodeProvisioner = &kubernetesProvisioner{}
+
+func (p *kubernetesProvisioner) PoolNodes(ctx context.Context, pool string) (*provTypes.NodeList, error) {
+	client, err := clusterForPool(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := poolNodeSelector(client, pool)
+	if err != nil {
+		return nil, err
+	}
+	return p.listNodes(client, selector, func(l *provision.LabelSet) bool {
+		return l.AppPool() == pool
+	})
+}
+
+func (p *kubernetesProvisioner) ClusterNodes(ctx context.Context, c *provTypes.Cluster) (*provTypes.NodeList, error) {
+	client, err := NewClusterClient(c)
+	if err != nil {
+		return nil, err
+	}
+	return p.listNodes(client, labels.Everything(), func(l *provision.LabelSet) bool {
+		return l.AppName() != ""
+	})
+}
+
+// poolNodeSelector selects the nodes used by units of the pool, which are all
+// the cluster nodes in single pool clusters.
+func poolNodeSelector(client *ClusterClient, pool string) (labels.Selector, error) {
+	singlePool, err := client.SinglePool()
+	if err != nil {
+		return nil, errors.WithMessage(err, "misconfigured cluster single pool value")
+	}
+	if singlePool {
+		return labels.Everything(), nil
+	}
+	return labels.SelectorFromSet(provision.NodeLabels(provision.NodeLabelsOpts{
+		Pool:   pool,
+		Prefix: tsuruLabelPrefix,
+	}).ToNodeByPoolSelector()), nil
+}
+
+// listNodes reads the nodes matching selector and the units scheduled on them
+// from the cluster informers. Units not scheduled on any node are reported as
+// pending when isPending accepts their labels.
+func (p *kubernetesProvisioner) listNodes(client *ClusterClient, selector labels.Selector, isPending func(*provision.LabelSet) bool) (*provTypes.NodeList, error) {
+	controller, err := getClusterController(p, client)
+	if err != nil {
+		return nil, err
+	}
+	nodeInformer, err := controller.getNodeInformer()
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := nodeInformer.Lister().List(selector)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	podInformer, err := controller.getPodInformer()
+	if err != nil {
+		return nil, err
+	}
+	pods, err := podInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	podsByNode := map[string][]apiv1.Pod{}
+	var pendingPods []apiv1.Pod
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			if isPending(labelSetFromMeta(&pod.ObjectMeta)) {
+				pendingPods = append(pendingPods, *pod)
+			}
+			continue
+		}
+		podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], *pod)
+	}
+	result := &provTypes.NodeList{
+		Nodes: make([]provTypes.Node, 0, len(nodes)),
+	}
+	for _, node := range nodes {
+		units, err := p.sortedUnits(podsByNode[node.Name])
+		if err != nil {
+			return nil, err
+		}
+		result.Nodes = append(result.Nodes, nodeToProvNode(client.Name, node, units))
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool {
+		return result.Nodes[i].Name < result.Nodes[j].Name
+	})
+	result.Pending, err = p.sortedUnits(pendingPods)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (p *kubernetesProvisioner) sortedUnits(pods []apiv1.Pod) ([]provTypes.Unit, error) {
+	units, err := p.podsToUnitsMultiple(pods, nil)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(units, func(i, j int) bool {
+		return units[i].Name < units[j].Name
+	})
+	return units, nil
+}
+
+func nodeToProvNode(clusterName string, node *apiv1.Node, units []provTypes.Unit) provTypes.Node {
+	result := provTypes.Node{
+		Name:          node.Name,
+		Cluster:       clusterName,
+		Pool:          labelSetFromMeta(&node.ObjectMeta).NodePool(),
+		Unschedulable: node.Spec.Unschedulable,
+		Capacity: provTypes.PoolResources{
+			MilliCPU: node.Status.Capacity.Cpu().MilliValue(),
+			Memory:   node.Status.Capacity.Memory().Value(),
+		},
+		Allocatable: provTypes.PoolResources{
+			MilliCPU: node.Status.Allocatable.Cpu().MilliValue(),
+			Memory:   node.Status.Allocatable.Memory().Value(),
+		},
+		Units: units,
+	}
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == apiv1.NodeInternalIP {
+			result.Address = addr.Address
+			break
+		}
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == apiv1.NodeReady {
+			result.Ready = cond.Status == apiv1.ConditionTrue
+		}
+		result.Conditions = append(result.Conditions, provTypes.NodeCondition{
+			Type:               string(cond.Type),
+			Status:             string(cond.Status),
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+			LastTransitionTime: cond.LastTransitionTime.Time.UTC(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/require"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (s *S) setupNodesCluster() {
+	s.createCapacityNode("n1", "test-default", false, "4", "8Gi")
+	s.createCapacityNode("n2", "test-default", true, "2", "4Gi")
+	s.createCapacityNode("n3", "pool1", false, "4", "8Gi")
+	node, err := s.client.CoreV1().Nodes().Get(context.TODO(), "n1", metav1.GetOptions{})
+	require.NoError(s.t, err)
+	node.Status.Addresses = []apiv1.NodeAddress{
+		{Type: apiv1.NodeHostName, Address: "n1"},
+		{Type: apiv1.NodeInternalIP, Address: "192.168.0.1"},
+	}
+	node.Status.Conditions = []apiv1.NodeCondition{
+		{Type: apiv1.NodeReady, Status: apiv1.ConditionTrue, Reason: "KubeletReady"},
+		{Type: apiv1.NodeMemoryPressure, Status: apiv1.ConditionFalse},
+	}
+	_, err = s.client.CoreV1().Nodes().UpdateStatus(context.TODO(), node, metav1.UpdateOptions{})
+	require.NoError(s.t, err)
+	appLabels := map[string]string{
+		"tsuru.io/is-tsuru":    "true",
+		"tsuru.io/app-name":    "myapp",
+		"tsuru.io/app-process": "web",
+		"tsuru.io/app-pool":    "test-default",
+	}
+	s.createCapacityPod("myapp-web-2", "n1", appLabels, apiv1.PodRunning, "1", "1Gi")
+	s.createCapacityPod("myapp-web-1", "n1", appLabels, apiv1.PodPending, "1", "1Gi")
+	s.createCapacityPod("myapp-web-3", "n3", appLabels, apiv1.PodRunning, "1", "1Gi")
+	s.createCapacityPod("myapp-web-4", "", appLabels, apiv1.PodPending, "1", "1Gi")
+	s.createCapacityPod("other-pod", "", map[string]string{"app": "other"}, apiv1.PodPending, "1", "1Gi")
+}
+
+func (s *S) TestPoolNodes(c *check.C) {
+	s.setupNodesCluster()
+	list, err := s.p.PoolNodes(context.TODO(), "test-default")
+	require.NoError(s.t, err)
+	nodes := list.Nodes
+	require.Len(s.t, nodes, 2)
+	require.Equal(s.t, "n1", nodes[0].Name)
+	require.Equal(s.t, "c1", nodes[0].Cluster)
+	require.Equal(s.t, "test-default", nodes[0].Pool)
+	require.Equal(s.t, "192.168.0.1", nodes[0].Address)
+	require.True(s.t, nodes[0].Ready)
+	require.False(s.t, nodes[0].Unschedulable)
+	require.Equal(s.t, provTypes.PoolResources{MilliCPU: 4000, Memory: 8 * 1024 * 1024 * 1024}, nodes[0].Allocatable)
+	require.Len(s.t, nodes[0].Conditions, 2)
+	require.Equal(s.t, "Ready", nodes[0].Conditions[0].Type)
+	require.Equal(s.t, "True", nodes[0].Conditions[0].Status)
+	require.Equal(s.t, "KubeletReady", nodes[0].Conditions[0].Reason)
+	require.Len(s.t, nodes[0].Units, 2)
+	require.Equal(s.t, "myapp-web-1", nodes[0].Units[0].Name)
+	require.Equal(s.t, provTypes.UnitStatusCreated, nodes[0].Units[0].Status)
+	require.Equal(s.t, "myapp-web-2", nodes[0].Units[1].Name)
+	require.Equal(s.t, "myapp", nodes[0].Units[1].AppName)
+	require.Equal(s.t, "n2", nodes[1].Name)
+	require.False(s.t, nodes[1].Ready)
+	require.True(s.t, nodes[1].Unschedulable)
+	require.Len(s.t, nodes[1].Units, 0)
+	require.Len(s.t, list.Pending, 1)
+	require.Equal(s.t, "myapp-web-4", list.Pending[0].Name)
+	require.Equal(s.t, "myapp", list.Pending[0].AppName)
+}
+
+func (s *S) TestPoolNodesPendingOtherPool(c *check.C) {
+	s.setupNodesCluster()
+	list, err := s.p.PoolNodes(context.TODO(), "pool1")
+	require.NoError(s.t, err)
+	require.Len(s.t, list.Nodes, 1)
+	require.Len(s.t, list.Pending, 0)
+}
+
+func (s *S) TestPoolNodesSinglePool(c *check.C) {
+	s.clusterClient.CustomData[singlePoolKey] = "true"
+	s.setupNodesCluster()
+	list, err := s.p.PoolNodes(context.TODO(), "test-default")
+	require.NoError(s.t, err)
+	require.Len(s.t, list.Nodes, 3)
+}
+
+func (s *S) TestClusterNodes(c *check.C) {
+	s.setupNodesCluster()
+	list, err := s.p.ClusterNodes(context.TODO(), s.clusterClient.Cluster)
+	require.NoError(s.t, err)
+	nodes := list.Nodes
+	require.Len(s.t, nodes, 3)
+	require.Equal(s.t, "n3", nodes[2].Name)
+	require.Equal(s.t, "pool1", nodes[2].Pool)
+	require.Len(s.t, nodes[2].Units, 1)
+	require.Equal(s.t, "myapp-web-3", nodes[2].Units[0].Name)
+	require.Len(s.t, list.Pending, 1)
+	require.Equal(s.t, "myapp-web-4", list.Pending[0].Name)
+}
@@ -63,6 +63,8 @@ const (
 	defaultPodRunningTimeout                   = 10 * time.Minute
 	defaultDeploymentProgressTimeout           = 10 * time.Minute
 	defaultAttachTimeoutAfterContainerFinished = time.Minute
+	defaultVolumeResizeTimeout                 = 10 * time.Minute
+	credentialsValidationTimeout               = 30 * time.Second
 	defaultPreStopSleepSeconds                 = 10
 )
 
@@ -94,6 +96,7 @@ var (
 	_ provision.MetricsProvisioner       = &kubernetesProvisioner{}
 	_ provision.AutoScaleProvisioner     = &kubernetesProvisioner{}
 	_ cluster.ClusteredProvisioner       = &kubernetesProvisioner{}
+	_ cluster.CredentialsValidator       = &kubernetesProvisioner{}
 	_ provision.UpdatableProvisioner     = &kubernetesProvisioner{}
 	_ provision.MultiRegistryProvisioner = &kubernetesProvisioner{}
 	_ provision.KillUnitProvisioner      = &kubernetesProvisioner{}
@@ -131,6 +134,9 @@ type kubernetesConfig struct {
 	// AttachTimeoutAfterContainerFinished is the time tsuru will wait for an
 	// attach call to finish after the attached container has finished.
 	AttachTimeoutAfterContainerFinished time.Duration
+	// VolumeResizeTimeout is the timeout for the storage provider to expand a
+	// volume claim after its capacity is increased.
+	VolumeResizeTimeout time.Duration
 	// HeadlessServicePort is the port used in headless service, by default the
 	// same port number used for container is used.
 	HeadlessServicePort int
@@ -172,6 +178,12 @@ func getKubeConfig() kubernetesConfig {
 	} else {
 		conf.AttachTimeoutAfterContainerFinished = defaultAttachTimeoutAfterContainerFinished
 	}
+	volumeResizeTimeout, _ := config.GetFloat("kubernetes:volume-resize-timeout")
+	if volumeResizeTimeout != 0 {
+		conf.VolumeResizeTimeout = time.Duration(volumeResizeTimeout * float64(time.Second))
+	} else {
+		conf.VolumeResizeTimeout = defaultVolumeResizeTimeout
+	}
 	conf.HeadlessServicePort, _ = config.GetInt("kubernetes:headless-service-port")
 	if conf.HeadlessServicePort == 0 {
 		conf.HeadlessServicePort, _ = strconv.Atoi(provision.WebProcessDefaultPort())
@@ -262,6 +274,19 @@ func (p *kubernetesProvisioner) InitializeCluster(ctx context.Context, c *provTy
 	return err
 }
 
+func (p *kubernetesProvisioner) ValidateClusterCredentials(ctx context.Context, c *provTypes.Cluster) error {
+	clusterClient, err := NewClusterClient(c)
+	if err != nil {
+		return err
+	}
+	err = clusterClient.SetTimeout(credentialsValidationTimeout)
+	if err != nil {
+		return err
+	}
+	_, err = clusterClient.Discovery().ServerVersion()
+	return err
+}
+
 func (p *kubernetesProvisioner) ValidateCluster(c *provTypes.Cluster) error {
 	multiErrors := tsuruErrors.NewMultiError()
 
@@ -481,7 +506,7 @@ func (p *kubernetesProvisioner) removeResources(ctx context.Context, client *Clu
 	if err != nil && !k8sErrors.IsNotFound(err) {
 		multiErrors.Add(errors.WithStack(err))
 	}
-	err = p.deleteAllAutoScale(ctx, app)
+	err = deleteAllAutoScale(ctx, client, app)
 	if err != nil {
 		multiErrors.Add(err)
 	}
@@ -1522,7 +1547,7 @@ loop:
 func appCustomResourceDefinition() *extensionsv1.CustomResourceDefinition {
 	preserveUnknownFields := true
 	return &extensionsv1.CustomResourceDefinition{
-		ObjectMeta: metav1.ObjectMeta{Name: "apps.tsuru.io"},
+		ObjectMeta: metav1.ObjectMeta{Name: appCRDName},
 		Spec: extensionsv1.CustomResourceDefinitionSpec{
 			Group: "tsuru.io",
 			Scope: extensionsv1.NamespaceScoped,
@@ -1551,7 +1576,7 @@ func appCustomResourceDefinition() *extensionsv1.CustomResourceDefinition {
 
 func appCustomResourceDefinitionV1Beta() *v1beta1.CustomResourceDefinition {
 	return &v1beta1.CustomResourceDefinition{
-		ObjectMeta: metav1.ObjectMeta{Name: "apps.tsuru.io"},
+		ObjectMeta: metav1.ObjectMeta{Name: appCRDName},
 		Spec: v1beta1.CustomResourceDefinitionSpec{
 			Group:   "tsuru.io",
 			Version: "v1",
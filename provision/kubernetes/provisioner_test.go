@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -1781,6 +1782,7 @@ func (s *S) TestGetKubeConfig(c *check.C) {
 	config.Set("kubernetes:pod-running-timeout", 2*60)
 	config.Set("kubernetes:deployment-progress-timeout", 3*60)
 	config.Set("kubernetes:attach-after-finish-timeout", 5)
+	config.Set("kubernetes:volume-resize-timeout", 4*60)
 	config.Set("kubernetes:headless-service-port", 8889)
 	defer config.Unset("kubernetes")
 	kubeConf := getKubeConfig()
@@ -1790,6 +1792,7 @@ func (s *S) TestGetKubeConfig(c *check.C) {
 		PodRunningTimeout:                   2 * time.Minute,
 		DeploymentProgressTimeout:           3 * time.Minute,
 		AttachTimeoutAfterContainerFinished: 5 * time.Second,
+		VolumeResizeTimeout:                 4 * time.Minute,
 		HeadlessServicePort:                 8889,
 	}, kubeConf)
 }
@@ -1803,6 +1806,7 @@ func (s *S) TestGetKubeConfigDefaults(c *check.C) {
 		PodRunningTimeout:                   10 * time.Minute,
 		DeploymentProgressTimeout:           10 * time.Minute,
 		AttachTimeoutAfterContainerFinished: time.Minute,
+		VolumeResizeTimeout:                 10 * time.Minute,
 		HeadlessServicePort:                 8888,
 	}, kubeConf)
 }
@@ -2447,6 +2451,16 @@ func (s *S) TestProvisionerValidation(c *check.C) {
 	require.ErrorContains(s.t, err, "kubeConfig.cluster.server field is required")
 }
 
+func (s *S) TestProvisionerValidateClusterCredentials(c *check.C) {
+	err := s.p.ValidateClusterCredentials(context.TODO(), s.clusterClient.Cluster)
+	require.NoError(s.t, err)
+	s.client.PrependReactor("get", "version", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("unauthorized")
+	})
+	err = s.p.ValidateClusterCredentials(context.TODO(), s.clusterClient.Cluster)
+	require.ErrorContains(s.t, err, "unauthorized")
+}
+
 func (s *S) TestProvisionerInitializeNoClusters(c *check.C) {
 	s.mockService.Cluster.OnFindByProvisioner = func(provName string) ([]provTypes.Cluster, error) {
 		return nil, provTypes.ErrNoCluster
@@ -22,7 +22,7 @@ func (f *requirementsFactors) memoryLimits(memory int64) resource.Quantity {
 	return *resource.NewQuantity(memory, resource.BinarySI)
 }
 
-func (f *requirementsFactors) memoryRequests(memory int64) resource.Quantity {
+func (f *requirementsFactors) memoryOvercommit() float64 {
 	memoryOvercommit := f.overCommit
 	if f.memoryOverCommit != 0 {
 		memoryOvercommit = f.memoryOverCommit
@@ -30,7 +30,11 @@ func (f *requirementsFactors) memoryRequests(memory int64) resource.Quantity {
 	if memoryOvercommit < 1 {
 		memoryOvercommit = 1 // memory cannot be less than 1
 	}
-	return *resource.NewQuantity(overcommitedValue(memory, memoryOvercommit), resource.BinarySI)
+	return memoryOvercommit
+}
+
+func (f *requirementsFactors) memoryRequests(memory int64) resource.Quantity {
+	return *resource.NewQuantity(overcommitedValue(memory, f.memoryOvercommit()), resource.BinarySI)
 }
 
 func (f *requirementsFactors) cpuLimits(resourceCPUBurst float64, cpuMilli int64) resource.Quantity {
@@ -46,7 +50,7 @@ func (f *requirementsFactors) cpuLimits(resourceCPUBurst float64, cpuMilli int64
 	return *resource.NewMilliQuantity(burstValue(cpuMilli, cpuBurst), resource.DecimalSI)
 }
 
-func (f *requirementsFactors) cpuRequests(cpuMilli int64) resource.Quantity {
+func (f *requirementsFactors) cpuOvercommit() float64 {
 	cpuOvercommit := f.overCommit
 	if f.cpuOverCommit != 0 {
 		cpuOvercommit = f.cpuOverCommit
@@ -54,7 +58,11 @@ func (f *requirementsFactors) cpuRequests(cpuMilli int64) resource.Quantity {
 	if cpuOvercommit < 1 {
 		cpuOvercommit = 1 // cpu cannot be less than 1
 	}
-	return *resource.NewMilliQuantity(overcommitedValue(cpuMilli, cpuOvercommit), resource.DecimalSI)
+	return cpuOvercommit
+}
+
+func (f *requirementsFactors) cpuRequests(cpuMilli int64) resource.Quantity {
+	return *resource.NewMilliQuantity(overcommitedValue(cpuMilli, f.cpuOvercommit()), resource.DecimalSI)
 }
 
 func overcommitedValue(v int64, overcommit float64) int64 {
@@ -38,9 +38,13 @@ import (
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	fakeapiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	vpaclientset "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
 	fakevpa "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned/fake"
 	vpaInformers "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/informers/externalversions"
+	"k8s.io/client-go/dynamic"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/informers/internalinterfaces"
 	"k8s.io/client-go/kubernetes"
@@ -65,6 +69,7 @@ type S struct {
 	mockService                   servicemock.MockService
 	factory                       informers.SharedInformerFactory
 	vpaFactory                    vpaInformers.SharedInformerFactory
+	dynamicClient                 *fakedynamic.FakeDynamicClient
 	defaultSharedInformerDuration time.Duration
 }
 
@@ -132,6 +137,12 @@ func (s *S) SetUpTest(c *check.C) {
 	KEDAClientForConfig = func(conf *rest.Config) (kedav1alpha1clientset.Interface, error) {
 		return s.client.KEDAClientForConfig, nil
 	}
+	s.dynamicClient = fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		volumeSnapshotGVR: "VolumeSnapshotList",
+	})
+	DynamicClientForConfig = func(conf *rest.Config) (dynamic.Interface, error) {
+		return s.dynamicClient, nil
+	}
 	routertest.FakeRouter.Reset()
 	err = pool.AddPool(context.TODO(), pool.AddPoolOptions{
 		Name:        "test-default",
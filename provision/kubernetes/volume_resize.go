@@ -0,0 +1,145 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/provision"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ provision.VolumeResizeProvisioner = &kubernetesProvisioner{}
+
+func (p *kubernetesProvisioner) ResizeVolume(ctx context.Context, v *volumeTypes.Volume, capacity string, w io.Writer) error {
+	client, err := clusterForPool(ctx, v.Pool)
+	if err != nil {
+		return err
+	}
+	return resizeVolume(ctx, client, v, capacity, w)
+}
+
+func validateVolumeResize(v *volumeTypes.Volume, opts *volumeOptions, capacity string) (resource.Quantity, error) {
+	newCapacity, err := resource.ParseQuantity(capacity)
+	if err != nil {
+		return newCapacity, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid capacity %q: %v", capacity, err)}
+	}
+	if newCapacity.Cmp(opts.Capacity) < 0 {
+		return newCapacity, volumeTypes.ErrVolumeShrinkNotAllowed
+	}
+	if !opts.MaxCapacity.IsZero() && newCapacity.Cmp(opts.MaxCapacity) > 0 {
+		return newCapacity, &tsuruErrors.ValidationError{
+			Message: fmt.Sprintf("capacity %s exceeds the maximum of %s allowed by plan %q", newCapacity.String(), opts.MaxCapacity.String(), v.Plan.Name),
+		}
+	}
+	return newCapacity, nil
+}
+
+func resizeVolume(ctx context.Context, client *ClusterClient, v *volumeTypes.Volume, capacity string, w io.Writer) error {
+	if w == nil {
+		w = io.Discard
+	}
+	opts, err := validateVolume(v)
+	if err != nil {
+		return err
+	}
+	if !opts.isPersistent() || opts.Plugin != "" {
+		return volumeTypes.ErrResizeNotSupported
+	}
+	newCapacity, err := validateVolumeResize(v, opts, capacity)
+	if err != nil {
+		return err
+	}
+	storageClass, err := client.StorageV1().StorageClasses().Get(ctx, opts.StorageClass, metav1.GetOptions{})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return volumeTypes.ErrResizeNotSupported
+	}
+	pvcs, err := pvcForVolume(ctx, client, v.Name)
+	if err != nil {
+		return err
+	}
+	if len(pvcs) == 0 {
+		return volumeTypes.ErrVolumeNotProvisioned
+	}
+	kubeConf := getKubeConfig()
+	for i := range pvcs {
+		pvc := &pvcs[i]
+		current := pvc.Spec.Resources.Requests[apiv1.ResourceStorage]
+		if newCapacity.Cmp(current) < 0 {
+			return volumeTypes.ErrVolumeShrinkNotAllowed
+		}
+		if newCapacity.Cmp(current) == 0 {
+			fmt.Fprintf(w, " ---> Volume claim %s/%s already requests %s\n", pvc.Namespace, pvc.Name, newCapacity.String())
+		} else {
+			fmt.Fprintf(w, " ---> Resizing volume claim %s/%s from %s to %s\n", pvc.Namespace, pvc.Name, current.String(), newCapacity.String())
+			if pvc.Spec.Resources.Requests == nil {
+				pvc.Spec.Resources.Requests = apiv1.ResourceList{}
+			}
+			pvc.Spec.Resources.Requests[apiv1.ResourceStorage] = newCapacity
+			_, err = client.CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(ctx, pvc, metav1.UpdateOptions{})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		tctx, cancel := context.WithTimeout(ctx, kubeConf.VolumeResizeTimeout)
+		err = waitForVolumeClaimResize(tctx, client, pvc.Namespace, pvc.Name, newCapacity, w)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForVolumeClaimResize waits until the claim reports the requested
+// capacity. A claim waiting only for its file system to be expanded by the
+// node is considered resized, the expansion finishes as soon as the volume is
+// mounted.
+func waitForVolumeClaimResize(ctx context.Context, client *ClusterClient, namespace, name string, capacity resource.Quantity, w io.Writer) error {
+	reported := map[apiv1.PersistentVolumeClaimConditionType]bool{}
+	var lastConditions []string
+	return waitFor(ctx, func() (bool, error) {
+		pvc, err := client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return true, errors.WithStack(err)
+		}
+		if current, ok := pvc.Status.Capacity[apiv1.ResourceStorage]; ok && current.Cmp(capacity) >= 0 {
+			fmt.Fprintf(w, " ---> Volume claim %s/%s resized to %s\n", namespace, name, current.String())
+			return true, nil
+		}
+		lastConditions = lastConditions[:0]
+		for _, cond := range pvc.Status.Conditions {
+			if cond.Status != apiv1.ConditionTrue {
+				continue
+			}
+			lastConditions = append(lastConditions, fmt.Sprintf("%s: %s", cond.Type, cond.Message))
+			if !reported[cond.Type] {
+				reported[cond.Type] = true
+				fmt.Fprintf(w, "  ---> %s %s\n", cond.Type, cond.Message)
+			}
+			if cond.Type == apiv1.PersistentVolumeClaimFileSystemResizePending {
+				fmt.Fprintf(w, " ---> Volume claim %s/%s resized, file system will be expanded by the node\n", namespace, name)
+				return true, nil
+			}
+		}
+		return false, nil
+	}, func() error {
+		if len(lastConditions) == 0 {
+			return errors.Errorf("volume claim %s/%s not resized", namespace, name)
+		}
+		return errors.Errorf("volume claim %s/%s not resized: %s", namespace, name, strings.Join(lastConditions, ", "))
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/errors"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func (s *S) createResizableVolume(allowExpansion bool) (*volumeTypes.Volume, string) {
+	config.Set("volume-plans:p1:kubernetes:storage-class", "my-class")
+	config.Set("volume-plans:p1:kubernetes:capacity", "20Gi")
+	config.Set("volume-plans:p1:kubernetes:max-capacity", "100Gi")
+	config.Set("volume-plans:p1:kubernetes:access-modes", "ReadWriteOnce")
+	_, err := s.client.StorageV1().StorageClasses().Create(context.TODO(), &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "my-class"},
+		AllowVolumeExpansion: &allowExpansion,
+	}, metav1.CreateOptions{})
+	require.NoError(s.t, err)
+	return s.createSnapshotVolume("v1")
+}
+
+func (s *S) TestResizeVolume(_ *check.C) {
+	defer config.Unset("volume-plans")
+	v, ns := s.createResizableVolume(true)
+	s.client.PrependReactor("update", "persistentvolumeclaims", func(action ktesting.Action) (bool, runtime.Object, error) {
+		pvc := action.(ktesting.UpdateAction).GetObject().(*apiv1.PersistentVolumeClaim)
+		pvc.Status.Capacity = apiv1.ResourceList{
+			apiv1.ResourceStorage: pvc.Spec.Resources.Requests[apiv1.ResourceStorage],
+		}
+		return false, nil, nil
+	})
+	var buf strings.Builder
+	err := s.p.ResizeVolume(context.TODO(), v, "30Gi", &buf)
+	require.NoError(s.t, err)
+	pvc, err := s.client.CoreV1().PersistentVolumeClaims(ns).Get(context.TODO(), volumeClaimName(v.Name), metav1.GetOptions{})
+	require.NoError(s.t, err)
+	expected := resource.MustParse("30Gi")
+	requested := pvc.Spec.Resources.Requests[apiv1.ResourceStorage]
+	require.Equal(s.t, 0, requested.Cmp(expected))
+	require.Contains(s.t, buf.String(), "Resizing volume claim "+ns+"/v1-tsuru-claim from 20Gi to 30Gi")
+	require.Contains(s.t, buf.String(), "Volume claim "+ns+"/v1-tsuru-claim resized to 30Gi")
+}
+
+func (s *S) TestResizeVolumeFileSystemResizePending(_ *check.C) {
+	defer config.Unset("volume-plans")
+	v, _ := s.createResizableVolume(true)
+	s.client.PrependReactor("update", "persistentvolumeclaims", func(action ktesting.Action) (bool, runtime.Object, error) {
+		pvc := action.(ktesting.UpdateAction).GetObject().(*apiv1.PersistentVolumeClaim)
+		pvc.Status.Conditions = []apiv1.PersistentVolumeClaimCondition{{
+			Type:    apiv1.PersistentVolumeClaimFileSystemResizePending,
+			Status:  apiv1.ConditionTrue,
+			Message: "Waiting for user to (re-)start a pod to finish file system resize of volume on node.",
+		}}
+		return false, nil, nil
+	})
+	var buf strings.Builder
+	err := s.p.ResizeVolume(context.TODO(), v, "30Gi", &buf)
+	require.NoError(s.t, err)
+	require.Contains(s.t, buf.String(), "FileSystemResizePending")
+	require.Contains(s.t, buf.String(), "file system will be expanded by the node")
+}
+
+func (s *S) TestResizeVolumeTimeout(_ *check.C) {
+	defer config.Unset("volume-plans")
+	config.Set("kubernetes:volume-resize-timeout", 1)
+	defer config.Unset("kubernetes:volume-resize-timeout")
+	v, _ := s.createResizableVolume(true)
+	start := time.Now()
+	err := s.p.ResizeVolume(context.TODO(), v, "30Gi", nil)
+	require.ErrorContains(s.t, err, "v1-tsuru-claim not resized")
+	require.Less(s.t, time.Since(start), 10*time.Second)
+}
+
+func (s *S) TestResizeVolumeShrink(_ *check.C) {
+	defer config.Unset("volume-plans")
+	v, _ := s.createResizableVolume(true)
+	err := s.p.ResizeVolume(context.TODO(), v, "10Gi", nil)
+	require.ErrorIs(s.t, err, volumeTypes.ErrVolumeShrinkNotAllowed)
+}
+
+func (s *S) TestResizeVolumeAbovePlanMaximum(_ *check.C) {
+	defer config.Unset("volume-plans")
+	v, _ := s.createResizableVolume(true)
+	err := s.p.ResizeVolume(context.TODO(), v, "200Gi", nil)
+	require.ErrorContains(s.t, err, `capacity 200Gi exceeds the maximum of 100Gi allowed by plan "p1"`)
+	var validationErr *errors.ValidationError
+	require.ErrorAs(s.t, err, &validationErr)
+}
+
+func (s *S) TestResizeVolumeExpansionNotAllowed(_ *check.C) {
+	defer config.Unset("volume-plans")
+	v, _ := s.createResizableVolume(false)
+	err := s.p.ResizeVolume(context.TODO(), v, "30Gi", nil)
+	require.ErrorIs(s.t, err, volumeTypes.ErrResizeNotSupported)
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/provision"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var volumeSnapshotGVR = schema.GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+var _ provision.VolumeSnapshotProvisioner = &kubernetesProvisioner{}
+
+func (p *kubernetesProvisioner) CreateVolumeSnapshot(ctx context.Context, v *volumeTypes.Volume, name string) (*volumeTypes.VolumeSnapshot, error) {
+	client, err := clusterForPool(ctx, v.Pool)
+	if err != nil {
+		return nil, err
+	}
+	return createVolumeSnapshot(ctx, client, v, name)
+}
+
+func (p *kubernetesProvisioner) ListVolumeSnapshots(ctx context.Context, v *volumeTypes.Volume) ([]volumeTypes.VolumeSnapshot, error) {
+	client, err := clusterForPool(ctx, v.Pool)
+	if err != nil {
+		return nil, err
+	}
+	return listVolumeSnapshots(ctx, client, v)
+}
+
+func (p *kubernetesProvisioner) GetVolumeSnapshot(ctx context.Context, v *volumeTypes.Volume, name string) (*volumeTypes.VolumeSnapshot, error) {
+	client, err := clusterForPool(ctx, v.Pool)
+	if err != nil {
+		return nil, err
+	}
+	return getVolumeSnapshot(ctx, client, v, name)
+}
+
+func (p *kubernetesProvisioner) DeleteVolumeSnapshot(ctx context.Context, v *volumeTypes.Volume, name string) error {
+	client, err := clusterForPool(ctx, v.Pool)
+	if err != nil {
+		return err
+	}
+	return deleteVolumeSnapshot(ctx, client, v, name)
+}
+
+// snapshotClientForVolume returns a client for the VolumeSnapshot resources
+// in the namespace where the claim of the volume lives. Snapshots can only
+// reference claims in their own namespace.
+func snapshotClientForVolume(ctx context.Context, client *ClusterClient, v *volumeTypes.Volume) (dynamic.ResourceInterface, *volumeOptions, error) {
+	opts, err := validateVolume(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !opts.isPersistent() || opts.SnapshotClass == "" {
+		return nil, nil, volumeTypes.ErrSnapshotNotSupported
+	}
+	pvcs, err := pvcForVolume(ctx, client, v.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(pvcs) == 0 {
+		return nil, nil, volumeTypes.ErrVolumeNotProvisioned
+	}
+	cli, err := DynamicClientForConfig(client.RestConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+	return cli.Resource(volumeSnapshotGVR).Namespace(pvcs[0].Namespace), opts, nil
+}
+
+func volumeSnapshotLabels(v *volumeTypes.Volume, name string) *provision.LabelSet {
+	labelSet := provision.VolumeLabels(provision.VolumeLabelsOpts{
+		Name:   v.Name,
+		Prefix: tsuruLabelPrefix,
+		Pool:   v.Pool,
+		Plan:   v.Plan.Name,
+		Team:   v.TeamOwner,
+	})
+	labelSet.SetVolumeSnapshot(name)
+	return labelSet
+}
+
+func createVolumeSnapshot(ctx context.Context, client *ClusterClient, v *volumeTypes.Volume, name string) (*volumeTypes.VolumeSnapshot, error) {
+	cli, opts, err := snapshotClientForVolume(ctx, client, v)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": volumeSnapshotGVR.GroupVersion().String(),
+		"kind":       "VolumeSnapshot",
+		"spec": map[string]interface{}{
+			"volumeSnapshotClassName": opts.SnapshotClass,
+			"source": map[string]interface{}{
+				"persistentVolumeClaimName": volumeClaimName(v.Name),
+			},
+		},
+	}}
+	snapshot.SetName(volumeSnapshotName(v.Name, name))
+	snapshot.SetLabels(volumeSnapshotLabels(v, name).ToLabels())
+	snapshot, err = cli.Create(ctx, snapshot, metav1.CreateOptions{})
+	if err != nil {
+		if k8sErrors.IsAlreadyExists(err) {
+			return nil, errors.Errorf("snapshot %q already exists for volume %q", name, v.Name)
+		}
+		return nil, errors.WithStack(err)
+	}
+	return volumeSnapshotFromUnstructured(v, snapshot), nil
+}
+
+func listVolumeSnapshots(ctx context.Context, client *ClusterClient, v *volumeTypes.Volume) ([]volumeTypes.VolumeSnapshot, error) {
+	cli, _, err := snapshotClientForVolume(ctx, client, v)
+	if err != nil {
+		return nil, err
+	}
+	labelSet := provision.VolumeLabels(provision.VolumeLabelsOpts{
+		Name:   v.Name,
+		Prefix: tsuruLabelPrefix,
+	})
+	list, err := cli.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set(labelSet.ToVolumeSelector())).String(),
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	snapshots := make([]volumeTypes.VolumeSnapshot, 0, len(list.Items))
+	for i := range list.Items {
+		snapshots = append(snapshots, *volumeSnapshotFromUnstructured(v, &list.Items[i]))
+	}
+	return snapshots, nil
+}
+
+func getVolumeSnapshot(ctx context.Context, client *ClusterClient, v *volumeTypes.Volume, name string) (*volumeTypes.VolumeSnapshot, error) {
+	cli, _, err := snapshotClientForVolume(ctx, client, v)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := cli.Get(ctx, volumeSnapshotName(v.Name, name), metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, volumeTypes.ErrVolumeSnapshotNotFound
+		}
+		return nil, errors.WithStack(err)
+	}
+	return volumeSnapshotFromUnstructured(v, snapshot), nil
+}
+
+func deleteVolumeSnapshot(ctx context.Context, client *ClusterClient, v *volumeTypes.Volume, name string) error {
+	cli, _, err := snapshotClientForVolume(ctx, client, v)
+	if err != nil {
+		return err
+	}
+	err = cli.Delete(ctx, volumeSnapshotName(v.Name, name), metav1.DeleteOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return volumeTypes.ErrVolumeSnapshotNotFound
+		}
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func volumeSnapshotFromUnstructured(v *volumeTypes.Volume, obj *unstructured.Unstructured) *volumeTypes.VolumeSnapshot {
+	labelSet := labelOnlySetFromMeta(&metav1.ObjectMeta{Labels: obj.GetLabels()})
+	snapshot := &volumeTypes.VolumeSnapshot{
+		Name:   labelSet.VolumeSnapshot(),
+		Volume: v.Name,
+	}
+	snapshot.Class, _, _ = unstructured.NestedString(obj.Object, "spec", "volumeSnapshotClassName")
+	snapshot.ReadyToUse, _, _ = unstructured.NestedBool(obj.Object, "status", "readyToUse")
+	snapshot.RestoreSize, _, _ = unstructured.NestedString(obj.Object, "status", "restoreSize")
+	snapshot.Error, _, _ = unstructured.NestedString(obj.Object, "status", "error", "message")
+	creationTime, _, _ := unstructured.NestedString(obj.Object, "status", "creationTime")
+	if creationTime != "" {
+		var t metav1.Time
+		if err := t.UnmarshalQueryParameter(creationTime); err == nil {
+			snapshot.CreationTime = &t.Time
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,169 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/servicemanager"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+	check "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func (s *S) createSnapshotVolume(name string) (*volumeTypes.Volume, string) {
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	err := s.p.Provision(context.TODO(), a)
+	require.NoError(s.t, err)
+	v := volumeTypes.Volume{
+		Name:      name,
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "test-default",
+		TeamOwner: "admin",
+	}
+	err = servicemanager.Volume.Create(context.TODO(), &v)
+	require.NoError(s.t, err)
+	err = servicemanager.Volume.BindApp(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &v,
+		AppName:    a.Name,
+		MountPoint: "/mnt",
+	})
+	require.NoError(s.t, err)
+	_, _, err = createVolumesForApp(context.TODO(), s.clusterClient, a)
+	require.NoError(s.t, err)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	require.NoError(s.t, err)
+	return &v, ns
+}
+
+func setSnapshotVolumePlan() {
+	config.Set("volume-plans:p1:kubernetes:storage-class", "my-class")
+	config.Set("volume-plans:p1:kubernetes:snapshot-class", "my-snap-class")
+	config.Set("volume-plans:p1:kubernetes:capacity", "20Gi")
+	config.Set("volume-plans:p1:kubernetes:access-modes", "ReadWriteOnce")
+}
+
+func (s *S) TestCreateVolumeSnapshot(_ *check.C) {
+	setSnapshotVolumePlan()
+	defer config.Unset("volume-plans")
+	v, ns := s.createSnapshotVolume("v1")
+	snap, err := s.p.CreateVolumeSnapshot(context.TODO(), v, "daily")
+	require.NoError(s.t, err)
+	require.Equal(s.t, &volumeTypes.VolumeSnapshot{Name: "daily", Volume: "v1", Class: "my-snap-class"}, snap)
+	obj, err := s.dynamicClient.Resource(volumeSnapshotGVR).Namespace(ns).Get(context.TODO(), "v1-tsuru-snap-daily", metav1.GetOptions{})
+	require.NoError(s.t, err)
+	require.Equal(s.t, map[string]string{
+		"tsuru.io/is-tsuru":        "true",
+		"tsuru.io/volume-name":     "v1",
+		"tsuru.io/volume-pool":     "test-default",
+		"tsuru.io/volume-plan":     "p1",
+		"tsuru.io/volume-team":     "admin",
+		"tsuru.io/volume-snapshot": "daily",
+	}, obj.GetLabels())
+	claim, _, _ := unstructured.NestedString(obj.Object, "spec", "source", "persistentVolumeClaimName")
+	require.Equal(s.t, "v1-tsuru-claim", claim)
+	_, err = s.p.CreateVolumeSnapshot(context.TODO(), v, "daily")
+	require.ErrorContains(s.t, err, `snapshot "daily" already exists for volume "v1"`)
+}
+
+func (s *S) TestCreateVolumeSnapshotNotSupported(_ *check.C) {
+	config.Set("volume-plans:p1:kubernetes:storage-class", "my-class")
+	config.Set("volume-plans:p1:kubernetes:capacity", "20Gi")
+	config.Set("volume-plans:p1:kubernetes:access-modes", "ReadWriteOnce")
+	defer config.Unset("volume-plans")
+	v, _ := s.createSnapshotVolume("v1")
+	_, err := s.p.CreateVolumeSnapshot(context.TODO(), v, "daily")
+	require.ErrorIs(s.t, err, volumeTypes.ErrSnapshotNotSupported)
+}
+
+func (s *S) TestCreateVolumeSnapshotNotProvisioned(_ *check.C) {
+	setSnapshotVolumePlan()
+	defer config.Unset("volume-plans")
+	v := volumeTypes.Volume{
+		Name:      "v1",
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "test-default",
+		TeamOwner: "admin",
+	}
+	err := servicemanager.Volume.Create(context.TODO(), &v)
+	require.NoError(s.t, err)
+	_, err = s.p.CreateVolumeSnapshot(context.TODO(), &v, "daily")
+	require.ErrorIs(s.t, err, volumeTypes.ErrVolumeNotProvisioned)
+}
+
+func (s *S) TestListAndGetVolumeSnapshots(_ *check.C) {
+	setSnapshotVolumePlan()
+	defer config.Unset("volume-plans")
+	v, ns := s.createSnapshotVolume("v1")
+	_, err := s.p.CreateVolumeSnapshot(context.TODO(), v, "daily")
+	require.NoError(s.t, err)
+	cli := s.dynamicClient.Resource(volumeSnapshotGVR).Namespace(ns)
+	obj, err := cli.Get(context.TODO(), "v1-tsuru-snap-daily", metav1.GetOptions{})
+	require.NoError(s.t, err)
+	err = unstructured.SetNestedMap(obj.Object, map[string]interface{}{
+		"readyToUse":   true,
+		"restoreSize":  "20Gi",
+		"creationTime": "2026-10-18T10:00:00Z",
+	}, "status")
+	require.NoError(s.t, err)
+	_, err = cli.Update(context.TODO(), obj, metav1.UpdateOptions{})
+	require.NoError(s.t, err)
+	snaps, err := s.p.ListVolumeSnapshots(context.TODO(), v)
+	require.NoError(s.t, err)
+	require.Len(s.t, snaps, 1)
+	require.Equal(s.t, "daily", snaps[0].Name)
+	require.True(s.t, snaps[0].ReadyToUse)
+	require.Equal(s.t, "20Gi", snaps[0].RestoreSize)
+	require.NotNil(s.t, snaps[0].CreationTime)
+	require.Equal(s.t, 2026, snaps[0].CreationTime.Year())
+	snap, err := s.p.GetVolumeSnapshot(context.TODO(), v, "daily")
+	require.NoError(s.t, err)
+	require.Equal(s.t, snaps[0], *snap)
+	_, err = s.p.GetVolumeSnapshot(context.TODO(), v, "weekly")
+	require.ErrorIs(s.t, err, volumeTypes.ErrVolumeSnapshotNotFound)
+}
+
+func (s *S) TestDeleteVolumeSnapshot(_ *check.C) {
+	setSnapshotVolumePlan()
+	defer config.Unset("volume-plans")
+	v, _ := s.createSnapshotVolume("v1")
+	_, err := s.p.CreateVolumeSnapshot(context.TODO(), v, "daily")
+	require.NoError(s.t, err)
+	err = s.p.DeleteVolumeSnapshot(context.TODO(), v, "daily")
+	require.NoError(s.t, err)
+	snaps, err := s.p.ListVolumeSnapshots(context.TODO(), v)
+	require.NoError(s.t, err)
+	require.Len(s.t, snaps, 0)
+	err = s.p.DeleteVolumeSnapshot(context.TODO(), v, "daily")
+	require.ErrorIs(s.t, err, volumeTypes.ErrVolumeSnapshotNotFound)
+}
+
+func (s *S) TestCreateVolumeFromSnapshot(_ *check.C) {
+	setSnapshotVolumePlan()
+	defer config.Unset("volume-plans")
+	v := volumeTypes.Volume{
+		Name:           "v2",
+		Plan:           volumeTypes.VolumePlan{Name: "p1"},
+		Pool:           "test-default",
+		TeamOwner:      "admin",
+		SnapshotSource: &volumeTypes.VolumeSnapshotSource{Volume: "v1", Snapshot: "daily"},
+	}
+	err := servicemanager.Volume.Create(context.TODO(), &v)
+	require.NoError(s.t, err)
+	opts, err := validateVolume(&v)
+	require.NoError(s.t, err)
+	err = createVolume(context.TODO(), s.clusterClient, &v, opts)
+	require.NoError(s.t, err)
+	pvc, err := s.client.CoreV1().PersistentVolumeClaims(s.clusterClient.PoolNamespace(v.Pool)).Get(context.TODO(), volumeClaimName(v.Name), metav1.GetOptions{})
+	require.NoError(s.t, err)
+	require.NotNil(s.t, pvc.Spec.DataSource)
+	require.Equal(s.t, "snapshot.storage.k8s.io", *pvc.Spec.DataSource.APIGroup)
+	require.Equal(s.t, "VolumeSnapshot", pvc.Spec.DataSource.Kind)
+	require.Equal(s.t, "v1-tsuru-snap-daily", pvc.Spec.DataSource.Name)
+}
@@ -13,6 +13,7 @@ import (
 	tsuruv1 "github.com/tsuru/tsuru/provision/kubernetes/pkg/apis/tsuru/v1"
 	"github.com/tsuru/tsuru/provision/provisiontest"
 	"github.com/tsuru/tsuru/servicemanager"
+	jobTypes "github.com/tsuru/tsuru/types/job"
 	volumeTypes "github.com/tsuru/tsuru/types/volume"
 	check "gopkg.in/check.v1"
 	apiv1 "k8s.io/api/core/v1"
@@ -580,3 +581,53 @@ func (s *S) TestVolumeExists(_ *check.C) {
 	require.NoError(s.t, err)
 	require.True(s.t, exists)
 }
+
+func (s *S) TestCreateVolumesForJob(_ *check.C) {
+	config.Set("volume-plans:p1:kubernetes:storage-class", "my-class")
+	config.Set("volume-plans:p1:kubernetes:capacity", "20Gi")
+	config.Set("volume-plans:p1:kubernetes:access-modes", "ReadWriteOnce")
+	defer config.Unset("volume-plans")
+	job := &jobTypes.Job{Name: "myjob", Pool: "test-default", TeamOwner: "admin"}
+	s.mockService.JobService.OnGetByName = func(name string) (*jobTypes.Job, error) {
+		require.Equal(s.t, job.Name, name)
+		return job, nil
+	}
+	v := volumeTypes.Volume{
+		Name:      "v1",
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "test-default",
+		TeamOwner: "admin",
+	}
+	err := servicemanager.Volume.Create(context.TODO(), &v)
+	require.NoError(s.t, err)
+	err = servicemanager.Volume.BindJob(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &v,
+		JobName:    job.Name,
+		MountPoint: "/data",
+		ReadOnly:   true,
+	})
+	require.NoError(s.t, err)
+	volumes, mounts, err := createVolumesForJob(context.TODO(), s.clusterClient, job)
+	require.NoError(s.t, err)
+	require.Equal(s.t, []apiv1.Volume{{
+		Name: volumeName(v.Name),
+		VolumeSource: apiv1.VolumeSource{
+			PersistentVolumeClaim: &apiv1.PersistentVolumeClaimVolumeSource{
+				ClaimName: volumeClaimName(v.Name),
+				ReadOnly:  true,
+			},
+		},
+	}}, volumes)
+	require.Equal(s.t, []apiv1.VolumeMount{{
+		Name:      volumeName(v.Name),
+		MountPath: "/data",
+		ReadOnly:  true,
+	}}, mounts)
+	ns := s.clusterClient.PoolNamespace(job.Pool)
+	_, err = s.client.CoreV1().PersistentVolumeClaims(ns).Get(context.TODO(), volumeClaimName(v.Name), metav1.GetOptions{})
+	require.NoError(s.t, err)
+	spec, err := buildJobSpec(context.TODO(), job, s.clusterClient, nil, nil)
+	require.NoError(s.t, err)
+	require.Equal(s.t, volumes, spec.Template.Spec.Volumes)
+	require.Equal(s.t, mounts, spec.Template.Spec.Containers[0].VolumeMounts)
+}
@@ -0,0 +1,212 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/provision"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var _ provision.VolumeUsageProvisioner = &kubernetesProvisioner{}
+
+// kubeletStatsSummary holds the subset of the kubelet summary API
+// (/stats/summary) used to report volume usage.
+type kubeletStatsSummary struct {
+	Pods []struct {
+		Volume []struct {
+			CapacityBytes  *int64 `json:"capacityBytes"`
+			UsedBytes      *int64 `json:"usedBytes"`
+			AvailableBytes *int64 `json:"availableBytes"`
+			Inodes         *int64 `json:"inodes"`
+			InodesUsed     *int64 `json:"inodesUsed"`
+			InodesFree     *int64 `json:"inodesFree"`
+			PVCRef         *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+var nodeStatsSummary = func(ctx context.Context, client *ClusterClient, nodeName string) (*kubeletStatsSummary, error) {
+	data, err := client.CoreV1().RESTClient().Get().
+		Resource("nodes").
+		Name(nodeName).
+		SubResource("proxy").
+		Suffix("stats", "summary").
+		DoRaw(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var summary kubeletStatsSummary
+	err = json.Unmarshal(data, &summary)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse stats summary from node %q", nodeName)
+	}
+	return &summary, nil
+}
+
+func (p *kubernetesProvisioner) VolumeUsage(ctx context.Context, v *volumeTypes.Volume) (*volumeTypes.VolumeUsage, error) {
+	client, err := clusterForPool(ctx, v.Pool)
+	if err != nil {
+		return nil, err
+	}
+	usages, err := p.volumesUsage(ctx, client, []volumeTypes.Volume{*v})
+	if usages[v.Name] == nil && err != nil {
+		return nil, err
+	}
+	return usages[v.Name], nil
+}
+
+// VolumesUsage reports the usage of the volumes in every cluster. Clusters
+// and nodes that could not be reached are skipped: the usage found in the
+// others is returned along with the errors.
+func (p *kubernetesProvisioner) VolumesUsage(ctx context.Context, volumes []volumeTypes.Volume) (map[string]*volumeTypes.VolumeUsage, error) {
+	multiErr := tsuruErrors.NewMultiError()
+	volumesByCluster := map[string][]volumeTypes.Volume{}
+	clients := map[string]*ClusterClient{}
+	clusterByPool := map[string]string{}
+	for _, v := range volumes {
+		clusterName, ok := clusterByPool[v.Pool]
+		if !ok {
+			client, err := clusterForPool(ctx, v.Pool)
+			if err != nil {
+				multiErr.Add(errors.Wrapf(err, "unable to get cluster for pool %q", v.Pool))
+			} else {
+				clusterName = client.Name
+				clients[clusterName] = client
+			}
+			clusterByPool[v.Pool] = clusterName
+		}
+		if clusterName == "" {
+			continue
+		}
+		volumesByCluster[clusterName] = append(volumesByCluster[clusterName], v)
+	}
+	result := map[string]*volumeTypes.VolumeUsage{}
+	for clusterName, clusterVolumes := range volumesByCluster {
+		usages, err := p.volumesUsage(ctx, clients[clusterName], clusterVolumes)
+		if err != nil {
+			multiErr.Add(errors.Wrapf(err, "unable to get volumes usage in cluster %q", clusterName))
+		}
+		for name, usage := range usages {
+			result[name] = usage
+		}
+	}
+	return result, multiErr.ToError()
+}
+
+// volumesUsage reads the volume stats reported by the kubelets of the nodes
+// running pods that mount the volume claims. Running pods are read from the
+// cluster pod informer and the summary of each node is fetched only once.
+// Volumes not mounted by any running pod have no usage information. Nodes
+// whose summary could not be fetched are skipped and reported in the
+// returned error.
+func (p *kubernetesProvisioner) volumesUsage(ctx context.Context, client *ClusterClient, volumes []volumeTypes.Volume) (map[string]*volumeTypes.VolumeUsage, error) {
+	claims := make(map[string]string, len(volumes))
+	for _, v := range volumes {
+		claims[volumeClaimName(v.Name)] = v.Name
+	}
+	nodes, err := p.nodesMountingClaims(client, claims)
+	if err != nil {
+		return nil, err
+	}
+	multiErr := tsuruErrors.NewMultiError()
+	result := map[string]*volumeTypes.VolumeUsage{}
+	for _, node := range nodes {
+		if len(result) == len(claims) {
+			break
+		}
+		summary, err := nodeStatsSummary(ctx, client, node)
+		if err != nil {
+			multiErr.Add(errors.Wrapf(err, "unable to get stats summary from node %q", node))
+			continue
+		}
+		for claimName, usage := range usagesFromSummary(summary, claims) {
+			if _, ok := result[claims[claimName]]; !ok {
+				result[claims[claimName]] = usage
+			}
+		}
+	}
+	return result, multiErr.ToError()
+}
+
+// nodesMountingClaims returns the nodes running pods that mount any of the
+// given claims.
+func (p *kubernetesProvisioner) nodesMountingClaims(client *ClusterClient, claims map[string]string) ([]string, error) {
+	controller, err := getClusterController(p, client)
+	if err != nil {
+		return nil, err
+	}
+	podInformer, err := controller.getPodInformer()
+	if err != nil {
+		return nil, err
+	}
+	pods, err := podInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var nodes []string
+	seen := map[string]struct{}{}
+	for _, pod := range pods {
+		if pod.Status.Phase != apiv1.PodRunning || pod.Spec.NodeName == "" {
+			continue
+		}
+		if _, ok := seen[pod.Spec.NodeName]; ok {
+			continue
+		}
+		for _, vol := range pod.Spec.Volumes {
+			if vol.PersistentVolumeClaim == nil {
+				continue
+			}
+			if _, ok := claims[vol.PersistentVolumeClaim.ClaimName]; ok {
+				seen[pod.Spec.NodeName] = struct{}{}
+				nodes = append(nodes, pod.Spec.NodeName)
+				break
+			}
+		}
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+// usagesFromSummary returns the usage of the given claims found in the node
+// summary, keyed by claim name.
+func usagesFromSummary(summary *kubeletStatsSummary, claims map[string]string) map[string]*volumeTypes.VolumeUsage {
+	valueOf := func(v *int64) int64 {
+		if v == nil {
+			return 0
+		}
+		return *v
+	}
+	result := map[string]*volumeTypes.VolumeUsage{}
+	for _, pod := range summary.Pods {
+		for _, vol := range pod.Volume {
+			if vol.PVCRef == nil {
+				continue
+			}
+			if _, ok := claims[vol.PVCRef.Name]; !ok {
+				continue
+			}
+			result[vol.PVCRef.Name] = &volumeTypes.VolumeUsage{
+				CapacityBytes:  valueOf(vol.CapacityBytes),
+				UsedBytes:      valueOf(vol.UsedBytes),
+				AvailableBytes: valueOf(vol.AvailableBytes),
+				Inodes:         valueOf(vol.Inodes),
+				InodesUsed:     valueOf(vol.InodesUsed),
+				InodesFree:     valueOf(vol.InodesFree),
+			}
+		}
+	}
+	return result
+}
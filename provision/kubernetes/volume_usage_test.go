@@ -0,0 +1,186 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/servicemanager"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (s *S) TestVolumeUsage(_ *check.C) {
+	config.Set("volume-plans:p1:kubernetes:storage-class", "my-class")
+	config.Set("volume-plans:p1:kubernetes:capacity", "20Gi")
+	config.Set("volume-plans:p1:kubernetes:access-modes", "ReadWriteOnce")
+	defer config.Unset("volume-plans")
+	v, ns := s.createSnapshotVolume("v1")
+	_, err := s.client.CoreV1().Pods(ns).Create(context.TODO(), &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp-web-1", Namespace: ns},
+		Spec: apiv1.PodSpec{
+			NodeName: "node1",
+			Volumes: []apiv1.Volume{{
+				Name: volumeName(v.Name),
+				VolumeSource: apiv1.VolumeSource{
+					PersistentVolumeClaim: &apiv1.PersistentVolumeClaimVolumeSource{ClaimName: volumeClaimName(v.Name)},
+				},
+			}},
+		},
+		Status: apiv1.PodStatus{Phase: apiv1.PodRunning},
+	}, metav1.CreateOptions{})
+	require.NoError(s.t, err)
+	var requestedNodes []string
+	oldStats := nodeStatsSummary
+	defer func() { nodeStatsSummary = oldStats }()
+	nodeStatsSummary = func(ctx context.Context, client *ClusterClient, nodeName string) (*kubeletStatsSummary, error) {
+		requestedNodes = append(requestedNodes, nodeName)
+		var summary kubeletStatsSummary
+		err := json.Unmarshal([]byte(`{"pods": [{"volume": [
+			{"name": "kube-api-access", "usedBytes": 1},
+			{"name": "v1-tsuru", "capacityBytes": 100, "usedBytes": 40, "availableBytes": 60,
+			 "inodes": 10, "inodesUsed": 4, "inodesFree": 6,
+			 "pvcRef": {"name": "v1-tsuru-claim", "namespace": "`+ns+`"}}
+		]}]}`), &summary)
+		return &summary, err
+	}
+	usage, err := s.p.VolumeUsage(context.TODO(), v)
+	require.NoError(s.t, err)
+	require.Equal(s.t, []string{"node1"}, requestedNodes)
+	require.Equal(s.t, &volumeTypes.VolumeUsage{
+		CapacityBytes:  100,
+		UsedBytes:      40,
+		AvailableBytes: 60,
+		Inodes:         10,
+		InodesUsed:     4,
+		InodesFree:     6,
+	}, usage)
+}
+
+func (s *S) TestVolumeUsageNotMounted(_ *check.C) {
+	config.Set("volume-plans:p1:kubernetes:storage-class", "my-class")
+	config.Set("volume-plans:p1:kubernetes:capacity", "20Gi")
+	config.Set("volume-plans:p1:kubernetes:access-modes", "ReadWriteOnce")
+	defer config.Unset("volume-plans")
+	v, _ := s.createSnapshotVolume("v1")
+	oldStats := nodeStatsSummary
+	defer func() { nodeStatsSummary = oldStats }()
+	nodeStatsSummary = func(ctx context.Context, client *ClusterClient, nodeName string) (*kubeletStatsSummary, error) {
+		require.Fail(s.t, "unexpected stats request")
+		return nil, nil
+	}
+	usage, err := s.p.VolumeUsage(context.TODO(), v)
+	require.NoError(s.t, err)
+	require.Nil(s.t, usage)
+}
+
+func (s *S) TestVolumesUsageFetchesEachNodeOnce(_ *check.C) {
+	config.Set("volume-plans:p1:kubernetes:storage-class", "my-class")
+	config.Set("volume-plans:p1:kubernetes:capacity", "20Gi")
+	config.Set("volume-plans:p1:kubernetes:access-modes", "ReadWriteOnce")
+	defer config.Unset("volume-plans")
+	v1, ns := s.createSnapshotVolume("v1")
+	v2 := &volumeTypes.Volume{
+		Name:      "v2",
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "test-default",
+		TeamOwner: "admin",
+	}
+	err := servicemanager.Volume.Create(context.TODO(), v2)
+	require.NoError(s.t, err)
+	var podVolumes []apiv1.Volume
+	for _, v := range []*volumeTypes.Volume{v1, v2} {
+		podVolumes = append(podVolumes, apiv1.Volume{
+			Name: volumeName(v.Name),
+			VolumeSource: apiv1.VolumeSource{
+				PersistentVolumeClaim: &apiv1.PersistentVolumeClaimVolumeSource{ClaimName: volumeClaimName(v.Name)},
+			},
+		})
+	}
+	_, err = s.client.CoreV1().Pods(ns).Create(context.TODO(), &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp-web-1", Namespace: ns},
+		Spec:       apiv1.PodSpec{NodeName: "node1", Volumes: podVolumes},
+		Status:     apiv1.PodStatus{Phase: apiv1.PodRunning},
+	}, metav1.CreateOptions{})
+	require.NoError(s.t, err)
+	var requestedNodes []string
+	oldStats := nodeStatsSummary
+	defer func() { nodeStatsSummary = oldStats }()
+	nodeStatsSummary = func(ctx context.Context, client *ClusterClient, nodeName string) (*kubeletStatsSummary, error) {
+		requestedNodes = append(requestedNodes, nodeName)
+		var summary kubeletStatsSummary
+		err := json.Unmarshal([]byte(`{"pods": [{"volume": [
+			{"name": "v1-tsuru", "capacityBytes": 100, "usedBytes": 40,
+			 "pvcRef": {"name": "v1-tsuru-claim", "namespace": "`+ns+`"}},
+			{"name": "v2-tsuru", "capacityBytes": 200, "usedBytes": 50,
+			 "pvcRef": {"name": "v2-tsuru-claim", "namespace": "`+ns+`"}}
+		]}]}`), &summary)
+		return &summary, err
+	}
+	usages, err := s.p.VolumesUsage(context.TODO(), []volumeTypes.Volume{*v1, *v2})
+	require.NoError(s.t, err)
+	require.Equal(s.t, []string{"node1"}, requestedNodes)
+	require.Equal(s.t, map[string]*volumeTypes.VolumeUsage{
+		"v1": {CapacityBytes: 100, UsedBytes: 40},
+		"v2": {CapacityBytes: 200, UsedBytes: 50},
+	}, usages)
+}
+
+func (s *S) TestVolumesUsageSkipsFailingNode(_ *check.C) {
+	config.Set("volume-plans:p1:kubernetes:storage-class", "my-class")
+	config.Set("volume-plans:p1:kubernetes:capacity", "20Gi")
+	config.Set("volume-plans:p1:kubernetes:access-modes", "ReadWriteOnce")
+	defer config.Unset("volume-plans")
+	v1, ns := s.createSnapshotVolume("v1")
+	v2 := &volumeTypes.Volume{
+		Name:      "v2",
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "test-default",
+		TeamOwner: "admin",
+	}
+	err := servicemanager.Volume.Create(context.TODO(), v2)
+	require.NoError(s.t, err)
+	for i, v := range []*volumeTypes.Volume{v1, v2} {
+		_, err = s.client.CoreV1().Pods(ns).Create(context.TODO(), &apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "myapp-web-" + v.Name, Namespace: ns},
+			Spec: apiv1.PodSpec{
+				NodeName: "node" + strconv.Itoa(i+1),
+				Volumes: []apiv1.Volume{{
+					Name: volumeName(v.Name),
+					VolumeSource: apiv1.VolumeSource{
+						PersistentVolumeClaim: &apiv1.PersistentVolumeClaimVolumeSource{ClaimName: volumeClaimName(v.Name)},
+					},
+				}},
+			},
+			Status: apiv1.PodStatus{Phase: apiv1.PodRunning},
+		}, metav1.CreateOptions{})
+		require.NoError(s.t, err)
+	}
+	oldStats := nodeStatsSummary
+	defer func() { nodeStatsSummary = oldStats }()
+	nodeStatsSummary = func(ctx context.Context, client *ClusterClient, nodeName string) (*kubeletStatsSummary, error) {
+		if nodeName == "node1" {
+			return nil, errors.New("kubelet unreachable")
+		}
+		var summary kubeletStatsSummary
+		err := json.Unmarshal([]byte(`{"pods": [{"volume": [
+			{"name": "v2-tsuru", "capacityBytes": 200, "usedBytes": 50,
+			 "pvcRef": {"name": "v2-tsuru-claim", "namespace": "`+ns+`"}}
+		]}]}`), &summary)
+		return &summary, err
+	}
+	usages, err := s.p.VolumesUsage(context.TODO(), []volumeTypes.Volume{*v1, *v2})
+	require.ErrorContains(s.t, err, "kubelet unreachable")
+	require.Equal(s.t, map[string]*volumeTypes.VolumeUsage{
+		"v2": {CapacityBytes: 200, UsedBytes: 50},
+	}, usages)
+}
@@ -54,6 +54,8 @@ const (
 	labelVolumePlan = "volume-plan"
 	labelVolumeTeam = "volume-team"
 
+	labelVolumeSnapshot = "volume-snapshot"
+
 	labelRestarts = "restarts"
 
 	labelClusterMetadata = "tsuru.io/cluster"
@@ -327,6 +329,14 @@ func (s *LabelSet) IsHeadlessService() bool {
 	return s.getBoolLabel(labelIsHeadlessService)
 }
 
+func (s *LabelSet) VolumeSnapshot() string {
+	return s.getLabel(labelVolumeSnapshot)
+}
+
+func (s *LabelSet) SetVolumeSnapshot(name string) {
+	s.addLabel(labelVolumeSnapshot, name)
+}
+
 func (s *LabelSet) SetRestarts(count int) {
 	s.addLabel(labelRestarts, strconv.Itoa(count))
 }
@@ -0,0 +1,49 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pool
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	provisionTypes "github.com/tsuru/tsuru/types/provision"
+)
+
+var ErrPoolCapacityNotSupported = errors.New("pool provisioner does not support capacity reports")
+
+// Capacity returns the resources allocated and available in the pool, with
+// the headroom estimated for each plan allowed in the pool.
+func (p *Pool) Capacity(ctx context.Context) (*provisionTypes.PoolCapacity, error) {
+	prov, err := p.GetProvisioner()
+	if err != nil {
+		return nil, err
+	}
+	capacityProv, ok := prov.(provision.PoolCapacityProvisioner)
+	if !ok {
+		return nil, ErrPoolCapacityNotSupported
+	}
+	planNames, err := p.GetPlans(ctx)
+	if err != nil && err != ErrPoolHasNoPlan {
+		return nil, err
+	}
+	allPlans, err := servicemanager.Plan.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	allowed := map[string]struct{}{}
+	for _, name := range planNames {
+		allowed[name] = struct{}{}
+	}
+	var plans []appTypes.Plan
+	for _, plan := range allPlans {
+		if _, ok := allowed[plan.Name]; ok {
+			plans = append(plans, plan)
+		}
+	}
+	return capacityProv.PoolCapacity(ctx, p.Name, plans)
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pool
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tsuru/tsuru/provision"
+	provisionTypes "github.com/tsuru/tsuru/types/provision"
+)
+
+var ErrPoolNodesNotSupported = errors.New("pool provisioner does not support listing nodes")
+
+// Nodes returns the nodes backing the pool, the units running on them and the
+// units of the pool not yet scheduled.
+func (p *Pool) Nodes(ctx context.Context) (*provisionTypes.NodeList, error) {
+	prov, err := p.GetProvisioner()
+	if err != nil {
+		return nil, err
+	}
+	nodeProv, ok := prov.(provision.NodeProvisioner)
+	if !ok {
+		return nil, ErrPoolNodesNotSupported
+	}
+	return nodeProv.PoolNodes(ctx, p.Name)
+}
@@ -240,6 +240,44 @@ type VolumeProvisioner interface {
 	DeleteVolume(ctx context.Context, volumeName, pool string) error
 }
 
+// VolumeSnapshotProvisioner is a provisioner able to take point-in-time
+// snapshots of persistent volumes.
+type VolumeSnapshotProvisioner interface {
+	CreateVolumeSnapshot(ctx context.Context, v *volumeTypes.Volume, name string) (*volumeTypes.VolumeSnapshot, error)
+	ListVolumeSnapshots(ctx context.Context, v *volumeTypes.Volume) ([]volumeTypes.VolumeSnapshot, error)
+	GetVolumeSnapshot(ctx context.Context, v *volumeTypes.Volume, name string) (*volumeTypes.VolumeSnapshot, error)
+	DeleteVolumeSnapshot(ctx context.Context, v *volumeTypes.Volume, name string) error
+}
+
+// VolumeResizeProvisioner is a provisioner able to expand the capacity of
+// provisioned volumes.
+type VolumeResizeProvisioner interface {
+	ResizeVolume(ctx context.Context, v *volumeTypes.Volume, capacity string, w io.Writer) error
+}
+
+// VolumeUsageProvisioner is a provisioner able to report disk and inode usage
+// of provisioned volumes.
+type VolumeUsageProvisioner interface {
+	VolumeUsage(ctx context.Context, v *volumeTypes.Volume) (*volumeTypes.VolumeUsage, error)
+	// VolumesUsage reports the usage of many volumes at once, keyed by
+	// volume name. Volumes without usage information are left out.
+	VolumesUsage(ctx context.Context, volumes []volumeTypes.Volume) (map[string]*volumeTypes.VolumeUsage, error)
+}
+
+// PoolCapacityProvisioner is a provisioner able to report the allocatable
+// and requested resources of the nodes in a pool.
+type PoolCapacityProvisioner interface {
+	PoolCapacity(ctx context.Context, pool string, plans []appTypes.Plan) (*provTypes.PoolCapacity, error)
+}
+
+// NodeProvisioner is a provisioner able to list the nodes backing pools and
+// clusters, with the units running on each of them and the units waiting to
+// be scheduled.
+type NodeProvisioner interface {
+	PoolNodes(ctx context.Context, pool string) (*provTypes.NodeList, error)
+	ClusterNodes(ctx context.Context, c *provTypes.Cluster) (*provTypes.NodeList, error)
+}
+
 func CPUValueOfAutoScaleSpec(s *provTypes.AutoScaleSpec, a *appTypes.App) (int, error) {
 	rawCPU := strings.TrimSuffix(s.AverageCPU, "%")
 	cpu, err := strconv.Atoi(rawCPU)
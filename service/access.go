@@ -0,0 +1,181 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+)
+
+// Access levels a team, app or job may have on a service instance. Each level
+// includes the ones before it: bind access allows reading the instance and
+// admin access allows everything. Apps and jobs may only be granted read or
+// bind access.
+const (
+	AccessLevelRead  = "read"
+	AccessLevelBind  = "bind"
+	AccessLevelAdmin = "admin"
+)
+
+var accessLevelWeight = map[string]int{
+	AccessLevelRead:  1,
+	AccessLevelBind:  2,
+	AccessLevelAdmin: 3,
+}
+
+func isValidAccessLevel(level string) bool {
+	_, ok := accessLevelWeight[level]
+	return ok
+}
+
+// TeamAccessLevel returns the access level of the team on the service
+// instance, or an empty string if the team has no access.
+func (si *ServiceInstance) TeamAccessLevel(teamName string) string {
+	if teamName == si.TeamOwner {
+		return AccessLevelAdmin
+	}
+	found := false
+	for _, t := range si.Teams {
+		if t == teamName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ""
+	}
+	if level, ok := si.TeamAccess[teamName]; ok {
+		return level
+	}
+	return AccessLevelAdmin
+}
+
+// TeamsWithAccess returns the teams that have, at least, the given access
+// level on the service instance.
+func (si *ServiceInstance) TeamsWithAccess(level string) []string {
+	var teams []string
+	for _, t := range si.Teams {
+		if accessLevelWeight[si.TeamAccessLevel(t)] >= accessLevelWeight[level] {
+			teams = append(teams, t)
+		}
+	}
+	return teams
+}
+
+// AppAccessLevel returns the access level of the app on the service instance.
+// Apps without a grant of their own have the access level of the team owning
+// them.
+func (si *ServiceInstance) AppAccessLevel(appName, teamOwner string) string {
+	if level, ok := si.AppAccess[appName]; ok {
+		return level
+	}
+	return si.TeamAccessLevel(teamOwner)
+}
+
+// JobAccessLevel is like AppAccessLevel, for jobs.
+func (si *ServiceInstance) JobAccessLevel(jobName, teamOwner string) string {
+	if level, ok := si.JobAccess[jobName]; ok {
+		return level
+	}
+	return si.TeamAccessLevel(teamOwner)
+}
+
+// HasGrants reports whether the service instance was explicitly granted to
+// teams other than its owner, or to apps or jobs.
+func (si *ServiceInstance) HasGrants() bool {
+	if len(si.TeamAccess) > 0 || len(si.AppAccess) > 0 || len(si.JobAccess) > 0 {
+		return true
+	}
+	for _, t := range si.Teams {
+		if t != si.TeamOwner {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAppBindAccess verifies whether the app is allowed to bind to the
+// service instance. Instances without explicit grants are not restricted.
+// Otherwise apps must have, at least, bind access, either through a grant to
+// the app itself or to the team owning it, and apps whose team has no grant
+// on the instance, and without a grant of their own, are not allowed to bind.
+func (si *ServiceInstance) CheckAppBindAccess(appName, teamOwner string) error {
+	if !si.HasGrants() {
+		return nil
+	}
+	return checkBindAccessLevel(si.AppAccessLevel(appName, teamOwner))
+}
+
+// CheckJobBindAccess is like CheckAppBindAccess, for jobs.
+func (si *ServiceInstance) CheckJobBindAccess(jobName, teamOwner string) error {
+	if !si.HasGrants() {
+		return nil
+	}
+	return checkBindAccessLevel(si.JobAccessLevel(jobName, teamOwner))
+}
+
+func checkBindAccessLevel(level string) error {
+	switch level {
+	case "":
+		return ErrBindAccessNotGranted
+	case AccessLevelRead:
+		return ErrAccessNotAllowsBind
+	}
+	return nil
+}
+
+// BindTeamsForApp returns the teams whose members may bind or unbind the app
+// on the service instance: the teams with, at least, bind access and, when
+// the app itself was granted bind access, the team owning it.
+func (si *ServiceInstance) BindTeamsForApp(appName, teamOwner string) []string {
+	teams := si.TeamsWithAccess(AccessLevelBind)
+	if accessLevelWeight[si.AppAccess[appName]] >= accessLevelWeight[AccessLevelBind] {
+		teams = append(teams, teamOwner)
+	}
+	return teams
+}
+
+// BindTeamsForJob is like BindTeamsForApp, for jobs.
+func (si *ServiceInstance) BindTeamsForJob(jobName, teamOwner string) []string {
+	teams := si.TeamsWithAccess(AccessLevelBind)
+	if accessLevelWeight[si.JobAccess[jobName]] >= accessLevelWeight[AccessLevelBind] {
+		teams = append(teams, teamOwner)
+	}
+	return teams
+}
+
+// GrantApp sets the access level of the app on the service instance,
+// regardless of the access of the team owning it. Apps may only be granted
+// read or bind access.
+func (si *ServiceInstance) GrantApp(ctx context.Context, appName, accessLevel string) error {
+	return si.grantResource(ctx, "app_access", appName, accessLevel)
+}
+
+// RevokeApp removes the grant of the app, which falls back to the access
+// level of the team owning it.
+func (si *ServiceInstance) RevokeApp(ctx context.Context, appName string) error {
+	return si.updateData(ctx, mongoBSON.M{"$unset": mongoBSON.M{"app_access." + appName: ""}})
+}
+
+// GrantJob is like GrantApp, for jobs.
+func (si *ServiceInstance) GrantJob(ctx context.Context, jobName, accessLevel string) error {
+	return si.grantResource(ctx, "job_access", jobName, accessLevel)
+}
+
+// RevokeJob is like RevokeApp, for jobs.
+func (si *ServiceInstance) RevokeJob(ctx context.Context, jobName string) error {
+	return si.updateData(ctx, mongoBSON.M{"$unset": mongoBSON.M{"job_access." + jobName: ""}})
+}
+
+func (si *ServiceInstance) grantResource(ctx context.Context, field, name, accessLevel string) error {
+	if accessLevel == "" {
+		accessLevel = AccessLevelBind
+	}
+	if accessLevel != AccessLevelRead && accessLevel != AccessLevelBind {
+		return ErrInvalidResourceAccessLevel
+	}
+	return si.updateData(ctx, mongoBSON.M{"$set": mongoBSON.M{field + "." + name: accessLevel}})
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/db/storagev2"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	check "gopkg.in/check.v1"
+)
+
+func (s *InstanceSuite) TestTeamAccessLevel(c *check.C) {
+	si := ServiceInstance{
+		Name:       "analytics",
+		TeamOwner:  "data",
+		Teams:      []string{"data", "web", "mobile", "ops"},
+		TeamAccess: map[string]string{"web": AccessLevelBind, "mobile": AccessLevelRead, "data": AccessLevelRead},
+	}
+	c.Assert(si.TeamAccessLevel("data"), check.Equals, AccessLevelAdmin)
+	c.Assert(si.TeamAccessLevel("web"), check.Equals, AccessLevelBind)
+	c.Assert(si.TeamAccessLevel("mobile"), check.Equals, AccessLevelRead)
+	c.Assert(si.TeamAccessLevel("ops"), check.Equals, AccessLevelAdmin)
+	c.Assert(si.TeamAccessLevel("other"), check.Equals, "")
+	c.Assert(si.TeamsWithAccess(AccessLevelRead), check.DeepEquals, []string{"data", "web", "mobile", "ops"})
+	c.Assert(si.TeamsWithAccess(AccessLevelBind), check.DeepEquals, []string{"data", "web", "ops"})
+	c.Assert(si.TeamsWithAccess(AccessLevelAdmin), check.DeepEquals, []string{"data", "ops"})
+}
+
+func (s *InstanceSuite) TestCheckBindAccess(c *check.C) {
+	si := ServiceInstance{
+		Name:       "analytics",
+		TeamOwner:  "data",
+		Teams:      []string{"data", "web", "mobile"},
+		TeamAccess: map[string]string{"web": AccessLevelBind, "mobile": AccessLevelRead},
+		AppAccess:  map[string]string{"reports": AccessLevelBind, "web-legacy": AccessLevelRead},
+		JobAccess:  map[string]string{"nightly": AccessLevelBind},
+	}
+	c.Assert(si.CheckAppBindAccess("dashboard", "data"), check.IsNil)
+	c.Assert(si.CheckAppBindAccess("site", "web"), check.IsNil)
+	c.Assert(si.CheckAppBindAccess("ios", "mobile"), check.Equals, ErrAccessNotAllowsBind)
+	c.Assert(si.CheckAppBindAccess("web-legacy", "web"), check.Equals, ErrAccessNotAllowsBind)
+	c.Assert(si.CheckAppBindAccess("reports", "finance"), check.IsNil)
+	c.Assert(si.CheckJobBindAccess("nightly", "finance"), check.IsNil)
+	c.Assert(si.BindTeamsForApp("reports", "finance"), check.DeepEquals, []string{"data", "web", "finance"})
+	c.Assert(si.BindTeamsForApp("web-legacy", "web"), check.DeepEquals, []string{"data", "web"})
+}
+
+func (s *InstanceSuite) TestCheckBindAccessTeamWithoutGrant(c *check.C) {
+	si := ServiceInstance{Name: "analytics", TeamOwner: "data", Teams: []string{"data", "web"}}
+	c.Assert(si.CheckAppBindAccess("site", "other"), check.Equals, ErrBindAccessNotGranted)
+	c.Assert(si.CheckJobBindAccess("nightly", "other"), check.Equals, ErrBindAccessNotGranted)
+	c.Assert(si.BindTeamsForApp("site", "other"), check.DeepEquals, []string{"data", "web"})
+}
+
+func (s *InstanceSuite) TestCheckBindAccessWithoutGrants(c *check.C) {
+	si := ServiceInstance{Name: "analytics", TeamOwner: "data", Teams: []string{"data"}}
+	c.Assert(si.HasGrants(), check.Equals, false)
+	c.Assert(si.CheckAppBindAccess("site", "other"), check.IsNil)
+	c.Assert(si.CheckJobBindAccess("nightly", "other"), check.IsNil)
+	si.AppAccess = map[string]string{"reports": AccessLevelBind}
+	c.Assert(si.HasGrants(), check.Equals, true)
+	c.Assert(si.CheckAppBindAccess("site", "other"), check.Equals, ErrBindAccessNotGranted)
+}
+
+func (s *InstanceSuite) TestGrantAppAccessLevel(c *check.C) {
+	sInstance := ServiceInstance{Name: "analytics", ServiceName: "mysql", TeamOwner: "data", Teams: []string{"data"}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &sInstance)
+	c.Assert(err, check.IsNil)
+	err = sInstance.GrantApp(context.TODO(), "reports", "")
+	c.Assert(err, check.IsNil)
+	err = sInstance.GrantJob(context.TODO(), "nightly", AccessLevelRead)
+	c.Assert(err, check.IsNil)
+	err = sInstance.GrantApp(context.TODO(), "reports", AccessLevelAdmin)
+	c.Assert(err, check.Equals, ErrInvalidResourceAccessLevel)
+	si, err := GetServiceInstance(context.TODO(), "mysql", "analytics")
+	c.Assert(err, check.IsNil)
+	c.Assert(si.AppAccess, check.DeepEquals, map[string]string{"reports": AccessLevelBind})
+	c.Assert(si.JobAccess, check.DeepEquals, map[string]string{"nightly": AccessLevelRead})
+	err = si.RevokeApp(context.TODO(), "reports")
+	c.Assert(err, check.IsNil)
+	si, err = GetServiceInstance(context.TODO(), "mysql", "analytics")
+	c.Assert(err, check.IsNil)
+	c.Assert(si.AppAccess, check.HasLen, 0)
+}
+
+func (s *InstanceSuite) TestGrantTeamAccessLevel(c *check.C) {
+	team := authTypes.Team{Name: "web"}
+	s.mockService.Team.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return &team, nil
+	}
+	sInstance := ServiceInstance{Name: "analytics", ServiceName: "mysql", TeamOwner: "data", Teams: []string{"data"}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &sInstance)
+	c.Assert(err, check.IsNil)
+	err = sInstance.Grant(context.TODO(), team.Name, AccessLevelBind)
+	c.Assert(err, check.IsNil)
+	si, err := GetServiceInstance(context.TODO(), "mysql", "analytics")
+	c.Assert(err, check.IsNil)
+	c.Assert(si.Teams, check.DeepEquals, []string{"data", "web"})
+	c.Assert(si.TeamAccessLevel("web"), check.Equals, AccessLevelBind)
+	err = si.Grant(context.TODO(), team.Name, "")
+	c.Assert(err, check.IsNil)
+	si, err = GetServiceInstance(context.TODO(), "mysql", "analytics")
+	c.Assert(err, check.IsNil)
+	c.Assert(si.TeamAccess, check.HasLen, 0)
+	c.Assert(si.TeamAccessLevel("web"), check.Equals, AccessLevelAdmin)
+}
+
+func (s *InstanceSuite) TestGrantTeamAccessLevelInvalid(c *check.C) {
+	si := ServiceInstance{Name: "analytics", ServiceName: "mysql", TeamOwner: "data", Teams: []string{"data"}}
+	err := si.Grant(context.TODO(), "web", "write")
+	c.Assert(err, check.Equals, ErrInvalidAccessLevel)
+	err = si.Grant(context.TODO(), "data", AccessLevelRead)
+	c.Assert(err, check.Equals, ErrTeamOwnerAccessLevel)
+}
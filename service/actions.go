@@ -226,6 +226,12 @@ var notifyUpdateServiceInstance = action.Action{
 		if err != nil {
 			return nil, err
 		}
+		if instance.ProvisionStatus == ProvisionStatusUpdating {
+			err = instance.startProvisionOperation(ctx.Context, instance.ProvisionStatus, instance.ProvisionOperation)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return instance, nil
 	},
 	Backward:  func(ctx action.BWContext) {},
@@ -405,22 +411,7 @@ var setBoundEnvsAction = &action.Action{
 		if args == nil {
 			return nil, errors.New("invalid arguments for pipeline, expected *bindAppPipelineArgs.")
 		}
-		envMap := ctx.Previous.(map[string]string)
-		envs := make([]bindTypes.ServiceEnvVar, 0, len(envMap))
-		for k, v := range envMap {
-			envs = append(envs, bindTypes.ServiceEnvVar{
-				ServiceName:  args.serviceInstance.ServiceName,
-				InstanceName: args.serviceInstance.Name,
-				EnvVar: bindTypes.EnvVar{
-					Public: false,
-					Name:   k,
-					Value:  v,
-				},
-			})
-		}
-		sort.Slice(envs, func(i, j int) bool {
-			return envs[i].Name < envs[j].Name
-		})
+		envs := args.serviceInstance.serviceEnvs(ctx.Previous.(map[string]string))
 		addArgs := bindTypes.AddInstanceArgs{
 			Envs:          envs,
 			ShouldRestart: args.shouldRestart,
@@ -449,22 +440,7 @@ var setJobBoundEnvsAction = &action.Action{
 		if args == nil {
 			return nil, errors.New("invalid arguments for pipeline, expected *bindJobPipelineArgs.")
 		}
-		envMap := ctx.Previous.(map[string]string)
-		envs := make([]bindTypes.ServiceEnvVar, 0, len(envMap))
-		for k, v := range envMap {
-			envs = append(envs, bindTypes.ServiceEnvVar{
-				ServiceName:  args.serviceInstance.ServiceName,
-				InstanceName: args.serviceInstance.Name,
-				EnvVar: bindTypes.EnvVar{
-					Public: false,
-					Name:   k,
-					Value:  v,
-				},
-			})
-		}
-		sort.Slice(envs, func(i, j int) bool {
-			return envs[i].Name < envs[j].Name
-		})
+		envs := args.serviceInstance.serviceEnvs(ctx.Previous.(map[string]string))
 
 		addArgs := jobTypes.AddInstanceArgs{
 			Envs:   envs,
@@ -486,6 +462,27 @@ var setJobBoundEnvsAction = &action.Action{
 	},
 }
 
+// serviceEnvs converts the envs returned by the service API on bind to
+// private service env vars, sorted by name.
+func (si *ServiceInstance) serviceEnvs(envMap map[string]string) []bindTypes.ServiceEnvVar {
+	envs := make([]bindTypes.ServiceEnvVar, 0, len(envMap))
+	for k, v := range envMap {
+		envs = append(envs, bindTypes.ServiceEnvVar{
+			ServiceName:  si.ServiceName,
+			InstanceName: si.Name,
+			EnvVar: bindTypes.EnvVar{
+				Public: false,
+				Name:   k,
+				Value:  v,
+			},
+		})
+	}
+	sort.Slice(envs, func(i, j int) bool {
+		return envs[i].Name < envs[j].Name
+	})
+	return envs
+}
+
 var reloadJobProvisioner = &action.Action{
 	Name: "reload-job-provisioner",
 	Forward: func(ctx action.FWContext) (action.Result, error) {
@@ -0,0 +1,282 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+)
+
+type RotateCredentialsArgs struct {
+	Restart   bool
+	Writer    io.Writer
+	Event     *event.Event
+	RequestID string
+}
+
+// CredentialsRotationResult holds the outcome of rotating the credentials of
+// a single bind.
+type CredentialsRotationResult struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+// CredentialsRotationError is returned when the credentials of at least one
+// bind could not be rotated.
+type CredentialsRotationError struct {
+	Failed int
+	Total  int
+}
+
+func (e *CredentialsRotationError) Error() string {
+	return fmt.Sprintf("failed to rotate credentials on %d of %d binds", e.Failed, e.Total)
+}
+
+// RotateCredentials asks the service API for new bind data for every app and
+// job bound to the instance, replacing the service envs on each of them. The
+// previous credentials are only revoked once the new ones are in place.
+// Binds are rotated one at a time, so apps are restarted in sequence. A
+// failure in one bind doesn't stop the remaining ones; the outcome of every
+// bind is returned and saved as custom data in the event.
+func (si *ServiceInstance) RotateCredentials(ctx context.Context, args RotateCredentialsArgs) ([]CredentialsRotationResult, error) {
+	if err := si.checkProvisioned(); err != nil {
+		return nil, err
+	}
+	if args.Writer == nil {
+		args.Writer = io.Discard
+	}
+	s, err := Get(ctx, si.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := s.getClientForPool(ctx, si.Pool)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]CredentialsRotationResult, 0, len(si.Apps)+len(si.Jobs))
+	var failed int
+	for _, appName := range si.Apps {
+		fmt.Fprintf(args.Writer, "---- Rotating credentials of app %q ----\n", appName)
+		err = si.rotateAppCredentials(ctx, endpoint, appName, args)
+		results = append(results, newCredentialsRotationResult("app", appName, err, args.Writer))
+		if err != nil {
+			failed++
+		}
+	}
+	for _, jobName := range si.Jobs {
+		fmt.Fprintf(args.Writer, "---- Rotating credentials of job %q ----\n", jobName)
+		err = si.rotateJobCredentials(ctx, endpoint, jobName, args)
+		results = append(results, newCredentialsRotationResult("job", jobName, err, args.Writer))
+		if err != nil {
+			failed++
+		}
+	}
+	if args.Event != nil {
+		if err = args.Event.SetOtherCustomData(ctx, results); err != nil {
+			log.Errorf("[service instance %s/%s] unable to save rotation results: %v", si.ServiceName, si.Name, err)
+		}
+	}
+	if failed > 0 {
+		return results, &CredentialsRotationError{Failed: failed, Total: len(results)}
+	}
+	return results, nil
+}
+
+func newCredentialsRotationResult(kind, name string, err error, w io.Writer) CredentialsRotationResult {
+	result := CredentialsRotationResult{Kind: kind, Name: name}
+	if err != nil {
+		result.Error = err.Error()
+		fmt.Fprintf(w, "  ---> failed: %v\n", err)
+		return result
+	}
+	fmt.Fprintf(w, "  ---> done\n")
+	return result
+}
+
+// generationalBinder is implemented by service clients that identify each
+// generation of the bind of an app or job separately, so that a new binding
+// can coexist with the previous one during a rotation. Other clients identify
+// binds only by the app or job, so binding again is expected to replace the
+// credentials on the service API and the previous binding is left alone.
+type generationalBinder interface {
+	bindsPerGeneration() bool
+}
+
+func bindsPerGeneration(endpoint ServiceClient) bool {
+	binder, ok := endpoint.(generationalBinder)
+	return ok && binder.bindsPerGeneration()
+}
+
+func bindGenerationKey(kind, name string) string {
+	return kind + "/" + name
+}
+
+// withNextBindGeneration returns a copy of the instance identifying the next
+// generation of the bind of the given app or job.
+func (si *ServiceInstance) withNextBindGeneration(kind, name string) *ServiceInstance {
+	next := *si
+	next.BindGenerations = make(map[string]int, len(si.BindGenerations)+1)
+	for k, v := range si.BindGenerations {
+		next.BindGenerations[k] = v
+	}
+	key := bindGenerationKey(kind, name)
+	next.BindGenerations[key] = si.BindGenerations[key] + 1
+	return &next
+}
+
+func (si *ServiceInstance) saveBindGeneration(ctx context.Context, next *ServiceInstance, kind, name string) error {
+	key := bindGenerationKey(kind, name)
+	err := si.updateData(ctx, mongoBSON.M{"$set": mongoBSON.M{"bind_generations." + key: next.BindGenerations[key]}})
+	if err != nil {
+		return err
+	}
+	si.BindGenerations = next.BindGenerations
+	return nil
+}
+
+func (si *ServiceInstance) instanceEnvs(envs []bindTypes.ServiceEnvVar) []bindTypes.ServiceEnvVar {
+	var result []bindTypes.ServiceEnvVar
+	for _, env := range envs {
+		if env.ServiceName == si.ServiceName && env.InstanceName == si.Name {
+			result = append(result, env)
+		}
+	}
+	return result
+}
+
+// rotateAppCredentials binds the app again to get new credentials, swaps the
+// service envs of the app and only then revokes the previous binding, so the
+// app never runs with revoked credentials. If the new credentials can't be
+// put in place, the previous envs are restored and the new binding is
+// revoked instead.
+func (si *ServiceInstance) rotateAppCredentials(ctx context.Context, endpoint ServiceClient, appName string, args RotateCredentialsArgs) error {
+	a, err := servicemanager.App.GetByName(ctx, appName)
+	if err != nil {
+		return err
+	}
+	next := si.withNextBindGeneration("app", appName)
+	envMap, err := endpoint.BindApp(ctx, next, a, nil, args.Event, args.RequestID)
+	if err != nil {
+		return errors.Wrap(err, "unable to issue new credentials")
+	}
+	oldEnvs := si.instanceEnvs(a.ServiceEnvs)
+	err = si.swapAppEnvs(ctx, a, si.serviceEnvs(envMap), args)
+	if err != nil {
+		if restoreErr := si.swapAppEnvs(ctx, a, oldEnvs, RotateCredentialsArgs{Writer: args.Writer}); restoreErr != nil {
+			log.Errorf("[service instance %s/%s] unable to restore the envs of app %q: %v", si.ServiceName, si.Name, appName, restoreErr)
+		}
+		if bindsPerGeneration(endpoint) {
+			if unbindErr := endpoint.UnbindApp(ctx, next, a, args.Event, args.RequestID); unbindErr != nil {
+				log.Errorf("[service instance %s/%s] unable to revoke the new credentials of app %q: %v", si.ServiceName, si.Name, appName, unbindErr)
+			}
+		}
+		return errors.Wrap(err, "unable to update the app envs")
+	}
+	if !bindsPerGeneration(endpoint) {
+		return nil
+	}
+	previous := *si
+	err = si.saveBindGeneration(ctx, next, "app", appName)
+	if err != nil {
+		return err
+	}
+	err = endpoint.UnbindApp(ctx, &previous, a, args.Event, args.RequestID)
+	if err != nil && err != ErrInstanceNotFoundInAPI {
+		return errors.Wrap(err, "unable to revoke the previous credentials")
+	}
+	return nil
+}
+
+// swapAppEnvs replaces the service envs of the instance on the app, restarting
+// it only once the new envs are set.
+func (si *ServiceInstance) swapAppEnvs(ctx context.Context, a *appTypes.App, envs []bindTypes.ServiceEnvVar, args RotateCredentialsArgs) error {
+	err := servicemanager.App.RemoveInstance(ctx, a, bindTypes.RemoveInstanceArgs{
+		ServiceName:  si.ServiceName,
+		InstanceName: si.Name,
+		Writer:       args.Writer,
+	})
+	if err != nil {
+		return err
+	}
+	return servicemanager.App.AddInstance(ctx, a, bindTypes.AddInstanceArgs{
+		Envs:          envs,
+		ShouldRestart: args.Restart,
+		Writer:        args.Writer,
+	})
+}
+
+// rotateJobCredentials is like rotateAppCredentials, updating the job on the
+// provisioner once its envs are swapped.
+func (si *ServiceInstance) rotateJobCredentials(ctx context.Context, endpoint ServiceClient, jobName string, args RotateCredentialsArgs) error {
+	job, err := servicemanager.Job.GetByName(ctx, jobName)
+	if err != nil {
+		return err
+	}
+	next := si.withNextBindGeneration("job", jobName)
+	envMap, err := endpoint.BindJob(ctx, next, job, args.Event, args.RequestID)
+	if err != nil {
+		return errors.Wrap(err, "unable to issue new credentials")
+	}
+	oldEnvs := si.instanceEnvs(job.Spec.ServiceEnvs)
+	err = si.swapJobEnvs(ctx, job, si.serviceEnvs(envMap), args)
+	if err != nil {
+		if restoreErr := si.swapJobEnvs(ctx, job, oldEnvs, RotateCredentialsArgs{Writer: args.Writer}); restoreErr != nil {
+			log.Errorf("[service instance %s/%s] unable to restore the envs of job %q: %v", si.ServiceName, si.Name, jobName, restoreErr)
+		}
+		if bindsPerGeneration(endpoint) {
+			if unbindErr := endpoint.UnbindJob(ctx, next, job, args.Event, args.RequestID); unbindErr != nil {
+				log.Errorf("[service instance %s/%s] unable to revoke the new credentials of job %q: %v", si.ServiceName, si.Name, jobName, unbindErr)
+			}
+		}
+		return errors.Wrap(err, "unable to update the job envs")
+	}
+	if !bindsPerGeneration(endpoint) {
+		return nil
+	}
+	previous := *si
+	err = si.saveBindGeneration(ctx, next, "job", jobName)
+	if err != nil {
+		return err
+	}
+	err = endpoint.UnbindJob(ctx, &previous, job, args.Event, args.RequestID)
+	if err != nil && err != ErrInstanceNotFoundInAPI {
+		return errors.Wrap(err, "unable to revoke the previous credentials")
+	}
+	return nil
+}
+
+func (si *ServiceInstance) swapJobEnvs(ctx context.Context, job *jobTypes.Job, envs []bindTypes.ServiceEnvVar, args RotateCredentialsArgs) error {
+	err := servicemanager.Job.RemoveServiceEnv(ctx, job, jobTypes.RemoveInstanceArgs{
+		ServiceName:  si.ServiceName,
+		InstanceName: si.Name,
+		Writer:       args.Writer,
+	})
+	if err != nil {
+		return err
+	}
+	err = servicemanager.Job.AddServiceEnv(ctx, job, jobTypes.AddInstanceArgs{
+		Envs:   envs,
+		Writer: args.Writer,
+	})
+	if err != nil {
+		return err
+	}
+	job, err = servicemanager.Job.GetByName(ctx, job.Name)
+	if err != nil {
+		return err
+	}
+	return servicemanager.Job.UpdateJobProv(ctx, job)
+}
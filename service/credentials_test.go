@@ -0,0 +1,118 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+
+	"github.com/tsuru/tsuru/db/storagev2"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	bindTypes "github.com/tsuru/tsuru/types/bind"
+	check "gopkg.in/check.v1"
+)
+
+func (s *InstanceSuite) TestRotateCredentials(c *check.C) {
+	var binds, unbinds int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodDelete && strings.HasSuffix(r.URL.Path, "/bind-app"):
+			atomic.AddInt32(&unbinds, 1)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/bind-app"):
+			atomic.AddInt32(&binds, 1)
+			w.Write([]byte(`{"DATABASE_PASSWORD":"new-secret"}`))
+		}
+	}))
+	defer ts.Close()
+	srv := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	a := &appTypes.App{Name: "myapp", ServiceEnvs: []bindTypes.ServiceEnvVar{
+		{ServiceName: "mysql", InstanceName: "my-db", EnvVar: bindTypes.EnvVar{Name: "DATABASE_PASSWORD", Value: "old-secret"}},
+	}}
+	s.mockService.App.Apps = []*appTypes.App{a}
+	si := ServiceInstance{Name: "my-db", ServiceName: srv.Name, Apps: []string{"myapp", "ghost"}}
+	results, err := si.RotateCredentials(context.TODO(), RotateCredentialsArgs{Event: createEvt(c)})
+	c.Assert(err, check.ErrorMatches, "failed to rotate credentials on 1 of 2 binds")
+	c.Assert(results, check.DeepEquals, []CredentialsRotationResult{
+		{Kind: "app", Name: "myapp"},
+		{Kind: "app", Name: "ghost", Error: appTypes.ErrAppNotFound.Error()},
+	})
+	c.Assert(atomic.LoadInt32(&unbinds), check.Equals, int32(0))
+	c.Assert(atomic.LoadInt32(&binds), check.Equals, int32(1))
+	c.Assert(a.ServiceEnvs, check.DeepEquals, []bindTypes.ServiceEnvVar{
+		{ServiceName: "mysql", InstanceName: "my-db", EnvVar: bindTypes.EnvVar{Name: "DATABASE_PASSWORD", Value: "new-secret"}},
+	})
+}
+
+func (s *InstanceSuite) TestRotateCredentialsBindFailureKeepsCurrentCredentials(c *check.C) {
+	var unbinds int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			atomic.AddInt32(&unbinds, 1)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	srv := Service{Name: "mysql", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	oldEnvs := []bindTypes.ServiceEnvVar{
+		{ServiceName: "mysql", InstanceName: "my-db", EnvVar: bindTypes.EnvVar{Name: "DATABASE_PASSWORD", Value: "old-secret"}},
+	}
+	a := &appTypes.App{Name: "myapp", ServiceEnvs: append([]bindTypes.ServiceEnvVar{}, oldEnvs...)}
+	s.mockService.App.Apps = []*appTypes.App{a}
+	si := ServiceInstance{Name: "my-db", ServiceName: srv.Name, Apps: []string{"myapp"}}
+	results, err := si.RotateCredentials(context.TODO(), RotateCredentialsArgs{Event: createEvt(c)})
+	c.Assert(err, check.ErrorMatches, "failed to rotate credentials on 1 of 1 binds")
+	c.Assert(results, check.HasLen, 1)
+	c.Assert(results[0].Error, check.Matches, "unable to issue new credentials: .*")
+	c.Assert(atomic.LoadInt32(&unbinds), check.Equals, int32(0))
+	c.Assert(a.ServiceEnvs, check.DeepEquals, oldEnvs)
+}
+
+func (s *InstanceSuite) TestRotateCredentialsOSBRevokesPreviousBinding(c *check.C) {
+	b := &fakeBroker{bindCode: http.StatusCreated}
+	ts := httptest.NewServer(b)
+	defer ts.Close()
+	srv := Service{Name: "mysql", Protocol: ProtocolOSB, Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	si := ServiceInstance{Name: "my-db", ServiceName: srv.Name, PlanName: "small", Apps: []string{"myapp"}}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &si)
+	c.Assert(err, check.IsNil)
+	a := &appTypes.App{Name: "myapp", ServiceEnvs: []bindTypes.ServiceEnvVar{
+		{ServiceName: "mysql", InstanceName: "my-db", EnvVar: bindTypes.EnvVar{Name: "URI", Value: "mysql://old"}},
+	}}
+	s.mockService.App.Apps = []*appTypes.App{a}
+	previousBinding := osbInstancePath(&si) + "/service_bindings/" + osbBindingID(&si, "app", "myapp")
+	_, err = si.RotateCredentials(context.TODO(), RotateCredentialsArgs{Event: createEvt(c)})
+	c.Assert(err, check.IsNil)
+	c.Assert(si.BindGenerations, check.DeepEquals, map[string]int{"app/myapp": 1})
+	newBinding := osbInstancePath(&si) + "/service_bindings/" + osbBindingID(&si, "app", "myapp")
+	c.Assert(newBinding, check.Not(check.Equals), previousBinding)
+	c.Assert(b.requests[len(b.requests)-2:], check.DeepEquals, []string{"PUT " + newBinding, "DELETE " + previousBinding})
+	dbInstance, err := GetServiceInstance(context.TODO(), srv.Name, si.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbInstance.BindGenerations, check.DeepEquals, map[string]int{"app/myapp": 1})
+}
+
+func (s *InstanceSuite) TestRotateCredentialsWhileProvisioning(c *check.C) {
+	si := ServiceInstance{Name: "my-db", ServiceName: "mysql", Apps: []string{"myapp"}, ProvisionStatus: ProvisionStatusProvisioning}
+	_, err := si.RotateCredentials(context.TODO(), RotateCredentialsArgs{})
+	c.Assert(err, check.Equals, ErrServiceInstanceProvisioning)
+}
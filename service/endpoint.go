@@ -96,6 +96,10 @@ func (c *endpointClient) Create(ctx context.Context, instance *ServiceInstance,
 	resp, err = c.issueRequest(ctx, "/resources", "POST", params, header)
 	if err == nil {
 		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusAccepted {
+			instance.ProvisionStatus = ProvisionStatusProvisioning
+			return nil
+		}
 		if resp.StatusCode < 300 {
 			return nil
 		}
@@ -0,0 +1,748 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/net"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+)
+
+const (
+	osbAPIVersion = "2.14"
+
+	osbStateInProgress = "in progress"
+	osbStateSucceeded  = "succeeded"
+	osbStateFailed     = "failed"
+)
+
+var (
+	// osbPollInterval and osbPollTimeout control how asynchronous operations
+	// on Open Service Broker APIs are awaited.
+	osbPollInterval = 5 * time.Second
+	osbPollTimeout  = 30 * time.Minute
+
+	// osbCatalogTTL is how long broker catalogs are cached.
+	osbCatalogTTL = 5 * time.Minute
+
+	osbNamespace = uuid.MustParse("5c3c7a54-2a8b-4d53-9b0b-b1ad1e1f6f4a")
+
+	osbEnvNameRegexp = regexp.MustCompile(`[^A-Z0-9_]+`)
+
+	ErrOSBServiceNotInCatalog = errors.New("service not found in the broker catalog")
+	ErrOSBPlanNotInCatalog    = errors.New("plan not found in the broker catalog")
+	ErrOSBProxyNotSupported   = &tsuruErrors.HTTP{
+		Code:    http.StatusNotImplemented,
+		Message: "proxy requests are not supported by Open Service Broker services",
+	}
+)
+
+var _ ServiceClient = &osbClient{}
+
+var osbCatalogs = osbCatalogCache{entries: map[string]osbCatalogCacheEntry{}}
+
+// osbCatalogCache holds the catalogs fetched from brokers, keyed by their
+// endpoint and credentials, so that each operation doesn't need to fetch
+// /v2/catalog again.
+type osbCatalogCache struct {
+	sync.Mutex
+	entries map[string]osbCatalogCacheEntry
+}
+
+type osbCatalogCacheEntry struct {
+	catalog   *osbCatalog
+	expiresAt time.Time
+}
+
+func (c *osbCatalogCache) get(key string) *osbCatalog {
+	c.Lock()
+	defer c.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil
+	}
+	return entry.catalog
+}
+
+func (c *osbCatalogCache) set(key string, catalog *osbCatalog) {
+	c.Lock()
+	defer c.Unlock()
+	c.entries[key] = osbCatalogCacheEntry{catalog: catalog, expiresAt: time.Now().Add(osbCatalogTTL)}
+}
+
+// osbClient is a ServiceClient speaking the Open Service Broker API v2.
+//
+// The tsuru service is matched to the catalog service with the same name (or
+// id). Brokers exposing a single service are used regardless of its name.
+type osbClient struct {
+	serviceName string
+	endpoint    string
+	username    string
+	password    string
+}
+
+type osbCatalog struct {
+	Services []osbCatalogService `json:"services"`
+}
+
+type osbCatalogService struct {
+	ID                   string           `json:"id"`
+	Name                 string           `json:"name"`
+	Description          string           `json:"description"`
+	Bindable             bool             `json:"bindable"`
+	InstancesRetrievable bool             `json:"instances_retrievable"`
+	Plans                []osbCatalogPlan `json:"plans"`
+}
+
+type osbCatalogPlan struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Bindable    *bool        `json:"bindable,omitempty"`
+	Schemas     *PlanSchemas `json:"schemas,omitempty"`
+}
+
+type osbOperationResponse struct {
+	Operation    string `json:"operation,omitempty"`
+	DashboardURL string `json:"dashboard_url,omitempty"`
+}
+
+type osbLastOperation struct {
+	State       string `json:"state"`
+	Description string `json:"description"`
+}
+
+type osbInstance struct {
+	ServiceID    string `json:"service_id"`
+	PlanID       string `json:"plan_id"`
+	DashboardURL string `json:"dashboard_url"`
+}
+
+type osbBinding struct {
+	Credentials map[string]interface{} `json:"credentials"`
+}
+
+type osbError struct {
+	Error       string `json:"error"`
+	Description string `json:"description"`
+}
+
+func (c *osbClient) Create(ctx context.Context, instance *ServiceInstance, evt *event.Event, requestID string) error {
+	svc, plan, err := c.catalogPlan(ctx, instance.PlanName, requestID)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"service_id":        svc.ID,
+		"plan_id":           plan.ID,
+		"organization_guid": instance.TeamOwner,
+		"space_guid":        instance.TeamOwner,
+		"context":           osbInstanceContext(instance),
+	}
+	if len(instance.Parameters) > 0 {
+		body["parameters"] = instance.Parameters
+	}
+	path := osbInstancePath(instance)
+	resp, err := c.doRequest(ctx, http.MethodPut, path, url.Values{"accepts_incomplete": {"true"}}, body, evt, instance, requestID)
+	if err != nil {
+		return log.WrapError(errors.Wrapf(err, "Failed to create the instance %s", instance.Name))
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return nil
+	case http.StatusAccepted:
+		var op osbOperationResponse
+		err = c.jsonFromResponse(resp, &op)
+		if err != nil {
+			return err
+		}
+		instance.ProvisionStatus = ProvisionStatusProvisioning
+		instance.ProvisionOperation = op.Operation
+		return nil
+	case http.StatusConflict:
+		return ErrInstanceAlreadyExistsInAPI
+	}
+	return log.WrapError(errors.Wrapf(c.buildErrorMessage(resp), "Failed to create the instance %s", instance.Name))
+}
+
+func (c *osbClient) Update(ctx context.Context, instance *ServiceInstance, evt *event.Event, requestID string) error {
+	svc, plan, err := c.catalogPlan(ctx, instance.PlanName, requestID)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"service_id": svc.ID,
+		"plan_id":    plan.ID,
+		"context":    osbInstanceContext(instance),
+	}
+	if len(instance.Parameters) > 0 {
+		body["parameters"] = instance.Parameters
+	}
+	path := osbInstancePath(instance)
+	resp, err := c.doRequest(ctx, http.MethodPatch, path, url.Values{"accepts_incomplete": {"true"}}, body, evt, instance, requestID)
+	if err != nil {
+		return log.WrapError(errors.Wrapf(err, "Failed to update the instance %s", instance.Name))
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusAccepted:
+		var op osbOperationResponse
+		err = c.jsonFromResponse(resp, &op)
+		if err != nil {
+			return err
+		}
+		instance.ProvisionStatus = ProvisionStatusUpdating
+		instance.ProvisionOperation = op.Operation
+		return nil
+	}
+	return log.WrapError(errors.Wrapf(c.buildErrorMessage(resp), "Failed to update the instance %s", instance.Name))
+}
+
+func (c *osbClient) Destroy(ctx context.Context, instance *ServiceInstance, evt *event.Event, requestID string) error {
+	svc, plan, err := c.catalogPlan(ctx, instance.PlanName, requestID)
+	if err != nil {
+		return err
+	}
+	path := osbInstancePath(instance)
+	query := url.Values{
+		"accepts_incomplete": {"true"},
+		"service_id":         {svc.ID},
+		"plan_id":            {plan.ID},
+	}
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, query, nil, evt, instance, requestID)
+	if err != nil {
+		return log.WrapError(errors.Wrapf(err, "Failed to destroy the instance %s", instance.Name))
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusAccepted:
+		var op osbOperationResponse
+		err = c.jsonFromResponse(resp, &op)
+		if err != nil {
+			return err
+		}
+		instance.ProvisionStatus = ProvisionStatusDeprovisioning
+		instance.ProvisionOperation = op.Operation
+		return nil
+	case http.StatusGone:
+		return ErrInstanceNotFoundInAPI
+	}
+	return log.WrapError(errors.Wrapf(c.buildErrorMessage(resp), "Failed to destroy the instance %s", instance.Name))
+}
+
+func (c *osbClient) BindApp(ctx context.Context, instance *ServiceInstance, app *appTypes.App, params BindAppParameters, evt *event.Event, requestID string) (map[string]string, error) {
+	if app == nil {
+		return nil, errors.New("app cannot be nil")
+	}
+	bindResource := map[string]interface{}{"app_guid": app.Name}
+	bindContext := map[string]interface{}{"app_name": app.Name, "app_pool": app.Pool}
+	envs, err := c.bind(ctx, instance, osbBindingID(instance, "app", app.Name), bindResource, bindContext, params, evt, requestID)
+	if err == ErrInstanceNotReady || err == ErrInstanceNotFoundInAPI {
+		return nil, err
+	}
+	if err != nil {
+		return nil, log.WrapError(errors.Wrapf(err, `Failed to bind the instance "%s/%s" to the app %q`, instance.ServiceName, instance.Name, app.Name))
+	}
+	return envs, nil
+}
+
+func (c *osbClient) BindJob(ctx context.Context, instance *ServiceInstance, job *jobTypes.Job, evt *event.Event, requestID string) (map[string]string, error) {
+	if job == nil {
+		return nil, errors.New("job cannot be nil")
+	}
+	bindContext := map[string]interface{}{"job_name": job.Name, "job_pool": job.Pool}
+	envs, err := c.bind(ctx, instance, osbBindingID(instance, "job", job.Name), nil, bindContext, nil, evt, requestID)
+	if err == ErrInstanceNotReady || err == ErrInstanceNotFoundInAPI {
+		return nil, err
+	}
+	if err != nil {
+		return nil, log.WrapError(errors.Wrapf(err, `Failed to bind the instance "%s/%s" to the job %q`, instance.ServiceName, instance.Name, job.Name))
+	}
+	return envs, nil
+}
+
+func (c *osbClient) UnbindApp(ctx context.Context, instance *ServiceInstance, app *appTypes.App, evt *event.Event, requestID string) error {
+	return c.unbind(ctx, instance, osbBindingID(instance, "app", app.Name), evt, requestID)
+}
+
+func (c *osbClient) UnbindJob(ctx context.Context, instance *ServiceInstance, job *jobTypes.Job, evt *event.Event, requestID string) error {
+	return c.unbind(ctx, instance, osbBindingID(instance, "job", job.Name), evt, requestID)
+}
+
+// Status reports the status of the instance. The last operation executed by
+// the broker is only queried while an asynchronous operation is pending, as
+// brokers reject last_operation requests for instances provisioned
+// synchronously. Ready instances are looked up on brokers whose catalog
+// service is instances_retrievable and assumed to be up otherwise.
+func (c *osbClient) Status(ctx context.Context, instance *ServiceInstance, requestID string) (string, error) {
+	svc, plan, err := c.catalogPlan(ctx, instance.PlanName, requestID)
+	if err != nil {
+		return "", err
+	}
+	if !instance.IsProvisioning() {
+		return c.instanceStatus(ctx, svc, instance, requestID)
+	}
+	removing := instance.ProvisionStatus == ProvisionStatusDeprovisioning
+	op, err := c.lastOperation(ctx, osbInstancePath(instance), svc.ID, plan.ID, instance.ProvisionOperation, instance, requestID)
+	if err == ErrInstanceNotFoundInAPI {
+		if removing {
+			return instanceStatusRemoved, nil
+		}
+		return "down", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	switch op.State {
+	case osbStateInProgress:
+		return "pending", nil
+	case osbStateFailed:
+		if op.Description != "" {
+			return "down (" + op.Description + ")", nil
+		}
+		return "down", nil
+	}
+	if removing {
+		return instanceStatusRemoved, nil
+	}
+	return "up", nil
+}
+
+func (c *osbClient) instanceStatus(ctx context.Context, svc *osbCatalogService, instance *ServiceInstance, requestID string) (string, error) {
+	if !svc.InstancesRetrievable {
+		return "up", nil
+	}
+	resp, err := c.doRequest(ctx, http.MethodGet, osbInstancePath(instance), nil, nil, nil, instance, requestID)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return "up", nil
+	case http.StatusNotFound, http.StatusGone:
+		return "down", nil
+	}
+	return "", c.buildErrorMessage(resp)
+}
+
+// Info returns the dashboard URL and plan of the instance, for brokers whose
+// catalog service is instances_retrievable. Instance parameters are left out
+// as they may hold secrets.
+func (c *osbClient) Info(ctx context.Context, instance *ServiceInstance, requestID string) ([]map[string]string, error) {
+	svc, err := c.catalogService(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if !svc.InstancesRetrievable {
+		return nil, nil
+	}
+	resp, err := c.doRequest(ctx, http.MethodGet, osbInstancePath(instance), nil, nil, nil, instance, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil
+	}
+	var result osbInstance
+	err = c.jsonFromResponse(resp, &result)
+	if err != nil {
+		return nil, err
+	}
+	var info []map[string]string
+	if result.DashboardURL != "" {
+		info = append(info, map[string]string{"label": "Dashboard", "value": result.DashboardURL})
+	}
+	for _, p := range svc.Plans {
+		if p.ID == result.PlanID {
+			info = append(info, map[string]string{"label": "Plan", "value": p.Name})
+			break
+		}
+	}
+	return info, nil
+}
+
+// Plans returns the plans of the service in the broker catalog, along with
+// their parameter schemas.
+func (c *osbClient) Plans(ctx context.Context, pool, requestID string) ([]Plan, error) {
+	svc, err := c.catalogService(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	plans := make([]Plan, len(svc.Plans))
+	for i, p := range svc.Plans {
+		plans[i] = Plan{Name: p.Name, Description: p.Description, Schemas: p.Schemas}
+	}
+	return plans, nil
+}
+
+func (c *osbClient) Proxy(ctx context.Context, opts *ProxyOpts) error {
+	return ErrOSBProxyNotSupported
+}
+
+func (c *osbClient) bindsPerGeneration() bool {
+	return true
+}
+
+func (c *osbClient) bind(ctx context.Context, instance *ServiceInstance, bindingID string, bindResource, bindContext map[string]interface{}, params map[string]interface{}, evt *event.Event, requestID string) (map[string]string, error) {
+	svc, plan, err := c.catalogPlan(ctx, instance.PlanName, requestID)
+	if err != nil {
+		return nil, err
+	}
+	bindCtx := osbInstanceContext(instance)
+	for k, v := range bindContext {
+		bindCtx[k] = v
+	}
+	body := map[string]interface{}{
+		"service_id": svc.ID,
+		"plan_id":    plan.ID,
+		"context":    bindCtx,
+	}
+	if bindResource != nil {
+		body["bind_resource"] = bindResource
+	}
+	if len(params) > 0 {
+		body["parameters"] = params
+	}
+	path := osbInstancePath(instance) + "/service_bindings/" + bindingID
+	resp, err := c.doRequest(ctx, http.MethodPut, path, url.Values{"accepts_incomplete": {"true"}}, body, evt, instance, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		var binding osbBinding
+		err = c.jsonFromResponse(resp, &binding)
+		if err != nil {
+			return nil, err
+		}
+		return osbCredentialsToEnvs(binding.Credentials), nil
+	case http.StatusAccepted:
+		var op osbOperationResponse
+		err = c.jsonFromResponse(resp, &op)
+		if err != nil {
+			return nil, err
+		}
+		err = c.waitLastOperation(ctx, path, svc.ID, plan.ID, op.Operation, evt, instance, requestID)
+		if err != nil {
+			return nil, err
+		}
+		return c.fetchBinding(ctx, instance, path, requestID)
+	case http.StatusNotFound, http.StatusGone:
+		return nil, ErrInstanceNotFoundInAPI
+	case http.StatusUnprocessableEntity:
+		var osbErr osbError
+		if json.NewDecoder(resp.Body).Decode(&osbErr) == nil && osbErr.Error == "ConcurrencyError" {
+			return nil, ErrInstanceNotReady
+		}
+		return nil, errors.Errorf("invalid response: %s (code: %d)", osbErr.Description, resp.StatusCode)
+	}
+	return nil, c.buildErrorMessage(resp)
+}
+
+func (c *osbClient) fetchBinding(ctx context.Context, instance *ServiceInstance, path, requestID string) (map[string]string, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, nil, nil, instance, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.buildErrorMessage(resp)
+	}
+	var binding osbBinding
+	err = c.jsonFromResponse(resp, &binding)
+	if err != nil {
+		return nil, err
+	}
+	return osbCredentialsToEnvs(binding.Credentials), nil
+}
+
+func (c *osbClient) unbind(ctx context.Context, instance *ServiceInstance, bindingID string, evt *event.Event, requestID string) error {
+	svc, plan, err := c.catalogPlan(ctx, instance.PlanName, requestID)
+	if err != nil {
+		return err
+	}
+	path := osbInstancePath(instance) + "/service_bindings/" + bindingID
+	query := url.Values{
+		"accepts_incomplete": {"true"},
+		"service_id":         {svc.ID},
+		"plan_id":            {plan.ID},
+	}
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, query, nil, evt, instance, requestID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusAccepted:
+		var op osbOperationResponse
+		err = c.jsonFromResponse(resp, &op)
+		if err != nil {
+			return err
+		}
+		err = c.waitLastOperation(ctx, path, svc.ID, plan.ID, op.Operation, evt, instance, requestID)
+		if err == ErrInstanceNotFoundInAPI {
+			return nil
+		}
+		return err
+	case http.StatusGone:
+		return ErrInstanceNotFoundInAPI
+	}
+	return log.WrapError(errors.Wrapf(c.buildErrorMessage(resp), "Failed to unbind (%q)", path))
+}
+
+// waitLastOperation polls the last_operation endpoint of an instance or a
+// binding until the broker reports the operation as finished.
+func (c *osbClient) waitLastOperation(ctx context.Context, path, serviceID, planID, operation string, evt *event.Event, instance *ServiceInstance, requestID string) error {
+	timeout := time.After(osbPollTimeout)
+	var lastDescription string
+	for {
+		op, err := c.lastOperation(ctx, path, serviceID, planID, operation, instance, requestID)
+		if err != nil {
+			return err
+		}
+		if evt != nil && op.Description != "" && op.Description != lastDescription {
+			fmt.Fprintf(evt, "  ---> %s\n", op.Description)
+			lastDescription = op.Description
+		}
+		switch op.State {
+		case osbStateSucceeded:
+			return nil
+		case osbStateFailed:
+			return errors.Errorf("broker operation on %q failed: %s", path, op.Description)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return errors.Errorf("timeout after %v waiting for broker operation on %q", osbPollTimeout, path)
+		case <-time.After(osbPollInterval):
+		}
+	}
+}
+
+func (c *osbClient) lastOperation(ctx context.Context, path, serviceID, planID, operation string, instance *ServiceInstance, requestID string) (*osbLastOperation, error) {
+	query := url.Values{
+		"service_id": {serviceID},
+		"plan_id":    {planID},
+	}
+	if operation != "" {
+		query.Set("operation", operation)
+	}
+	resp, err := c.doRequest(ctx, http.MethodGet, path+"/last_operation", query, nil, nil, instance, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var op osbLastOperation
+		err = c.jsonFromResponse(resp, &op)
+		if err != nil {
+			return nil, err
+		}
+		return &op, nil
+	case http.StatusNotFound, http.StatusGone:
+		return nil, ErrInstanceNotFoundInAPI
+	}
+	return nil, c.buildErrorMessage(resp)
+}
+
+func (c *osbClient) catalogService(ctx context.Context, requestID string) (*osbCatalogService, error) {
+	catalog, err := c.catalog(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(catalog.Services) == 1 {
+		return &catalog.Services[0], nil
+	}
+	for i, svc := range catalog.Services {
+		if svc.Name == c.serviceName || svc.ID == c.serviceName {
+			return &catalog.Services[i], nil
+		}
+	}
+	return nil, ErrOSBServiceNotInCatalog
+}
+
+func (c *osbClient) catalog(ctx context.Context, requestID string) (*osbCatalog, error) {
+	cacheKey := c.endpoint + "\x00" + c.username + "\x00" + c.password
+	if catalog := osbCatalogs.get(cacheKey); catalog != nil {
+		return catalog, nil
+	}
+	resp, err := c.doRequest(ctx, http.MethodGet, "/v2/catalog", nil, nil, nil, nil, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Wrap(c.buildErrorMessage(resp), "Failed to get the broker catalog")
+	}
+	var catalog osbCatalog
+	err = c.jsonFromResponse(resp, &catalog)
+	if err != nil {
+		return nil, err
+	}
+	osbCatalogs.set(cacheKey, &catalog)
+	return &catalog, nil
+}
+
+func (c *osbClient) catalogPlan(ctx context.Context, planName, requestID string) (*osbCatalogService, *osbCatalogPlan, error) {
+	svc, err := c.catalogService(ctx, requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if planName == "" && len(svc.Plans) == 1 {
+		return svc, &svc.Plans[0], nil
+	}
+	for i, p := range svc.Plans {
+		if p.Name == planName || p.ID == planName {
+			return svc, &svc.Plans[i], nil
+		}
+	}
+	return nil, nil, ErrOSBPlanNotInCatalog
+}
+
+func (c *osbClient) doRequest(ctx context.Context, method, path string, query url.Values, body interface{}, evt *event.Event, instance *ServiceInstance, requestID string) (*http.Response, error) {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	rawURL := strings.TrimRight(c.endpoint, "/") + "/" + strings.Trim(path, "/")
+	if len(query) > 0 {
+		rawURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	header, err := baseHeader(ctx, evt, instance, requestID)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Broker-API-Version", osbAPIVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.SetBasicAuth(c.username, c.password)
+	t0 := time.Now()
+	resp, err := net.Dial15Full300ClientWithPool.Do(req)
+	requestLatencies.WithLabelValues(c.serviceName).Observe(time.Since(t0).Seconds())
+	if err != nil {
+		requestErrors.WithLabelValues(c.serviceName).Inc()
+	}
+	return resp, err
+}
+
+func (c *osbClient) buildErrorMessage(resp *http.Response) error {
+	b, _ := io.ReadAll(resp.Body)
+	var osbErr osbError
+	if json.Unmarshal(b, &osbErr) == nil && osbErr.Description != "" {
+		return errors.Errorf("invalid response: %s (code: %d)", osbErr.Description, resp.StatusCode)
+	}
+	return errors.Errorf("invalid response: %s (code: %d)", string(b), resp.StatusCode)
+}
+
+func (c *osbClient) jsonFromResponse(resp *http.Response, v interface{}) error {
+	err := json.NewDecoder(resp.Body).Decode(v)
+	if err != nil && err != io.EOF {
+		log.Errorf("Got error while parsing broker json: %s", err)
+		return err
+	}
+	return nil
+}
+
+func osbInstancePath(instance *ServiceInstance) string {
+	return "/v2/service_instances/" + osbInstanceID(instance)
+}
+
+// osbInstanceID returns a stable identifier for the service instance on the
+// broker, as the Open Service Broker API expects platform generated GUIDs.
+func osbInstanceID(instance *ServiceInstance) string {
+	return uuid.NewSHA1(osbNamespace, []byte(instance.ServiceName+"/"+instance.Name)).String()
+}
+
+// osbBindingID returns a stable identifier for the bind of an app or job,
+// changing on each credentials rotation.
+func osbBindingID(instance *ServiceInstance, kind, name string) string {
+	id := instance.ServiceName + "/" + instance.Name + "/" + kind + "/" + name
+	if generation := instance.BindGenerations[bindGenerationKey(kind, name)]; generation > 0 {
+		id += "/" + strconv.Itoa(generation)
+	}
+	return uuid.NewSHA1(osbNamespace, []byte(id)).String()
+}
+
+func osbInstanceContext(instance *ServiceInstance) map[string]interface{} {
+	ctx := map[string]interface{}{
+		"platform":      "tsuru",
+		"instance_name": instance.Name,
+		"team_owner":    instance.TeamOwner,
+	}
+	if instance.Pool != "" {
+		ctx["pool"] = instance.Pool
+	}
+	return ctx
+}
+
+// osbCredentialsToEnvs converts binding credentials to environment variables,
+// upper casing their names. Nested values are encoded as JSON.
+func osbCredentialsToEnvs(credentials map[string]interface{}) map[string]string {
+	envs := map[string]string{}
+	for k, v := range osbFlatten(credentials) {
+		name := osbEnvNameRegexp.ReplaceAllString(strings.ToUpper(k), "_")
+		envs[name] = v
+	}
+	return envs
+}
+
+func osbFlatten(values map[string]interface{}) map[string]string {
+	result := map[string]string{}
+	for k, v := range values {
+		switch value := v.(type) {
+		case string:
+			result[k] = value
+		default:
+			data, err := json.Marshal(value)
+			if err != nil {
+				continue
+			}
+			result[k] = string(data)
+		}
+	}
+	return result
+}
@@ -0,0 +1,279 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+const osbCatalogJSON = `{"services": [{
+	"id": "svc-id",
+	"name": "mysql",
+	"bindable": true,
+	"instances_retrievable": true,
+	"plans": [
+		{"id": "small-id", "name": "small", "description": "small plan", "schemas": {
+			"service_instance": {"create": {"parameters": {"type": "object", "properties": {"size": {"type": "integer"}}}}}
+		}},
+		{"id": "large-id", "name": "large", "description": "large plan"}
+	]
+}]}`
+
+type fakeBroker struct {
+	sync.Mutex
+	requests       []string
+	bodies         []map[string]interface{}
+	headers        []http.Header
+	provisionCode  int
+	updateCode     int
+	deleteCode     int
+	lastOperations []string
+	operations     []string
+	bindCode       int
+}
+
+func (b *fakeBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.Lock()
+	defer b.Unlock()
+	b.requests = append(b.requests, r.Method+" "+r.URL.Path)
+	b.headers = append(b.headers, r.Header)
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	b.bodies = append(b.bodies, body)
+	switch {
+	case r.URL.Path == "/v2/catalog":
+		w.Write([]byte(osbCatalogJSON))
+	case len(r.URL.Path) > len("/last_operation") && r.URL.Path[len(r.URL.Path)-len("/last_operation"):] == "/last_operation":
+		b.operations = append(b.operations, r.URL.Query().Get("operation"))
+		state := "succeeded"
+		if len(b.lastOperations) > 0 {
+			state, b.lastOperations = b.lastOperations[0], b.lastOperations[1:]
+		}
+		json.NewEncoder(w).Encode(map[string]string{"state": state, "description": "state is " + state})
+	case r.Method == http.MethodPut && r.URL.Path == osbInstancePath(&ServiceInstance{ServiceName: "mysql", Name: "my-db"}):
+		w.WriteHeader(b.provisionCode)
+		w.Write([]byte(`{"operation": "op-1"}`))
+	case r.Method == http.MethodPut:
+		w.WriteHeader(b.bindCode)
+		w.Write([]byte(`{"credentials": {"uri": "mysql://db:3306", "port": 3306, "user-name": "root"}}`))
+	case r.Method == http.MethodPatch:
+		w.WriteHeader(b.updateCode)
+		w.Write([]byte(`{"operation": "op-2"}`))
+	case r.Method == http.MethodDelete:
+		if b.deleteCode == 0 {
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.WriteHeader(b.deleteCode)
+		w.Write([]byte(`{"operation": "op-3"}`))
+	case r.Method == http.MethodGet:
+		w.Write([]byte(`{"plan_id": "small-id", "dashboard_url": "http://dashboard", "parameters": {"size": 2, "password": "s3cr3t"}}`))
+	}
+}
+
+func (s *S) newOSBClient(b *fakeBroker) (*osbClient, func()) {
+	ts := httptest.NewServer(b)
+	return &osbClient{serviceName: "mysql", endpoint: ts.URL, username: "user", password: "pass"}, ts.Close
+}
+
+func (s *S) TestGetClientOSB(c *check.C) {
+	srv := Service{Name: "mysql", Protocol: ProtocolOSB, Endpoint: map[string]string{"production": "broker.example.com"}, Password: "pass"}
+	cli, err := srv.getClient("production")
+	c.Assert(err, check.IsNil)
+	c.Assert(cli, check.DeepEquals, &osbClient{serviceName: "mysql", endpoint: "http://broker.example.com", username: "mysql", password: "pass"})
+}
+
+func (s *S) TestCreateServiceInvalidProtocol(c *check.C) {
+	srv := Service{Name: "mysql", Protocol: "soap", Endpoint: map[string]string{"production": "broker.example.com"}, Password: "pass", OwnerTeams: []string{s.team.Name}}
+	err := Create(context.TODO(), srv)
+	c.Assert(err, check.ErrorMatches, `Invalid protocol "soap", valid values are: tsuru, osb`)
+}
+
+func (s *S) TestOSBClientPlans(c *check.C) {
+	b := &fakeBroker{}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	plans, err := cli.Plans(context.TODO(), "", "")
+	c.Assert(err, check.IsNil)
+	c.Assert(plans, check.HasLen, 2)
+	c.Assert(plans[0].Name, check.Equals, "small")
+	c.Assert(plans[0].CreateParametersSchema(), check.NotNil)
+	c.Assert(plans[1], check.DeepEquals, Plan{Name: "large", Description: "large plan"})
+	c.Assert(b.headers[0].Get("X-Broker-API-Version"), check.Equals, osbAPIVersion)
+}
+
+func (s *S) TestOSBClientCreate(c *check.C) {
+	b := &fakeBroker{provisionCode: http.StatusCreated}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	instance := ServiceInstance{Name: "my-db", ServiceName: "mysql", PlanName: "small", TeamOwner: "team", Parameters: map[string]interface{}{"size": "2"}}
+	err := cli.Create(context.TODO(), &instance, createEvt(c), "")
+	c.Assert(err, check.IsNil)
+	c.Assert(b.requests, check.DeepEquals, []string{"GET /v2/catalog", "PUT " + osbInstancePath(&instance)})
+	c.Assert(b.bodies[1]["service_id"], check.Equals, "svc-id")
+	c.Assert(b.bodies[1]["plan_id"], check.Equals, "small-id")
+	c.Assert(b.bodies[1]["parameters"], check.DeepEquals, map[string]interface{}{"size": "2"})
+}
+
+func (s *S) TestOSBClientCreateAsync(c *check.C) {
+	b := &fakeBroker{provisionCode: http.StatusAccepted}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	instance := ServiceInstance{Name: "my-db", ServiceName: "mysql", PlanName: "small", TeamOwner: "team"}
+	err := cli.Create(context.TODO(), &instance, createEvt(c), "")
+	c.Assert(err, check.IsNil)
+	c.Assert(instance.ProvisionStatus, check.Equals, ProvisionStatusProvisioning)
+	c.Assert(instance.ProvisionOperation, check.Equals, "op-1")
+	c.Assert(b.requests, check.DeepEquals, []string{"GET /v2/catalog", "PUT " + osbInstancePath(&instance)})
+}
+
+func (s *S) TestOSBClientUpdateAsync(c *check.C) {
+	b := &fakeBroker{updateCode: http.StatusAccepted}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	instance := ServiceInstance{Name: "my-db", ServiceName: "mysql", PlanName: "large", TeamOwner: "team"}
+	err := cli.Update(context.TODO(), &instance, createEvt(c), "")
+	c.Assert(err, check.IsNil)
+	c.Assert(instance.ProvisionStatus, check.Equals, ProvisionStatusUpdating)
+	c.Assert(instance.ProvisionOperation, check.Equals, "op-2")
+	c.Assert(b.requests, check.DeepEquals, []string{"GET /v2/catalog", "PATCH " + osbInstancePath(&instance)})
+}
+
+func (s *S) TestOSBClientDestroyAsync(c *check.C) {
+	b := &fakeBroker{deleteCode: http.StatusAccepted}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	instance := ServiceInstance{Name: "my-db", ServiceName: "mysql", PlanName: "small", TeamOwner: "team"}
+	err := cli.Destroy(context.TODO(), &instance, createEvt(c), "")
+	c.Assert(err, check.IsNil)
+	c.Assert(instance.ProvisionStatus, check.Equals, ProvisionStatusDeprovisioning)
+	c.Assert(instance.ProvisionOperation, check.Equals, "op-3")
+	c.Assert(b.requests, check.DeepEquals, []string{"GET /v2/catalog", "DELETE " + osbInstancePath(&instance)})
+}
+
+func (s *S) TestOSBClientWaitLastOperationFailed(c *check.C) {
+	defer func(interval time.Duration) { osbPollInterval = interval }(osbPollInterval)
+	osbPollInterval = time.Millisecond
+	b := &fakeBroker{lastOperations: []string{"in progress", "failed"}}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	instance := ServiceInstance{Name: "my-db", ServiceName: "mysql", PlanName: "small", TeamOwner: "team"}
+	err := cli.waitLastOperation(context.TODO(), osbInstancePath(&instance), "svc-id", "small-id", "op-1", createEvt(c), &instance, "")
+	c.Assert(err, check.ErrorMatches, `broker operation on ".*" failed: state is failed`)
+	path := osbInstancePath(&instance)
+	c.Assert(b.requests, check.DeepEquals, []string{
+		"GET " + path + "/last_operation",
+		"GET " + path + "/last_operation",
+	})
+}
+
+func (s *S) TestOSBClientCreateUnknownPlan(c *check.C) {
+	b := &fakeBroker{}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	instance := ServiceInstance{Name: "my-db", ServiceName: "mysql", PlanName: "huge", TeamOwner: "team"}
+	err := cli.Create(context.TODO(), &instance, createEvt(c), "")
+	c.Assert(err, check.Equals, ErrOSBPlanNotInCatalog)
+}
+
+func (s *S) TestOSBClientBindApp(c *check.C) {
+	b := &fakeBroker{bindCode: http.StatusCreated}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	instance := ServiceInstance{Name: "my-db", ServiceName: "mysql", PlanName: "small", TeamOwner: "team"}
+	envs, err := cli.BindApp(context.TODO(), &instance, &appTypes.App{Name: "myapp", Pool: "mypool"}, nil, createEvt(c), "")
+	c.Assert(err, check.IsNil)
+	c.Assert(envs, check.DeepEquals, map[string]string{
+		"URI":       "mysql://db:3306",
+		"PORT":      "3306",
+		"USER_NAME": "root",
+	})
+	c.Assert(b.requests[1], check.Equals, "PUT "+osbInstancePath(&instance)+"/service_bindings/"+osbBindingID(&instance, "app", "myapp"))
+	c.Assert(b.bodies[1]["bind_resource"], check.DeepEquals, map[string]interface{}{"app_guid": "myapp"})
+}
+
+func (s *S) TestOSBClientUnbindAppGone(c *check.C) {
+	b := &fakeBroker{}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	instance := ServiceInstance{Name: "my-db", ServiceName: "mysql", PlanName: "small", TeamOwner: "team"}
+	err := cli.UnbindApp(context.TODO(), &instance, &appTypes.App{Name: "myapp"}, createEvt(c), "")
+	c.Assert(err, check.Equals, ErrInstanceNotFoundInAPI)
+}
+
+func (s *S) TestOSBClientStatus(c *check.C) {
+	b := &fakeBroker{lastOperations: []string{"in progress", "failed", "succeeded"}}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	instance := ServiceInstance{Name: "my-db", ServiceName: "mysql", PlanName: "small", TeamOwner: "team", ProvisionStatus: ProvisionStatusProvisioning, ProvisionOperation: "op-1"}
+	for _, expected := range []string{"pending", "down (state is failed)", "up"} {
+		status, err := cli.Status(context.TODO(), &instance, "")
+		c.Assert(err, check.IsNil)
+		c.Assert(status, check.Equals, expected)
+	}
+	c.Assert(b.operations, check.DeepEquals, []string{"op-1", "op-1", "op-1"})
+}
+
+func (s *S) TestOSBClientStatusDeprovisioning(c *check.C) {
+	b := &fakeBroker{lastOperations: []string{"in progress", "failed", "succeeded"}}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	instance := ServiceInstance{Name: "my-db", ServiceName: "mysql", PlanName: "small", TeamOwner: "team", ProvisionStatus: ProvisionStatusDeprovisioning, ProvisionOperation: "op-3"}
+	for _, expected := range []string{"pending", "down (state is failed)", instanceStatusRemoved} {
+		status, err := cli.Status(context.TODO(), &instance, "")
+		c.Assert(err, check.IsNil)
+		c.Assert(status, check.Equals, expected)
+	}
+}
+
+func (s *S) TestOSBClientStatusProvisionedSynchronously(c *check.C) {
+	b := &fakeBroker{}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	instance := ServiceInstance{Name: "my-db", ServiceName: "mysql", PlanName: "small", TeamOwner: "team"}
+	status, err := cli.Status(context.TODO(), &instance, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(status, check.Equals, "up")
+	c.Assert(b.requests, check.DeepEquals, []string{"GET /v2/catalog", "GET " + osbInstancePath(&instance)})
+}
+
+func (s *S) TestOSBClientCatalogCached(c *check.C) {
+	b := &fakeBroker{}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	_, err := cli.Plans(context.TODO(), "", "")
+	c.Assert(err, check.IsNil)
+	_, err = cli.Plans(context.TODO(), "", "")
+	c.Assert(err, check.IsNil)
+	c.Assert(b.requests, check.DeepEquals, []string{"GET /v2/catalog"})
+}
+
+func (s *S) TestOSBClientInfo(c *check.C) {
+	b := &fakeBroker{}
+	cli, stop := s.newOSBClient(b)
+	defer stop()
+	instance := ServiceInstance{Name: "my-db", ServiceName: "mysql", PlanName: "small", TeamOwner: "team"}
+	info, err := cli.Info(context.TODO(), &instance, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(info, check.DeepEquals, []map[string]string{
+		{"label": "Dashboard", "value": "http://dashboard"},
+		{"label": "Plan", "value": "small"},
+	})
+}
+
+func (s *S) TestOSBClientProxyNotSupported(c *check.C) {
+	cli := &osbClient{}
+	err := cli.Proxy(context.TODO(), &ProxyOpts{})
+	c.Assert(err, check.Equals, ErrOSBProxyNotSupported)
+}
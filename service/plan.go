@@ -6,12 +6,15 @@ package service
 
 import (
 	"context"
+
+	"github.com/tsuru/tsuru/log"
 )
 
 // Plan represents a service plan
 type Plan struct {
 	Name        string
 	Description string
+	Schemas     *PlanSchemas `json:"schemas,omitempty"`
 }
 
 func GetPlansByService(ctx context.Context, svc Service, pool, requestID string) ([]Plan, error) {
@@ -38,3 +41,23 @@ func GetPlanByServiceAndPlanName(ctx context.Context, svc Service, pool, planNam
 	}
 	return Plan{}, nil
 }
+
+// validateInstanceParameters validates the parameters of a service instance
+// against the schema published by the service for the instance plan. Services
+// without a plan schema, or failing to list their plans, are not validated
+// here: their API remains responsible for rejecting invalid parameters.
+func validateInstanceParameters(ctx context.Context, svc Service, instance ServiceInstance, update bool, requestID string) error {
+	if instance.PlanName == "" {
+		return nil
+	}
+	plan, err := GetPlanByServiceAndPlanName(ctx, svc, instance.Pool, instance.PlanName, requestID)
+	if err != nil {
+		log.Errorf("[service instance %s/%s] unable to get plans to validate parameters: %v", svc.Name, instance.Name, err)
+		return nil
+	}
+	schema := plan.CreateParametersSchema()
+	if update {
+		schema = plan.UpdateParametersSchema()
+	}
+	return schema.Validate(instance.PlanName, instance.Parameters)
+}
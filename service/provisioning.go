@@ -0,0 +1,296 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/db/storagev2"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	ProvisionStatusProvisioning   = "provisioning"
+	ProvisionStatusUpdating       = "updating"
+	ProvisionStatusDeprovisioning = "deprovisioning"
+	ProvisionStatusReady          = "ready"
+	ProvisionStatusFailed         = "failed"
+)
+
+// instanceStatusRemoved is the status reported by service clients once the
+// asynchronous removal of an instance finishes.
+const instanceStatusRemoved = "removed"
+
+const provisionResumeEventKind = "service-instance-provision-resume"
+
+var (
+	provisioningPollInterval = 10 * time.Second
+	provisioningTimeout      = time.Hour
+	provisionResumeInterval  = time.Minute
+)
+
+var pendingProvisionStatuses = []string{
+	ProvisionStatusProvisioning,
+	ProvisionStatusUpdating,
+	ProvisionStatusDeprovisioning,
+}
+
+// IsProvisioning reports whether the service API is still provisioning,
+// updating or removing the instance.
+func (si *ServiceInstance) IsProvisioning() bool {
+	return isPendingProvisionStatus(si.ProvisionStatus)
+}
+
+func isPendingProvisionStatus(status string) bool {
+	for _, pending := range pendingProvisionStatuses {
+		if status == pending {
+			return true
+		}
+	}
+	return false
+}
+
+func (si *ServiceInstance) checkProvisioned() error {
+	if si.IsProvisioning() {
+		return ErrServiceInstanceProvisioning
+	}
+	switch si.ProvisionStatus {
+	case ProvisionStatusFailed:
+		return si.provisionFailedError()
+	}
+	return nil
+}
+
+func (si *ServiceInstance) provisionFailedError() error {
+	if si.ProvisionError == "" {
+		return ErrServiceInstanceProvisionFailed
+	}
+	return errors.Wrap(ErrServiceInstanceProvisionFailed, si.ProvisionError)
+}
+
+// WaitProvisioning polls the service API status endpoint until it reports the
+// pending provisioning, update or removal of the instance as finished, writing
+// the progress to evt. The event is marked as done once the operation
+// finishes, so callers must hand it over to this method.
+func (si *ServiceInstance) WaitProvisioning(ctx context.Context, evt *event.Event, requestID string) (err error) {
+	defer func() { evt.Done(ctx, err) }()
+	pendingStatus := si.ProvisionStatus
+	fmt.Fprintf(evt, "Waiting for service instance %q to be %s...\n", si.Name, provisionDoneMessage(pendingStatus))
+	timeout := time.After(provisioningTimeout)
+	var lastStatus string
+	for {
+		status, statusErr := si.Status(ctx, requestID)
+		if statusErr != nil {
+			fmt.Fprintf(evt, "  ---> unable to get the instance status: %v\n", statusErr)
+		} else if status != lastStatus {
+			fmt.Fprintf(evt, "  ---> %s\n", status)
+			lastStatus = status
+		}
+		switch si.ProvisionStatus {
+		case ProvisionStatusReady:
+			if si.ProvisionError != "" {
+				return errors.New(si.ProvisionError)
+			}
+			fmt.Fprintf(evt, "Service instance %q is %s.\n", si.Name, provisionDoneMessage(pendingStatus))
+			return nil
+		case ProvisionStatusFailed:
+			return si.provisionFailedError()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			msg := fmt.Sprintf("timeout after %v waiting for the service API", provisioningTimeout)
+			if pendingStatus != ProvisionStatusProvisioning {
+				if setErr := si.setProvisionStatus(ctx, ProvisionStatusReady, msg); setErr != nil {
+					log.Errorf("[service instance %s/%s] unable to set provision status: %v", si.ServiceName, si.Name, setErr)
+				}
+				return errors.New(msg)
+			}
+			if setErr := si.setProvisionStatus(ctx, ProvisionStatusFailed, msg); setErr != nil {
+				log.Errorf("[service instance %s/%s] unable to set provision status: %v", si.ServiceName, si.Name, setErr)
+				si.ProvisionError = msg
+			}
+			return si.provisionFailedError()
+		case <-time.After(provisioningPollInterval):
+		}
+	}
+}
+
+func provisionDoneMessage(pendingStatus string) string {
+	switch pendingStatus {
+	case ProvisionStatusUpdating:
+		return "updated"
+	case ProvisionStatusDeprovisioning:
+		return "removed"
+	}
+	return "ready"
+}
+
+// refreshProvisionStatus updates the provision status of an instance with a
+// pending operation according to the status reported by the service API.
+// Failed updates and removals keep the instance ready, with the failure in
+// its provision error, and finished removals delete the instance.
+func (si *ServiceInstance) refreshProvisionStatus(ctx context.Context, apiStatus string) error {
+	if !si.IsProvisioning() || apiStatus == "pending" {
+		return nil
+	}
+	failed := strings.HasPrefix(apiStatus, "down")
+	switch si.ProvisionStatus {
+	case ProvisionStatusUpdating:
+		if failed {
+			return si.setProvisionStatus(ctx, ProvisionStatusReady, fmt.Sprintf("service API failed to update the instance: %s", apiStatus))
+		}
+	case ProvisionStatusDeprovisioning:
+		if apiStatus == instanceStatusRemoved {
+			err := si.removeData(ctx)
+			if err != nil {
+				return err
+			}
+			si.ProvisionStatus = ProvisionStatusReady
+			si.ProvisionError = ""
+			return nil
+		}
+		if failed {
+			return si.setProvisionStatus(ctx, ProvisionStatusReady, fmt.Sprintf("service API failed to remove the instance: %s", apiStatus))
+		}
+	default:
+		if failed {
+			return si.setProvisionStatus(ctx, ProvisionStatusFailed, fmt.Sprintf("service API reported the instance as %s", apiStatus))
+		}
+	}
+	return si.setProvisionStatus(ctx, ProvisionStatusReady, "")
+}
+
+// startProvisionOperation records an operation accepted asynchronously by the
+// service API, to be tracked by WaitProvisioning.
+func (si *ServiceInstance) startProvisionOperation(ctx context.Context, status, operation string) error {
+	err := si.updateData(ctx, mongoBSON.M{"$set": mongoBSON.M{
+		"provision_status":    status,
+		"provision_error":     "",
+		"provision_operation": operation,
+	}})
+	if err != nil {
+		return err
+	}
+	si.ProvisionStatus = status
+	si.ProvisionError = ""
+	si.ProvisionOperation = operation
+	return nil
+}
+
+func (si *ServiceInstance) setProvisionStatus(ctx context.Context, status, provisionErr string) error {
+	update := mongoBSON.M{"$set": mongoBSON.M{
+		"provision_status": status,
+		"provision_error":  provisionErr,
+	}}
+	if !isPendingProvisionStatus(status) {
+		update["$unset"] = mongoBSON.M{"provision_operation": ""}
+	}
+	err := si.updateData(ctx, update)
+	if err != nil {
+		return err
+	}
+	si.ProvisionStatus = status
+	si.ProvisionError = provisionErr
+	if !isPendingProvisionStatus(status) {
+		si.ProvisionOperation = ""
+	}
+	return nil
+}
+
+// InitializeProvisioningResumer starts periodically resuming the polling of
+// instances whose pending operation is no longer being awaited, e.g. because the
+// API instance waiting for them was restarted.
+func InitializeProvisioningResumer() {
+	r := &provisioningResumer{
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go r.start()
+	shutdown.Register(r)
+}
+
+type provisioningResumer struct {
+	quit chan struct{}
+	done chan struct{}
+}
+
+func (r *provisioningResumer) start() {
+	defer close(r.done)
+	for {
+		err := ResumeProvisioning(context.Background())
+		if err != nil {
+			log.Errorf("[service instance provisioning] unable to resume provisioning: %v", err)
+		}
+		select {
+		case <-r.quit:
+			return
+		case <-time.After(provisionResumeInterval):
+		}
+	}
+}
+
+func (r *provisioningResumer) Shutdown(ctx context.Context) error {
+	close(r.quit)
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// ResumeProvisioning starts waiting for the pending operation of every
+// instance still being provisioned, updated or removed without a running event holding its lock. Instances
+// being awaited by any API instance are skipped, as their event still holds
+// the lock; the locks of events left behind by stopped API instances expire
+// and are released by the event cleaner.
+func ResumeProvisioning(ctx context.Context) error {
+	collection, err := storagev2.ServiceInstancesCollection()
+	if err != nil {
+		return err
+	}
+	cursor, err := collection.Find(ctx, mongoBSON.M{"provision_status": mongoBSON.M{"$in": pendingProvisionStatuses}})
+	if err != nil {
+		return err
+	}
+	var instances []ServiceInstance
+	err = cursor.All(ctx, &instances)
+	if err != nil {
+		return err
+	}
+	multi := tsuruErrors.NewMultiError()
+	for i := range instances {
+		si := &instances[i]
+		permValue := si.ServiceName + "/" + si.Name
+		evt, err := event.NewInternal(ctx, &event.Opts{
+			Target:       eventTypes.Target{Type: eventTypes.TargetTypeServiceInstance, Value: permValue},
+			InternalKind: provisionResumeEventKind,
+			Allowed: event.Allowed(permission.PermServiceInstanceReadEvents,
+				append(permission.Contexts(permTypes.CtxTeam, si.Teams),
+					permission.Context(permTypes.CtxServiceInstance, permValue))...),
+		})
+		if err != nil {
+			if _, isLocked := err.(event.ErrEventLocked); !isLocked {
+				multi.Add(err)
+			}
+			continue
+		}
+		go si.WaitProvisioning(ctx, evt, "")
+	}
+	return multi.ToError()
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/tsuru/tsuru/db/storagev2"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	mongoBSON "go.mongodb.org/mongo-driver/bson"
+	check "gopkg.in/check.v1"
+)
+
+func (s *InstanceSuite) TestCreateAcceptedMarksInstanceAsProvisioning(c *check.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+	srv := Service{Name: "mongodb", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t", OwnerTeams: []string{s.team.Name}}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	instance := ServiceInstance{Name: "instance", ServiceName: srv.Name, TeamOwner: s.team.Name}
+	err = CreateServiceInstance(context.TODO(), instance, &srv, createEvt(c), "")
+	c.Assert(err, check.IsNil)
+	si, err := GetServiceInstance(context.TODO(), srv.Name, instance.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(si.ProvisionStatus, check.Equals, ProvisionStatusProvisioning)
+	c.Assert(si.IsProvisioning(), check.Equals, true)
+}
+
+func (s *InstanceSuite) TestWaitProvisioning(c *check.C) {
+	defer func(interval time.Duration) { provisioningPollInterval = interval }(provisioningPollInterval)
+	provisioningPollInterval = time.Millisecond
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	srv := Service{Name: "mongodb", Endpoint: map[string]string{"production": ts.URL}}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	si := ServiceInstance{Name: "instance", ServiceName: srv.Name, ProvisionStatus: ProvisionStatusProvisioning}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &si)
+	c.Assert(err, check.IsNil)
+	err = si.WaitProvisioning(context.TODO(), createEvt(c), "")
+	c.Assert(err, check.IsNil)
+	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(3))
+	var dbInstance ServiceInstance
+	err = serviceInstancesCollection.FindOne(context.TODO(), mongoBSON.M{"name": si.Name}).Decode(&dbInstance)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbInstance.ProvisionStatus, check.Equals, ProvisionStatusReady)
+}
+
+func (s *InstanceSuite) TestWaitProvisioningFailed(c *check.C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	srv := Service{Name: "mongodb", Endpoint: map[string]string{"production": ts.URL}}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	si := ServiceInstance{Name: "instance", ServiceName: srv.Name, ProvisionStatus: ProvisionStatusProvisioning}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &si)
+	c.Assert(err, check.IsNil)
+	err = si.WaitProvisioning(context.TODO(), createEvt(c), "")
+	c.Assert(err, check.ErrorMatches, "service API reported the instance as down: service instance provisioning failed")
+	var dbInstance ServiceInstance
+	err = serviceInstancesCollection.FindOne(context.TODO(), mongoBSON.M{"name": si.Name}).Decode(&dbInstance)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbInstance.ProvisionStatus, check.Equals, ProvisionStatusFailed)
+}
+
+func (s *InstanceSuite) TestWaitProvisioningRemoval(c *check.C) {
+	defer func(interval time.Duration) { provisioningPollInterval = interval }(provisioningPollInterval)
+	provisioningPollInterval = time.Millisecond
+	b := &fakeBroker{lastOperations: []string{"in progress", "succeeded"}}
+	ts := httptest.NewServer(b)
+	defer ts.Close()
+	srv := Service{Name: "mysql", Protocol: ProtocolOSB, Endpoint: map[string]string{"production": ts.URL}, Password: "pass"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	si := ServiceInstance{Name: "my-db", ServiceName: srv.Name, PlanName: "small", ProvisionStatus: ProvisionStatusDeprovisioning, ProvisionOperation: "op-3"}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &si)
+	c.Assert(err, check.IsNil)
+	err = si.WaitProvisioning(context.TODO(), createEvt(c), "")
+	c.Assert(err, check.IsNil)
+	c.Assert(b.operations, check.DeepEquals, []string{"op-3", "op-3"})
+	_, err = GetServiceInstance(context.TODO(), srv.Name, si.Name)
+	c.Assert(err, check.Equals, ErrServiceInstanceNotFound)
+}
+
+func (s *InstanceSuite) TestWaitProvisioningUpdateFailed(c *check.C) {
+	defer func(interval time.Duration) { provisioningPollInterval = interval }(provisioningPollInterval)
+	provisioningPollInterval = time.Millisecond
+	b := &fakeBroker{lastOperations: []string{"failed"}}
+	ts := httptest.NewServer(b)
+	defer ts.Close()
+	srv := Service{Name: "mysql", Protocol: ProtocolOSB, Endpoint: map[string]string{"production": ts.URL}, Password: "pass"}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	si := ServiceInstance{Name: "my-db", ServiceName: srv.Name, PlanName: "small", ProvisionStatus: ProvisionStatusUpdating, ProvisionOperation: "op-2"}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &si)
+	c.Assert(err, check.IsNil)
+	err = si.WaitProvisioning(context.TODO(), createEvt(c), "")
+	c.Assert(err, check.ErrorMatches, `service API failed to update the instance: down \(state is failed\)`)
+	dbInstance, err := GetServiceInstance(context.TODO(), srv.Name, si.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbInstance.ProvisionStatus, check.Equals, ProvisionStatusReady)
+	c.Assert(dbInstance.ProvisionOperation, check.Equals, "")
+}
+
+func (s *InstanceSuite) TestResumeProvisioning(c *check.C) {
+	defer func(interval time.Duration) { provisioningPollInterval = interval }(provisioningPollInterval)
+	provisioningPollInterval = time.Millisecond
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	srv := Service{Name: "mongodb", Endpoint: map[string]string{"production": ts.URL}}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	si := ServiceInstance{Name: "instance", ServiceName: srv.Name, ProvisionStatus: ProvisionStatusProvisioning}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &si)
+	c.Assert(err, check.IsNil)
+	err = ResumeProvisioning(context.TODO())
+	c.Assert(err, check.IsNil)
+	timeout := time.After(5 * time.Second)
+	for {
+		dbInstance, err := GetServiceInstance(context.TODO(), srv.Name, si.Name)
+		c.Assert(err, check.IsNil)
+		if dbInstance.ProvisionStatus == ProvisionStatusReady {
+			break
+		}
+		select {
+		case <-timeout:
+			c.Fatal("timeout waiting for the provisioning to be resumed")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func (s *InstanceSuite) TestResumeProvisioningSkipsInstancesBeingAwaited(c *check.C) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+	srv := Service{Name: "mongodb", Endpoint: map[string]string{"production": ts.URL}}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	si := ServiceInstance{Name: "instance", ServiceName: srv.Name, ProvisionStatus: ProvisionStatusProvisioning}
+	serviceInstancesCollection, err := storagev2.ServiceInstancesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &si)
+	c.Assert(err, check.IsNil)
+	evt, err := event.NewInternal(context.TODO(), &event.Opts{
+		Target:       eventTypes.Target{Type: eventTypes.TargetTypeServiceInstance, Value: "mongodb/instance"},
+		InternalKind: "service-instance.create",
+		Allowed:      event.Allowed(permission.PermServiceInstanceReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	defer evt.Done(context.TODO(), nil)
+	err = ResumeProvisioning(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(0))
+}
+
+func (s *InstanceSuite) TestBindAppWhileProvisioning(c *check.C) {
+	si := ServiceInstance{Name: "instance", ServiceName: "mongodb", ProvisionStatus: ProvisionStatusProvisioning}
+	err := si.BindApp(context.TODO(), &appTypes.App{Name: "myapp"}, nil, true, nil, createEvt(c), "")
+	c.Assert(err, check.Equals, ErrServiceInstanceProvisioning)
+}
@@ -0,0 +1,254 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PlanSchemas holds the JSON Schemas published by a service for a plan. The
+// layout follows the one used by the Open Service Broker API, so services
+// may return the same document to both protocols.
+type PlanSchemas struct {
+	ServiceInstance *ServiceInstanceSchemas `json:"service_instance,omitempty"`
+}
+
+// ServiceInstanceSchemas holds the schemas used to validate the parameters
+// of service instance operations.
+type ServiceInstanceSchemas struct {
+	Create *ParametersSchema `json:"create,omitempty"`
+	Update *ParametersSchema `json:"update,omitempty"`
+}
+
+type ParametersSchema struct {
+	Parameters *Schema `json:"parameters,omitempty"`
+}
+
+// Schema is the subset of JSON Schema understood by tsuru when validating
+// service instance parameters. Keywords not listed here are kept out of the
+// validation and are ignored.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+}
+
+// ParameterError describes a single parameter that does not match the plan
+// schema.
+type ParameterError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// InvalidParametersError is returned when service instance parameters do not
+// match the schema published by the service for the plan.
+type InvalidParametersError struct {
+	Plan   string
+	Errors []ParameterError
+}
+
+func (e *InvalidParametersError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "invalid parameters for plan %q:", e.Plan)
+	for _, fieldErr := range e.Errors {
+		fmt.Fprintf(&sb, "\n  - %s: %s", fieldErr.Field, fieldErr.Message)
+	}
+	return sb.String()
+}
+
+// CreateParametersSchema returns the schema used to validate parameters when
+// creating a service instance, or nil when the plan doesn't publish one.
+func (p *Plan) CreateParametersSchema() *Schema {
+	if p.Schemas == nil || p.Schemas.ServiceInstance == nil || p.Schemas.ServiceInstance.Create == nil {
+		return nil
+	}
+	return p.Schemas.ServiceInstance.Create.Parameters
+}
+
+// UpdateParametersSchema returns the schema used to validate parameters when
+// updating a service instance, or nil when the plan doesn't publish one.
+func (p *Plan) UpdateParametersSchema() *Schema {
+	if p.Schemas == nil || p.Schemas.ServiceInstance == nil || p.Schemas.ServiceInstance.Update == nil {
+		return nil
+	}
+	return p.Schemas.ServiceInstance.Update.Parameters
+}
+
+// Validate checks params against the schema, returning an
+// InvalidParametersError listing every field that does not match it.
+//
+// Parameters sent as form values arrive as strings, so strings are accepted
+// for numeric and boolean types as long as they can be parsed as such.
+func (s *Schema) Validate(plan string, params map[string]interface{}) error {
+	if s == nil {
+		return nil
+	}
+	var value interface{} = params
+	if params == nil {
+		value = map[string]interface{}{}
+	}
+	var errs []ParameterError
+	s.validate("parameters", value, &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return &InvalidParametersError{Plan: plan, Errors: errs}
+}
+
+func (s *Schema) validate(field string, value interface{}, errs *[]ParameterError) {
+	addErr := func(format string, args ...interface{}) {
+		*errs = append(*errs, ParameterError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		addErr("must be one of %s", formatEnum(s.Enum))
+		return
+	}
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			addErr("must be an object")
+			return
+		}
+		s.validateObject(field, obj, errs)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			addErr("must be an array")
+			return
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				s.Items.validate(fmt.Sprintf("%s[%d]", field, i), item, errs)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			addErr("must be a string")
+			return
+		}
+		if s.MinLength != nil && len(str) < *s.MinLength {
+			addErr("must have at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && len(str) > *s.MaxLength {
+			addErr("must have at most %d characters", *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			re, err := regexp.Compile(s.Pattern)
+			if err == nil && !re.MatchString(str) {
+				addErr("must match pattern %q", s.Pattern)
+			}
+		}
+	case "integer", "number":
+		n, ok := toNumber(value)
+		if !ok || (s.Type == "integer" && n != math.Trunc(n)) {
+			addErr("must be of type %s", s.Type)
+			return
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			addErr("must be greater than or equal to %v", *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			addErr("must be less than or equal to %v", *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := toBool(value); !ok {
+			addErr("must be a boolean")
+		}
+	default:
+		if obj, ok := value.(map[string]interface{}); ok && len(s.Properties) > 0 {
+			s.validateObject(field, obj, errs)
+		}
+	}
+}
+
+func (s *Schema) validateObject(field string, obj map[string]interface{}, errs *[]ParameterError) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, ParameterError{Field: field + "." + name, Message: "is required"})
+		}
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		propSchema, ok := s.Properties[k]
+		if !ok {
+			if allowed, isBool := s.AdditionalProperties.(bool); isBool && !allowed {
+				*errs = append(*errs, ParameterError{Field: field + "." + k, Message: "is not allowed"})
+			}
+			continue
+		}
+		if propSchema != nil {
+			propSchema.validate(field+"."+k, obj[k], errs)
+		}
+	}
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func toBool(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	}
+	return false, false
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	str := fmt.Sprint(value)
+	for _, e := range enum {
+		if fmt.Sprint(e) == str {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprintf("%q", fmt.Sprint(e))
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/tsuru/db/storagev2"
+	check "gopkg.in/check.v1"
+)
+
+const planSchemasJSON = `[{
+	"name": "small",
+	"description": "small plan",
+	"schemas": {
+		"service_instance": {
+			"create": {
+				"parameters": {
+					"type": "object",
+					"required": ["size"],
+					"additionalProperties": false,
+					"properties": {
+						"size": {"type": "integer", "minimum": 1, "maximum": 10},
+						"engine": {"type": "string", "enum": ["mysql", "postgres"]},
+						"backup": {"type": "boolean"}
+					}
+				}
+			},
+			"update": {
+				"parameters": {
+					"type": "object",
+					"properties": {
+						"size": {"type": "integer", "minimum": 1}
+					}
+				}
+			}
+		}
+	}
+}]`
+
+func (s *S) TestSchemaValidate(c *check.C) {
+	var plans []Plan
+	err := json.Unmarshal([]byte(planSchemasJSON), &plans)
+	c.Assert(err, check.IsNil)
+	schema := plans[0].CreateParametersSchema()
+	c.Assert(schema, check.NotNil)
+	err = schema.Validate("small", map[string]interface{}{"size": "3", "engine": "mysql", "backup": "true"})
+	c.Assert(err, check.IsNil)
+	err = schema.Validate("small", map[string]interface{}{"size": float64(3)})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestSchemaValidateFieldErrors(c *check.C) {
+	var plans []Plan
+	err := json.Unmarshal([]byte(planSchemasJSON), &plans)
+	c.Assert(err, check.IsNil)
+	err = plans[0].CreateParametersSchema().Validate("small", map[string]interface{}{
+		"size":   "30",
+		"engine": "oracle",
+		"backup": "maybe",
+		"extra":  "x",
+	})
+	c.Assert(err, check.FitsTypeOf, &InvalidParametersError{})
+	c.Assert(err.(*InvalidParametersError).Errors, check.DeepEquals, []ParameterError{
+		{Field: "parameters.backup", Message: "must be a boolean"},
+		{Field: "parameters.engine", Message: `must be one of "mysql", "postgres"`},
+		{Field: "parameters.extra", Message: "is not allowed"},
+		{Field: "parameters.size", Message: "must be less than or equal to 10"},
+	})
+	err = plans[0].CreateParametersSchema().Validate("small", nil)
+	c.Assert(err, check.ErrorMatches, `(?s)invalid parameters for plan "small":.*parameters.size: is required`)
+}
+
+func (s *S) TestSchemaValidateNilSchema(c *check.C) {
+	plan := Plan{Name: "small"}
+	c.Assert(plan.CreateParametersSchema(), check.IsNil)
+	c.Assert(plan.UpdateParametersSchema(), check.IsNil)
+	c.Assert(plan.CreateParametersSchema().Validate("small", map[string]interface{}{"a": "b"}), check.IsNil)
+}
+
+func (s *S) TestCreateServiceInstanceValidatesParameters(c *check.C) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/resources/plans" {
+			w.Write([]byte(planSchemasJSON))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+	srv := Service{Name: "mongodb", Endpoint: map[string]string{"production": ts.URL}, Password: "s3cr3t", OwnerTeams: []string{s.team.Name}}
+	servicesCollection, err := storagev2.ServicesCollection()
+	c.Assert(err, check.IsNil)
+	_, err = servicesCollection.InsertOne(context.TODO(), &srv)
+	c.Assert(err, check.IsNil)
+	instance := ServiceInstance{Name: "instance", PlanName: "small", TeamOwner: s.team.Name, Parameters: map[string]interface{}{"size": "0"}}
+	evt := createEvt(c)
+	err = CreateServiceInstance(context.TODO(), instance, &srv, evt, "")
+	c.Assert(err, check.FitsTypeOf, &InvalidParametersError{})
+	c.Assert(requests, check.DeepEquals, []string{"GET /resources/plans"})
+	instance.Parameters = map[string]interface{}{"size": "2"}
+	err = CreateServiceInstance(context.TODO(), instance, &srv, evt, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(requests, check.DeepEquals, []string{"GET /resources/plans", "GET /resources/plans", "POST /resources"})
+}
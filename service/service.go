@@ -39,8 +39,18 @@ type Service struct {
 	//
 	// This field is immutable (after creating Service).
 	IsMultiCluster bool `bson:"is_multi_cluster"`
+	// Protocol is the API spoken by the service endpoints. It defaults to
+	// ProtocolTsuru, the tsuru service API.
+	Protocol string `bson:"protocol,omitempty"`
 }
 
+const (
+	// ProtocolTsuru is the tsuru service API protocol.
+	ProtocolTsuru = "tsuru"
+	// ProtocolOSB is the Open Service Broker API v2 protocol.
+	ProtocolOSB = "osb"
+)
+
 type BindAppParameters map[string]interface{}
 
 type ProxyOpts struct {
@@ -325,6 +335,9 @@ func (s *Service) getClient(endpoints ...string) (ServiceClient, error) {
 			if p := schemeRegexp.MatchString(e); !p {
 				e = "http://" + e
 			}
+			if s.Protocol == ProtocolOSB {
+				return &osbClient{serviceName: s.Name, endpoint: e, username: s.getUsername(), password: s.Password}, nil
+			}
 			cli := &endpointClient{serviceName: s.Name, endpoint: e, username: s.getUsername(), password: s.Password}
 			return cli, nil
 		} else {
@@ -354,6 +367,9 @@ func (s *Service) validate(ctx context.Context, skipName bool) (err error) {
 	if len(s.Endpoint) == 0 {
 		return fmt.Errorf("At least one endpoint is required")
 	}
+	if s.Protocol != "" && s.Protocol != ProtocolTsuru && s.Protocol != ProtocolOSB {
+		return fmt.Errorf("Invalid protocol %q, valid values are: %s, %s", s.Protocol, ProtocolTsuru, ProtocolOSB)
+	}
 	return s.validateOwnerTeams(ctx)
 }
 
@@ -46,6 +46,13 @@ var (
 	ErrRegularServiceInstanceCannotBelongToPool = errors.New("regular (non-multi-cluster) service instance cannot belong to a pool")
 	ErrRevokeInstanceTeamOwnerAccess            = errors.New("cannot revoke the instance's team owner access")
 	ErrInvalidProxyPath                         = errors.New("invalid proxy path")
+	ErrServiceInstanceProvisioning              = errors.New("service instance is still being provisioned")
+	ErrServiceInstanceProvisionFailed           = errors.New("service instance provisioning failed")
+	ErrInvalidAccessLevel                       = errors.New("invalid access level, valid values are: read, bind, admin")
+	ErrTeamOwnerAccessLevel                     = errors.New("cannot change the access level of the instance's team owner")
+	ErrInvalidResourceAccessLevel               = errors.New("invalid access level, valid values are: read, bind")
+	ErrAccessNotAllowsBind                      = errors.New("read-only access to this service instance does not allow binding")
+	ErrBindAccessNotGranted                     = errors.New("neither the team owner nor the app or job were granted access to this service instance")
 	instanceNameRegexp                          = regexp.MustCompile(`^[A-Za-z][-a-zA-Z0-9_]+$`)
 )
 
@@ -68,6 +75,28 @@ type ServiceInstance struct {
 	// NOTE: after the service instance is created, this field turns immutable.
 	Pool string `json:"pool,omitempty"`
 
+	// ProvisionStatus tracks instances whose creation, update or removal was
+	// accepted asynchronously by the service API. An empty value means the
+	// instance was provisioned synchronously and is ready.
+	ProvisionStatus string `bson:"provision_status,omitempty" json:"provision_status,omitempty"`
+	ProvisionError  string `bson:"provision_error,omitempty" json:"provision_error,omitempty"`
+	// ProvisionOperation is the operation id returned by the service API
+	// when accepting the pending operation, sent back when polling its status.
+	ProvisionOperation string `bson:"provision_operation,omitempty" json:"-"`
+
+	// BindGenerations counts the credential rotations of each bind, keyed by
+	// "app/<name>" or "job/<name>", for service APIs that identify each
+	// generation of a bind separately.
+	BindGenerations map[string]int `bson:"bind_generations,omitempty" json:"-"`
+
+	// TeamAccess holds the access level of each granted team. Teams without
+	// an entry, as well as the team owner, have admin access.
+	TeamAccess map[string]string `bson:"team_access,omitempty" json:"team_access,omitempty"`
+	// AppAccess and JobAccess hold the access level of apps and jobs granted
+	// individually, overriding the access of the team owning them.
+	AppAccess map[string]string `bson:"app_access,omitempty" json:"app_access,omitempty"`
+	JobAccess map[string]string `bson:"job_access,omitempty" json:"job_access,omitempty"`
+
 	// ForceRemove indicates whether service instance should be removed even the
 	// related call to service API fails.
 	ForceRemove bool `bson:"-" json:"-"`
@@ -92,12 +121,19 @@ func DeleteInstance(ctx context.Context, si *ServiceInstance, evt *event.Event,
 			return err
 		}
 		fmt.Fprintf(evt, "could not delete the service instance on service api: %v. ignoring this error due to force removal...\n", err)
+	} else if si.ProvisionStatus == ProvisionStatusDeprovisioning {
+		// the instance is deleted by WaitProvisioning once the service API
+		// reports the removal as finished.
+		return si.startProvisionOperation(ctx, si.ProvisionStatus, si.ProvisionOperation)
 	}
+	return si.removeData(ctx)
+}
+
+func (si *ServiceInstance) removeData(ctx context.Context) error {
 	collection, err := storagev2.ServiceInstancesCollection()
 	if err != nil {
 		return err
 	}
-
 	_, err = collection.DeleteOne(ctx, mongoBSON.M{"name": si.Name, "service_name": si.ServiceName})
 	return err
 }
@@ -193,6 +229,10 @@ func (si *ServiceInstance) Update(ctx context.Context, service Service, updateDa
 	} else {
 		updateData.Tags = tags
 	}
+	err = validateInstanceParameters(ctx, service, updateData, true, requestID)
+	if err != nil {
+		return err
+	}
 	actions := []*action.Action{&updateServiceInstance, &notifyUpdateServiceInstance}
 	pipeline := action.NewPipeline(actions...)
 	return pipeline.Execute(ctx, service, *si, updateData, evt, requestID)
@@ -209,6 +249,9 @@ func (si *ServiceInstance) updateData(ctx context.Context, update mongoBSON.M) e
 
 // BindApp makes the bind between the service instance and an app.
 func (si *ServiceInstance) BindApp(ctx context.Context, app *appTypes.App, params BindAppParameters, shouldRestart bool, writer io.Writer, evt *event.Event, requestID string) error {
+	if err := si.checkProvisioned(); err != nil {
+		return err
+	}
 	args := bindAppPipelineArgs{
 		serviceInstance: si,
 		app:             app,
@@ -229,6 +272,9 @@ func (si *ServiceInstance) BindApp(ctx context.Context, app *appTypes.App, param
 
 // BindJob makes the bind between the service instance and a job.
 func (si *ServiceInstance) BindJob(ctx context.Context, job *jobTypes.Job, writer io.Writer, evt *event.Event, requestID string) error {
+	if err := si.checkProvisioned(); err != nil {
+		return err
+	}
 	args := bindJobPipelineArgs{
 		serviceInstance: si,
 		job:             job,
@@ -317,15 +363,41 @@ func (si *ServiceInstance) Status(ctx context.Context, requestID string) (string
 	if err != nil {
 		return "", err
 	}
-	return endpoint.Status(ctx, si, requestID)
+	status, err := endpoint.Status(ctx, si, requestID)
+	if err != nil {
+		return "", err
+	}
+	err = si.refreshProvisionStatus(ctx, status)
+	if err != nil {
+		return "", err
+	}
+	return status, nil
 }
 
-func (si *ServiceInstance) Grant(ctx context.Context, teamName string) error {
+// Grant gives the team access to the service instance. An empty access level
+// is the same as AccessLevelAdmin.
+func (si *ServiceInstance) Grant(ctx context.Context, teamName, accessLevel string) error {
+	if accessLevel == "" {
+		accessLevel = AccessLevelAdmin
+	}
+	if !isValidAccessLevel(accessLevel) {
+		return ErrInvalidAccessLevel
+	}
+	if teamName == si.TeamOwner && accessLevel != AccessLevelAdmin {
+		return ErrTeamOwnerAccessLevel
+	}
 	team, err := servicemanager.Team.FindByName(ctx, teamName)
 	if err != nil {
 		return err
 	}
-	return si.updateData(ctx, mongoBSON.M{"$addToSet": mongoBSON.M{"teams": team.Name}})
+	accessKey := "team_access." + team.Name
+	update := mongoBSON.M{"$addToSet": mongoBSON.M{"teams": team.Name}}
+	if accessLevel == AccessLevelAdmin {
+		update["$unset"] = mongoBSON.M{accessKey: ""}
+	} else {
+		update["$set"] = mongoBSON.M{accessKey: accessLevel}
+	}
+	return si.updateData(ctx, update)
 }
 
 func (si *ServiceInstance) Revoke(ctx context.Context, teamName string) error {
@@ -336,7 +408,10 @@ func (si *ServiceInstance) Revoke(ctx context.Context, teamName string) error {
 	if err != nil {
 		return err
 	}
-	return si.updateData(ctx, mongoBSON.M{"$pull": mongoBSON.M{"teams": team.Name}})
+	return si.updateData(ctx, mongoBSON.M{
+		"$pull":  mongoBSON.M{"teams": team.Name},
+		"$unset": mongoBSON.M{"team_access." + team.Name: ""},
+	})
 }
 
 func genericServiceInstancesFilter(services interface{}, teams []string) mongoBSON.M {
@@ -404,6 +479,10 @@ func CreateServiceInstance(ctx context.Context, instance ServiceInstance, servic
 	instance.ServiceName = service.Name
 	instance.Teams = []string{instance.TeamOwner}
 	instance.Tags = processTags(instance.Tags)
+	err = validateInstanceParameters(ctx, *service, instance, false, requestID)
+	if err != nil {
+		return err
+	}
 	actions := []*action.Action{&notifyCreateServiceInstance, &createServiceInstance}
 	pipeline := action.NewPipeline(actions...)
 	return pipeline.Execute(ctx, *service, &instance, evt, requestID)
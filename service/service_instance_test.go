@@ -1388,7 +1388,7 @@ func (s *InstanceSuite) TestGrantTeamToInstance(c *check.C) {
 	c.Assert(err, check.IsNil)
 	_, err = serviceInstancesCollection.InsertOne(context.TODO(), &sInstance)
 	c.Assert(err, check.IsNil)
-	sInstance.Grant(context.TODO(), team.Name)
+	sInstance.Grant(context.TODO(), team.Name, "")
 	si, err := GetServiceInstance(context.TODO(), "mysql", "j4sql")
 	c.Assert(err, check.IsNil)
 	c.Assert(si.Teams, check.DeepEquals, []string{"test2"})
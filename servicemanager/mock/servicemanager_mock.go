@@ -114,6 +114,8 @@ func (m *MockService) ResetTeam() {
 	m.Team.OnList = nil
 	m.Team.OnRemove = nil
 	m.Team.OnFindByNames = nil
+	m.Team.OnSetParent = nil
+	m.Team.OnHierarchy = nil
 }
 
 func (m *MockService) ResetUserQuota() {
@@ -29,6 +29,7 @@ var (
 	TeamToken       auth.TeamTokenService
 	Job             job.JobService
 	Webhook         event.WebhookService
+	Audit           event.AuditService
 	AppQuota        quota.QuotaService[*app.App]
 	UserQuota       quota.LegacyQuotaService
 	TeamQuota       quota.QuotaService[*auth.Team]
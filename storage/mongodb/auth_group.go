@@ -76,6 +76,39 @@ func (s *authGroupStorage) RemoveRole(ctx context.Context, name, roleName, conte
 	return err
 }
 
+func (s *authGroupStorage) SetRoles(ctx context.Context, name string, roles []auth.RoleInstance) error {
+	if name == "" {
+		return errAuthGroupNameEmpty
+	}
+	collection, err := storagev2.AuthGroupsCollection()
+	if err != nil {
+		return err
+	}
+	bsonRoles := make([]mongoBSON.D, len(roles))
+	for i, role := range roles {
+		bsonRoles[i] = roleToBson(role)
+	}
+	_, err = collection.UpdateOne(ctx, mongoBSON.M{"name": name}, mongoBSON.M{
+		"$set": mongoBSON.M{"roles": bsonRoles},
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (s *authGroupStorage) Delete(ctx context.Context, name string) error {
+	collection, err := storagev2.AuthGroupsCollection()
+	if err != nil {
+		return err
+	}
+	result, err := collection.DeleteOne(ctx, mongoBSON.M{"name": name})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return auth.ErrGroupNotFound
+	}
+	return nil
+}
+
 func roleToBson(ri auth.RoleInstance) mongoBSON.D {
 	// Order matters in $addToSet, that's why bson.D is used instead
 	// of bson.M.
@@ -32,6 +32,8 @@ type cluster struct {
 	Default     bool
 	KubeConfig  *provision.KubeConfig `bson:",omitempty"`
 	HTTPProxy   string                `json:"httpProxy,omitempty"`
+
+	CredentialsExpiry []provision.ClusterCredentialExpiry `bson:"-"`
 }
 
 func (s *clusterStorage) Upsert(ctx context.Context, c provision.Cluster) error {
@@ -20,10 +20,12 @@ type TeamStorage struct{}
 var _ auth.TeamStorage = &TeamStorage{}
 
 type team struct {
-	Name         string `bson:"_id"`
-	CreatingUser string
-	Tags         []string
-	Quota        quota.Quota
+	Name             string `bson:"_id"`
+	CreatingUser     string
+	Tags             []string
+	Quota            quota.Quota
+	Parent           string `bson:",omitempty"`
+	ShareParentQuota bool   `bson:",omitempty"`
 }
 
 func (s *TeamStorage) Insert(ctx context.Context, t auth.Team) error {
@@ -97,6 +99,13 @@ func (s *TeamStorage) FindByNames(ctx context.Context, names []string) ([]auth.T
 	return s.findByQuery(ctx, query)
 }
 
+func (s *TeamStorage) FindWithParent(ctx context.Context) ([]auth.Team, error) {
+	// Matches non-empty strings only, the same filter of the partial index
+	// on parent, so the index is used.
+	query := mongoBSON.M{"parent": mongoBSON.M{"$gt": ""}}
+	return s.findByQuery(ctx, query)
+}
+
 func (s *TeamStorage) findByQuery(ctx context.Context, query mongoBSON.M) ([]auth.Team, error) {
 	var teams []team
 	collection, err := storagev2.TeamsCollection()
@@ -12,6 +12,7 @@ import (
 	"github.com/tsuru/tsuru/types/auth"
 	mongoBSON "go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type teamTokenStorage struct{}
@@ -160,6 +161,39 @@ func (s *teamTokenStorage) Update(ctx context.Context, token auth.TeamToken) err
 	return nil
 }
 
+func (s *teamTokenStorage) PullExpiredRoles(ctx context.Context, tokenID string, now time.Time) ([]auth.RoleInstance, error) {
+	collection, err := storagev2.TeamTokensCollection()
+	if err != nil {
+		return nil, err
+	}
+	span := newMongoDBSpan(ctx, mongoSpanUpdate, collection.Name())
+	defer span.Finish()
+
+	var previous teamToken
+	err = collection.FindOneAndUpdate(ctx, mongoBSON.M{
+		"token_id":        tokenID,
+		"roles.expiresat": mongoBSON.M{"$lte": now},
+	}, mongoBSON.M{
+		"$pull": mongoBSON.M{
+			"roles": mongoBSON.M{"expiresat": mongoBSON.M{"$lte": now}},
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.Before)).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	var removed []auth.RoleInstance
+	for _, r := range previous.Roles {
+		if r.Expired(now) {
+			removed = append(removed, r)
+		}
+	}
+	return removed, nil
+}
+
 func (s *teamTokenStorage) Delete(ctx context.Context, token string) error {
 	collection, err := storagev2.TeamTokensCollection()
 	if err != nil {
@@ -97,3 +97,49 @@ func (s *AuthGroupSuite) TestList(c *check.C) {
 	c.Assert(groups, check.HasLen, 1)
 	c.Assert(groups[0].Name, check.Equals, "g2")
 }
+
+func (s *AuthGroupSuite) TestSetRoles(c *check.C) {
+	err := s.AuthGroupStorage.AddRole(context.TODO(), "g1", "r1", "v1")
+	c.Assert(err, check.IsNil)
+	err = s.AuthGroupStorage.SetRoles(context.TODO(), "g1", []auth.RoleInstance{
+		{Name: "r2", ContextValue: "v1"},
+		{Name: "r2", ContextValue: "v2"},
+	})
+	c.Assert(err, check.IsNil)
+	err = s.AuthGroupStorage.SetRoles(context.TODO(), "g2", []auth.RoleInstance{
+		{Name: "r1", ContextValue: "v1"},
+	})
+	c.Assert(err, check.IsNil)
+	groups, err := s.AuthGroupStorage.List(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.DeepEquals, []auth.Group{
+		{
+			Name: "g1",
+			Roles: []auth.RoleInstance{
+				{Name: "r2", ContextValue: "v1"},
+				{Name: "r2", ContextValue: "v2"},
+			},
+		},
+		{
+			Name:  "g2",
+			Roles: []auth.RoleInstance{{Name: "r1", ContextValue: "v1"}},
+		},
+	})
+	err = s.AuthGroupStorage.AddRole(context.TODO(), "g2", "r1", "v1")
+	c.Assert(err, check.IsNil)
+	groups, err = s.AuthGroupStorage.List(context.TODO(), []string{"g2"})
+	c.Assert(err, check.IsNil)
+	c.Assert(groups[0].Roles, check.HasLen, 1)
+}
+
+func (s *AuthGroupSuite) TestDelete(c *check.C) {
+	err := s.AuthGroupStorage.AddRole(context.TODO(), "g1", "r1", "v1")
+	c.Assert(err, check.IsNil)
+	err = s.AuthGroupStorage.Delete(context.TODO(), "g1")
+	c.Assert(err, check.IsNil)
+	groups, err := s.AuthGroupStorage.List(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.HasLen, 0)
+	err = s.AuthGroupStorage.Delete(context.TODO(), "g1")
+	c.Assert(err, check.Equals, auth.ErrGroupNotFound)
+}
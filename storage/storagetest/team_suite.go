@@ -108,6 +108,24 @@ func (s *TeamSuite) TestFindTeamByNamesNotFound(c *check.C) {
 	c.Assert(teams, check.HasLen, 0)
 }
 
+func (s *TeamSuite) TestFindTeamWithParent(c *check.C) {
+	t1 := auth.Team{Name: "team1", Tags: []string{}}
+	err := s.TeamStorage.Insert(context.TODO(), t1)
+	c.Assert(err, check.IsNil)
+	t2 := auth.Team{Name: "team2", Tags: []string{}, Parent: "team1", ShareParentQuota: true}
+	err = s.TeamStorage.Insert(context.TODO(), t2)
+	c.Assert(err, check.IsNil)
+	t3 := auth.Team{Name: "team3", Tags: []string{}, Parent: "team1"}
+	err = s.TeamStorage.Insert(context.TODO(), t3)
+	c.Assert(err, check.IsNil)
+	t3.Parent = ""
+	err = s.TeamStorage.Update(context.TODO(), t3)
+	c.Assert(err, check.IsNil)
+	teams, err := s.TeamStorage.FindWithParent(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(teams, check.DeepEquals, []auth.Team{t2})
+}
+
 func (s *TeamSuite) TestDeleteTeam(c *check.C) {
 	team := auth.Team{Name: "atreides"}
 	err := s.TeamStorage.Insert(context.TODO(), team)
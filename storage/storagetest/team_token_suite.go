@@ -164,3 +164,22 @@ func (s *TeamTokenSuite) TestUpdateTeamTokenNotFound(c *check.C) {
 	err := s.TeamTokenStorage.Update(context.TODO(), t)
 	c.Assert(err, check.Equals, auth.ErrTeamTokenNotFound)
 }
+
+func (s *TeamTokenSuite) TestPullExpiredRolesTeamToken(c *check.C) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	expired := auth.RoleInstance{Name: "pool-admin", ContextValue: "prod", ExpiresAt: now.Add(-time.Minute)}
+	valid := auth.RoleInstance{Name: "pool-admin", ContextValue: "dev", ExpiresAt: now.Add(time.Minute)}
+	permanent := auth.RoleInstance{Name: "app.deploy", ContextValue: "t1"}
+	t := auth.TeamToken{Token: "9382908", TokenID: "a", Team: "team1", Roles: []auth.RoleInstance{expired, valid, permanent}}
+	err := s.TeamTokenStorage.Insert(context.TODO(), t)
+	c.Assert(err, check.IsNil)
+	removed, err := s.TeamTokenStorage.PullExpiredRoles(context.TODO(), t.TokenID, now)
+	c.Assert(err, check.IsNil)
+	c.Assert(removed, check.DeepEquals, []auth.RoleInstance{expired})
+	token, err := s.TeamTokenStorage.FindByTokenID(context.TODO(), t.TokenID)
+	c.Assert(err, check.IsNil)
+	c.Assert(token.Roles, check.DeepEquals, []auth.RoleInstance{valid, permanent})
+	removed, err = s.TeamTokenStorage.PullExpiredRoles(context.TODO(), t.TokenID, now)
+	c.Assert(err, check.IsNil)
+	c.Assert(removed, check.IsNil)
+}
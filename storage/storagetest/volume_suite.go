@@ -184,6 +184,56 @@ func (s *VolumeSuite) Test_InsertBinds(c *check.C) {
 	c.Assert(bindsInDB, check.HasLen, 0)
 }
 
+func (s *VolumeSuite) Test_BindsForJob(c *check.C) {
+	binds := []volume.VolumeBind{
+		{
+			ID: volume.VolumeBindID{
+				Job:        "my-job",
+				Volume:     "my-volume",
+				MountPoint: "/mnt",
+			},
+			ReadOnly: true,
+		},
+		{
+			ID: volume.VolumeBindID{
+				App:        "my-job",
+				Volume:     "my-volume",
+				MountPoint: "/mnt",
+			},
+		},
+		{
+			ID: volume.VolumeBindID{
+				Job:        "my-job",
+				Volume:     "my-volume2",
+				MountPoint: "/data",
+			},
+		},
+	}
+	for _, bind := range binds {
+		err := s.VolumeStorage.InsertBind(context.TODO(), &bind)
+		c.Assert(err, check.IsNil)
+	}
+
+	bindsInDB, err := s.VolumeStorage.BindsForJob(context.TODO(), "", "my-job")
+	c.Assert(err, check.IsNil)
+	c.Assert(bindsInDB, check.DeepEquals, []volume.VolumeBind{binds[0], binds[2]})
+
+	bindsInDB, err = s.VolumeStorage.BindsForJob(context.TODO(), "my-volume", "my-job")
+	c.Assert(err, check.IsNil)
+	c.Assert(bindsInDB, check.DeepEquals, binds[0:1])
+
+	bindsInDB, err = s.VolumeStorage.BindsForApp(context.TODO(), "my-volume", "my-job")
+	c.Assert(err, check.IsNil)
+	c.Assert(bindsInDB, check.DeepEquals, binds[1:2])
+
+	err = s.VolumeStorage.RemoveBind(context.TODO(), binds[0].ID)
+	c.Assert(err, check.IsNil)
+
+	bindsInDB, err = s.VolumeStorage.BindsForJob(context.TODO(), "my-volume", "my-job")
+	c.Assert(err, check.IsNil)
+	c.Assert(bindsInDB, check.HasLen, 0)
+}
+
 func (s *VolumeSuite) Test_RenameTeam(c *check.C) {
 	vol := &volume.Volume{
 		Name:      "my-volume",
@@ -4,7 +4,12 @@
 
 package auth
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var ErrGroupNotFound = errors.New("group not found")
 
 type Group struct {
 	Name  string         `json:"name"`
@@ -19,4 +24,8 @@ type GroupService interface {
 	List(ctx context.Context, filter []string) ([]Group, error)
 	AddRole(ctx context.Context, name, roleName, contextValue string) error
 	RemoveRole(ctx context.Context, name, roleName, contextValue string) error
+	// SetRoles replaces every role assigned to the group, creating it if
+	// needed.
+	SetRoles(ctx context.Context, name string, roles []RoleInstance) error
+	Delete(ctx context.Context, name string) error
 }
@@ -14,6 +14,8 @@ type MockGroupService struct {
 	OnAddRole    func(name, roleName, contextValue string) error
 	OnRemoveRole func(name, roleName, contextValue string) error
 	OnList       func(filter []string) ([]Group, error)
+	OnSetRoles   func(name string, roles []RoleInstance) error
+	OnDelete     func(name string) error
 }
 
 func (m *MockGroupService) AddRole(ctx context.Context, name string, roleName, contextValue string) error {
@@ -36,3 +38,17 @@ func (m *MockGroupService) List(ctx context.Context, filter []string) ([]Group,
 	}
 	return m.OnList(filter)
 }
+
+func (m *MockGroupService) SetRoles(ctx context.Context, name string, roles []RoleInstance) error {
+	if m.OnSetRoles == nil {
+		return nil
+	}
+	return m.OnSetRoles(name, roles)
+}
+
+func (m *MockGroupService) Delete(ctx context.Context, name string) error {
+	if m.OnDelete == nil {
+		return nil
+	}
+	return m.OnDelete(name)
+}
@@ -15,17 +15,44 @@ import (
 var _ quota.QuotaItem = &Team{}
 
 // Team represents a real world team, a team has one creating user and a name.
+// Permissions granted on a team also apply to its descendants, and a team may
+// share the app quota of its parent.
 type Team struct {
-	Name         string      `json:"name"`
-	CreatingUser string      `json:"creatingUser"`
-	Tags         []string    `json:"tags"`
-	Quota        quota.Quota `json:"quota"`
+	Name             string      `json:"name"`
+	CreatingUser     string      `json:"creatingUser"`
+	Tags             []string    `json:"tags"`
+	Quota            quota.Quota `json:"quota"`
+	Parent           string      `json:"parent,omitempty"`
+	ShareParentQuota bool        `json:"shareParentQuota,omitempty"`
 }
 
 func (t Team) GetName() string {
 	return t.Name
 }
 
+// TeamHierarchy maps each team to its child teams.
+type TeamHierarchy map[string][]string
+
+// Descendants returns every team below team, closest ones first.
+func (h TeamHierarchy) Descendants(team string) []string {
+	var descendants []string
+	visited := map[string]bool{team: true}
+	queue := []string{team}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range h[current] {
+			if visited[child] {
+				continue
+			}
+			visited[child] = true
+			descendants = append(descendants, child)
+			queue = append(queue, child)
+		}
+	}
+	return descendants
+}
+
 type TeamService interface {
 	Create(context.Context, string, []string, *User) error
 	Update(context.Context, string, []string) error
@@ -33,6 +60,8 @@ type TeamService interface {
 	FindByName(context.Context, string) (*Team, error)
 	FindByNames(context.Context, []string) ([]Team, error)
 	Remove(context.Context, string) error
+	SetParent(ctx context.Context, name, parent string, shareQuota bool) error
+	Hierarchy(context.Context) (TeamHierarchy, error)
 }
 
 type TeamStorage interface {
@@ -41,6 +70,7 @@ type TeamStorage interface {
 	FindAll(context.Context) ([]Team, error)
 	FindByName(context.Context, string) (*Team, error)
 	FindByNames(context.Context, []string) ([]Team, error)
+	FindWithParent(context.Context) ([]Team, error)
 	Delete(context.Context, Team) error
 }
 
@@ -49,6 +79,8 @@ var (
 		Message: "Invalid team name, team names should start with a letter and" +
 			"contain only lower case letters, numbers, dashes and underscore",
 	}
-	ErrTeamAlreadyExists = errors.New("team already exists")
-	ErrTeamNotFound      = errors.New("team not found")
+	ErrTeamAlreadyExists  = errors.New("team already exists")
+	ErrTeamNotFound       = errors.New("team not found")
+	ErrParentTeamNotFound = &tsuruErrors.ValidationError{Message: "parent team not found"}
+	ErrTeamParentCycle    = &tsuruErrors.ValidationError{Message: "a team cannot be a descendant of itself"}
 )
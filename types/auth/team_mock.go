@@ -11,12 +11,13 @@ var _ TeamService = &MockTeamService{}
 
 // MockTeamStorage implements TeamStorage interface
 type MockTeamStorage struct {
-	OnInsert      func(Team) error
-	OnUpdate      func(Team) error
-	OnFindAll     func() ([]Team, error)
-	OnFindByName  func(string) (*Team, error)
-	OnFindByNames func([]string) ([]Team, error)
-	OnDelete      func(Team) error
+	OnInsert         func(Team) error
+	OnUpdate         func(Team) error
+	OnFindAll        func() ([]Team, error)
+	OnFindByName     func(string) (*Team, error)
+	OnFindByNames    func([]string) ([]Team, error)
+	OnFindWithParent func() ([]Team, error)
+	OnDelete         func(Team) error
 }
 
 func (m *MockTeamStorage) Insert(ctx context.Context, t Team) error {
@@ -39,6 +40,10 @@ func (m *MockTeamStorage) FindByNames(ctx context.Context, names []string) ([]Te
 	return m.OnFindByNames(names)
 }
 
+func (m *MockTeamStorage) FindWithParent(ctx context.Context) ([]Team, error) {
+	return m.OnFindWithParent()
+}
+
 func (m *MockTeamStorage) Delete(ctx context.Context, t Team) error {
 	return m.OnDelete(t)
 }
@@ -50,6 +55,8 @@ type MockTeamService struct {
 	OnFindByName  func(string) (*Team, error)
 	OnFindByNames func([]string) ([]Team, error)
 	OnRemove      func(string) error
+	OnSetParent   func(string, string, bool) error
+	OnHierarchy   func() (TeamHierarchy, error)
 }
 
 func (m *MockTeamService) Create(ctx context.Context, teamName string, tags []string, user *User) error {
@@ -93,3 +100,17 @@ func (m *MockTeamService) Remove(ctx context.Context, teamName string) error {
 	}
 	return m.OnRemove(teamName)
 }
+
+func (m *MockTeamService) SetParent(ctx context.Context, teamName, parent string, shareQuota bool) error {
+	if m.OnSetParent == nil {
+		return nil
+	}
+	return m.OnSetParent(teamName, parent, shareQuota)
+}
+
+func (m *MockTeamService) Hierarchy(ctx context.Context) (TeamHierarchy, error) {
+	if m.OnHierarchy == nil {
+		return nil, nil
+	}
+	return m.OnHierarchy()
+}
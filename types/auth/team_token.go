@@ -43,6 +43,9 @@ type TeamTokenStorage interface {
 	FindByTeams(ctx context.Context, teams []string) ([]TeamToken, error)
 	UpdateLastAccess(ctx context.Context, token string) error
 	Update(context.Context, TeamToken) error
+	// PullExpiredRoles atomically removes the role assignments of the token
+	// expired at the given time, returning the removed assignments.
+	PullExpiredRoles(ctx context.Context, tokenID string, now time.Time) ([]RoleInstance, error)
 	Delete(ctx context.Context, tokenID string) error
 }
 
@@ -55,7 +58,9 @@ type TeamTokenService interface {
 	FindByTokenID(ctx context.Context, tokenID string) (TeamToken, error)
 	FindByUserToken(ctx context.Context, t Token) ([]TeamToken, error)
 	AddRole(ctx context.Context, tokenID string, roleName, contextValue string) error
+	AddRoleInstance(ctx context.Context, tokenID string, roleInstance RoleInstance) error
 	RemoveRole(ctx context.Context, tokenID string, roleName, contextValue string) error
+	RemoveExpiredRoles(ctx context.Context, now time.Time) (map[string][]RoleInstance, error)
 }
 
 var (
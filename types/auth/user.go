@@ -24,19 +24,43 @@ type User struct {
 	// In other words, it does not exist in the storage.
 	FromToken bool
 	Disabled  bool
+	MFA       *MFA
 
 	APIKeyLastAccess   time.Time
 	APIKeyUsageCounter int64
 }
 
+// MFA is the time-based one-time password second factor of a user.
+type MFA struct {
+	Enabled bool
+	Secret  string
+	// RecoveryCodes are SHA-256 hashes of single use codes accepted in place
+	// of a one-time password.
+	RecoveryCodes []string
+	// LastStep is the time step of the last one-time password accepted,
+	// codes of this step or older are rejected so they can't be replayed.
+	LastStep int64
+}
+
 type RoleInstance struct {
 	Name         string
 	ContextValue string
+	// ExpiresAt is set for temporary assignments, which stop granting
+	// permissions and are pruned once it is reached.
+	ExpiresAt time.Time `bson:",omitempty"`
+	Reason    string    `bson:",omitempty" json:",omitempty"`
+}
+
+// Expired reports whether a temporary assignment is no longer valid at the
+// given time.
+func (r RoleInstance) Expired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && !now.Before(r.ExpiresAt)
 }
 
 type ErrTeamStillUsed struct {
 	Apps             []string
 	ServiceInstances []string
+	Teams            []string
 }
 
 var (
@@ -50,5 +74,8 @@ func (e *ErrTeamStillUsed) Error() string {
 	if len(e.Apps) > 0 {
 		return fmt.Sprintf("Apps: %s", strings.Join(e.Apps, ", "))
 	}
+	if len(e.Teams) > 0 {
+		return fmt.Sprintf("Child teams: %s", strings.Join(e.Teams, ", "))
+	}
 	return fmt.Sprintf("Service instances: %s", strings.Join(e.ServiceInstances, ", "))
 }
@@ -0,0 +1,57 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"context"
+	"time"
+)
+
+// AuditSchemaVersion is bumped whenever a field of AuditEntry changes its
+// meaning or is removed, adding fields keeps the version.
+const AuditSchemaVersion = 1
+
+const (
+	AuditResultSuccess  = "success"
+	AuditResultFailure  = "failure"
+	AuditResultCanceled = "canceled"
+	// AuditResultDenied is the result of requests rejected by a permission
+	// check, they have no event.
+	AuditResultDenied = "denied"
+)
+
+// AuditEntry is a finished event as written to the audit stream. Custom data
+// is never included, so payloads such as environment variables don't leak
+// to the sink.
+type AuditEntry struct {
+	Version      int           `json:"version"`
+	Sequence     uint64        `json:"sequence"`
+	Instance     string        `json:"instance,omitempty"`
+	EventID      string        `json:"event_id,omitempty"`
+	RequestID    string        `json:"request_id,omitempty"`
+	Time         time.Time     `json:"time"`
+	StartTime    time.Time     `json:"start_time"`
+	Actor        AuditActor    `json:"actor"`
+	Action       string        `json:"action"`
+	Target       AuditTarget   `json:"target"`
+	ExtraTargets []AuditTarget `json:"extra_targets,omitempty"`
+	SourceIP     string        `json:"source_ip,omitempty"`
+	Result       string        `json:"result"`
+	Error        string        `json:"error,omitempty"`
+}
+
+type AuditActor struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type AuditTarget struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type AuditService interface {
+	Record(ctx context.Context, entry AuditEntry)
+}
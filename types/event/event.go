@@ -30,6 +30,7 @@ type EventData struct {
 	Kind            Kind
 	Owner           Owner
 	SourceIP        string
+	RequestID       string `bson:",omitempty"`
 	LockUpdateTime  time.Time
 	Error           string
 	Log             string     `bson:",omitempty"`
@@ -158,6 +159,7 @@ var (
 	TargetTypeWebhook         = TargetType("webhook")
 	TargetTypeGC              = TargetType("gc")
 	TargetTypeRouter          = TargetType("router")
+	TargetTypeGroup           = TargetType("group")
 
 	ErrInvalidTargetType = errors.New("invalid event target type")
 )
@@ -202,6 +204,8 @@ func GetTargetType(t string) (TargetType, error) {
 		return TargetTypeWebhook, nil
 	case "router":
 		return TargetTypeRouter, nil
+	case "group":
+		return TargetTypeGroup, nil
 	}
 	return TargetType(""), ErrInvalidTargetType
 }
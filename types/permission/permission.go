@@ -40,6 +40,9 @@ type PermissionContext struct {
 type Permission struct {
 	Scheme  *PermissionScheme
 	Context PermissionContext
+	// Deny marks a permission excluded by a role. A matching deny wins over
+	// any allowed permission.
+	Deny bool
 }
 
 func (p *Permission) String() string {
@@ -7,6 +7,7 @@ package provision
 import (
 	"context"
 	"errors"
+	"time"
 
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
@@ -25,6 +26,25 @@ type Cluster struct {
 	Default     bool              `json:"default"`
 	KubeConfig  *KubeConfig       `json:"kubeConfig,omitempty"`
 	HTTPProxy   string            `json:"httpProxy,omitempty"`
+
+	CredentialsExpiry []ClusterCredentialExpiry `json:"credentialsExpiry,omitempty"`
+}
+
+// ClusterCredentialExpiry reports when one of the credentials used to access
+// a cluster stops being valid.
+type ClusterCredentialExpiry struct {
+	Credential string    `json:"credential"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// ClusterCredentials are the credentials swapped when rotating the access to
+// a cluster. Empty fields keep the current value.
+type ClusterCredentials struct {
+	CaCert     []byte      `json:"cacert,omitempty"`
+	ClientCert []byte      `json:"clientcert,omitempty"`
+	ClientKey  []byte      `json:"clientkey,omitempty"`
+	Token      string      `json:"token,omitempty"`
+	KubeConfig *KubeConfig `json:"kubeConfig,omitempty"`
 }
 
 type KubeConfig struct {
@@ -46,6 +66,7 @@ type ClusterService interface {
 	FindByPool(ctx context.Context, provisioner, pool string) (*Cluster, error)
 	FindByPools(ctx context.Context, provisioner string, pools []string) (map[string]Cluster, error)
 	Delete(context.Context, Cluster) error
+	RotateCredentials(ctx context.Context, name string, credentials ClusterCredentials) error
 }
 
 type ClusterStorage interface {
@@ -66,4 +87,6 @@ func (c *Cluster) CleanUpSensitive() {
 var (
 	ErrClusterNotFound = errors.New("cluster not found")
 	ErrNoCluster       = errors.New("no cluster")
+
+	ErrClusterCredentialsRequired = errors.New("at least one credential must be provided")
 )
@@ -70,6 +70,7 @@ type MockClusterService struct {
 	OnFindByPool        func(string, string) (*Cluster, error)
 	OnFindByPools       func(string, []string) (map[string]Cluster, error)
 	OnDelete            func(Cluster) error
+	OnRotateCredentials func(string, ClusterCredentials) error
 }
 
 func (m *MockClusterService) Create(ctx context.Context, c Cluster) error {
@@ -127,3 +128,10 @@ func (m *MockClusterService) Delete(ctx context.Context, c Cluster) error {
 	}
 	return m.OnDelete(c)
 }
+
+func (m *MockClusterService) RotateCredentials(ctx context.Context, name string, credentials ClusterCredentials) error {
+	if m.OnRotateCredentials == nil {
+		return nil
+	}
+	return m.OnRotateCredentials(name, credentials)
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package provision
+
+import "time"
+
+// NodeCondition is the current state of one aspect of a node, as reported by
+// the cluster, like Ready or MemoryPressure.
+type NodeCondition struct {
+	Type               string
+	Status             string
+	Reason             string `json:",omitempty"`
+	Message            string `json:",omitempty"`
+	LastTransitionTime time.Time
+}
+
+// Node is a cluster node backing a pool, along with the tsuru units scheduled
+// on it.
+type Node struct {
+	Name          string
+	Cluster       string
+	Pool          string `json:",omitempty"`
+	Address       string `json:",omitempty"`
+	Ready         bool
+	Unschedulable bool
+	Conditions    []NodeCondition `json:",omitempty"`
+	Capacity      PoolResources
+	Allocatable   PoolResources
+	Units         []Unit `json:",omitempty"`
+}
+
+// NodeList is the list of nodes backing a pool or cluster, along with the
+// units not yet scheduled on any of them.
+type NodeList struct {
+	Nodes   []Node
+	Pending []Unit `json:",omitempty"`
+}
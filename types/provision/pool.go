@@ -30,3 +30,40 @@ type PoolService interface {
 	FindByName(ctx context.Context, name string) (*Pool, error)
 	Services(ctx context.Context, pool string) ([]string, error)
 }
+
+// PoolResources holds an amount of CPU, in millicores, and memory, in bytes.
+type PoolResources struct {
+	MilliCPU int64
+	Memory   int64
+}
+
+// PoolCapacity reports the resources of the nodes in a pool, how much of
+// them is already requested and how many more units of each plan would fit.
+type PoolCapacity struct {
+	Pool             string
+	Nodes            int
+	Allocatable      PoolResources
+	Requested        PoolResources
+	Available        PoolResources
+	CPUOvercommit    float64
+	MemoryOvercommit float64
+	Apps             []AppCapacity  `json:",omitempty"`
+	Headroom         []PlanHeadroom `json:",omitempty"`
+}
+
+// AppCapacity is the amount of resources requested by the units of an app
+// running with a given plan.
+type AppCapacity struct {
+	App       string
+	Plan      string
+	Units     int
+	Requested PoolResources
+}
+
+// PlanHeadroom is the number of additional units of a plan that fit in the
+// available resources of a pool, considering the pool overcommit.
+type PlanHeadroom struct {
+	Plan         string
+	UnitRequests PoolResources
+	Units        int64
+}
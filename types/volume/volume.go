@@ -7,6 +7,8 @@ package volume
 import (
 	"context"
 	"encoding/json"
+	"io"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -18,6 +20,18 @@ var (
 	ErrVolumeBindNotFound       = errors.New("volume bind not found")
 	ErrVolumeAlreadyProvisioned = errors.New("updating a volume already provisioned is not supported, a new volume must be created and the old one deleted if necessary")
 	ErrVolumePlanNotFound       = errors.New("volume-plan not present in pool constraint")
+	ErrVolumeSnapshotNotFound   = errors.New("volume snapshot not found")
+	ErrVolumeSnapshotNotReady   = errors.New("volume snapshot is not ready to be restored")
+	ErrVolumeNotProvisioned     = errors.New("volume is not provisioned yet")
+	ErrSnapshotNotSupported     = errors.New("volume snapshots are not supported by this volume plan")
+	ErrResizeNotSupported       = errors.New("volume resize is not supported by this volume plan")
+	ErrVolumeShrinkNotAllowed   = errors.New("volume capacity can only be increased")
+	ErrVolumeTeamAlreadyAllowed = errors.New("team already has access to this volume")
+	ErrVolumeTeamNotAllowed     = errors.New("team does not have access to this volume")
+	ErrVolumeTeamOwnerAccess    = errors.New("the team owner always has full access to the volume")
+	ErrVolumeBindNotAllowed     = errors.New("team is not allowed to bind this volume")
+	ErrVolumeBindReadOnly       = errors.New("team is only allowed to bind this volume as read-only")
+	ErrVolumeBindOtherCluster   = errors.New("volume can only be bound to apps and jobs running in the same cluster as the volume pool")
 )
 
 type VolumePlan struct {
@@ -25,10 +39,13 @@ type VolumePlan struct {
 	Opts map[string]interface{}
 }
 
+// VolumeBindID identifies a bind of the volume to either an app or a job, Job
+// is only set for job binds.
 type VolumeBindID struct {
 	App        string
 	MountPoint string
 	Volume     string
+	Job        string `bson:",omitempty" json:",omitempty"`
 }
 
 type VolumeBind struct {
@@ -44,6 +61,49 @@ type Volume struct {
 	Status    string
 	Binds     []VolumeBind      `bson:"-"`
 	Opts      map[string]string `bson:",omitempty"`
+
+	// SnapshotSource is set on volumes restored from a snapshot, the volume
+	// data is populated from it once the volume is provisioned.
+	SnapshotSource *VolumeSnapshotSource `bson:",omitempty" json:",omitempty"`
+
+	// Teams restricts the teams whose apps and jobs may bind the volume.
+	// When empty the volume is unrestricted, as it was before team access
+	// existed; once any team is granted access, only TeamOwner and the teams
+	// listed here may bind it.
+	Teams []VolumeTeamAccess `bson:",omitempty" json:",omitempty"`
+
+	// Usage is only filled in volume info, it is collected from the
+	// provisioner and never stored.
+	Usage *VolumeUsage `bson:"-" json:",omitempty"`
+}
+
+type VolumeTeamAccess struct {
+	Team     string
+	ReadOnly bool
+}
+
+type VolumeUsage struct {
+	CapacityBytes  int64
+	UsedBytes      int64
+	AvailableBytes int64
+	Inodes         int64
+	InodesUsed     int64
+	InodesFree     int64
+}
+
+type VolumeSnapshotSource struct {
+	Volume   string
+	Snapshot string
+}
+
+type VolumeSnapshot struct {
+	Name         string
+	Volume       string
+	Class        string
+	ReadyToUse   bool
+	RestoreSize  string
+	CreationTime *time.Time `json:",omitempty"`
+	Error        string     `json:",omitempty"`
 }
 
 func (v *Volume) UnmarshalPlan(result interface{}) error {
@@ -54,11 +114,82 @@ func (v *Volume) UnmarshalPlan(result interface{}) error {
 	return errors.WithStack(json.Unmarshal(jsonData, result))
 }
 
+func (v *Volume) findTeam(team string) int {
+	for i, t := range v.Teams {
+		if t.Team == team {
+			return i
+		}
+	}
+	return -1
+}
+
+// HasTeam reports whether apps from team are allowed to bind the volume.
+func (v *Volume) HasTeam(team string) bool {
+	return len(v.Teams) == 0 || team == v.TeamOwner || v.findTeam(team) > -1
+}
+
+func (v *Volume) GrantAccess(team string, readOnly bool) error {
+	if team == v.TeamOwner {
+		return ErrVolumeTeamOwnerAccess
+	}
+	if v.findTeam(team) > -1 {
+		return ErrVolumeTeamAlreadyAllowed
+	}
+	v.Teams = append(v.Teams, VolumeTeamAccess{Team: team, ReadOnly: readOnly})
+	return nil
+}
+
+// RevokeAccess removes the access granted to team. Binds already made by
+// apps and jobs of the team are kept, only new binds are refused. Revoking the
+// last granted team lifts the restriction, see Teams.
+func (v *Volume) RevokeAccess(team string) error {
+	if team == v.TeamOwner {
+		return ErrVolumeTeamOwnerAccess
+	}
+	index := v.findTeam(team)
+	if index < 0 {
+		return ErrVolumeTeamNotAllowed
+	}
+	v.Teams = append(v.Teams[:index], v.Teams[index+1:]...)
+	return nil
+}
+
+// CheckBindAccess returns an error if apps from team are not allowed to bind
+// the volume with the given read-only mode. Volumes without granted teams are
+// not restricted.
+func (v *Volume) CheckBindAccess(team string, readOnly bool) error {
+	if len(v.Teams) == 0 || team == v.TeamOwner {
+		return nil
+	}
+	index := v.findTeam(team)
+	if index < 0 {
+		return ErrVolumeBindNotAllowed
+	}
+	if v.Teams[index].ReadOnly && !readOnly {
+		return ErrVolumeBindReadOnly
+	}
+	return nil
+}
+
+type ResizeOpts struct {
+	Volume   *Volume
+	Capacity string
+	Writer   io.Writer
+}
+
 type BindOpts struct {
 	Volume     *Volume
 	AppName    string
+	JobName    string
 	MountPoint string
 	ReadOnly   bool
+	// TeamOwner is the team owning the app or job, when set the bind is
+	// only allowed if the team has access to the volume.
+	TeamOwner string
+	// Pool is the pool of the app or job, when set the bind is only allowed
+	// if the pool is served by the same provisioner and cluster as the
+	// volume pool.
+	Pool string
 }
 
 type Filter struct {
@@ -72,6 +203,7 @@ type VolumeService interface {
 	Update(ctx context.Context, v *Volume) error
 	Delete(ctx context.Context, v *Volume) error
 	ListByApp(ctx context.Context, appName string) ([]Volume, error)
+	ListByJob(ctx context.Context, jobName string) ([]Volume, error)
 	ListByFilter(ctx context.Context, f *Filter) ([]Volume, error)
 	ListPlans(ctx context.Context) (map[string][]VolumePlan, error)
 	CheckPoolVolumeConstraints(ctx context.Context, volume Volume) error
@@ -80,7 +212,22 @@ type VolumeService interface {
 	BindApp(ctx context.Context, opts *BindOpts) error
 	UnbindApp(ctx context.Context, opts *BindOpts) error
 	BindsForApp(ctx context.Context, v *Volume, appName string) ([]VolumeBind, error)
+	BindJob(ctx context.Context, opts *BindOpts) error
+	UnbindJob(ctx context.Context, opts *BindOpts) error
+	BindsForJob(ctx context.Context, v *Volume, jobName string) ([]VolumeBind, error)
 	Binds(ctx context.Context, v *Volume) ([]VolumeBind, error)
+
+	CreateSnapshot(ctx context.Context, v *Volume, name string) (*VolumeSnapshot, error)
+	ListSnapshots(ctx context.Context, v *Volume) ([]VolumeSnapshot, error)
+	DeleteSnapshot(ctx context.Context, v *Volume, name string) error
+	RestoreSnapshot(ctx context.Context, v *Volume, snapshotName string, newVolume *Volume) error
+
+	Resize(ctx context.Context, opts *ResizeOpts) error
+	Usage(ctx context.Context, v *Volume) (*VolumeUsage, error)
+	UsageByVolume(ctx context.Context, volumes []Volume) (map[string]*VolumeUsage, error)
+
+	GrantAccess(ctx context.Context, v *Volume, team string, readOnly bool) error
+	RevokeAccess(ctx context.Context, v *Volume, team string) error
 }
 
 type VolumeStorage interface {
@@ -93,6 +240,7 @@ type VolumeStorage interface {
 	RemoveBind(ctx context.Context, id VolumeBindID) error
 	Binds(ctx context.Context, volumeName string) ([]VolumeBind, error)
 	BindsForApp(ctx context.Context, volumeName, appName string) ([]VolumeBind, error)
+	BindsForJob(ctx context.Context, volumeName, jobName string) ([]VolumeBind, error)
 
 	RenameTeam(ctx context.Context, oldName, newName string) error
 }
@@ -13,14 +13,27 @@ type MockVolumeService struct {
 	OnUpdate                     func(ctx context.Context, v *Volume) error
 	OnGet                        func(ctx context.Context, appName string) (*Volume, error)
 	OnListByApp                  func(ctx context.Context, appName string) ([]Volume, error)
+	OnListByJob                  func(ctx context.Context, jobName string) ([]Volume, error)
 	OnListByFilter               func(ctx context.Context, f *Filter) ([]Volume, error)
 	OnDelete                     func(ctx context.Context, v *Volume) error
 	OnBindApp                    func(ctx context.Context, opts *BindOpts) error
 	OnUnbindApp                  func(ctx context.Context, opts *BindOpts) error
 	OnBinds                      func(ctx context.Context, v *Volume) ([]VolumeBind, error)
 	OnBindsForApp                func(ctx context.Context, v *Volume, appName string) ([]VolumeBind, error)
+	OnBindJob                    func(ctx context.Context, opts *BindOpts) error
+	OnUnbindJob                  func(ctx context.Context, opts *BindOpts) error
+	OnBindsForJob                func(ctx context.Context, v *Volume, jobName string) ([]VolumeBind, error)
 	OnListPlans                  func(ctx context.Context) (map[string][]VolumePlan, error)
 	OnCheckPoolVolumeConstraints func(ctx context.Context, volume Volume) error
+	OnCreateSnapshot             func(ctx context.Context, v *Volume, name string) (*VolumeSnapshot, error)
+	OnListSnapshots              func(ctx context.Context, v *Volume) ([]VolumeSnapshot, error)
+	OnDeleteSnapshot             func(ctx context.Context, v *Volume, name string) error
+	OnRestoreSnapshot            func(ctx context.Context, v *Volume, snapshotName string, newVolume *Volume) error
+	OnResize                     func(ctx context.Context, opts *ResizeOpts) error
+	OnUsage                      func(ctx context.Context, v *Volume) (*VolumeUsage, error)
+	OnUsageByVolume              func(ctx context.Context, volumes []Volume) (map[string]*VolumeUsage, error)
+	OnGrantAccess                func(ctx context.Context, v *Volume, team string, readOnly bool) error
+	OnRevokeAccess               func(ctx context.Context, v *Volume, team string) error
 }
 
 func (m *MockVolumeService) VolumeService() (Volume, error) {
@@ -65,6 +78,13 @@ func (m *MockVolumeService) ListByApp(ctx context.Context, appName string) ([]Vo
 	return nil, nil
 }
 
+func (m *MockVolumeService) ListByJob(ctx context.Context, jobName string) ([]Volume, error) {
+	if m.OnListByJob != nil {
+		return m.OnListByJob(ctx, jobName)
+	}
+	return nil, nil
+}
+
 func (m *MockVolumeService) ListByFilter(ctx context.Context, f *Filter) ([]Volume, error) {
 	if m.OnListByFilter != nil {
 		return m.OnListByFilter(ctx, f)
@@ -107,6 +127,27 @@ func (m *MockVolumeService) BindsForApp(ctx context.Context, v *Volume, appName
 	return nil, nil
 }
 
+func (m *MockVolumeService) BindJob(ctx context.Context, opts *BindOpts) error {
+	if m.OnBindJob != nil {
+		return m.OnBindJob(ctx, opts)
+	}
+	return nil
+}
+
+func (m *MockVolumeService) UnbindJob(ctx context.Context, opts *BindOpts) error {
+	if m.OnUnbindJob != nil {
+		return m.OnUnbindJob(ctx, opts)
+	}
+	return nil
+}
+
+func (m *MockVolumeService) BindsForJob(ctx context.Context, v *Volume, jobName string) ([]VolumeBind, error) {
+	if m.OnBindsForJob != nil {
+		return m.OnBindsForJob(ctx, v, jobName)
+	}
+	return nil, nil
+}
+
 func (m *MockVolumeService) ListPlans(ctx context.Context) (map[string][]VolumePlan, error) {
 	if m.OnListPlans != nil {
 		return m.OnListPlans(ctx)
@@ -120,3 +161,66 @@ func (m *MockVolumeService) CheckPoolVolumeConstraints(ctx context.Context, volu
 	}
 	return nil
 }
+
+func (m *MockVolumeService) CreateSnapshot(ctx context.Context, v *Volume, name string) (*VolumeSnapshot, error) {
+	if m.OnCreateSnapshot != nil {
+		return m.OnCreateSnapshot(ctx, v, name)
+	}
+	return nil, nil
+}
+
+func (m *MockVolumeService) ListSnapshots(ctx context.Context, v *Volume) ([]VolumeSnapshot, error) {
+	if m.OnListSnapshots != nil {
+		return m.OnListSnapshots(ctx, v)
+	}
+	return nil, nil
+}
+
+func (m *MockVolumeService) DeleteSnapshot(ctx context.Context, v *Volume, name string) error {
+	if m.OnDeleteSnapshot != nil {
+		return m.OnDeleteSnapshot(ctx, v, name)
+	}
+	return nil
+}
+
+func (m *MockVolumeService) RestoreSnapshot(ctx context.Context, v *Volume, snapshotName string, newVolume *Volume) error {
+	if m.OnRestoreSnapshot != nil {
+		return m.OnRestoreSnapshot(ctx, v, snapshotName, newVolume)
+	}
+	return nil
+}
+
+func (m *MockVolumeService) Resize(ctx context.Context, opts *ResizeOpts) error {
+	if m.OnResize != nil {
+		return m.OnResize(ctx, opts)
+	}
+	return nil
+}
+
+func (m *MockVolumeService) Usage(ctx context.Context, v *Volume) (*VolumeUsage, error) {
+	if m.OnUsage != nil {
+		return m.OnUsage(ctx, v)
+	}
+	return nil, nil
+}
+
+func (m *MockVolumeService) UsageByVolume(ctx context.Context, volumes []Volume) (map[string]*VolumeUsage, error) {
+	if m.OnUsageByVolume != nil {
+		return m.OnUsageByVolume(ctx, volumes)
+	}
+	return nil, nil
+}
+
+func (m *MockVolumeService) GrantAccess(ctx context.Context, v *Volume, team string, readOnly bool) error {
+	if m.OnGrantAccess != nil {
+		return m.OnGrantAccess(ctx, v, team, readOnly)
+	}
+	return v.GrantAccess(team, readOnly)
+}
+
+func (m *MockVolumeService) RevokeAccess(ctx context.Context, v *Volume, team string) error {
+	if m.OnRevokeAccess != nil {
+		return m.OnRevokeAccess(ctx, v, team)
+	}
+	return v.RevokeAccess(team)
+}
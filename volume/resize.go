@@ -0,0 +1,46 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+)
+
+// Resize expands the capacity of an already provisioned volume, which can't
+// be changed through Update. The new capacity is saved in the volume opts
+// once the provisioner reports the volume as resized.
+func (s *volumeService) Resize(ctx context.Context, opts *volumeTypes.ResizeOpts) error {
+	if opts.Writer == nil {
+		opts.Writer = io.Discard
+	}
+	v := opts.Volume
+	p, err := pool.GetPoolByName(ctx, v.Pool)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	prov, err := p.GetProvisioner()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	resizeProv, ok := prov.(provision.VolumeResizeProvisioner)
+	if !ok {
+		return volumeTypes.ErrResizeNotSupported
+	}
+	err = resizeProv.ResizeVolume(ctx, v, opts.Capacity, opts.Writer)
+	if err != nil {
+		return err
+	}
+	if v.Opts == nil {
+		v.Opts = map[string]string{}
+	}
+	v.Opts["capacity"] = opts.Capacity
+	return s.storage.Save(ctx, v)
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+)
+
+type resizeProvisioner struct {
+	volumeProvisioner
+	resizeErr error
+	resizedTo string
+}
+
+func (p *resizeProvisioner) ResizeVolume(ctx context.Context, v *volumeTypes.Volume, capacity string, w io.Writer) error {
+	if p.resizeErr != nil {
+		return p.resizeErr
+	}
+	p.resizedTo = capacity
+	fmt.Fprintf(w, "resized %s to %s\n", v.Name, capacity)
+	return nil
+}
+
+func setupResizeTest(t *testing.T) (*volumeService, *resizeProvisioner, *volumeTypes.Volume) {
+	setupTest(t)
+	setupConfig(`
+volume-plans:
+  p1:
+    volumeprov:
+       driver: local
+`)
+	resizeProv := &resizeProvisioner{
+		volumeProvisioner: volumeProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance},
+	}
+	provision.Register("volumeprov", func() (provision.Provisioner, error) {
+		return resizeProv, nil
+	})
+	t.Cleanup(func() { provision.Unregister("volumeprov") })
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{
+		Name:        "volumepool",
+		Provisioner: "volumeprov",
+	})
+	require.NoError(t, err)
+	svc := &volumeService{storage: &volumeTypes.MockVolumeStorage{}}
+	vol := volumeTypes.Volume{
+		Name:      "v1",
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "volumepool",
+		TeamOwner: "myteam",
+		Opts:      map[string]string{"capacity": "1Gi"},
+	}
+	err = svc.Create(context.TODO(), &vol)
+	require.NoError(t, err)
+	return svc, resizeProv, &vol
+}
+
+func TestVolumeResize(t *testing.T) {
+	svc, resizeProv, vol := setupResizeTest(t)
+	resizeProv.isProvisioned = true
+	var buf strings.Builder
+	err := svc.Resize(context.TODO(), &volumeTypes.ResizeOpts{Volume: vol, Capacity: "2Gi", Writer: &buf})
+	require.NoError(t, err)
+	require.Equal(t, "2Gi", resizeProv.resizedTo)
+	require.Equal(t, "resized v1 to 2Gi\n", buf.String())
+	dbVol, err := svc.Get(context.TODO(), "v1")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"capacity": "2Gi"}, dbVol.Opts)
+}
+
+func TestVolumeResizeProvisionerError(t *testing.T) {
+	svc, resizeProv, vol := setupResizeTest(t)
+	resizeProv.resizeErr = volumeTypes.ErrVolumeShrinkNotAllowed
+	err := svc.Resize(context.TODO(), &volumeTypes.ResizeOpts{Volume: vol, Capacity: "512Mi"})
+	require.ErrorIs(t, err, volumeTypes.ErrVolumeShrinkNotAllowed)
+	dbVol, err := svc.Get(context.TODO(), "v1")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"capacity": "1Gi"}, dbVol.Opts)
+}
+
+func TestVolumeResizeNotSupported(t *testing.T) {
+	setupTest(t)
+	svc := &volumeService{storage: &volumeTypes.MockVolumeStorage{}}
+	vol := volumeTypes.Volume{Name: "v1", Pool: "mypool"}
+	err := svc.Resize(context.TODO(), &volumeTypes.ResizeOpts{Volume: &vol, Capacity: "2Gi"})
+	require.ErrorIs(t, err, volumeTypes.ErrResizeNotSupported)
+}
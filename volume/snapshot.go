@@ -0,0 +1,103 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+	"github.com/tsuru/tsuru/validation"
+)
+
+func (s *volumeService) CreateSnapshot(ctx context.Context, v *volumeTypes.Volume, name string) (*volumeTypes.VolumeSnapshot, error) {
+	if !validation.ValidateName(name) {
+		msg := "Invalid snapshot name, snapshot name should have at most 40 " +
+			"characters, containing only lower case letters, numbers or dashes, " +
+			"starting with a letter."
+		return nil, errors.WithStack(&tsuruErrors.ValidationError{Message: msg})
+	}
+	snapProv, err := snapshotProvisioner(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	return snapProv.CreateVolumeSnapshot(ctx, v, name)
+}
+
+func (s *volumeService) ListSnapshots(ctx context.Context, v *volumeTypes.Volume) ([]volumeTypes.VolumeSnapshot, error) {
+	snapProv, err := snapshotProvisioner(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	return snapProv.ListVolumeSnapshots(ctx, v)
+}
+
+func (s *volumeService) DeleteSnapshot(ctx context.Context, v *volumeTypes.Volume, name string) error {
+	snapProv, err := snapshotProvisioner(ctx, v)
+	if err != nil {
+		return err
+	}
+	return snapProv.DeleteVolumeSnapshot(ctx, v, name)
+}
+
+// RestoreSnapshot creates newVolume with its data populated from the given
+// snapshot of v. Pool, plan, team owner and opts not set in newVolume are
+// copied from the source volume. Snapshots can only be restored in the same
+// pool as the source volume.
+func (s *volumeService) RestoreSnapshot(ctx context.Context, v *volumeTypes.Volume, snapshotName string, newVolume *volumeTypes.Volume) error {
+	if newVolume.Pool == "" {
+		newVolume.Pool = v.Pool
+	}
+	if newVolume.Pool != v.Pool {
+		return errors.WithStack(&tsuruErrors.ValidationError{Message: "snapshots can only be restored in the same pool as the source volume"})
+	}
+	if newVolume.Plan.Name == "" {
+		newVolume.Plan.Name = v.Plan.Name
+	}
+	if newVolume.TeamOwner == "" {
+		newVolume.TeamOwner = v.TeamOwner
+	}
+	if len(newVolume.Opts) == 0 && len(v.Opts) > 0 {
+		newVolume.Opts = make(map[string]string, len(v.Opts))
+		for k, val := range v.Opts {
+			newVolume.Opts[k] = val
+		}
+	}
+	snapProv, err := snapshotProvisioner(ctx, v)
+	if err != nil {
+		return err
+	}
+	snapshot, err := snapProv.GetVolumeSnapshot(ctx, v, snapshotName)
+	if err != nil {
+		return err
+	}
+	if !snapshot.ReadyToUse {
+		return volumeTypes.ErrVolumeSnapshotNotReady
+	}
+	newVolume.SnapshotSource = &volumeTypes.VolumeSnapshotSource{
+		Volume:   v.Name,
+		Snapshot: snapshotName,
+	}
+	return s.Create(ctx, newVolume)
+}
+
+func snapshotProvisioner(ctx context.Context, v *volumeTypes.Volume) (provision.VolumeSnapshotProvisioner, error) {
+	p, err := pool.GetPoolByName(ctx, v.Pool)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	prov, err := p.GetProvisioner()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	snapProv, ok := prov.(provision.VolumeSnapshotProvisioner)
+	if !ok {
+		return nil, volumeTypes.ErrSnapshotNotSupported
+	}
+	return snapProv, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+)
+
+type fakeSnapshotProvisioner struct {
+	volumeProvisioner
+	snapshots map[string]volumeTypes.VolumeSnapshot
+}
+
+func (p *fakeSnapshotProvisioner) CreateVolumeSnapshot(ctx context.Context, v *volumeTypes.Volume, name string) (*volumeTypes.VolumeSnapshot, error) {
+	snap := volumeTypes.VolumeSnapshot{Name: name, Volume: v.Name}
+	p.snapshots[name] = snap
+	return &snap, nil
+}
+
+func (p *fakeSnapshotProvisioner) ListVolumeSnapshots(ctx context.Context, v *volumeTypes.Volume) ([]volumeTypes.VolumeSnapshot, error) {
+	var result []volumeTypes.VolumeSnapshot
+	for _, snap := range p.snapshots {
+		if snap.Volume == v.Name {
+			result = append(result, snap)
+		}
+	}
+	return result, nil
+}
+
+func (p *fakeSnapshotProvisioner) GetVolumeSnapshot(ctx context.Context, v *volumeTypes.Volume, name string) (*volumeTypes.VolumeSnapshot, error) {
+	snap, ok := p.snapshots[name]
+	if !ok || snap.Volume != v.Name {
+		return nil, volumeTypes.ErrVolumeSnapshotNotFound
+	}
+	return &snap, nil
+}
+
+func (p *fakeSnapshotProvisioner) DeleteVolumeSnapshot(ctx context.Context, v *volumeTypes.Volume, name string) error {
+	if _, ok := p.snapshots[name]; !ok {
+		return volumeTypes.ErrVolumeSnapshotNotFound
+	}
+	delete(p.snapshots, name)
+	return nil
+}
+
+func setupSnapshotTest(t *testing.T) (*volumeService, *fakeSnapshotProvisioner, *volumeTypes.Volume) {
+	setupTest(t)
+	setupConfig(`
+volume-plans:
+  p1:
+    volumeprov:
+       driver: local
+`)
+	snapProv := &fakeSnapshotProvisioner{
+		volumeProvisioner: volumeProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance},
+		snapshots:         map[string]volumeTypes.VolumeSnapshot{},
+	}
+	provision.Register("volumeprov", func() (provision.Provisioner, error) {
+		return snapProv, nil
+	})
+	t.Cleanup(func() { provision.Unregister("volumeprov") })
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{
+		Name:        "volumepool",
+		Provisioner: "volumeprov",
+	})
+	require.NoError(t, err)
+	svc := &volumeService{storage: &volumeTypes.MockVolumeStorage{}}
+	vol := volumeTypes.Volume{
+		Name:      "v1",
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "volumepool",
+		TeamOwner: "myteam",
+		Opts:      map[string]string{"capacity": "1Gi"},
+	}
+	err = svc.Create(context.TODO(), &vol)
+	require.NoError(t, err)
+	return svc, snapProv, &vol
+}
+
+func TestVolumeCreateAndListSnapshots(t *testing.T) {
+	svc, _, vol := setupSnapshotTest(t)
+	snap, err := svc.CreateSnapshot(context.TODO(), vol, "daily")
+	require.NoError(t, err)
+	require.Equal(t, &volumeTypes.VolumeSnapshot{Name: "daily", Volume: "v1"}, snap)
+	snaps, err := svc.ListSnapshots(context.TODO(), vol)
+	require.NoError(t, err)
+	require.Equal(t, []volumeTypes.VolumeSnapshot{{Name: "daily", Volume: "v1"}}, snaps)
+	err = svc.DeleteSnapshot(context.TODO(), vol, "daily")
+	require.NoError(t, err)
+	snaps, err = svc.ListSnapshots(context.TODO(), vol)
+	require.NoError(t, err)
+	require.Len(t, snaps, 0)
+}
+
+func TestVolumeCreateSnapshotInvalidName(t *testing.T) {
+	svc, _, vol := setupSnapshotTest(t)
+	_, err := svc.CreateSnapshot(context.TODO(), vol, "Invalid_Name")
+	require.ErrorContains(t, err, "Invalid snapshot name")
+}
+
+func TestVolumeSnapshotNotSupported(t *testing.T) {
+	setupTest(t)
+	svc := &volumeService{storage: &volumeTypes.MockVolumeStorage{}}
+	vol := volumeTypes.Volume{Name: "v1", Pool: "mypool"}
+	_, err := svc.CreateSnapshot(context.TODO(), &vol, "daily")
+	require.ErrorIs(t, err, volumeTypes.ErrSnapshotNotSupported)
+}
+
+func TestVolumeRestoreSnapshot(t *testing.T) {
+	svc, snapProv, vol := setupSnapshotTest(t)
+	snapProv.snapshots["daily"] = volumeTypes.VolumeSnapshot{Name: "daily", Volume: "v1", ReadyToUse: true}
+	newVol := volumeTypes.Volume{Name: "v2"}
+	err := svc.RestoreSnapshot(context.TODO(), vol, "daily", &newVol)
+	require.NoError(t, err)
+	restored, err := svc.Get(context.TODO(), "v2")
+	require.NoError(t, err)
+	require.Equal(t, "volumepool", restored.Pool)
+	require.Equal(t, "p1", restored.Plan.Name)
+	require.Equal(t, "myteam", restored.TeamOwner)
+	require.Equal(t, map[string]string{"capacity": "1Gi"}, restored.Opts)
+	require.Equal(t, &volumeTypes.VolumeSnapshotSource{Volume: "v1", Snapshot: "daily"}, restored.SnapshotSource)
+}
+
+func TestVolumeRestoreSnapshotNotReady(t *testing.T) {
+	svc, snapProv, vol := setupSnapshotTest(t)
+	snapProv.snapshots["daily"] = volumeTypes.VolumeSnapshot{Name: "daily", Volume: "v1"}
+	err := svc.RestoreSnapshot(context.TODO(), vol, "daily", &volumeTypes.Volume{Name: "v2"})
+	require.ErrorIs(t, err, volumeTypes.ErrVolumeSnapshotNotReady)
+	_, err = svc.Get(context.TODO(), "v2")
+	require.ErrorIs(t, err, volumeTypes.ErrVolumeNotFound)
+}
+
+func TestVolumeRestoreSnapshotOtherPool(t *testing.T) {
+	svc, snapProv, vol := setupSnapshotTest(t)
+	snapProv.snapshots["daily"] = volumeTypes.VolumeSnapshot{Name: "daily", Volume: "v1", ReadyToUse: true}
+	err := svc.RestoreSnapshot(context.TODO(), vol, "daily", &volumeTypes.Volume{Name: "v2", Pool: "mypool"})
+	require.ErrorContains(t, err, "same pool")
+}
@@ -0,0 +1,243 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tsuru/tsuru/api/shutdown"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/servicemanager"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+)
+
+var (
+	usageLabels         = []string{"volume", "team", "pool"}
+	capacityBytesDesc   = prometheus.NewDesc("tsuru_volume_capacity_bytes", "Capacity in bytes of the volume", usageLabels, nil)
+	usedBytesDesc       = prometheus.NewDesc("tsuru_volume_used_bytes", "Number of used bytes in the volume", usageLabels, nil)
+	availableBytesDesc  = prometheus.NewDesc("tsuru_volume_available_bytes", "Number of available bytes in the volume", usageLabels, nil)
+	inodesDesc          = prometheus.NewDesc("tsuru_volume_inodes", "Maximum number of inodes in the volume", usageLabels, nil)
+	inodesUsedDesc      = prometheus.NewDesc("tsuru_volume_inodes_used", "Number of used inodes in the volume", usageLabels, nil)
+	inodesFreeDesc      = prometheus.NewDesc("tsuru_volume_inodes_free", "Number of free inodes in the volume", usageLabels, nil)
+	usageFetchFailDesc  = prometheus.NewDesc("tsuru_volume_usage_fetch_fail", "indicates whether failed to list volumes or fetch their usage", []string{}, nil)
+	usageCollectTimeout = 30 * time.Second
+	// usageRefreshInterval is how often the usage exposed to Prometheus is
+	// collected from the provisioners.
+	usageRefreshInterval = time.Minute
+)
+
+func init() {
+	prometheus.MustRegister(usageCollector)
+}
+
+func (s *volumeService) Usage(ctx context.Context, v *volumeTypes.Volume) (*volumeTypes.VolumeUsage, error) {
+	p, err := pool.GetPoolByName(ctx, v.Pool)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	prov, err := p.GetProvisioner()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	usageProv, ok := prov.(provision.VolumeUsageProvisioner)
+	if !ok {
+		return nil, nil
+	}
+	return usageProv.VolumeUsage(ctx, v)
+}
+
+// UsageByVolume reports the usage of the volumes, keyed by volume name,
+// asking each provisioner once for all of its volumes. Pools and
+// provisioners that fail are skipped: the usage of the remaining volumes is
+// returned along with the errors.
+func (s *volumeService) UsageByVolume(ctx context.Context, volumes []volumeTypes.Volume) (map[string]*volumeTypes.VolumeUsage, error) {
+	multiErr := tsuruErrors.NewMultiError()
+	byProvisioner := map[string][]volumeTypes.Volume{}
+	provisioners := map[string]provision.VolumeUsageProvisioner{}
+	poolProvisioner := map[string]string{}
+	for _, v := range volumes {
+		provName, ok := poolProvisioner[v.Pool]
+		if !ok {
+			usageProv, name, err := poolUsageProvisioner(ctx, v.Pool)
+			if err != nil {
+				multiErr.Add(err)
+			} else if usageProv != nil {
+				provName = name
+				provisioners[provName] = usageProv
+			}
+			poolProvisioner[v.Pool] = provName
+		}
+		if provName == "" {
+			continue
+		}
+		byProvisioner[provName] = append(byProvisioner[provName], v)
+	}
+	result := map[string]*volumeTypes.VolumeUsage{}
+	for provName, provVolumes := range byProvisioner {
+		usages, err := provisioners[provName].VolumesUsage(ctx, provVolumes)
+		if err != nil {
+			multiErr.Add(err)
+		}
+		for name, usage := range usages {
+			result[name] = usage
+		}
+	}
+	return result, multiErr.ToError()
+}
+
+// poolUsageProvisioner returns the provisioner of the pool, and its name, if
+// it reports volume usage.
+func poolUsageProvisioner(ctx context.Context, poolName string) (provision.VolumeUsageProvisioner, string, error) {
+	p, err := pool.GetPoolByName(ctx, poolName)
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+	prov, err := p.GetProvisioner()
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+	usageProv, ok := prov.(provision.VolumeUsageProvisioner)
+	if !ok {
+		return nil, "", nil
+	}
+	return usageProv, prov.GetName(), nil
+}
+
+// usageCollector is the collector registered in Prometheus. Scrapes are
+// served from the usage cached by its last refresh, see
+// InitializeUsageCollector.
+var usageCollector = &volumeUsageCollector{}
+
+// InitializeUsageCollector starts refreshing periodically the volume usage
+// exposed to Prometheus.
+func InitializeUsageCollector() {
+	usageCollector.start()
+	shutdown.Register(usageCollector)
+}
+
+type volumeUsageSample struct {
+	labels []string
+	usage  volumeTypes.VolumeUsage
+}
+
+type volumeUsageCollector struct {
+	mu      sync.RWMutex
+	samples []volumeUsageSample
+	failed  bool
+	quit    chan struct{}
+	done    chan struct{}
+}
+
+func (c *volumeUsageCollector) start() {
+	c.quit = make(chan struct{})
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		for {
+			c.refresh(context.Background())
+			select {
+			case <-c.quit:
+				return
+			case <-time.After(usageRefreshInterval):
+			}
+		}
+	}()
+}
+
+func (c *volumeUsageCollector) Shutdown(ctx context.Context) error {
+	if c.quit == nil {
+		return nil
+	}
+	close(c.quit)
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// refresh lists every volume and fetches their usage, replacing the cached
+// samples. When the usage of some volumes could not be fetched, the failure
+// is flagged and the volumes keep their previous samples, if any.
+func (c *volumeUsageCollector) refresh(ctx context.Context) {
+	if servicemanager.Volume == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, usageCollectTimeout)
+	defer cancel()
+	volumes, err := servicemanager.Volume.ListByFilter(ctx, nil)
+	if err != nil {
+		log.Errorf("Could not list volumes to collect usage: %s", err.Error())
+		c.mu.Lock()
+		c.failed = true
+		c.mu.Unlock()
+		return
+	}
+	usages, usageErr := servicemanager.Volume.UsageByVolume(ctx, volumes)
+	if usageErr != nil {
+		log.Errorf("Could not get usage of volumes: %s", usageErr.Error())
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	previous := map[string]volumeUsageSample{}
+	if usageErr != nil {
+		for _, sample := range c.samples {
+			previous[sample.labels[0]] = sample
+		}
+	}
+	samples := make([]volumeUsageSample, 0, len(volumes))
+	for _, v := range volumes {
+		usage := usages[v.Name]
+		if usage == nil {
+			if sample, ok := previous[v.Name]; ok {
+				samples = append(samples, sample)
+			}
+			continue
+		}
+		samples = append(samples, volumeUsageSample{
+			labels: []string{v.Name, v.TeamOwner, v.Pool},
+			usage:  *usage,
+		})
+	}
+	c.failed = usageErr != nil
+	c.samples = samples
+}
+
+func (c *volumeUsageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- capacityBytesDesc
+	ch <- usedBytesDesc
+	ch <- availableBytesDesc
+	ch <- inodesDesc
+	ch <- inodesUsedDesc
+	ch <- inodesFreeDesc
+	ch <- usageFetchFailDesc
+}
+
+func (c *volumeUsageCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	failureValue := float64(0)
+	if c.failed {
+		failureValue = float64(1)
+	}
+	ch <- prometheus.MustNewConstMetric(usageFetchFailDesc, prometheus.GaugeValue, failureValue)
+	for _, sample := range c.samples {
+		usage, labels := sample.usage, sample.labels
+		ch <- prometheus.MustNewConstMetric(capacityBytesDesc, prometheus.GaugeValue, float64(usage.CapacityBytes), labels...)
+		ch <- prometheus.MustNewConstMetric(usedBytesDesc, prometheus.GaugeValue, float64(usage.UsedBytes), labels...)
+		ch <- prometheus.MustNewConstMetric(availableBytesDesc, prometheus.GaugeValue, float64(usage.AvailableBytes), labels...)
+		ch <- prometheus.MustNewConstMetric(inodesDesc, prometheus.GaugeValue, float64(usage.Inodes), labels...)
+		ch <- prometheus.MustNewConstMetric(inodesUsedDesc, prometheus.GaugeValue, float64(usage.InodesUsed), labels...)
+		ch <- prometheus.MustNewConstMetric(inodesFreeDesc, prometheus.GaugeValue, float64(usage.InodesFree), labels...)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/tsuru/tsuru/servicemanager"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+)
+
+func TestVolumeUsageCollector(t *testing.T) {
+	oldService := servicemanager.Volume
+	defer func() { servicemanager.Volume = oldService }()
+	usageCalls := 0
+	servicemanager.Volume = &volumeTypes.MockVolumeService{
+		OnListByFilter: func(ctx context.Context, f *volumeTypes.Filter) ([]volumeTypes.Volume, error) {
+			return []volumeTypes.Volume{
+				{Name: "v1", TeamOwner: "myteam", Pool: "mypool"},
+				{Name: "v2", TeamOwner: "myteam", Pool: "mypool"},
+				{Name: "v3", TeamOwner: "otherteam", Pool: "mypool"},
+			}, nil
+		},
+		OnUsageByVolume: func(ctx context.Context, volumes []volumeTypes.Volume) (map[string]*volumeTypes.VolumeUsage, error) {
+			usageCalls++
+			require.Len(t, volumes, 3)
+			return map[string]*volumeTypes.VolumeUsage{
+				"v1": {CapacityBytes: 100, UsedBytes: 40, AvailableBytes: 60, Inodes: 10, InodesUsed: 4, InodesFree: 6},
+			}, nil
+		},
+	}
+	collector := &volumeUsageCollector{}
+	collector.refresh(context.Background())
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	metricGroups, err := registry.Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, group := range metricGroups {
+		for _, m := range group.Metric {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if group.GetName() != "tsuru_volume_usage_fetch_fail" {
+				require.Equal(t, map[string]string{"volume": "v1", "team": "myteam", "pool": "mypool"}, labels)
+			}
+			values[group.GetName()] = m.GetGauge().GetValue()
+		}
+	}
+	require.Equal(t, map[string]float64{
+		"tsuru_volume_usage_fetch_fail": 0,
+		"tsuru_volume_capacity_bytes":   100,
+		"tsuru_volume_used_bytes":       40,
+		"tsuru_volume_available_bytes":  60,
+		"tsuru_volume_inodes":           10,
+		"tsuru_volume_inodes_used":      4,
+		"tsuru_volume_inodes_free":      6,
+	}, values)
+	_, err = registry.Gather()
+	require.NoError(t, err)
+	require.Equal(t, 1, usageCalls)
+}
+
+func TestVolumeUsageCollectorListError(t *testing.T) {
+	oldService := servicemanager.Volume
+	defer func() { servicemanager.Volume = oldService }()
+	servicemanager.Volume = &volumeTypes.MockVolumeService{
+		OnListByFilter: func(ctx context.Context, f *volumeTypes.Filter) ([]volumeTypes.Volume, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	collector := &volumeUsageCollector{}
+	collector.refresh(context.Background())
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	metricGroups, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, metricGroups, 1)
+	require.Equal(t, "tsuru_volume_usage_fetch_fail", metricGroups[0].GetName())
+	require.Equal(t, float64(1), metricGroups[0].Metric[0].GetGauge().GetValue())
+}
+
+func TestVolumeUsageCollectorUsageError(t *testing.T) {
+	oldService := servicemanager.Volume
+	defer func() { servicemanager.Volume = oldService }()
+	var usageErr error
+	usages := map[string]*volumeTypes.VolumeUsage{
+		"v1": {CapacityBytes: 100, UsedBytes: 40},
+		"v2": {CapacityBytes: 200, UsedBytes: 50},
+	}
+	servicemanager.Volume = &volumeTypes.MockVolumeService{
+		OnListByFilter: func(ctx context.Context, f *volumeTypes.Filter) ([]volumeTypes.Volume, error) {
+			return []volumeTypes.Volume{
+				{Name: "v1", TeamOwner: "myteam", Pool: "mypool"},
+				{Name: "v2", TeamOwner: "myteam", Pool: "otherpool"},
+			}, nil
+		},
+		OnUsageByVolume: func(ctx context.Context, volumes []volumeTypes.Volume) (map[string]*volumeTypes.VolumeUsage, error) {
+			return usages, usageErr
+		},
+	}
+	collector := &volumeUsageCollector{}
+	collector.refresh(context.Background())
+	usages = map[string]*volumeTypes.VolumeUsage{
+		"v1": {CapacityBytes: 100, UsedBytes: 45},
+	}
+	usageErr = errors.New("cluster unreachable")
+	collector.refresh(context.Background())
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	metricGroups, err := registry.Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, group := range metricGroups {
+		if group.GetName() != "tsuru_volume_usage_fetch_fail" && group.GetName() != "tsuru_volume_used_bytes" {
+			continue
+		}
+		for _, m := range group.Metric {
+			key := group.GetName()
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "volume" {
+					key += ":" + l.GetValue()
+				}
+			}
+			values[key] = m.GetGauge().GetValue()
+		}
+	}
+	require.Equal(t, map[string]float64{
+		"tsuru_volume_usage_fetch_fail": 1,
+		"tsuru_volume_used_bytes:v1":    45,
+		"tsuru_volume_used_bytes:v2":    50,
+	}, values)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/tsuru/tsuru/provision/pool"
 	"github.com/tsuru/tsuru/servicemanager"
 	"github.com/tsuru/tsuru/storage"
+	provTypes "github.com/tsuru/tsuru/types/provision"
 	volumeTypes "github.com/tsuru/tsuru/types/volume"
 	"github.com/tsuru/tsuru/validation"
 )
@@ -91,6 +92,18 @@ func (s *volumeService) ListByApp(ctx context.Context, appName string) ([]volume
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	return s.listByBinds(ctx, binds)
+}
+
+func (s *volumeService) ListByJob(ctx context.Context, jobName string) ([]volumeTypes.Volume, error) {
+	binds, err := s.storage.BindsForJob(ctx, "", jobName)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return s.listByBinds(ctx, binds)
+}
+
+func (s *volumeService) listByBinds(ctx context.Context, binds []volumeTypes.VolumeBind) ([]volumeTypes.Volume, error) {
 	if len(binds) == 0 {
 		return []volumeTypes.Volume{}, nil
 	}
@@ -150,12 +163,32 @@ func (s *volumeService) Delete(ctx context.Context, v *volumeTypes.Volume) error
 }
 
 func (s *volumeService) BindApp(ctx context.Context, opts *volumeTypes.BindOpts) error {
+	return s.insertBind(ctx, opts, volumeTypes.VolumeBindID{
+		App:        opts.AppName,
+		MountPoint: opts.MountPoint,
+		Volume:     opts.Volume.Name,
+	})
+}
+
+func (s *volumeService) BindJob(ctx context.Context, opts *volumeTypes.BindOpts) error {
+	return s.insertBind(ctx, opts, volumeTypes.VolumeBindID{
+		Job:        opts.JobName,
+		MountPoint: opts.MountPoint,
+		Volume:     opts.Volume.Name,
+	})
+}
+
+func (s *volumeService) insertBind(ctx context.Context, opts *volumeTypes.BindOpts, id volumeTypes.VolumeBindID) error {
+	if opts.TeamOwner != "" {
+		if err := opts.Volume.CheckBindAccess(opts.TeamOwner, opts.ReadOnly); err != nil {
+			return err
+		}
+	}
+	if err := checkBindPool(ctx, opts.Volume, opts.Pool); err != nil {
+		return err
+	}
 	bind := &volumeTypes.VolumeBind{
-		ID: volumeTypes.VolumeBindID{
-			App:        opts.AppName,
-			MountPoint: opts.MountPoint,
-			Volume:     opts.Volume.Name,
-		},
+		ID:       id,
 		ReadOnly: opts.ReadOnly,
 	}
 
@@ -166,6 +199,63 @@ func (s *volumeService) BindApp(ctx context.Context, opts *volumeTypes.BindOpts)
 	return err
 }
 
+// checkBindPool returns ErrVolumeBindOtherCluster if apps and jobs from pool
+// can't mount the volume, i.e. pool is not served by the same provisioner and
+// cluster as the volume pool.
+func checkBindPool(ctx context.Context, v *volumeTypes.Volume, poolName string) error {
+	if poolName == "" || poolName == v.Pool {
+		return nil
+	}
+	volumeProv, volumeCluster, err := poolCluster(ctx, v.Pool)
+	if err != nil {
+		return err
+	}
+	bindProv, bindCluster, err := poolCluster(ctx, poolName)
+	if err != nil {
+		return err
+	}
+	if volumeProv != bindProv || volumeCluster != bindCluster {
+		return volumeTypes.ErrVolumeBindOtherCluster
+	}
+	return nil
+}
+
+func poolCluster(ctx context.Context, poolName string) (string, string, error) {
+	p, err := pool.GetPoolByName(ctx, poolName)
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	prov, err := p.GetProvisioner()
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	c, err := servicemanager.Cluster.FindByPool(ctx, prov.GetName(), poolName)
+	if err != nil {
+		if errors.Cause(err) == provTypes.ErrNoCluster {
+			return prov.GetName(), "", nil
+		}
+		return "", "", err
+	}
+	if c == nil {
+		return prov.GetName(), "", nil
+	}
+	return prov.GetName(), c.Name, nil
+}
+
+func (s *volumeService) GrantAccess(ctx context.Context, v *volumeTypes.Volume, team string, readOnly bool) error {
+	if err := v.GrantAccess(team, readOnly); err != nil {
+		return err
+	}
+	return s.storage.Save(ctx, v)
+}
+
+func (s *volumeService) RevokeAccess(ctx context.Context, v *volumeTypes.Volume, team string) error {
+	if err := v.RevokeAccess(team); err != nil {
+		return err
+	}
+	return s.storage.Save(ctx, v)
+}
+
 func (s *volumeService) UnbindApp(ctx context.Context, opts *volumeTypes.BindOpts) error {
 	return s.storage.RemoveBind(ctx, volumeTypes.VolumeBindID{
 		App:        opts.AppName,
@@ -174,6 +264,14 @@ func (s *volumeService) UnbindApp(ctx context.Context, opts *volumeTypes.BindOpt
 	})
 }
 
+func (s *volumeService) UnbindJob(ctx context.Context, opts *volumeTypes.BindOpts) error {
+	return s.storage.RemoveBind(ctx, volumeTypes.VolumeBindID{
+		Job:        opts.JobName,
+		Volume:     opts.Volume.Name,
+		MountPoint: opts.MountPoint,
+	})
+}
+
 func (s *volumeService) Binds(ctx context.Context, v *volumeTypes.Volume) ([]volumeTypes.VolumeBind, error) {
 	if v.Binds != nil {
 		return v.Binds, nil
@@ -209,6 +307,24 @@ func (s *volumeService) BindsForApp(ctx context.Context, v *volumeTypes.Volume,
 	return binds, nil
 }
 
+func (s *volumeService) BindsForJob(ctx context.Context, v *volumeTypes.Volume, jobName string) ([]volumeTypes.VolumeBind, error) {
+	if v != nil && v.Binds != nil {
+		binds := []volumeTypes.VolumeBind{}
+		for _, bind := range v.Binds {
+			if bind.ID.Job == jobName {
+				binds = append(binds, bind)
+			}
+		}
+		return binds, nil
+	}
+
+	var volumeName string
+	if v != nil {
+		volumeName = v.Name
+	}
+	return s.storage.BindsForJob(ctx, volumeName, jobName)
+}
+
 func (s *volumeService) ListPlans(ctx context.Context) (map[string][]volumeTypes.VolumePlan, error) {
 	plans := map[string][]volumeTypes.VolumePlan{}
 	plansRaw, err := config.Get("volume-plans")
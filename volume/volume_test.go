@@ -20,6 +20,7 @@ import (
 	"github.com/tsuru/tsuru/servicemanager"
 	_ "github.com/tsuru/tsuru/storage/mongodb"
 	authTypes "github.com/tsuru/tsuru/types/auth"
+	provTypes "github.com/tsuru/tsuru/types/provision"
 	volumeTypes "github.com/tsuru/tsuru/types/volume"
 )
 
@@ -419,6 +420,206 @@ func TestVolumeBindAppMultipleMounts(t *testing.T) {
 	require.EqualValues(t, expected, binds)
 }
 
+func TestVolumeBindAppTeamAccess(t *testing.T) {
+	setupTest(t)
+	volumeService := &volumeService{storage: &volumeTypes.MockVolumeStorage{}}
+	vol := volumeTypes.Volume{
+		Name:      "v1",
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "mypool",
+		TeamOwner: "myteam",
+	}
+	err := volumeService.Create(context.TODO(), &vol)
+	require.NoError(t, err)
+	err = volumeService.BindApp(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		AppName:    "otherapp",
+		MountPoint: "/mnt0",
+		TeamOwner:  "otherteam",
+	})
+	require.NoError(t, err)
+	err = volumeService.GrantAccess(context.TODO(), &vol, "otherteam", true)
+	require.NoError(t, err)
+	err = volumeService.BindApp(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		AppName:    "thirdapp",
+		MountPoint: "/mnt1",
+		TeamOwner:  "thirdteam",
+	})
+	require.ErrorIs(t, err, volumeTypes.ErrVolumeBindNotAllowed)
+	err = volumeService.BindApp(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		AppName:    "otherapp",
+		MountPoint: "/mnt1",
+		TeamOwner:  "otherteam",
+	})
+	require.ErrorIs(t, err, volumeTypes.ErrVolumeBindReadOnly)
+	err = volumeService.BindApp(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		AppName:    "otherapp",
+		MountPoint: "/mnt1",
+		ReadOnly:   true,
+		TeamOwner:  "otherteam",
+	})
+	require.NoError(t, err)
+	err = volumeService.BindApp(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		AppName:    "myapp",
+		MountPoint: "/mnt1",
+		TeamOwner:  "myteam",
+	})
+	require.NoError(t, err)
+	binds, err := volumeService.Binds(context.TODO(), &vol)
+	require.NoError(t, err)
+	require.Len(t, binds, 3)
+	err = volumeService.RevokeAccess(context.TODO(), &vol, "otherteam")
+	require.NoError(t, err)
+	binds, err = volumeService.storage.Binds(context.TODO(), vol.Name)
+	require.NoError(t, err)
+	require.Len(t, binds, 3)
+}
+
+func TestVolumeBindOtherCluster(t *testing.T) {
+	setupTest(t)
+	oldCluster := servicemanager.Cluster
+	defer func() { servicemanager.Cluster = oldCluster }()
+	servicemanager.Cluster = &provTypes.MockClusterService{
+		OnFindByPool: func(prov, pool string) (*provTypes.Cluster, error) {
+			if pool == "otherpool" {
+				return &provTypes.Cluster{Name: "c2"}, nil
+			}
+			return &provTypes.Cluster{Name: "c1"}, nil
+		},
+	}
+	volumeService := &volumeService{storage: &volumeTypes.MockVolumeStorage{}}
+	vol := volumeTypes.Volume{
+		Name:      "v1",
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "mypool",
+		TeamOwner: "myteam",
+	}
+	err := volumeService.Create(context.TODO(), &vol)
+	require.NoError(t, err)
+	err = volumeService.BindJob(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		JobName:    "myjob",
+		MountPoint: "/data",
+		Pool:       "otherpool",
+	})
+	require.ErrorIs(t, err, volumeTypes.ErrVolumeBindOtherCluster)
+	err = volumeService.BindApp(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		AppName:    "myapp",
+		MountPoint: "/data",
+		Pool:       "otherpool",
+	})
+	require.ErrorIs(t, err, volumeTypes.ErrVolumeBindOtherCluster)
+	err = volumeService.BindJob(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		JobName:    "myjob",
+		MountPoint: "/data",
+		Pool:       "mypool",
+	})
+	require.NoError(t, err)
+}
+
+func TestVolumeBindJob(t *testing.T) {
+	setupTest(t)
+	volumeService := &volumeService{storage: &volumeTypes.MockVolumeStorage{}}
+	vol := volumeTypes.Volume{
+		Name:      "v1",
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "mypool",
+		TeamOwner: "myteam",
+	}
+	err := volumeService.Create(context.TODO(), &vol)
+	require.NoError(t, err)
+	err = volumeService.BindJob(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		JobName:    "myjob",
+		MountPoint: "/data",
+		ReadOnly:   true,
+		TeamOwner:  "myteam",
+	})
+	require.NoError(t, err)
+	err = volumeService.BindApp(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		AppName:    "myjob",
+		MountPoint: "/data",
+	})
+	require.NoError(t, err)
+	err = volumeService.BindJob(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		JobName:    "myjob",
+		MountPoint: "/data",
+	})
+	require.ErrorIs(t, err, volumeTypes.ErrVolumeAlreadyBound)
+	err = volumeService.GrantAccess(context.TODO(), &vol, "team2", false)
+	require.NoError(t, err)
+	err = volumeService.BindJob(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		JobName:    "otherjob",
+		MountPoint: "/data",
+		TeamOwner:  "otherteam",
+	})
+	require.ErrorIs(t, err, volumeTypes.ErrVolumeBindNotAllowed)
+	binds, err := volumeService.BindsForJob(context.TODO(), &vol, "myjob")
+	require.NoError(t, err)
+	require.Equal(t, []volumeTypes.VolumeBind{
+		{ID: volumeTypes.VolumeBindID{Job: "myjob", MountPoint: "/data", Volume: "v1"}, ReadOnly: true},
+	}, binds)
+	vols, err := volumeService.ListByJob(context.TODO(), "myjob")
+	require.NoError(t, err)
+	require.Len(t, vols, 1)
+	require.Equal(t, "v1", vols[0].Name)
+	err = volumeService.UnbindJob(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &vol,
+		JobName:    "myjob",
+		MountPoint: "/data",
+	})
+	require.NoError(t, err)
+	binds, err = volumeService.BindsForJob(context.TODO(), &vol, "myjob")
+	require.NoError(t, err)
+	require.Len(t, binds, 0)
+	binds, err = volumeService.BindsForApp(context.TODO(), &vol, "myjob")
+	require.NoError(t, err)
+	require.Len(t, binds, 1)
+}
+
+func TestVolumeGrantAndRevokeAccess(t *testing.T) {
+	setupTest(t)
+	volumeService := &volumeService{storage: &volumeTypes.MockVolumeStorage{}}
+	vol := volumeTypes.Volume{
+		Name:      "v1",
+		Plan:      volumeTypes.VolumePlan{Name: "p1"},
+		Pool:      "mypool",
+		TeamOwner: "myteam",
+	}
+	err := volumeService.Create(context.TODO(), &vol)
+	require.NoError(t, err)
+	err = volumeService.GrantAccess(context.TODO(), &vol, "myteam", false)
+	require.ErrorIs(t, err, volumeTypes.ErrVolumeTeamOwnerAccess)
+	err = volumeService.GrantAccess(context.TODO(), &vol, "team2", false)
+	require.NoError(t, err)
+	err = volumeService.GrantAccess(context.TODO(), &vol, "team3", true)
+	require.NoError(t, err)
+	err = volumeService.GrantAccess(context.TODO(), &vol, "team2", true)
+	require.ErrorIs(t, err, volumeTypes.ErrVolumeTeamAlreadyAllowed)
+	dbVol, err := volumeService.Get(context.TODO(), "v1")
+	require.NoError(t, err)
+	require.Equal(t, []volumeTypes.VolumeTeamAccess{
+		{Team: "team2"},
+		{Team: "team3", ReadOnly: true},
+	}, dbVol.Teams)
+	err = volumeService.RevokeAccess(context.TODO(), dbVol, "team2")
+	require.NoError(t, err)
+	err = volumeService.RevokeAccess(context.TODO(), dbVol, "team2")
+	require.ErrorIs(t, err, volumeTypes.ErrVolumeTeamNotAllowed)
+	dbVol, err = volumeService.Get(context.TODO(), "v1")
+	require.NoError(t, err)
+	require.Equal(t, []volumeTypes.VolumeTeamAccess{{Team: "team3", ReadOnly: true}}, dbVol.Teams)
+}
+
 func TestLoadBindsForApp(t *testing.T) {
 	setupTest(t)
 	volumeService := &volumeService{storage: &volumeTypes.MockVolumeStorage{}}
@@ -803,6 +1004,8 @@ func TestRenameTeam(t *testing.T) {
 	vol2 := volumeTypes.Volume{Name: "v2", Plan: volumeTypes.VolumePlan{Name: "p1"}, Pool: "mypool", TeamOwner: "otherteam"}
 	err = volumeService.Create(context.TODO(), &vol2)
 	require.NoError(t, err)
+	err = volumeService.GrantAccess(context.TODO(), &vol2, "myteam", true)
+	require.NoError(t, err)
 	err = volumeService.storage.RenameTeam(context.TODO(), "myteam", "mynewteam")
 	require.NoError(t, err)
 	vols, err := volumeService.ListByFilter(context.TODO(), nil)
@@ -810,4 +1013,5 @@ func TestRenameTeam(t *testing.T) {
 	sort.Slice(vols, func(i, j int) bool { return vols[i].Name < vols[j].Name })
 	require.Equal(t, "mynewteam", vols[0].TeamOwner)
 	require.Equal(t, "otherteam", vols[1].TeamOwner)
+	require.Equal(t, []volumeTypes.VolumeTeamAccess{{Team: "mynewteam", ReadOnly: true}}, vols[1].Teams)
 }